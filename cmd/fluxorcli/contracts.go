@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// contractDirective marks a struct as an EventBus contract:
+//
+//	//fluxor:address payments.authorize reply=PaymentAuthorizeReply
+const contractDirective = "//fluxor:address"
+
+// contract is a struct bound to an EventBus address.
+type contract struct {
+	Type    string // payload struct
+	Address string
+	Reply   string // reply struct for request/reply contracts, "" for events
+}
+
+// parseContracts returns the package name and the annotated structs of a Go
+// source file, in declaration order.
+func parseContracts(filename string, src []byte) (string, []contract, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return "", nil, err
+	}
+
+	structs := make(map[string]bool)
+	var contracts []contract
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			_, isStruct := ts.Type.(*ast.StructType)
+			structs[ts.Name.Name] = isStruct
+
+			doc := ts.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+			c, found, err := contractOf(ts.Name.Name, doc)
+			if err != nil {
+				return "", nil, fmt.Errorf("%s: %w", fset.Position(ts.Pos()), err)
+			}
+			if !found {
+				continue
+			}
+			if !isStruct {
+				return "", nil, fmt.Errorf("%s: %s is annotated with %s but is not a struct", fset.Position(ts.Pos()), ts.Name.Name, contractDirective)
+			}
+			contracts = append(contracts, c)
+		}
+	}
+
+	seen := make(map[string]string)
+	for _, c := range contracts {
+		if c.Reply != "" && !structs[c.Reply] {
+			return "", nil, fmt.Errorf("%s: reply type %s is not a struct declared in %s", c.Type, c.Reply, filename)
+		}
+		if other, ok := seen[c.Address]; ok {
+			return "", nil, fmt.Errorf("address %q is used by both %s and %s", c.Address, other, c.Type)
+		}
+		seen[c.Address] = c.Type
+	}
+	return file.Name.Name, contracts, nil
+}
+
+// contractOf reads the contract directive from a type's doc comment.
+func contractOf(typeName string, doc *ast.CommentGroup) (contract, bool, error) {
+	c := contract{Type: typeName}
+	if doc == nil {
+		return c, false, nil
+	}
+	for _, comment := range doc.List {
+		rest, ok := strings.CutPrefix(comment.Text, contractDirective)
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 || (rest[0] != ' ' && rest[0] != '\t') {
+			return c, false, fmt.Errorf("%s: %s needs an address", typeName, contractDirective)
+		}
+		c.Address = fields[0]
+		for _, opt := range fields[1:] {
+			reply, ok := strings.CutPrefix(opt, "reply=")
+			if !ok || reply == "" {
+				return c, false, fmt.Errorf("%s: unknown %s option %q", typeName, contractDirective, opt)
+			}
+			c.Reply = reply
+		}
+		return c, true, nil
+	}
+	return c, false, nil
+}
+
+// generateContracts renders the typed EventBus helpers for contracts.
+func generateContracts(pkg, source string, contracts []contract) ([]byte, error) {
+	hasReply := false
+	for _, c := range contracts {
+		hasReply = hasReply || c.Reply != ""
+	}
+
+	var buf bytes.Buffer
+	err := contractsTemplate.Execute(&buf, map[string]interface{}{
+		"Package":   pkg,
+		"Source":    source,
+		"Contracts": contracts,
+		"HasReply":  hasReply,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// runContracts generates the helpers for the contracts in input and writes
+// them to output (default: input with a _fluxor.go suffix).
+func runContracts(input, output string) (string, int, error) {
+	src, err := os.ReadFile(input)
+	if err != nil {
+		return "", 0, err
+	}
+	pkg, contracts, err := parseContracts(input, src)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(contracts) == 0 {
+		return "", 0, fmt.Errorf("no %s annotations found in %s", contractDirective, input)
+	}
+
+	code, err := generateContracts(pkg, filepath.Base(input), contracts)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to generate helpers: %w", err)
+	}
+	if output == "" {
+		output = strings.TrimSuffix(input, ".go") + "_fluxor.go"
+	}
+	if err := os.WriteFile(output, code, 0644); err != nil {
+		return "", 0, err
+	}
+	return output, len(contracts), nil
+}
+
+var contractsTemplate = template.Must(template.New("contracts").Parse(`// Code generated by fluxorcli contracts from {{.Source}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- if .HasReply}}
+	"fmt"
+	"time"
+{{end}}
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+{{range .Contracts}}
+// {{.Type}}Address is the EventBus address of {{.Type}} messages.
+const {{.Type}}Address = {{printf "%q" .Address}}
+
+// Publish{{.Type}} publishes msg to every consumer of {{.Type}}Address.
+func Publish{{.Type}}(bus core.EventBus, msg {{.Type}}) error {
+	return bus.Publish({{.Type}}Address, msg)
+}
+
+// Send{{.Type}} sends msg to one consumer of {{.Type}}Address.
+func Send{{.Type}}(bus core.EventBus, msg {{.Type}}) error {
+	return bus.Send({{.Type}}Address, msg)
+}
+{{if .Reply}}
+// Request{{.Type}} sends msg to {{.Type}}Address and decodes the reply.
+// A consumer that fails the request yields a *core.ReplyError.
+func Request{{.Type}}(bus core.EventBus, msg {{.Type}}, timeout time.Duration) (*{{.Reply}}, error) {
+	reply, err := bus.Request({{.Type}}Address, msg, timeout)
+	if err != nil {
+		return nil, err
+	}
+	var resp {{.Reply}}
+	if err := reply.DecodeBody(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode %s reply: %w", {{.Type}}Address, err)
+	}
+	return &resp, nil
+}
+
+// Consume{{.Type}} handles {{.Type}} requests and replies with the handler's
+// result. Bodies that do not decode are failed with code 400 and handler
+// errors with code 500.
+func Consume{{.Type}}(bus core.EventBus, handler func(ctx core.FluxorContext, msg {{.Type}}) ({{.Reply}}, error), opts ...core.ConsumerOption) core.Consumer {
+	return bus.Consumer({{.Type}}Address, opts...).Handler(func(ctx core.FluxorContext, m core.Message) error {
+		var msg {{.Type}}
+		if err := m.DecodeBody(&msg); err != nil {
+			return m.Fail(400, err.Error())
+		}
+		resp, err := handler(ctx, msg)
+		if err != nil {
+			return m.Fail(500, err.Error())
+		}
+		return m.Reply(resp)
+	})
+}
+{{else}}
+// Consume{{.Type}} handles {{.Type}} messages. Bodies that do not decode are
+// returned as handler errors.
+func Consume{{.Type}}(bus core.EventBus, handler func(ctx core.FluxorContext, msg {{.Type}}) error, opts ...core.ConsumerOption) core.Consumer {
+	return bus.Consumer({{.Type}}Address, opts...).Handler(func(ctx core.FluxorContext, m core.Message) error {
+		var msg {{.Type}}
+		if err := m.DecodeBody(&msg); err != nil {
+			return err
+		}
+		return handler(ctx, msg)
+	})
+}
+{{end}}{{end}}`))
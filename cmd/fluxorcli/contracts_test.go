@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateContracts_MatchesSample(t *testing.T) {
+	dir := filepath.Join("internal", "samplecontracts")
+	src, err := os.ReadFile(filepath.Join(dir, "contracts.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, contracts, err := parseContracts("contracts.go", src)
+	if err != nil {
+		t.Fatalf("parseContracts() error = %v", err)
+	}
+	want := []contract{
+		{Type: "WorkRequest", Address: "work.process", Reply: "WorkResponse"},
+		{Type: "WorkDone", Address: "work.done"},
+	}
+	if pkg != "samplecontracts" || len(contracts) != len(want) || contracts[0] != want[0] || contracts[1] != want[1] {
+		t.Fatalf("parseContracts() = %s %+v, want %+v", pkg, contracts, want)
+	}
+
+	code, err := generateContracts(pkg, "contracts.go", contracts)
+	if err != nil {
+		t.Fatalf("generateContracts() error = %v", err)
+	}
+	committed, err := os.ReadFile(filepath.Join(dir, "contracts_fluxor.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(code, committed) {
+		t.Errorf("generated helpers differ from %s; run go generate ./cmd/fluxorcli/...", dir)
+	}
+	for _, fn := range []string{"func RequestWorkRequest(", "func ConsumeWorkDone(", "func PublishWorkDone("} {
+		if !bytes.Contains(code, []byte(fn)) {
+			t.Errorf("generated code is missing %s", fn)
+		}
+	}
+	if bytes.Contains(code, []byte("func RequestWorkDone(")) {
+		t.Error("event contracts without a reply should not get a Request helper")
+	}
+}
+
+func TestParseContracts_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"missing address": "//fluxor:address\ntype A struct{}",
+		"unknown option":  "//fluxor:address a.b codec=json\ntype A struct{}",
+		"not a struct":    "//fluxor:address a.b\ntype A string",
+		"unknown reply":   "//fluxor:address a.b reply=Missing\ntype A struct{}",
+		"duplicate":       "//fluxor:address a.b\ntype A struct{}\n\n//fluxor:address a.b\ntype B struct{}",
+	}
+	for name, decls := range tests {
+		t.Run(name, func(t *testing.T) {
+			src := "package p\n\n" + decls + "\n"
+			if _, _, err := parseContracts("p.go", []byte(src)); err == nil {
+				t.Errorf("parseContracts() accepted %q", decls)
+			}
+		})
+	}
+}
+
+func TestRunContracts_WritesOutput(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "events.go")
+	src := "package events\n\n// Ping is a heartbeat.\n//\n//fluxor:address ping\ntype Ping struct{}\n"
+	if err := os.WriteFile(input, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output, n, err := runContracts(input, "")
+	if err != nil {
+		t.Fatalf("runContracts() error = %v", err)
+	}
+	if n != 1 || output != filepath.Join(dir, "events_fluxor.go") {
+		t.Errorf("runContracts() = %s, %d", output, n)
+	}
+	code, _ := os.ReadFile(output)
+	if !strings.Contains(string(code), `const PingAddress = "ping"`) || strings.Contains(string(code), `"time"`) {
+		t.Errorf("generated code:\n%s", code)
+	}
+
+	if err := os.WriteFile(input, []byte("package events\n\ntype Plain struct{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := runContracts(input, ""); err == nil {
+		t.Error("runContracts() should fail without annotations")
+	}
+}
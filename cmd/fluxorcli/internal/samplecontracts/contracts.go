@@ -0,0 +1,26 @@
+// Package samplecontracts is the contract file the fluxorcli contracts tests
+// generate helpers for.
+package samplecontracts
+
+//go:generate go run ../.. contracts contracts.go
+
+// WorkRequest is a job for a worker.
+//
+//fluxor:address work.process reply=WorkResponse
+type WorkRequest struct {
+	ID      string `json:"id"`
+	Payload string `json:"payload"`
+}
+
+// WorkResponse is a worker's result.
+type WorkResponse struct {
+	ID     string `json:"id"`
+	Result string `json:"result"`
+}
+
+// WorkDone is broadcast once a job has been processed.
+//
+//fluxor:address work.done
+type WorkDone struct {
+	ID string `json:"id"`
+}
@@ -0,0 +1,79 @@
+// Code generated by fluxorcli contracts from contracts.go; DO NOT EDIT.
+
+package samplecontracts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// WorkRequestAddress is the EventBus address of WorkRequest messages.
+const WorkRequestAddress = "work.process"
+
+// PublishWorkRequest publishes msg to every consumer of WorkRequestAddress.
+func PublishWorkRequest(bus core.EventBus, msg WorkRequest) error {
+	return bus.Publish(WorkRequestAddress, msg)
+}
+
+// SendWorkRequest sends msg to one consumer of WorkRequestAddress.
+func SendWorkRequest(bus core.EventBus, msg WorkRequest) error {
+	return bus.Send(WorkRequestAddress, msg)
+}
+
+// RequestWorkRequest sends msg to WorkRequestAddress and decodes the reply.
+// A consumer that fails the request yields a *core.ReplyError.
+func RequestWorkRequest(bus core.EventBus, msg WorkRequest, timeout time.Duration) (*WorkResponse, error) {
+	reply, err := bus.Request(WorkRequestAddress, msg, timeout)
+	if err != nil {
+		return nil, err
+	}
+	var resp WorkResponse
+	if err := reply.DecodeBody(&resp); err != nil {
+		return nil, fmt.Errorf("failed to decode %s reply: %w", WorkRequestAddress, err)
+	}
+	return &resp, nil
+}
+
+// ConsumeWorkRequest handles WorkRequest requests and replies with the handler's
+// result. Bodies that do not decode are failed with code 400 and handler
+// errors with code 500.
+func ConsumeWorkRequest(bus core.EventBus, handler func(ctx core.FluxorContext, msg WorkRequest) (WorkResponse, error), opts ...core.ConsumerOption) core.Consumer {
+	return bus.Consumer(WorkRequestAddress, opts...).Handler(func(ctx core.FluxorContext, m core.Message) error {
+		var msg WorkRequest
+		if err := m.DecodeBody(&msg); err != nil {
+			return m.Fail(400, err.Error())
+		}
+		resp, err := handler(ctx, msg)
+		if err != nil {
+			return m.Fail(500, err.Error())
+		}
+		return m.Reply(resp)
+	})
+}
+
+// WorkDoneAddress is the EventBus address of WorkDone messages.
+const WorkDoneAddress = "work.done"
+
+// PublishWorkDone publishes msg to every consumer of WorkDoneAddress.
+func PublishWorkDone(bus core.EventBus, msg WorkDone) error {
+	return bus.Publish(WorkDoneAddress, msg)
+}
+
+// SendWorkDone sends msg to one consumer of WorkDoneAddress.
+func SendWorkDone(bus core.EventBus, msg WorkDone) error {
+	return bus.Send(WorkDoneAddress, msg)
+}
+
+// ConsumeWorkDone handles WorkDone messages. Bodies that do not decode are
+// returned as handler errors.
+func ConsumeWorkDone(bus core.EventBus, handler func(ctx core.FluxorContext, msg WorkDone) error, opts ...core.ConsumerOption) core.Consumer {
+	return bus.Consumer(WorkDoneAddress, opts...).Handler(func(ctx core.FluxorContext, m core.Message) error {
+		var msg WorkDone
+		if err := m.DecodeBody(&msg); err != nil {
+			return err
+		}
+		return handler(ctx, msg)
+	})
+}
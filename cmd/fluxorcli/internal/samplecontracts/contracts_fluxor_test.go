@@ -0,0 +1,61 @@
+package samplecontracts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func TestRequestWorkRequest_DecodesReply(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	bus := gocmd.EventBus()
+
+	ConsumeWorkRequest(bus, func(ctx core.FluxorContext, msg WorkRequest) (WorkResponse, error) {
+		if msg.Payload == "" {
+			return WorkResponse{}, errors.New("empty payload")
+		}
+		return WorkResponse{ID: msg.ID, Result: "processed " + msg.Payload}, nil
+	})
+
+	resp, err := RequestWorkRequest(bus, WorkRequest{ID: "job-1", Payload: "data"}, time.Second)
+	if err != nil {
+		t.Fatalf("RequestWorkRequest() error = %v", err)
+	}
+	if resp.ID != "job-1" || resp.Result != "processed data" {
+		t.Errorf("reply = %+v", resp)
+	}
+
+	_, err = RequestWorkRequest(bus, WorkRequest{ID: "job-2"}, time.Second)
+	var replyErr *core.ReplyError
+	if !errors.As(err, &replyErr) || replyErr.FailureCode != 500 {
+		t.Errorf("RequestWorkRequest() error = %v, want a 500 *core.ReplyError", err)
+	}
+}
+
+func TestPublishWorkDone_DecodesEvent(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	bus := gocmd.EventBus()
+
+	got := make(chan WorkDone, 1)
+	ConsumeWorkDone(bus, func(ctx core.FluxorContext, msg WorkDone) error {
+		got <- msg
+		return nil
+	})
+
+	if err := PublishWorkDone(bus, WorkDone{ID: "job-1"}); err != nil {
+		t.Fatalf("PublishWorkDone() error = %v", err)
+	}
+	select {
+	case msg := <-got:
+		if msg.ID != "job-1" {
+			t.Errorf("event = %+v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("WorkDone was not delivered")
+	}
+}
@@ -12,6 +12,8 @@ const version = "0.1.0"
 
 func main() {
 	newCmd := flag.NewFlagSet("new", flag.ExitOnError)
+	contractsCmd := flag.NewFlagSet("contracts", flag.ExitOnError)
+	contractsOut := contractsCmd.String("o", "", "output file (default <file>_fluxor.go)")
 
 	if len(os.Args) < 2 {
 		printUsage()
@@ -36,6 +38,19 @@ func main() {
 		fmt.Printf("  cd %s\n", appName)
 		fmt.Printf("  go mod tidy\n")
 		fmt.Printf("  go run .\n\n")
+	case "contracts":
+		contractsCmd.Parse(os.Args[2:])
+		if contractsCmd.NArg() != 1 {
+			fmt.Fprintf(os.Stderr, "Error: one contracts file is required\n\n")
+			fmt.Fprintf(os.Stderr, "Usage: fluxorcli contracts [-o output.go] <contracts.go>\n")
+			os.Exit(1)
+		}
+		output, n, err := runContracts(contractsCmd.Arg(0), *contractsOut)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Generated helpers for %d contracts in %s\n", n, output)
 	case "version", "-v", "--version":
 		fmt.Printf("fluxorcli version %s\n", version)
 	default:
@@ -48,6 +63,7 @@ func printUsage() {
 	fmt.Fprintf(os.Stderr, "Fluxor CLI - Create and manage Fluxor applications\n\n")
 	fmt.Fprintf(os.Stderr, "Usage:\n")
 	fmt.Fprintf(os.Stderr, "  fluxorcli new <appname>    Create a new Fluxor application\n")
+	fmt.Fprintf(os.Stderr, "  fluxorcli contracts <file> Generate typed EventBus helpers for contracts\n")
 	fmt.Fprintf(os.Stderr, "  fluxorcli version          Show version\n\n")
 }
 
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/fluxorio/fluxor/pkg/web"
+)
+
+// Accepts file uploads with a description and stores them under ./uploads.
+// The upload route takes bodies of up to 64 MB; every other route keeps the
+// server's 1 MB limit.
+//
+//	go run ./examples/file-upload
+//	curl -F description=logs -F file=@app.log http://localhost:8080/uploads
+func main() {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	dir := "uploads"
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	config := web.DefaultFastHTTPServerConfig(":8080")
+	config.MaxRequestBodySize = 1 << 20
+	config.MaxMultipartMemory = 8 << 20 // larger files are spooled to disk
+	server := web.NewFastHTTPServer(gocmd, config)
+	router := server.FastRouter()
+
+	router.GETFast("/", func(c *web.FastRequestContext) error {
+		c.RequestCtx.SetContentType("text/html; charset=utf-8")
+		c.RequestCtx.SetBodyString(page)
+		return nil
+	})
+
+	router.POSTFast("/uploads", func(c *web.FastRequestContext) error {
+		file, header, err := c.FormFile("file")
+		if err != nil {
+			return c.JSON(400, map[string]string{"error": err.Error()})
+		}
+		_ = file.Close()
+		// Never trust the client's path
+		name := filepath.Base(header.Filename)
+		if err := c.SaveUploadedFile("file", filepath.Join(dir, name)); err != nil {
+			return err
+		}
+		return c.JSON(201, map[string]interface{}{
+			"name":        name,
+			"size":        header.Size,
+			"description": c.FormValue("description"),
+		})
+	})
+	router.SetBodyLimit("POST", "/uploads", 64<<20)
+
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		<-sigChan
+		if err := server.Stop(); err != nil {
+			log.Printf("stop: %v", err)
+		}
+	}()
+
+	log.Println("Upload form on http://localhost:8080/")
+	if err := server.Start(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+const page = `<!doctype html>
+<title>Upload</title>
+<form method="post" action="/uploads" enctype="multipart/form-data">
+  <input name="description" placeholder="Description">
+  <input type="file" name="file">
+  <button>Upload</button>
+</form>
+`
@@ -2,6 +2,7 @@ package verticles
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -308,31 +309,18 @@ func TestWorkerVerticle_Handler_InvalidBody(t *testing.T) {
 
 	// Send invalid message (not a WorkRequest)
 	workerAddr := contracts.WorkerAddress + "." + workerID
-	reply, err := gocmd.EventBus().Request(workerAddr, "invalid-body", 2*time.Second)
-	if err != nil {
-		t.Fatalf("Request failed: %v", err)
-	}
-
-	// Check that reply contains failure information
-	var failureResp map[string]interface{}
-	if err := reply.DecodeBody(&failureResp); err != nil {
-		t.Fatalf("DecodeBody failed: %v", err)
-	}
+	_, err = gocmd.EventBus().Request(workerAddr, "invalid-body", 2*time.Second)
 
-	failureCode, ok := failureResp["failureCode"].(float64) // JSON numbers decode as float64
-	if !ok {
-		t.Error("Expected failureCode in response")
+	// Check that the failure surfaces as a typed reply error
+	var replyErr *core.ReplyError
+	if !errors.As(err, &replyErr) {
+		t.Fatalf("Expected *core.ReplyError, got %v", err)
 	}
-	if int(failureCode) != 400 {
-		t.Errorf("Expected failureCode 400, got %v", failureCode)
-	}
-
-	message, ok := failureResp["message"].(string)
-	if !ok {
-		t.Error("Expected message in response")
+	if replyErr.FailureCode != 400 {
+		t.Errorf("Expected failureCode 400, got %d", replyErr.FailureCode)
 	}
-	if message != "Invalid body" {
-		t.Errorf("Expected message 'Invalid body', got %s", message)
+	if replyErr.Message != "Invalid body" {
+		t.Errorf("Expected message 'Invalid body', got %s", replyErr.Message)
 	}
 }
 
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/fluxorio/fluxor/pkg/web"
+)
+
+// A WebSocket echo server. Every message is echoed back to its sender and
+// published to "chat.messages", which all connected clients subscribe to.
+//
+//	go run ./examples/websocket-echo
+//	websocat ws://localhost:8080/ws/alice
+func main() {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	server := web.NewFastHTTPServer(gocmd, web.DefaultFastHTTPServerConfig(":8080"))
+	router := server.FastRouter()
+
+	router.WS("/ws/:name", func(conn *web.WSConn) error {
+		name := conn.Param("name")
+
+		// Broadcast: forward chat messages from everyone to this client
+		consumer := conn.EventBus.Consumer("chat.messages").Handler(func(ctx core.FluxorContext, msg core.Message) error {
+			return conn.WriteJSON(msg.Body())
+		})
+		defer consumer.Unregister()
+
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return nil // client went away or the server is stopping
+			}
+			if err := conn.WriteMessage(messageType, data); err != nil {
+				return err
+			}
+			if err := conn.EventBus.Publish("chat.messages", map[string]interface{}{"from": name, "text": string(data)}); err != nil {
+				return err
+			}
+		}
+	})
+
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		<-sigChan
+		// Open connections receive a going-away close frame
+		if err := server.Stop(); err != nil {
+			log.Printf("stop: %v", err)
+		}
+	}()
+
+	log.Println("WebSocket echo server on ws://localhost:8080/ws/:name")
+	if err := server.Start(); err != nil {
+		log.Fatal(err)
+	}
+}
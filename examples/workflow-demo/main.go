@@ -7,6 +7,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"time"
@@ -205,13 +206,12 @@ func (v *ApiGateway) Start(ctx core.FluxorContext) error {
 			return c.JSON(500, map[string]interface{}{"error": err.Error()})
 		}
 
-		// Wait a bit for simple workflows to complete
-		time.Sleep(100 * time.Millisecond)
-
-		// Get result
-		execCtx, err := v.wfVerticle.Engine().GetExecution(execID)
+		// Wait for the workflow to finish
+		awaitCtx, cancel := context.WithTimeout(c.Context(), 10*time.Second)
+		defer cancel()
+		execCtx, err := v.wfVerticle.Engine().AwaitExecution(awaitCtx, execID)
 		if err != nil {
-			return c.JSON(500, map[string]interface{}{"error": err.Error()})
+			return c.JSON(500, map[string]interface{}{"executionId": execID, "error": err.Error()})
 		}
 
 		// Return the last node output or format node output
@@ -1,8 +1,10 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/fluxorio/fluxor/pkg/core"
 	"github.com/fluxorio/fluxor/pkg/fluxor"
@@ -100,10 +102,12 @@ func (v *OpenAIGateway) Start(ctx core.FluxorContext) error {
 			return c.JSON(500, map[string]interface{}{"error": err.Error()})
 		}
 
-		// Get result (simplified - in production, use proper async handling)
-		execCtx, err := v.wfVerticle.Engine().GetExecution(execID)
+		// Wait for the workflow (including the API call) to finish
+		awaitCtx, cancel := context.WithTimeout(c.Context(), 60*time.Second)
+		defer cancel()
+		execCtx, err := v.wfVerticle.Engine().AwaitExecution(awaitCtx, execID)
 		if err != nil {
-			return c.JSON(500, map[string]interface{}{"error": err.Error()})
+			return c.JSON(500, map[string]interface{}{"executionId": execID, "error": err.Error()})
 		}
 
 		return c.JSON(200, map[string]interface{}{
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/fluxorio/fluxor/pkg/web"
+	"github.com/fluxorio/fluxor/pkg/workflow"
+)
+
+// Streams a workflow's progress to the browser with Server-Sent Events.
+// Opening /reports/events runs the "report" workflow and sends an event as
+// each node starts and finishes; the stream ends with the execution.
+//
+//	go run ./examples/workflow-sse
+//	open http://localhost:8080/ (or: curl -N http://localhost:8080/reports/events)
+func main() {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	engine := workflow.NewEngine(gocmd.EventBus())
+	report := &workflow.WorkflowDefinition{
+		ID: "report",
+		Nodes: []workflow.NodeDefinition{
+			{ID: "fetch", Type: "wait", Config: map[string]interface{}{"duration": "700ms"}, Next: []string{"aggregate"}},
+			{ID: "aggregate", Type: "wait", Config: map[string]interface{}{"duration": "700ms"}, Next: []string{"render"}},
+			{ID: "render", Type: "set", Config: map[string]interface{}{"values": map[string]interface{}{"report": "ready"}}},
+		},
+	}
+	if err := engine.RegisterWorkflow(report); err != nil {
+		log.Fatal(err)
+	}
+
+	// Node middleware reports every node run to the open streams
+	progress := newProgressHub()
+	engine.UseNodeMiddleware(func(next workflow.NodeHandler) workflow.NodeHandler {
+		return func(ctx context.Context, input *workflow.NodeInput) (*workflow.NodeOutput, error) {
+			execID := input.Context.ExecutionID
+			progress.publish(nodeEvent{ExecutionID: execID, NodeID: input.NodeID, Event: "node.started"})
+			output, err := next(ctx, input)
+			ev := nodeEvent{ExecutionID: execID, NodeID: input.NodeID, Event: "node.completed"}
+			if err != nil {
+				ev.Event, ev.Error = "node.failed", err.Error()
+			}
+			progress.publish(ev)
+			return output, err
+		}
+	})
+
+	server := web.NewFastHTTPServer(gocmd, web.DefaultFastHTTPServerConfig(":8080"))
+	router := server.FastRouter()
+
+	router.GETFast("/", func(c *web.FastRequestContext) error {
+		c.RequestCtx.SetContentType("text/html; charset=utf-8")
+		c.RequestCtx.SetBodyString(page)
+		return nil
+	})
+
+	router.GETFast("/reports/events", func(c *web.FastRequestContext) error {
+		return c.SSE(func(w *web.SSEWriter) error {
+			// Subscribe before starting, so no event is missed
+			events := progress.subscribe()
+			defer progress.unsubscribe(events)
+
+			execID, err := engine.ExecuteWorkflow(w.Context(), report.ID, map[string]interface{}{})
+			if err != nil {
+				return err
+			}
+			finished := make(chan error, 1)
+			go func() {
+				_, err := engine.AwaitExecution(w.Context(), execID)
+				finished <- err
+			}()
+
+			send := func(ev nodeEvent) error {
+				if ev.ExecutionID != execID {
+					return nil // another client's run
+				}
+				return w.Send(web.SSEEvent{Event: ev.Event, Data: ev})
+			}
+			for {
+				select {
+				case <-w.Context().Done():
+					return nil // the browser went away
+				case ev := <-events:
+					if err := send(ev); err != nil {
+						return err
+					}
+				case err := <-finished:
+					// Every node event was published before the execution ended
+					for len(events) > 0 {
+						if err := send(<-events); err != nil {
+							return err
+						}
+					}
+					ev := nodeEvent{ExecutionID: execID, Event: "execution.finished", Status: "completed"}
+					if err != nil {
+						ev.Status, ev.Error = "failed", err.Error()
+					}
+					return send(ev)
+				}
+			}
+		})
+	})
+
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		<-sigChan
+		if err := server.Stop(); err != nil {
+			log.Printf("stop: %v", err)
+		}
+	}()
+
+	log.Println("Workflow progress on http://localhost:8080/")
+	if err := server.Start(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// nodeEvent is one progress update sent to the browser.
+type nodeEvent struct {
+	ExecutionID string `json:"executionId"`
+	NodeID      string `json:"nodeId,omitempty"`
+	Event       string `json:"event"`
+	Status      string `json:"status,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// progressHub copies node events to every subscribed stream.
+type progressHub struct {
+	mu   sync.Mutex
+	subs map[chan nodeEvent]bool
+}
+
+func newProgressHub() *progressHub {
+	return &progressHub{subs: make(map[chan nodeEvent]bool)}
+}
+
+func (h *progressHub) subscribe() chan nodeEvent {
+	ch := make(chan nodeEvent, 64)
+	h.mu.Lock()
+	h.subs[ch] = true
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *progressHub) unsubscribe(ch chan nodeEvent) {
+	h.mu.Lock()
+	delete(h.subs, ch)
+	h.mu.Unlock()
+}
+
+func (h *progressHub) publish(ev nodeEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default: // a stalled client misses the event rather than stalling the workflow
+		}
+	}
+}
+
+const page = `<!doctype html>
+<title>Report progress</title>
+<button onclick="run()">Run report</button>
+<ul id="log"></ul>
+<script>
+function run() {
+  const log = document.getElementById("log");
+  log.innerHTML = "";
+  const source = new EventSource("/reports/events");
+  const show = (e) => {
+    const ev = JSON.parse(e.data);
+    const li = document.createElement("li");
+    li.textContent = ev.event + " " + (ev.nodeId || ev.status);
+    log.appendChild(li);
+    if (ev.event === "execution.finished") source.close();
+  };
+  for (const name of ["node.started", "node.completed", "node.failed", "execution.finished"]) {
+    source.addEventListener(name, show);
+  }
+}
+</script>
+`
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/fsnotify/fsnotify"
+)
+
+// ChangedAddress is the EventBus address WatcherVerticle publishes to by default.
+const ChangedAddress = "config.changed"
+
+// defaultWatchDebounce coalesces the bursts of events editors emit per save.
+const defaultWatchDebounce = 100 * time.Millisecond
+
+// Change is the body of a config.changed event.
+type Change struct {
+	// Path of the watched config file
+	Path string `json:"path"`
+
+	// Keys that were added, removed or modified, as dotted paths
+	// (e.g. "server.port"), sorted
+	Keys []string `json:"keys"`
+
+	// Config is the complete reloaded configuration
+	Config map[string]interface{} `json:"config"`
+}
+
+// WatcherConfig configures a WatcherVerticle.
+type WatcherConfig struct {
+	// Path of the config file (json/yaml, see Load)
+	Path string
+
+	// Address to publish Change events to (default ChangedAddress)
+	Address string
+
+	// Debounce waits this long after the last file event before reloading
+	// (default 100ms)
+	Debounce time.Duration
+}
+
+// WatcherVerticle watches a config file and publishes a Change to the
+// EventBus whenever a save changes its content, so other verticles can react
+// to new settings without a restart.
+//
+// The file's directory is watched rather than the file itself, so editors
+// that save by writing a temp file and renaming it are handled. A file that
+// fails to load (e.g. a half-written save) is logged and ignored; the next
+// valid save is diffed against the last valid config.
+type WatcherVerticle struct {
+	*core.BaseVerticle
+
+	path     string
+	address  string
+	debounce time.Duration
+	logger   core.Logger
+
+	mu      sync.RWMutex
+	current map[string]interface{}
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// NewWatcherVerticle creates a verticle watching config.Path.
+func NewWatcherVerticle(config WatcherConfig) *WatcherVerticle {
+	v := &WatcherVerticle{
+		BaseVerticle: core.NewBaseVerticle("config-watcher"),
+		path:         filepath.Clean(config.Path),
+		address:      config.Address,
+		debounce:     config.Debounce,
+		logger:       core.NewDefaultLogger(),
+	}
+	if v.address == "" {
+		v.address = ChangedAddress
+	}
+	if v.debounce <= 0 {
+		v.debounce = defaultWatchDebounce
+	}
+	return v
+}
+
+// Start loads the config and starts watching it.
+func (v *WatcherVerticle) Start(ctx core.FluxorContext) error {
+	if err := v.BaseVerticle.Start(ctx); err != nil {
+		return err
+	}
+
+	current, err := loadMap(v.path)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create config watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(v.path)); err != nil {
+		_ = watcher.Close()
+		return fmt.Errorf("failed to watch %s: %w", v.path, err)
+	}
+	v.watcher = watcher
+	v.done = make(chan struct{})
+
+	// Published only once watching, so a non-nil Current means saves are seen
+	v.mu.Lock()
+	v.current = current
+	v.mu.Unlock()
+	go v.watch()
+	return nil
+}
+
+// Stop stops watching the config file.
+func (v *WatcherVerticle) Stop(ctx core.FluxorContext) error {
+	if v.watcher != nil {
+		_ = v.watcher.Close()
+		<-v.done
+		v.watcher = nil
+	}
+	return v.BaseVerticle.Stop(ctx)
+}
+
+// Current returns the last successfully loaded configuration (nil until started).
+func (v *WatcherVerticle) Current() map[string]interface{} {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.current
+}
+
+func (v *WatcherVerticle) watch() {
+	defer close(v.done)
+
+	timer := time.NewTimer(v.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case event, ok := <-v.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != v.path || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+				continue
+			}
+			timer.Reset(v.debounce)
+		case err, ok := <-v.watcher.Errors:
+			if !ok {
+				return
+			}
+			v.logger.Error(fmt.Sprintf("config watcher error for %s: %v", v.path, err))
+		case <-timer.C:
+			v.reload()
+		}
+	}
+}
+
+// reload loads the file and publishes the keys that changed, if any.
+func (v *WatcherVerticle) reload() {
+	next, err := loadMap(v.path)
+	if err != nil {
+		v.logger.Error(fmt.Sprintf("config reload of %s failed, keeping current config: %v", v.path, err))
+		return
+	}
+
+	v.mu.Lock()
+	keys := ChangedKeys(v.current, next)
+	if len(keys) > 0 {
+		v.current = next
+	}
+	v.mu.Unlock()
+	if len(keys) == 0 {
+		return
+	}
+
+	change := Change{Path: v.path, Keys: keys, Config: next}
+	if err := v.EventBus().Publish(v.address, change); err != nil {
+		v.logger.Error(fmt.Sprintf("failed to publish config change for %s: %v", v.path, err))
+	}
+}
+
+func loadMap(path string) (map[string]interface{}, error) {
+	cfg := make(map[string]interface{})
+	if err := Load(path, &cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// ChangedKeys returns the dotted paths of the leaf values that differ between
+// two configs (added, removed or modified), sorted. Nested maps are compared
+// key by key; any other value, including lists, is compared as a whole.
+func ChangedKeys(old, new map[string]interface{}) []string {
+	var keys []string
+	diffMaps("", old, new, &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+func diffMaps(prefix string, old, new map[string]interface{}, keys *[]string) {
+	for k, ov := range old {
+		nv, ok := new[k]
+		if !ok {
+			*keys = append(*keys, prefix+k)
+			continue
+		}
+		diffValues(prefix+k, ov, nv, keys)
+	}
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			*keys = append(*keys, prefix+k)
+		}
+	}
+}
+
+func diffValues(key string, old, new interface{}, keys *[]string) {
+	om, oldIsMap := old.(map[string]interface{})
+	nm, newIsMap := new.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		diffMaps(key+".", om, nm, keys)
+		return
+	}
+	if !reflect.DeepEqual(old, new) {
+		*keys = append(*keys, key)
+	}
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func startWatcher(t *testing.T, path string) (*WatcherVerticle, <-chan Change) {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	changes := make(chan Change, 10)
+	gocmd.EventBus().Consumer(ChangedAddress).Handler(func(ctx core.FluxorContext, msg core.Message) error {
+		var change Change
+		if err := msg.DecodeBody(&change); err != nil {
+			return err
+		}
+		changes <- change
+		return nil
+	})
+
+	v := NewWatcherVerticle(WatcherConfig{Path: path, Debounce: 20 * time.Millisecond})
+	if _, err := gocmd.DeployVerticle(v); err != nil {
+		t.Fatalf("DeployVerticle() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for v.Current() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("watcher verticle did not start")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return v, changes
+}
+
+func TestWatcherVerticle_PublishesChangedKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	writeConfig(t, path, "server:\n  port: 8080\n  host: localhost\nlog: info\n")
+	v, changes := startWatcher(t, path)
+
+	writeConfig(t, path, "server:\n  port: 9090\n  host: localhost\nlog: info\nfeature: true\n")
+
+	select {
+	case change := <-changes:
+		if want := []string{"feature", "server.port"}; !reflect.DeepEqual(change.Keys, want) {
+			t.Errorf("Keys = %v, want %v", change.Keys, want)
+		}
+		if change.Path != path {
+			t.Errorf("Path = %q, want %q", change.Path, path)
+		}
+		server, _ := change.Config["server"].(map[string]interface{})
+		if server["port"] != float64(9090) {
+			t.Errorf("Config server.port = %v, want 9090", server["port"])
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no config.changed event after modifying the file")
+	}
+
+	server, _ := v.Current()["server"].(map[string]interface{})
+	if server["port"] != 9090 {
+		t.Errorf("Current() server.port = %v, want 9090", server["port"])
+	}
+}
+
+func TestWatcherVerticle_IgnoresInvalidAndUnchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.json")
+	writeConfig(t, path, `{"a": 1, "b": {"c": "x"}}`)
+	_, changes := startWatcher(t, path)
+
+	writeConfig(t, path, `{"a": 1,`)
+	writeConfig(t, path, `{"b": {"c": "x"}, "a": 1}`)
+	select {
+	case change := <-changes:
+		t.Fatalf("unexpected change %v for invalid or identical content", change.Keys)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// Atomic save: write a temp file and rename it over the config
+	tmp := path + ".tmp"
+	writeConfig(t, tmp, `{"a": 1, "b": {"c": "y"}}`)
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	select {
+	case change := <-changes:
+		if want := []string{"b.c"}; !reflect.DeepEqual(change.Keys, want) {
+			t.Errorf("Keys = %v, want %v", change.Keys, want)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no config.changed event after renaming over the file")
+	}
+}
+
+func TestChangedKeys(t *testing.T) {
+	old := map[string]interface{}{
+		"same":    1,
+		"removed": true,
+		"list":    []interface{}{1, 2},
+		"db":      map[string]interface{}{"dsn": "a", "pool": map[string]interface{}{"max": 5}},
+	}
+	new := map[string]interface{}{
+		"same":  1,
+		"added": "x",
+		"list":  []interface{}{1, 3},
+		"db":    map[string]interface{}{"dsn": "a", "pool": map[string]interface{}{"max": 10}},
+	}
+	want := []string{"added", "db.pool.max", "list", "removed"}
+	if got := ChangedKeys(old, new); !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedKeys() = %v, want %v", got, want)
+	}
+	if got := ChangedKeys(old, old); len(got) != 0 {
+		t.Errorf("ChangedKeys() of identical configs = %v, want none", got)
+	}
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
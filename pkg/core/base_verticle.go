@@ -175,10 +175,10 @@ func (bv *BaseVerticle) RegisterConsumer(consumer Consumer) {
 
 // Consumer creates and registers a consumer for the given address
 // Returns the consumer for further configuration
-func (bv *BaseVerticle) Consumer(address string) Consumer {
+func (bv *BaseVerticle) Consumer(address string, opts ...ConsumerOption) Consumer {
 	// Fail-fast: verticle must be started
 	failfast.NotNil(bv.eventBus, "eventBus (verticle not started - cannot create consumer)")
-	consumer := bv.eventBus.Consumer(address)
+	consumer := bv.eventBus.Consumer(address, opts...)
 	bv.RegisterConsumer(consumer)
 	return consumer
 }
@@ -199,6 +199,14 @@ func (bv *BaseVerticle) Send(address string, body interface{}) error {
 	return bv.eventBus.Send(address, body)
 }
 
+// SendWithTimeout is a convenience method to send messages with backpressure (see EventBus.SendWithTimeout)
+func (bv *BaseVerticle) SendWithTimeout(address string, body interface{}, timeout time.Duration) error {
+	if bv.eventBus == nil {
+		return &EventBusError{Code: "NOT_STARTED", Message: "verticle not started"}
+	}
+	return bv.eventBus.SendWithTimeout(address, body, timeout)
+}
+
 // EventLoop returns the event loop executor for this verticle
 // Each verticle has its own event loop for sequential event processing
 func (bv *BaseVerticle) EventLoop() concurrency.Executor {
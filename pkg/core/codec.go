@@ -0,0 +1,134 @@
+package core
+
+import "sync"
+
+// HeaderContentType carries the name of the codec used to encode a message body.
+// Consumers decode with the codec registered under that name.
+const HeaderContentType = "content-type"
+
+// CodecNameJSON is the name of the built-in JSON codec (the default).
+const CodecNameJSON = "application/json"
+
+// Codec encodes and decodes EventBus message bodies.
+//
+// Codecs must be safe for concurrent use.
+type Codec interface {
+	// Name identifies the codec and is sent as the HeaderContentType header,
+	// e.g. "application/json" or "application/msgpack".
+	Name() string
+
+	// Encode encodes v into bytes.
+	Encode(v interface{}) ([]byte, error)
+
+	// Decode decodes data into v.
+	Decode(data []byte, v interface{}) error
+}
+
+// JSONCodec returns the built-in JSON codec (backed by JSONEncode/JSONDecode).
+func JSONCodec() Codec {
+	return jsonCodec{}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                            { return CodecNameJSON }
+func (jsonCodec) Encode(v interface{}) ([]byte, error)    { return JSONEncode(v) }
+func (jsonCodec) Decode(data []byte, v interface{}) error { return JSONDecode(data, v) }
+
+// codecRegistry holds the codecs known to an EventBus and the default used for encoding.
+//
+// Thread-safety: all methods are safe for concurrent use.
+// A nil *codecRegistry behaves as a registry containing only JSON.
+type codecRegistry struct {
+	mu          sync.RWMutex
+	codecs      map[string]Codec
+	defaultName string
+}
+
+func newCodecRegistry() *codecRegistry {
+	return &codecRegistry{
+		codecs:      map[string]Codec{CodecNameJSON: JSONCodec()},
+		defaultName: CodecNameJSON,
+	}
+}
+
+// register adds or replaces a codec by name - fail-fast on invalid input
+func (r *codecRegistry) register(codec Codec) error {
+	if codec == nil {
+		return &EventBusError{Code: "INVALID_INPUT", Message: "codec cannot be nil"}
+	}
+	name := codec.Name()
+	if name == "" {
+		return &EventBusError{Code: "INVALID_INPUT", Message: "codec name cannot be empty"}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codecs[name] = codec
+	return nil
+}
+
+// setDefault selects a registered codec for encoding outgoing bodies
+func (r *codecRegistry) setDefault(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.codecs[name]; !ok {
+		return &EventBusError{Code: "UNKNOWN_CODEC", Message: "codec not registered: " + name}
+	}
+	r.defaultName = name
+	return nil
+}
+
+// lookup returns the codec for name; empty name means the default codec
+func (r *codecRegistry) lookup(name string) (Codec, error) {
+	if r == nil {
+		if name == "" || name == CodecNameJSON {
+			return JSONCodec(), nil
+		}
+		return nil, &EventBusError{Code: "UNKNOWN_CODEC", Message: "codec not registered: " + name}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if name == "" {
+		name = r.defaultName
+	}
+	codec, ok := r.codecs[name]
+	if !ok {
+		return nil, &EventBusError{Code: "UNKNOWN_CODEC", Message: "codec not registered: " + name}
+	}
+	return codec, nil
+}
+
+// encode encodes body with the default codec and returns the content type to send.
+// []byte bodies are treated as pre-encoded and passed through without a content type.
+func (r *codecRegistry) encode(body interface{}) ([]byte, string, error) {
+	// Fail-fast: validate body
+	if err := ValidateBody(body); err != nil {
+		return nil, "", err
+	}
+
+	// If already []byte, return as-is
+	if data, ok := body.([]byte); ok {
+		return data, "", nil
+	}
+
+	codec, err := r.lookup("")
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := codec.Encode(body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, codec.Name(), nil
+}
+
+// decode decodes data with the codec named by contentType (default codec when empty)
+func (r *codecRegistry) decode(contentType string, data []byte, v interface{}) error {
+	codec, err := r.lookup(contentType)
+	if err != nil {
+		return err
+	}
+	return codec.Decode(data, v)
+}
@@ -0,0 +1,74 @@
+// Package msgpack provides a MessagePack codec for the Fluxor EventBus.
+//
+// Usage:
+//
+//	eb := gocmd.EventBus()
+//	_ = eb.RegisterCodec(msgpack.New())
+//	_ = eb.SetDefaultCodec(msgpack.Name)
+package msgpack
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Name is the codec name carried in the core.HeaderContentType header.
+const Name = "application/msgpack"
+
+// codec implements core.Codec using MessagePack.
+// Struct fields use `msgpack` tags, falling back to `json` tags.
+type codec struct{}
+
+// New creates a MessagePack codec.
+func New() core.Codec {
+	return codec{}
+}
+
+func (codec) Name() string {
+	return Name
+}
+
+func (codec) Encode(v interface{}) ([]byte, error) {
+	// Fail-fast: validate input
+	if v == nil {
+		return nil, &core.EventBusError{Code: "INVALID_INPUT", Message: "cannot encode nil value"}
+	}
+	data, err := marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("msgpack encode failed: %w", err)
+	}
+	return data, nil
+}
+
+func (codec) Decode(data []byte, v interface{}) error {
+	// Fail-fast: validate inputs
+	if len(data) == 0 {
+		return &core.EventBusError{Code: "INVALID_INPUT", Message: "cannot decode empty data"}
+	}
+	if v == nil {
+		return &core.EventBusError{Code: "INVALID_INPUT", Message: "cannot decode into nil value"}
+	}
+	if err := unmarshal(data, v); err != nil {
+		return fmt.Errorf("msgpack decode failed: %w", err)
+	}
+	return nil
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
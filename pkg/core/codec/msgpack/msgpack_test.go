@@ -0,0 +1,105 @@
+package msgpack
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+type order struct {
+	ID     string   `json:"id"`
+	Amount float64  `json:"amount"`
+	Items  []string `json:"items"`
+}
+
+func TestCodec_RoundTripStruct(t *testing.T) {
+	c := New()
+	in := order{ID: "o-1", Amount: 12.5, Items: []string{"a", "b"}}
+
+	data, err := c.Encode(in)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var out order
+	if err := c.Decode(data, &out); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round-trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestCodec_RoundTripMap(t *testing.T) {
+	c := New()
+	in := map[string]interface{}{"name": "fluxor", "tags": []interface{}{"x", "y"}}
+
+	data, err := c.Encode(in)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var out map[string]interface{}
+	if err := c.Decode(data, &out); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round-trip = %#v, want %#v", out, in)
+	}
+}
+
+func TestCodec_FailFast(t *testing.T) {
+	c := New()
+	if _, err := c.Encode(nil); err == nil {
+		t.Error("Encode(nil) should fail")
+	}
+	if err := c.Decode(nil, &order{}); err == nil {
+		t.Error("Decode() with empty data should fail")
+	}
+	if err := c.Decode([]byte{0x80}, nil); err == nil {
+		t.Error("Decode() into nil should fail")
+	}
+}
+
+func TestCodec_EventBus(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	if err := eb.RegisterCodec(New()); err != nil {
+		t.Fatalf("RegisterCodec() error = %v", err)
+	}
+	if err := eb.SetDefaultCodec(Name); err != nil {
+		t.Fatalf("SetDefaultCodec() error = %v", err)
+	}
+
+	received := make(chan core.Message, 1)
+	eb.Consumer("test.msgpack").Handler(func(ctx core.FluxorContext, msg core.Message) error {
+		received <- msg
+		return nil
+	})
+
+	in := order{ID: "o-2", Amount: 3, Items: []string{"z"}}
+	if err := eb.Send("test.msgpack", in); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if ct := msg.Headers()[core.HeaderContentType]; ct != Name {
+			t.Errorf("%s = %q, want %q", core.HeaderContentType, ct, Name)
+		}
+		var out order
+		if err := msg.DecodeBody(&out); err != nil {
+			t.Fatalf("DecodeBody() error = %v", err)
+		}
+		if !reflect.DeepEqual(in, out) {
+			t.Errorf("DecodeBody() = %+v, want %+v", out, in)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+}
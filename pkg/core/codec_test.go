@@ -0,0 +1,117 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// upperCodec is a toy codec that stores strings upper-cased, used to verify
+// that consumers decode with the codec named in the content-type header.
+type upperCodec struct{}
+
+func (upperCodec) Name() string { return "text/upper" }
+
+func (upperCodec) Encode(v interface{}) ([]byte, error) {
+	s, _ := v.(string)
+	return []byte(strings.ToUpper(s)), nil
+}
+
+func (upperCodec) Decode(data []byte, v interface{}) error {
+	p, ok := v.(*string)
+	if !ok {
+		return &EventBusError{Code: "INVALID_INPUT", Message: "upperCodec decodes into *string"}
+	}
+	*p = string(data)
+	return nil
+}
+
+func TestCodec_DefaultIsJSON(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	received := make(chan Message, 1)
+	eb.Consumer("test.codec.json").Handler(func(ctx FluxorContext, msg Message) error {
+		received <- msg
+		return nil
+	})
+
+	if err := eb.Send("test.codec.json", map[string]interface{}{"n": 1}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if ct := msg.Headers()[HeaderContentType]; ct != CodecNameJSON {
+			t.Errorf("%s = %q, want %q", HeaderContentType, ct, CodecNameJSON)
+		}
+		var body map[string]interface{}
+		if err := msg.DecodeBody(&body); err != nil {
+			t.Fatalf("DecodeBody() error = %v", err)
+		}
+		if body["n"] != float64(1) {
+			t.Errorf("body = %v, want n=1", body)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+}
+
+func TestCodec_RegisterAndSetDefault(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	// Fail-fast: invalid registrations
+	if err := eb.RegisterCodec(nil); err == nil {
+		t.Error("RegisterCodec(nil) should fail")
+	}
+	if err := eb.SetDefaultCodec("text/upper"); err == nil {
+		t.Error("SetDefaultCodec() with unregistered codec should fail")
+	}
+
+	if err := eb.RegisterCodec(upperCodec{}); err != nil {
+		t.Fatalf("RegisterCodec() error = %v", err)
+	}
+	if err := eb.SetDefaultCodec("text/upper"); err != nil {
+		t.Fatalf("SetDefaultCodec() error = %v", err)
+	}
+
+	received := make(chan Message, 1)
+	eb.Consumer("test.codec.upper").Handler(func(ctx FluxorContext, msg Message) error {
+		received <- msg
+		return nil
+	})
+
+	if err := eb.Send("test.codec.upper", "hello"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	select {
+	case msg := <-received:
+		if ct := msg.Headers()[HeaderContentType]; ct != "text/upper" {
+			t.Errorf("%s = %q, want %q", HeaderContentType, ct, "text/upper")
+		}
+		var body string
+		if err := msg.DecodeBody(&body); err != nil {
+			t.Fatalf("DecodeBody() error = %v", err)
+		}
+		if body != "HELLO" {
+			t.Errorf("body = %q, want %q", body, "HELLO")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message not received")
+	}
+}
+
+func TestCodec_UnknownContentType(t *testing.T) {
+	msg := newMessage([]byte("x"), map[string]string{HeaderContentType: "application/unknown"}, "", nil)
+
+	var v interface{}
+	err := msg.DecodeBody(&v)
+	if ce, ok := err.(*EventBusError); !ok || ce.Code != "UNKNOWN_CODEC" {
+		t.Errorf("DecodeBody() error = %v, want UNKNOWN_CODEC", err)
+	}
+}
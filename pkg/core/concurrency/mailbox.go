@@ -24,18 +24,24 @@ type Mailbox interface {
 	// Returns ErrMailboxClosed if mailbox is closed
 	Send(msg interface{}) error
 
+	// SendContext sends a message, blocking until there is space or ctx is done
+	// Returns ctx.Err() if ctx is done first
+	// Returns ErrMailboxClosed if mailbox is closed
+	SendContext(ctx context.Context, msg interface{}) error
+
 	// Receive receives a message from the mailbox
 	// Blocks until a message is available or ctx is cancelled
-	// Returns ErrMailboxClosed if mailbox is closed
+	// Returns ErrMailboxClosed once the mailbox is closed and drained
 	Receive(ctx context.Context) (interface{}, error)
 
 	// TryReceive attempts to receive a message without blocking
 	// Returns (msg, true) if message available, (nil, false) if empty
-	// Returns ErrMailboxClosed if mailbox is closed
+	// Returns ErrMailboxClosed once the mailbox is closed and drained
 	TryReceive() (interface{}, bool, error)
 
 	// Close closes the mailbox
-	// After closing, Send/Receive operations will return ErrMailboxClosed
+	// After closing, Send returns ErrMailboxClosed; messages already queued
+	// can still be received
 	Close()
 
 	// Capacity returns the maximum capacity of the mailbox
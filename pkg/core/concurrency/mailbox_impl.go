@@ -2,6 +2,7 @@ package concurrency
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 )
 
@@ -11,6 +12,11 @@ type boundedMailbox struct {
 	ch       chan interface{} // Hidden: internal channel
 	closed   int32            // Atomic flag for thread-safe close check
 	capacity int
+
+	// Senders hold mu for reading, so Close never closes ch under a send;
+	// done wakes the blocked ones first
+	mu   sync.RWMutex
+	done chan struct{}
 }
 
 // NewBoundedMailbox creates a new bounded mailbox
@@ -24,12 +30,15 @@ func NewBoundedMailbox(capacity int) Mailbox {
 	return &boundedMailbox{
 		ch:       make(chan interface{}, capacity), // Hidden: channel creation
 		capacity: capacity,
+		done:     make(chan struct{}),
 	}
 }
 
 // Send implements Mailbox interface
 // Hides channel send and select statements
 func (mb *boundedMailbox) Send(msg interface{}) error {
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
 	if atomic.LoadInt32(&mb.closed) == 1 {
 		return ErrMailboxClosed
 	}
@@ -44,6 +53,29 @@ func (mb *boundedMailbox) Send(msg interface{}) error {
 	}
 }
 
+// SendContext implements Mailbox interface
+// Hides blocking channel send and select statements
+func (mb *boundedMailbox) SendContext(ctx context.Context, msg interface{}) error {
+	// Fail-fast: context cannot be nil
+	if ctx == nil {
+		failFastIf(true, "context cannot be nil")
+	}
+	mb.mu.RLock()
+	defer mb.mu.RUnlock()
+	if atomic.LoadInt32(&mb.closed) == 1 {
+		return ErrMailboxClosed
+	}
+
+	select {
+	case mb.ch <- msg: // Hidden: channel send
+		return nil
+	case <-mb.done:
+		return ErrMailboxClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // Receive implements Mailbox interface
 // Hides channel receive and select statements
 func (mb *boundedMailbox) Receive(ctx context.Context) (interface{}, error) {
@@ -51,11 +83,9 @@ func (mb *boundedMailbox) Receive(ctx context.Context) (interface{}, error) {
 	if ctx == nil {
 		failFastIf(true, "context cannot be nil")
 	}
-	if atomic.LoadInt32(&mb.closed) == 1 {
-		return nil, ErrMailboxClosed
-	}
 
-	// Receive with context cancellation
+	// Receive with context cancellation; a closed mailbox still hands out
+	// its queued messages, then reports ErrMailboxClosed
 	select {
 	case msg, ok := <-mb.ch: // Hidden: channel receive
 		if !ok {
@@ -70,11 +100,7 @@ func (mb *boundedMailbox) Receive(ctx context.Context) (interface{}, error) {
 // TryReceive implements Mailbox interface
 // Hides channel receive and select statements
 func (mb *boundedMailbox) TryReceive() (interface{}, bool, error) {
-	if atomic.LoadInt32(&mb.closed) == 1 {
-		return nil, false, ErrMailboxClosed
-	}
-
-	// Try to receive (non-blocking)
+	// Try to receive (non-blocking); queued messages outlive Close
 	select {
 	case msg, ok := <-mb.ch: // Hidden: channel receive
 		if !ok {
@@ -91,7 +117,10 @@ func (mb *boundedMailbox) TryReceive() (interface{}, bool, error) {
 // Hides channel close operation
 func (mb *boundedMailbox) Close() {
 	if atomic.CompareAndSwapInt32(&mb.closed, 0, 1) {
+		close(mb.done)
+		mb.mu.Lock()
 		close(mb.ch) // Hidden: channel close
+		mb.mu.Unlock()
 	}
 }
 
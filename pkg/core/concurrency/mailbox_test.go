@@ -3,6 +3,7 @@ package concurrency
 import (
 	"context"
 	"testing"
+	"time"
 )
 
 func TestNewBoundedMailbox(t *testing.T) {
@@ -36,6 +37,32 @@ func TestMailbox_Send(t *testing.T) {
 	}
 }
 
+func TestMailbox_SendContext(t *testing.T) {
+	mailbox := NewBoundedMailbox(1)
+	mailbox.Send("message1")
+
+	// Full mailbox: blocks until ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := mailbox.SendContext(ctx, "message2"); err != context.DeadlineExceeded {
+		t.Errorf("SendContext() to full mailbox error = %v, want DeadlineExceeded", err)
+	}
+
+	// Space frees up while blocked: send succeeds
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		mailbox.TryReceive()
+	}()
+	if err := mailbox.SendContext(context.Background(), "message2"); err != nil {
+		t.Errorf("SendContext() after space freed error = %v", err)
+	}
+
+	mailbox.Close()
+	if err := mailbox.SendContext(context.Background(), "message3"); err != ErrMailboxClosed {
+		t.Errorf("SendContext() to closed mailbox error = %v, want ErrMailboxClosed", err)
+	}
+}
+
 func TestMailbox_Receive(t *testing.T) {
 	mailbox := NewBoundedMailbox(10)
 	ctx := context.Background()
@@ -106,6 +133,49 @@ func TestMailbox_Close(t *testing.T) {
 	}
 }
 
+func TestMailbox_CloseRacingSends(t *testing.T) {
+	// Sends racing Close report ErrMailboxClosed rather than panicking
+	for i := 0; i < 50; i++ {
+		mailbox := NewBoundedMailbox(1)
+		_ = mailbox.Send("fill")
+		done := make(chan error, 2)
+		go func() { done <- mailbox.Send("a") }()
+		go func() { done <- mailbox.SendContext(context.Background(), "b") }()
+		mailbox.Close()
+		for j := 0; j < 2; j++ {
+			select {
+			case err := <-done:
+				if err != ErrMailboxClosed && err != ErrMailboxFull {
+					t.Fatalf("send racing Close() error = %v, want ErrMailboxClosed or ErrMailboxFull", err)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("SendContext() still blocked after Close()")
+			}
+		}
+	}
+}
+
+func TestMailbox_CloseDrainsQueuedMessages(t *testing.T) {
+	mailbox := NewBoundedMailbox(10)
+	_ = mailbox.Send("a")
+	_ = mailbox.Send("b")
+	mailbox.Close()
+
+	ctx := context.Background()
+	if msg, err := mailbox.Receive(ctx); err != nil || msg != "a" {
+		t.Errorf("Receive() = %v, %v; want a", msg, err)
+	}
+	if msg, ok, err := mailbox.TryReceive(); err != nil || !ok || msg != "b" {
+		t.Errorf("TryReceive() = %v, %v, %v; want b", msg, ok, err)
+	}
+	if _, err := mailbox.Receive(ctx); err != ErrMailboxClosed {
+		t.Errorf("Receive() on a drained mailbox error = %v, want ErrMailboxClosed", err)
+	}
+	if _, _, err := mailbox.TryReceive(); err != ErrMailboxClosed {
+		t.Errorf("TryReceive() on a drained mailbox error = %v, want ErrMailboxClosed", err)
+	}
+}
+
 func TestMailbox_Size(t *testing.T) {
 	mailbox := NewBoundedMailbox(10)
 
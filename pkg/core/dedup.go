@@ -0,0 +1,116 @@
+package core
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core/failfast"
+)
+
+// HeaderMessageID carries the message ID used for deduplication (see WithDedup).
+// The EventBus generates one on Publish/Send/Request; redelivered messages keep it.
+const HeaderMessageID = "x-message-id"
+
+// DefaultDedupMaxEntries bounds the number of IDs a dedup cache remembers.
+// When full, the oldest IDs are forgotten before their window ends.
+const DefaultDedupMaxEntries = 10000
+
+// WithDedup makes the consumer skip messages whose HeaderMessageID was already
+// seen within window. Messages without an ID are always delivered.
+// Use Consumer.Duplicates() to read the number of skipped messages.
+// Fail-fast: panics if window is not positive.
+func WithDedup(window time.Duration) ConsumerOption {
+	if window <= 0 {
+		failfast.Err(&EventBusError{Code: "INVALID_INPUT", Message: "dedup window must be positive"})
+	}
+	return func(o *consumerOptions) {
+		o.dedupWindow = window
+	}
+}
+
+// dedupEntry records when an ID was first seen
+type dedupEntry struct {
+	id string
+	at time.Time
+}
+
+// dedupCache remembers message IDs for a time window, bounded by maxEntries.
+//
+// Entries are kept in insertion order, which is also expiry order, so
+// eviction only ever looks at the front of the queue.
+// A nil *dedupCache never reports duplicates.
+type dedupCache struct {
+	mu         sync.Mutex
+	window     time.Duration
+	maxEntries int
+	seen       map[string]time.Time
+	queue      []dedupEntry
+	head       int
+	duplicates atomic.Uint64
+}
+
+func newDedupCache(window time.Duration, maxEntries int) *dedupCache {
+	if window <= 0 {
+		return nil
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultDedupMaxEntries
+	}
+	return &dedupCache{
+		window:     window,
+		maxEntries: maxEntries,
+		seen:       make(map[string]time.Time),
+	}
+}
+
+// isDuplicate records id and reports whether it was already seen within the window
+func (d *dedupCache) isDuplicate(id string) bool {
+	if d == nil || id == "" {
+		return false
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.evict(now)
+	if at, ok := d.seen[id]; ok && now.Sub(at) < d.window {
+		d.duplicates.Add(1)
+		return true
+	}
+
+	d.seen[id] = now
+	d.queue = append(d.queue, dedupEntry{id: id, at: now})
+	return false
+}
+
+// evict drops expired entries and enforces maxEntries - caller holds d.mu
+func (d *dedupCache) evict(now time.Time) {
+	for d.head < len(d.queue) {
+		e := d.queue[d.head]
+		if now.Sub(e.at) < d.window && len(d.seen) < d.maxEntries {
+			break
+		}
+		// Only forget the ID if it was not re-recorded after this entry
+		if at, ok := d.seen[e.id]; ok && at.Equal(e.at) {
+			delete(d.seen, e.id)
+		}
+		d.queue[d.head] = dedupEntry{}
+		d.head++
+	}
+
+	// Compact once the consumed prefix dominates the backing array
+	if d.head > 0 && d.head >= len(d.queue)/2 {
+		d.queue = append(d.queue[:0], d.queue[d.head:]...)
+		d.head = 0
+	}
+}
+
+// count returns the number of duplicates skipped so far
+func (d *dedupCache) count() uint64 {
+	if d == nil {
+		return 0
+	}
+	return d.duplicates.Load()
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDedupCache_Window(t *testing.T) {
+	d := newDedupCache(50*time.Millisecond, 0)
+
+	if d.isDuplicate("a") {
+		t.Fatal("first a reported as duplicate")
+	}
+	if !d.isDuplicate("a") {
+		t.Error("second a within window not reported as duplicate")
+	}
+	if d.isDuplicate("b") {
+		t.Error("distinct ID b reported as duplicate")
+	}
+	if d.isDuplicate("") {
+		t.Error("empty ID must never be a duplicate")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if d.isDuplicate("a") {
+		t.Error("a after window expired reported as duplicate")
+	}
+	if got := d.count(); got != 1 {
+		t.Errorf("count() = %d, want 1", got)
+	}
+}
+
+func TestDedupCache_Bounded(t *testing.T) {
+	d := newDedupCache(time.Hour, 3)
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		d.isDuplicate(id)
+	}
+	if got := len(d.seen); got > 3 {
+		t.Errorf("cache holds %d IDs, want at most 3", got)
+	}
+	// Oldest IDs were forgotten to make room; the newest are still remembered
+	if !d.isDuplicate("e") {
+		t.Error("newest ID e should still be remembered")
+	}
+	if d.isDuplicate("a") {
+		t.Error("oldest ID a should have been evicted")
+	}
+}
+
+func TestDedupCache_Nil(t *testing.T) {
+	var d *dedupCache
+	if d.isDuplicate("a") || d.isDuplicate("a") {
+		t.Error("nil cache must not report duplicates")
+	}
+	if newDedupCache(0, 0) != nil {
+		t.Error("zero window should disable dedup")
+	}
+}
+
+func TestConsumer_WithDedup(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	var handled int32
+	c := eb.Consumer("test.dedup", WithDedup(time.Minute))
+	c.Handler(func(ctx FluxorContext, msg Message) error {
+		atomic.AddInt32(&handled, 1)
+		return nil
+	})
+
+	// Simulate redelivery: the same message ID arrives twice
+	mailbox := c.(*consumer).mailbox
+	for _, id := range []string{"m-1", "m-1", "m-2"} {
+		if err := mailbox.Send(newMessage([]byte(`{}`), map[string]string{HeaderMessageID: id}, "", eb)); err != nil {
+			t.Fatalf("mailbox.Send() error = %v", err)
+		}
+	}
+
+	// Regular sends get distinct generated IDs and all pass
+	for i := 0; i < 3; i++ {
+		if err := eb.Send("test.dedup", "same body"); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) && atomic.LoadInt32(&handled) < 5 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if got := atomic.LoadInt32(&handled); got != 5 {
+		t.Errorf("handled = %d, want 5 (m-1, m-2 and 3 sends)", got)
+	}
+	if got := c.Duplicates(); got != 1 {
+		t.Errorf("Duplicates() = %d, want 1", got)
+	}
+}
+
+func TestEventBus_MessageIDHeader(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	ids := make(chan string, 2)
+	eb.Consumer("test.ids").Handler(func(ctx FluxorContext, msg Message) error {
+		ids <- msg.Headers()[HeaderMessageID]
+		return nil
+	})
+
+	_ = eb.Publish("test.ids", "a")
+	_ = eb.Publish("test.ids", "a")
+
+	first, second := <-ids, <-ids
+	if first == "" || second == "" {
+		t.Fatalf("message IDs = %q, %q, want generated IDs", first, second)
+	}
+	if first == second {
+		t.Errorf("message IDs should be distinct, both %q", first)
+	}
+}
@@ -2,8 +2,11 @@ package core
 
 import (
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core/failfast"
 )
 
 // Message represents a message on the event bus
@@ -20,7 +23,8 @@ type Message interface {
 	// Reply sends a reply to this message
 	Reply(body interface{}) error
 
-	// DecodeBody decodes the message body into v
+	// DecodeBody decodes the message body into v using the codec named by
+	// the HeaderContentType header (the bus default codec when absent)
 	DecodeBody(v interface{}) error
 
 	// Fail indicates that processing failed
@@ -33,18 +37,24 @@ type message struct {
 	headers      map[string]string
 	replyAddress string
 	eventBus     EventBus
+	codecs       *codecRegistry // resolves HeaderContentType in DecodeBody (nil = JSON only)
 	mu           sync.RWMutex
 }
 
-func newMessage(body interface{}, headers map[string]string, replyAddress string, eventBus EventBus) Message {
+func newMessage(body interface{}, headers map[string]string, replyAddress string, bus EventBus) Message {
 	if headers == nil {
 		headers = make(map[string]string)
 	}
+	var codecs *codecRegistry
+	if eb, ok := bus.(*eventBus); ok {
+		codecs = eb.codecs
+	}
 	return &message{
 		body:         body,
 		headers:      headers,
 		replyAddress: replyAddress,
-		eventBus:     eventBus,
+		eventBus:     bus,
+		codecs:       codecs,
 	}
 }
 
@@ -82,21 +92,69 @@ func (m *message) DecodeBody(v interface{}) error {
 	defer m.mu.RUnlock()
 
 	if data, ok := m.body.([]byte); ok {
-		return JSONDecode(data, v)
+		return m.codecs.decode(m.headers[HeaderContentType], data, v)
 	}
 	return fmt.Errorf("body is not []byte, got %T", m.body)
 }
 
 func (m *message) Fail(failureCode int, message string) error {
-	// In a real implementation, this would send a failure response
-	return m.Reply(map[string]interface{}{
+	if m.replyAddress == "" {
+		return ErrNoReplyAddress
+	}
+	body := failureBody(failureCode, message)
+	// Mark the reply so Request returns a *ReplyError instead of a Message
+	if eb, ok := m.eventBus.(*eventBus); ok {
+		return eb.send(m.replyAddress, body, 0, map[string]string{HeaderFailure: strconv.Itoa(failureCode)})
+	}
+	return m.eventBus.Send(m.replyAddress, body)
+}
+
+// HeaderFailure marks a reply sent with Message.Fail; its value is the failure code.
+// Request turns such replies into a *ReplyError, so plain map replies are never
+// mistaken for failures.
+const HeaderFailure = "x-failure"
+
+// ReplyError is returned by Request when the handler answered with Message.Fail.
+type ReplyError struct {
+	FailureCode int
+	Message     string
+}
+
+func (e *ReplyError) Error() string {
+	return fmt.Sprintf("request failed (code %d): %s", e.FailureCode, e.Message)
+}
+
+func failureBody(failureCode int, message string) map[string]interface{} {
+	return map[string]interface{}{
 		"failureCode": failureCode,
 		"message":     message,
-	})
+	}
+}
+
+// replyFailure returns a *ReplyError if headers mark a failure reply, nil otherwise.
+func replyFailure(codecs *codecRegistry, headers map[string]string, body interface{}) error {
+	code, ok := headers[HeaderFailure]
+	if !ok {
+		return nil
+	}
+	replyErr := &ReplyError{}
+	replyErr.FailureCode, _ = strconv.Atoi(code)
+
+	var decoded struct {
+		Message string `json:"message"`
+	}
+	if data, ok := body.([]byte); ok {
+		if err := codecs.decode(headers[HeaderContentType], data, &decoded); err == nil {
+			replyErr.Message = decoded.Message
+		} else {
+			replyErr.Message = string(data)
+		}
+	}
+	return replyErr
 }
 
 // EventBus provides publish-subscribe and point-to-point messaging.
-// Default data format is JSON.
+// Default data format is JSON; other formats can be plugged in via RegisterCodec.
 //
 // Thread-safety: All methods are safe for concurrent use.
 //
@@ -109,18 +167,28 @@ func (m *message) Fail(failureCode int, message string) error {
 //   - Runtime errors in Publish/Send/Request are expected (network issues, etc.)
 type EventBus interface {
 	// Publish publishes a message to all handlers registered for the address.
-	// Body is automatically JSON encoded if not already []byte.
+	// Body is encoded with the default codec (JSON) if not already []byte.
 	// Returns error if address is invalid or encoding fails.
 	Publish(address string, body interface{}) error
 
 	// Send sends a point-to-point message to one handler.
-	// Body is automatically JSON encoded if not already []byte.
+	// When several consumers share the address, deliveries rotate round-robin;
+	// a consumer with a full mailbox is skipped in favour of the next one.
+	// Body is encoded with the default codec (JSON) if not already []byte.
 	// Returns error if address is invalid, no handlers registered, or encoding fails.
 	Send(address string, body interface{}) error
 
+	// SendWithTimeout is Send with soft backpressure: when every consumer
+	// mailbox is full it waits up to timeout for space instead of failing at once.
+	// Returns ErrTimeout if no space freed up in time.
+	// Clustered buses have no local mailbox to wait on and behave like Send.
+	SendWithTimeout(address string, body interface{}, timeout time.Duration) error
+
 	// Request sends a message and expects a reply within timeout.
-	// Body is automatically JSON encoded if not already []byte.
+	// Body is encoded with the default codec (JSON) if not already []byte.
 	// Returns error if address is invalid, no handlers, timeout exceeded, or encoding fails.
+	// If the handler replies with Message.Fail, the error is a *ReplyError
+	// carrying the failure code and message.
 	Request(address string, body interface{}, timeout time.Duration) (Message, error)
 
 	// Consumer creates a consumer for the given address.
@@ -135,7 +203,21 @@ type EventBus interface {
 	//       return nil
 	//   })
 	//   defer consumer.Unregister()
-	Consumer(address string) Consumer
+	//
+	// Options (e.g. WithMailboxSize) are applied before the consumer is created.
+	Consumer(address string, opts ...ConsumerOption) Consumer
+
+	// RegisterCodec registers (or replaces) a body codec under codec.Name().
+	// Returns error if codec is nil or its name is empty.
+	RegisterCodec(codec Codec) error
+
+	// SetDefaultCodec selects the registered codec used to encode outgoing bodies.
+	// JSON is the default. Returns error if no codec is registered under name.
+	SetDefaultCodec(name string) error
+
+	// Metrics returns a snapshot of the bus's delivery counters
+	// (published, sent, requests, replies, dropped, encode and handler errors).
+	Metrics() EventBusMetrics
 
 	// Close closes the event bus and releases all resources.
 	// After Close, all other methods will fail.
@@ -147,16 +229,79 @@ type Consumer interface {
 	// Handler sets the message handler
 	Handler(handler MessageHandler) Consumer
 
-	// Completion returns a channel that will be closed when the consumer is closed
+	// Completion returns a channel that is closed once the consumer is
+	// unregistered and the messages it had queued have been handled
 	Completion() <-chan struct{}
 
-	// Unregister unregisters the consumer
+	// Pending returns the number of messages queued for the consumer and not
+	// yet handled
+	Pending() int
+
+	// Unregister unregisters the consumer and cancels the context passed to its
+	// handlers, so in-flight handlers can stop early. Messages already queued
+	// are still handled (with the cancelled context) before Completion closes.
 	Unregister() error
+
+	// Duplicates returns the number of messages skipped by WithDedup (0 when disabled)
+	Duplicates() uint64
 }
 
-// MessageHandler handles incoming messages
+// MessageHandler handles incoming messages.
+// ctx.Context() is cancelled when the consumer is unregistered or the
+// EventBus closes; long-running handlers should watch ctx.Context().Done().
 type MessageHandler func(ctx FluxorContext, msg Message) error
 
+// DefaultConsumerMailboxSize is the consumer mailbox capacity when WithMailboxSize is not given
+const DefaultConsumerMailboxSize = 100
+
+// ConsumerOption configures a consumer at creation time (see EventBus.Consumer)
+type ConsumerOption func(*consumerOptions)
+
+// consumerOptions holds the resolved options of a consumer
+type consumerOptions struct {
+	mailboxSize int
+	dedupWindow time.Duration // zero disables dedup
+	partitions  int           // zero disables per-key partitions
+}
+
+func newConsumerOptions(opts []ConsumerOption) consumerOptions {
+	o := consumerOptions{mailboxSize: DefaultConsumerMailboxSize}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// WithMailboxSize sets the capacity of the consumer mailbox.
+// A full mailbox makes Send/Request fail with ErrTimeout and Publish drop the message.
+// Fail-fast: panics if n is not positive.
+// Only the in-memory EventBus buffers per consumer; clustered buses ignore it.
+func WithMailboxSize(n int) ConsumerOption {
+	if n <= 0 {
+		failfast.Err(&EventBusError{Code: "INVALID_INPUT", Message: "mailbox size must be positive"})
+	}
+	return func(o *consumerOptions) {
+		o.mailboxSize = n
+	}
+}
+
+// Dead-letter headers and reason codes (see EventBusOptions.DeadLetterAddress)
+const (
+	// HeaderDeadLetterReason carries the reason code of a dead-lettered message
+	HeaderDeadLetterReason = "x-dead-letter-reason"
+
+	// HeaderOriginalAddress carries the address a dead-lettered message was sent to
+	HeaderOriginalAddress = "x-original-address"
+
+	// DeadLetterReasonNoHandlers: no consumer was registered for the address
+	DeadLetterReasonNoHandlers = "NO_HANDLERS"
+
+	// DeadLetterReasonMailboxFull: the consumer mailbox was full (backpressure)
+	DeadLetterReasonMailboxFull = "MAILBOX_FULL"
+)
+
 // Errors
 var (
 	ErrNoReplyAddress = &EventBusError{Code: "NO_REPLY_ADDRESS", Message: "No reply address available"}
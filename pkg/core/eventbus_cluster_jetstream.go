@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fluxorio/fluxor/pkg/core/concurrency"
@@ -136,8 +137,11 @@ func NewClusterEventBusJetStream(ctx context.Context, gocmd GoCMD, cfg ClusterJe
 		requestTimeout: reqTimeout,
 		ackWait:        ackWait,
 		maxAckPending:  maxAckPending,
+		codecs:         newCodecRegistry(),
 		executor:       concurrency.NewExecutor(ctx, execCfg),
 		logger:         NewDefaultLogger(),
+		stats:          &eventBusStats{},
+		metrics:        newEventBusMetrics(metricsFor(gocmd)),
 	}
 
 	// Ensure streams exist (idempotent).
@@ -164,8 +168,12 @@ type clusterJSEventBus struct {
 	ackWait       time.Duration
 	maxAckPending int
 
+	codecs *codecRegistry
+
 	executor concurrency.Executor
 	logger   Logger
+	metrics  *eventBusMetrics
+	stats    *eventBusStats
 
 	mu        sync.Mutex
 	consumers []*clusterJSConsumer
@@ -214,22 +222,27 @@ func (eb *clusterJSEventBus) Publish(address string, body interface{}) error {
 		return err
 	}
 
-	data, err := encodeBody(body)
+	data, header, err := encodeNATSBody(eb.codecs, body)
 	if err != nil {
+		atomic.AddInt64(&eb.stats.encodeErrors, 1)
 		return err
 	}
 
 	msg := &nats.Msg{
 		Subject: eb.subjectPub(address),
 		Data:    data,
-		Header:  nats.Header{},
+		Header:  header,
 	}
 	if rid := GetRequestID(eb.ctx); rid != "" {
 		msg.Header.Set("X-Request-ID", rid)
 	}
 
-	_, err = eb.js.PublishMsg(msg)
-	return err
+	if _, err := eb.js.PublishMsg(msg); err != nil {
+		return err
+	}
+	atomic.AddInt64(&eb.stats.published, 1)
+	eb.metrics.message(address, "publish")
+	return nil
 }
 
 func (eb *clusterJSEventBus) Send(address string, body interface{}) error {
@@ -240,22 +253,35 @@ func (eb *clusterJSEventBus) Send(address string, body interface{}) error {
 		return err
 	}
 
-	data, err := encodeBody(body)
+	data, header, err := encodeNATSBody(eb.codecs, body)
 	if err != nil {
+		atomic.AddInt64(&eb.stats.encodeErrors, 1)
 		return err
 	}
 
 	msg := &nats.Msg{
 		Subject: eb.subjectSend(address),
 		Data:    data,
-		Header:  nats.Header{},
+		Header:  header,
 	}
 	if rid := GetRequestID(eb.ctx); rid != "" {
 		msg.Header.Set("X-Request-ID", rid)
 	}
 
-	_, err = eb.js.PublishMsg(msg)
-	return err
+	if _, err := eb.js.PublishMsg(msg); err != nil {
+		return err
+	}
+	atomic.AddInt64(&eb.stats.sent, 1)
+	eb.metrics.message(address, "send")
+	return nil
+}
+
+// SendWithTimeout behaves like Send: the publish ack is the only backpressure signal.
+func (eb *clusterJSEventBus) SendWithTimeout(address string, body interface{}, timeout time.Duration) error {
+	if err := ValidateTimeout(timeout); err != nil {
+		return err
+	}
+	return eb.Send(address, body)
 }
 
 func (eb *clusterJSEventBus) Request(address string, body interface{}, timeout time.Duration) (Message, error) {
@@ -270,8 +296,9 @@ func (eb *clusterJSEventBus) Request(address string, body interface{}, timeout t
 		return nil, err
 	}
 
-	data, err := encodeBody(body)
+	data, header, err := encodeNATSBody(eb.codecs, body)
 	if err != nil {
+		atomic.AddInt64(&eb.stats.encodeErrors, 1)
 		return nil, err
 	}
 	if timeout <= 0 {
@@ -281,7 +308,7 @@ func (eb *clusterJSEventBus) Request(address string, body interface{}, timeout t
 	msg := &nats.Msg{
 		Subject: eb.subjectReq(address),
 		Data:    data,
-		Header:  nats.Header{},
+		Header:  header,
 	}
 	if rid := GetRequestID(eb.ctx); rid != "" {
 		msg.Header.Set("X-Request-ID", rid)
@@ -291,6 +318,8 @@ func (eb *clusterJSEventBus) Request(address string, body interface{}, timeout t
 	if err != nil {
 		return nil, err
 	}
+	atomic.AddInt64(&eb.stats.requests, 1)
+	eb.metrics.message(address, "request")
 
 	h := make(map[string]string)
 	for k, v := range resp.Header {
@@ -298,6 +327,9 @@ func (eb *clusterJSEventBus) Request(address string, body interface{}, timeout t
 			h[k] = v[0]
 		}
 	}
+	if err := replyFailure(eb.codecs, h, resp.Data); err != nil {
+		return nil, err
+	}
 
 	return &clusterNATSMessage{
 		body:         resp.Data,
@@ -309,19 +341,34 @@ func (eb *clusterJSEventBus) Request(address string, body interface{}, timeout t
 			nc:             eb.nc,
 			prefix:         eb.prefix,
 			requestTimeout: eb.requestTimeout,
+			codecs:         eb.codecs,
 			executor:       eb.executor,
 			logger:         eb.logger,
+			metrics:        eb.metrics,
+			stats:          eb.stats,
 		},
 	}, nil
 }
 
-func (eb *clusterJSEventBus) Consumer(address string) Consumer {
+func (eb *clusterJSEventBus) Consumer(address string, opts ...ConsumerOption) Consumer {
 	// Fail-fast: keep contract consistent with in-memory EventBus.
 	// Invalid address is a programmer error and should be caught in dev.
 	if err := ValidateAddress(address); err != nil {
 		failfast.Err(err)
 	}
-	return newClusterJSConsumer(address, eb)
+	return newClusterJSConsumer(address, eb, newConsumerOptions(opts))
+}
+
+func (eb *clusterJSEventBus) RegisterCodec(codec Codec) error {
+	return eb.codecs.register(codec)
+}
+
+func (eb *clusterJSEventBus) SetDefaultCodec(name string) error {
+	return eb.codecs.setDefault(name)
+}
+
+func (eb *clusterJSEventBus) Metrics() EventBusMetrics {
+	return eb.stats.snapshot()
 }
 
 func (eb *clusterJSEventBus) Close() error {
@@ -361,14 +408,21 @@ type clusterJSConsumer struct {
 	subs       []*nats.Subscription
 	completion chan struct{}
 	registered bool
+	dedup      *dedupCache // nil unless WithDedup
+
+	// ctx is passed to handlers and cancelled by Unregister
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
-func newClusterJSConsumer(address string, eb *clusterJSEventBus) *clusterJSConsumer {
+func newClusterJSConsumer(address string, eb *clusterJSEventBus, opts consumerOptions) *clusterJSConsumer {
 	c := &clusterJSConsumer{
 		address:    address,
 		eb:         eb,
 		completion: make(chan struct{}),
+		dedup:      newDedupCache(opts.dedupWindow, DefaultDedupMaxEntries),
 	}
+	c.ctx, c.cancel = context.WithCancel(eb.ctx)
 	eb.mu.Lock()
 	eb.consumers = append(eb.consumers, c)
 	eb.mu.Unlock()
@@ -437,6 +491,22 @@ func (c *clusterJSConsumer) Handler(handler MessageHandler) Consumer {
 
 func (c *clusterJSConsumer) Completion() <-chan struct{} { return c.completion }
 
+func (c *clusterJSConsumer) Duplicates() uint64 { return c.dedup.count() }
+
+// Pending returns the messages buffered by the NATS client for the consumer's
+// subscriptions and not yet passed to the handler executor.
+func (c *clusterJSConsumer) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending := 0
+	for _, s := range c.subs {
+		if n, _, err := s.Pending(); err == nil {
+			pending += n
+		}
+	}
+	return pending
+}
+
 func (c *clusterJSConsumer) Unregister() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -446,6 +516,8 @@ func (c *clusterJSConsumer) Unregister() error {
 	}
 	c.subs = nil
 
+	c.cancel()
+
 	select {
 	case <-c.completion:
 	default:
@@ -485,6 +557,8 @@ func (c *clusterJSConsumer) onCoreMsg() nats.MsgHandler {
 		)
 		if err := c.eb.executor.Submit(task); err != nil {
 			c.eb.logger.Info(fmt.Sprintf("cluster consumer overloaded for %s: %v", c.address, err))
+			atomic.AddInt64(&c.eb.stats.dropped, 1)
+			c.eb.metrics.undeliverable(c.address, DeadLetterReasonMailboxFull)
 		}
 	}
 }
@@ -496,8 +570,12 @@ func (c *clusterJSConsumer) handleMsg(nm *nats.Msg) error {
 	if h == nil {
 		return nil
 	}
+	// Duplicate (e.g. redelivery after a lost ack): ack without handling
+	if c.dedup.isDuplicate(natsMessageID(nm)) {
+		return nil
+	}
 
-	base := c.eb.ctx
+	base := c.ctx
 	if rid := nm.Header.Get("X-Request-ID"); rid != "" {
 		base = WithRequestID(base, rid)
 	}
@@ -520,12 +598,24 @@ func (c *clusterJSConsumer) handleMsg(nm *nats.Msg) error {
 			nc:             c.eb.nc,
 			prefix:         c.eb.prefix,
 			requestTimeout: c.eb.requestTimeout,
+			codecs:         c.eb.codecs,
 			executor:       c.eb.executor,
 			logger:         c.eb.logger,
+			metrics:        c.eb.metrics,
+			stats:          c.eb.stats,
 		},
 	}
 
-	return h(fctx, msg)
+	var start time.Time
+	if c.eb.metrics != nil {
+		start = time.Now()
+	}
+	err := h(fctx, msg)
+	if err != nil {
+		atomic.AddInt64(&c.eb.stats.handlerErrors, 1)
+	}
+	c.eb.metrics.handled(c.address, start, err)
+	return err
 }
 
 func sanitizeStreamName(prefix string) string {
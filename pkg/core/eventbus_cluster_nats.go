@@ -3,7 +3,9 @@ package core
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fluxorio/fluxor/pkg/core/concurrency"
@@ -28,6 +30,11 @@ type ClusterNATSConfig struct {
 	// ExecutorConfig controls bounded handler execution.
 	// If zero, defaults are used.
 	ExecutorConfig concurrency.ExecutorConfig
+
+	// DeadLetterAddress, when set, receives (via Publish) messages dropped because
+	// the local executor was overloaded. Headers match the in-memory EventBus:
+	// HeaderDeadLetterReason and HeaderOriginalAddress.
+	DeadLetterAddress string
 }
 
 // NewClusterEventBusNATS creates a clustered EventBus backed by NATS.
@@ -74,16 +81,27 @@ func NewClusterEventBusNATS(ctx context.Context, gocmd GoCMD, cfg ClusterNATSCon
 		return nil, err
 	}
 
+	if cfg.DeadLetterAddress != "" {
+		if err := ValidateAddress(cfg.DeadLetterAddress); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+
 	executor := concurrency.NewExecutor(ctx, execCfg)
 
 	return &clusterNATSEventBus{
-		ctx:            ctx,
-		gocmd:          gocmd,
-		nc:             nc,
-		prefix:         prefix,
-		requestTimeout: reqTimeout,
-		executor:       executor,
-		logger:         NewDefaultLogger(),
+		ctx:               ctx,
+		gocmd:             gocmd,
+		nc:                nc,
+		prefix:            prefix,
+		requestTimeout:    reqTimeout,
+		deadLetterAddress: cfg.DeadLetterAddress,
+		codecs:            newCodecRegistry(),
+		executor:          executor,
+		logger:            NewDefaultLogger(),
+		stats:             &eventBusStats{},
+		metrics:           newEventBusMetrics(metricsFor(gocmd)),
 	}, nil
 }
 
@@ -93,11 +111,15 @@ type clusterNATSEventBus struct {
 
 	nc *nats.Conn
 
-	prefix         string
-	requestTimeout time.Duration
+	prefix            string
+	requestTimeout    time.Duration
+	deadLetterAddress string
+	codecs            *codecRegistry
 
 	executor concurrency.Executor
 	logger   Logger
+	metrics  *eventBusMetrics
+	stats    *eventBusStats
 }
 
 func (eb *clusterNATSEventBus) Publish(address string, body interface{}) error {
@@ -108,21 +130,27 @@ func (eb *clusterNATSEventBus) Publish(address string, body interface{}) error {
 		return err
 	}
 
-	data, err := encodeBody(body)
+	data, header, err := encodeNATSBody(eb.codecs, body)
 	if err != nil {
+		atomic.AddInt64(&eb.stats.encodeErrors, 1)
 		return err
 	}
 
 	msg := &nats.Msg{
 		Subject: eb.subjectPub(address),
 		Data:    data,
-		Header:  nats.Header{},
+		Header:  header,
 	}
 	if rid := GetRequestID(eb.ctx); rid != "" {
 		msg.Header.Set("X-Request-ID", rid)
 	}
 
-	return eb.nc.PublishMsg(msg)
+	if err := eb.nc.PublishMsg(msg); err != nil {
+		return err
+	}
+	atomic.AddInt64(&eb.stats.published, 1)
+	eb.metrics.message(address, "publish")
+	return nil
 }
 
 func (eb *clusterNATSEventBus) Send(address string, body interface{}) error {
@@ -133,21 +161,35 @@ func (eb *clusterNATSEventBus) Send(address string, body interface{}) error {
 		return err
 	}
 
-	data, err := encodeBody(body)
+	data, header, err := encodeNATSBody(eb.codecs, body)
 	if err != nil {
+		atomic.AddInt64(&eb.stats.encodeErrors, 1)
 		return err
 	}
 
 	msg := &nats.Msg{
 		Subject: eb.subjectSend(address),
 		Data:    data,
-		Header:  nats.Header{},
+		Header:  header,
 	}
 	if rid := GetRequestID(eb.ctx); rid != "" {
 		msg.Header.Set("X-Request-ID", rid)
 	}
 
-	return eb.nc.PublishMsg(msg)
+	if err := eb.nc.PublishMsg(msg); err != nil {
+		return err
+	}
+	atomic.AddInt64(&eb.stats.sent, 1)
+	eb.metrics.message(address, "send")
+	return nil
+}
+
+// SendWithTimeout behaves like Send: NATS buffers outgoing messages client-side.
+func (eb *clusterNATSEventBus) SendWithTimeout(address string, body interface{}, timeout time.Duration) error {
+	if err := ValidateTimeout(timeout); err != nil {
+		return err
+	}
+	return eb.Send(address, body)
 }
 
 func (eb *clusterNATSEventBus) Request(address string, body interface{}, timeout time.Duration) (Message, error) {
@@ -161,8 +203,9 @@ func (eb *clusterNATSEventBus) Request(address string, body interface{}, timeout
 		return nil, err
 	}
 
-	data, err := encodeBody(body)
+	data, header, err := encodeNATSBody(eb.codecs, body)
 	if err != nil {
+		atomic.AddInt64(&eb.stats.encodeErrors, 1)
 		return nil, err
 	}
 
@@ -173,7 +216,7 @@ func (eb *clusterNATSEventBus) Request(address string, body interface{}, timeout
 	msg := &nats.Msg{
 		Subject: eb.subjectReq(address),
 		Data:    data,
-		Header:  nats.Header{},
+		Header:  header,
 	}
 	if rid := GetRequestID(eb.ctx); rid != "" {
 		msg.Header.Set("X-Request-ID", rid)
@@ -183,6 +226,8 @@ func (eb *clusterNATSEventBus) Request(address string, body interface{}, timeout
 	if err != nil {
 		return nil, err
 	}
+	atomic.AddInt64(&eb.stats.requests, 1)
+	eb.metrics.message(address, "request")
 
 	h := make(map[string]string)
 	for k, v := range resp.Header {
@@ -190,6 +235,9 @@ func (eb *clusterNATSEventBus) Request(address string, body interface{}, timeout
 			h[k] = v[0]
 		}
 	}
+	if err := replyFailure(eb.codecs, h, resp.Data); err != nil {
+		return nil, err
+	}
 
 	return &clusterNATSMessage{
 		body:         resp.Data,
@@ -199,14 +247,26 @@ func (eb *clusterNATSEventBus) Request(address string, body interface{}, timeout
 	}, nil
 }
 
-func (eb *clusterNATSEventBus) Consumer(address string) Consumer {
+func (eb *clusterNATSEventBus) Consumer(address string, opts ...ConsumerOption) Consumer {
 	// Fail-fast: keep contract consistent with in-memory EventBus.
 	// Invalid address is a programmer error and should be caught in dev.
 	if err := ValidateAddress(address); err != nil {
 		failfast.Err(err)
 	}
 	// Create consumer object. Handler() will create subscriptions.
-	return newClusterNATSConsumer(address, eb)
+	return newClusterNATSConsumer(address, eb, newConsumerOptions(opts))
+}
+
+func (eb *clusterNATSEventBus) RegisterCodec(codec Codec) error {
+	return eb.codecs.register(codec)
+}
+
+func (eb *clusterNATSEventBus) SetDefaultCodec(name string) error {
+	return eb.codecs.setDefault(name)
+}
+
+func (eb *clusterNATSEventBus) Metrics() EventBusMetrics {
+	return eb.stats.snapshot()
 }
 
 func (eb *clusterNATSEventBus) Close() error {
@@ -230,6 +290,34 @@ func (eb *clusterNATSEventBus) subjectReq(address string) string {
 	return eb.prefix + ".req." + address
 }
 
+// deadLetter republishes a dropped NATS message to the dead-letter address (best-effort).
+func (eb *clusterNATSEventBus) deadLetter(address string, nm *nats.Msg, reason string) {
+	if address == eb.deadLetterAddress && address != "" {
+		return
+	}
+	atomic.AddInt64(&eb.stats.dropped, 1)
+	eb.metrics.undeliverable(address, reason)
+	if eb.deadLetterAddress == "" {
+		return
+	}
+
+	dl := &nats.Msg{
+		Subject: eb.subjectPub(eb.deadLetterAddress),
+		Data:    nm.Data,
+		Header:  nats.Header{},
+	}
+	for k, v := range nm.Header {
+		dl.Header[k] = v
+	}
+	// Assign directly (not Header.Set) to keep keys identical to the in-memory bus
+	dl.Header[HeaderDeadLetterReason] = []string{reason}
+	dl.Header[HeaderOriginalAddress] = []string{address}
+
+	if err := eb.nc.PublishMsg(dl); err != nil {
+		eb.logger.Error(fmt.Sprintf("dead-letter publish failed for address %s: %v", address, err))
+	}
+}
+
 type clusterNATSConsumer struct {
 	address string
 	eb      *clusterNATSEventBus
@@ -239,14 +327,22 @@ type clusterNATSConsumer struct {
 	subs       []*nats.Subscription
 	completion chan struct{}
 	registered bool
+	dedup      *dedupCache // nil unless WithDedup
+
+	// ctx is passed to handlers and cancelled by Unregister
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
-func newClusterNATSConsumer(address string, eb *clusterNATSEventBus) *clusterNATSConsumer {
-	return &clusterNATSConsumer{
+func newClusterNATSConsumer(address string, eb *clusterNATSEventBus, opts consumerOptions) *clusterNATSConsumer {
+	c := &clusterNATSConsumer{
 		address:    address,
 		eb:         eb,
 		completion: make(chan struct{}),
+		dedup:      newDedupCache(opts.dedupWindow, DefaultDedupMaxEntries),
 	}
+	c.ctx, c.cancel = context.WithCancel(eb.ctx)
+	return c
 }
 
 func (c *clusterNATSConsumer) Handler(handler MessageHandler) Consumer {
@@ -284,6 +380,22 @@ func (c *clusterNATSConsumer) Handler(handler MessageHandler) Consumer {
 
 func (c *clusterNATSConsumer) Completion() <-chan struct{} { return c.completion }
 
+func (c *clusterNATSConsumer) Duplicates() uint64 { return c.dedup.count() }
+
+// Pending returns the messages buffered by the NATS client for the consumer's
+// subscriptions and not yet passed to the handler executor.
+func (c *clusterNATSConsumer) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending := 0
+	for _, s := range c.subs {
+		if n, _, err := s.Pending(); err == nil {
+			pending += n
+		}
+	}
+	return pending
+}
+
 func (c *clusterNATSConsumer) Unregister() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -293,6 +405,8 @@ func (c *clusterNATSConsumer) Unregister() error {
 	}
 	c.subs = nil
 
+	c.cancel()
+
 	select {
 	case <-c.completion:
 	default:
@@ -312,6 +426,7 @@ func (c *clusterNATSConsumer) onMsg(_ string) nats.MsgHandler {
 		)
 		if err := c.eb.executor.Submit(task); err != nil {
 			c.eb.logger.Info(fmt.Sprintf("cluster consumer overloaded for %s: %v", c.address, err))
+			c.eb.deadLetter(c.address, nm, DeadLetterReasonMailboxFull)
 		}
 	}
 }
@@ -323,9 +438,12 @@ func (c *clusterNATSConsumer) handleMsg(nm *nats.Msg) error {
 	if h == nil {
 		return nil
 	}
+	if c.dedup.isDuplicate(natsMessageID(nm)) {
+		return nil
+	}
 
 	// Build context and propagate request ID if present.
-	base := c.eb.ctx
+	base := c.ctx
 	if rid := nm.Header.Get("X-Request-ID"); rid != "" {
 		base = WithRequestID(base, rid)
 	}
@@ -345,7 +463,16 @@ func (c *clusterNATSConsumer) handleMsg(nm *nats.Msg) error {
 		eb:           c.eb,
 	}
 
-	return h(fctx, msg)
+	var start time.Time
+	if c.eb.metrics != nil {
+		start = time.Now()
+	}
+	err := h(fctx, msg)
+	if err != nil {
+		atomic.AddInt64(&c.eb.stats.handlerErrors, 1)
+	}
+	c.eb.metrics.handled(c.address, start, err)
+	return err
 }
 
 type clusterNATSMessage struct {
@@ -379,25 +506,37 @@ func (m *clusterNATSMessage) ReplyAddress() string {
 }
 
 func (m *clusterNATSMessage) Reply(body interface{}) error {
+	return m.reply(body, nil)
+}
+
+// reply publishes body to the reply subject with optional extra headers.
+func (m *clusterNATSMessage) reply(body interface{}, extraHeaders map[string]string) error {
 	if m.replySubject == "" {
 		return ErrNoReplyAddress
 	}
 
-	data, err := encodeBody(body)
+	data, header, err := encodeNATSBody(m.eb.codecs, body)
 	if err != nil {
 		return err
 	}
 
+	for k, v := range extraHeaders {
+		header[k] = []string{v}
+	}
 	reply := &nats.Msg{
 		Subject: m.replySubject,
 		Data:    data,
-		Header:  nats.Header{},
+		Header:  header,
 	}
 	if rid := GetRequestID(m.eb.ctx); rid != "" {
 		reply.Header.Set("X-Request-ID", rid)
 	}
 
-	return m.eb.nc.PublishMsg(reply)
+	if err := m.eb.nc.PublishMsg(reply); err != nil {
+		return err
+	}
+	atomic.AddInt64(&m.eb.stats.replies, 1)
+	return nil
 }
 
 func (m *clusterNATSMessage) DecodeBody(v interface{}) error {
@@ -408,19 +547,38 @@ func (m *clusterNATSMessage) DecodeBody(v interface{}) error {
 	if !ok {
 		return fmt.Errorf("body is not []byte, got %T", m.body)
 	}
-	return JSONDecode(data, v)
+	return m.eb.codecs.decode(m.headers[HeaderContentType], data, v)
 }
 
 func (m *clusterNATSMessage) Fail(failureCode int, message string) error {
-	return m.Reply(map[string]interface{}{
-		"failureCode": failureCode,
-		"message":     message,
-	})
+	return m.reply(failureBody(failureCode, message), map[string]string{HeaderFailure: strconv.Itoa(failureCode)})
+}
+
+// encodeNATSBody encodes body with the default codec and returns the NATS
+// headers to send, including HeaderContentType when a codec was applied.
+func encodeNATSBody(codecs *codecRegistry, body interface{}) ([]byte, nats.Header, error) {
+	partitionKey, body := partitionKeyOf(body)
+	data, contentType, err := codecs.encode(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	header := nats.Header{}
+	if partitionKey != "" {
+		header[HeaderPartitionKey] = []string{partitionKey}
+	}
+	if contentType != "" {
+		// Assign directly (not Header.Set) to keep the key identical to the in-memory bus
+		header[HeaderContentType] = []string{contentType}
+	}
+	header[HeaderMessageID] = []string{generateUUID()}
+	return data, header, nil
 }
 
-func encodeBody(body interface{}) ([]byte, error) {
-	if bodyBytes, ok := body.([]byte); ok {
-		return bodyBytes, nil
+// natsMessageID returns the HeaderMessageID value of nm. The key is read as
+// sent (lowercase), since nats.Header keys are not canonicalized here.
+func natsMessageID(nm *nats.Msg) string {
+	if v := nm.Header[HeaderMessageID]; len(v) > 0 {
+		return v[0]
 	}
-	return JSONEncode(body)
+	return ""
 }
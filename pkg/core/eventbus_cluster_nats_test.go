@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"errors"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -158,6 +159,24 @@ func TestClusterEventBusNATS_PublishSendRequest(t *testing.T) {
 	if !resp.OK || resp.Msg != "hi" {
 		t.Fatalf("unexpected response: %+v", resp)
 	}
+
+	// Request/Fail surfaces a typed error.
+	bus.Consumer("missing").Handler(func(_ FluxorContext, msg Message) error {
+		return msg.Fail(404, "not found")
+	})
+	reply, err = bus.Request("missing", map[string]string{"id": "42"}, 2*time.Second)
+	var replyErr *ReplyError
+	if !errors.As(err, &replyErr) || reply != nil {
+		t.Fatalf("Request to failing handler: reply=%v err=%v, want *ReplyError", reply, err)
+	}
+	if replyErr.FailureCode != 404 || replyErr.Message != "not found" {
+		t.Fatalf("unexpected ReplyError: %+v", replyErr)
+	}
+
+	m := bus.Metrics()
+	if m.Published != 10 || m.Sent != 50 || m.Requests != 2 || m.Replies != 2 {
+		t.Fatalf("Metrics() = %+v, want 10 published, 50 sent, 2 requests, 2 replies", m)
+	}
 }
 
 func TestNewClusterEventBusNATS_FailFast_InvalidInputs(t *testing.T) {
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 )
@@ -44,6 +45,117 @@ func TestConsumer_Completion(t *testing.T) {
 	}
 }
 
+func TestConsumer_CompletionAfterDrain(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	gate := make(chan struct{})
+	var mu sync.Mutex
+	var handled []int
+	consumer := eb.Consumer("test.drain").Handler(func(ctx FluxorContext, msg Message) error {
+		<-gate
+		var n int
+		if err := msg.DecodeBody(&n); err != nil {
+			return err
+		}
+		mu.Lock()
+		handled = append(handled, n)
+		mu.Unlock()
+		return nil
+	})
+	for i := 1; i <= 5; i++ {
+		if err := eb.Publish("test.drain", i); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	// One message is held by the blocked handler, four wait in the mailbox
+	waitForPending(t, consumer, 4)
+
+	if err := consumer.Unregister(); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+	select {
+	case <-consumer.Completion():
+		t.Fatal("Completion closed before the queued messages were handled")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(gate)
+	select {
+	case <-consumer.Completion():
+	case <-time.After(2 * time.Second):
+		t.Fatal("Completion not closed after the mailbox drained")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 5 {
+		t.Errorf("handled %v, want all 5 queued messages", handled)
+	}
+	if n := consumer.Pending(); n != 0 {
+		t.Errorf("Pending() = %d after drain, want 0", n)
+	}
+}
+
+func TestConsumer_Pending(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	// No handler yet: messages stay queued
+	consumer := eb.Consumer("test.pending")
+	if n := consumer.Pending(); n != 0 {
+		t.Errorf("Pending() = %d on a new consumer, want 0", n)
+	}
+	for i := 0; i < 3; i++ {
+		if err := eb.Publish("test.pending", i); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	if n := consumer.Pending(); n != 3 {
+		t.Errorf("Pending() = %d, want 3", n)
+	}
+
+	consumer.Handler(func(ctx FluxorContext, msg Message) error { return nil })
+	waitForPending(t, consumer, 0)
+	_ = consumer.Unregister()
+}
+
+func TestConsumer_PendingWithPartitions(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	gate := make(chan struct{})
+	consumer := eb.Consumer("test.pending.keyed", WithPartitions(2)).Handler(func(ctx FluxorContext, msg Message) error {
+		<-gate
+		return nil
+	})
+	defer consumer.Unregister()
+	defer close(gate)
+
+	for i := 0; i < 6; i++ {
+		if err := eb.Publish("test.pending.keyed", Partitioned("same-key", i)); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	// One message is being handled; the rest count whether they sit in the
+	// consumer mailbox or in the partition
+	waitForPending(t, consumer, 5)
+}
+
+// waitForPending waits until consumer.Pending() reports want.
+func waitForPending(t *testing.T, consumer Consumer, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for consumer.Pending() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Pending() = %d, want %d", consumer.Pending(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
 func TestConsumer_MultipleConsumers(t *testing.T) {
 	ctx := context.Background()
 	gocmd := NewGoCMD(ctx)
@@ -133,3 +245,265 @@ func TestConsumer_RequestIDPropagation(t *testing.T) {
 		t.Error("Timeout waiting for message with request ID")
 	}
 }
+
+func TestConsumer_SendRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	gocmd := NewGoCMD(ctx)
+	defer gocmd.Close()
+
+	eb := gocmd.EventBus()
+	defer eb.Close()
+
+	const consumers = 3
+	const sends = 300
+
+	var mu sync.Mutex
+	counts := make([]int, consumers)
+	var wg sync.WaitGroup
+	wg.Add(sends)
+
+	for i := 0; i < consumers; i++ {
+		idx := i
+		eb.Consumer("test.rr").Handler(func(ctx FluxorContext, msg Message) error {
+			mu.Lock()
+			counts[idx]++
+			mu.Unlock()
+			wg.Done()
+			return nil
+		})
+	}
+
+	for i := 0; i < sends; i++ {
+		if err := eb.Send("test.rr", i); err != nil {
+			t.Fatalf("Send() #%d error = %v", i, err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for messages")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, n := range counts {
+		if n < 80 || n > 120 {
+			t.Errorf("consumer %d received %d messages, want roughly %d (counts=%v)", i, n, sends/consumers, counts)
+		}
+	}
+}
+
+func TestConsumer_SendSkipsFullMailbox(t *testing.T) {
+	ctx := context.Background()
+	gocmd := NewGoCMD(ctx)
+	defer gocmd.Close()
+
+	eb := gocmd.EventBus()
+	defer eb.Close()
+
+	// No handler: mailbox is never drained; fill it up front
+	stalled := eb.Consumer("test.rr.full")
+	defer stalled.Unregister()
+	for stalled.(*consumer).mailbox.Send("filler") == nil {
+	}
+
+	received := make(chan struct{}, 64)
+	eb.Consumer("test.rr.full").Handler(func(ctx FluxorContext, msg Message) error {
+		received <- struct{}{}
+		return nil
+	})
+
+	for i := 0; i < 50; i++ {
+		if err := eb.Send("test.rr.full", i); err != nil {
+			t.Fatalf("Send() #%d error = %v, want fallback to next consumer", i, err)
+		}
+	}
+
+	deadline := time.After(2 * time.Second)
+	for i := 0; i < 50; i++ {
+		select {
+		case <-received:
+		case <-deadline:
+			t.Fatalf("healthy consumer received %d messages, want 50", i)
+		}
+	}
+
+	// Once every consumer is full, Send reports backpressure
+	for eb.(*eventBus).consumers["test.rr.full"][1].mailbox.Send("filler") == nil {
+	}
+	if err := eb.Send("test.rr.full", "overflow"); err != ErrTimeout {
+		t.Errorf("Send() with all mailboxes full error = %v, want ErrTimeout", err)
+	}
+}
+
+func TestConsumer_RequestRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	gocmd := NewGoCMD(ctx)
+	defer gocmd.Close()
+
+	eb := gocmd.EventBus()
+	defer eb.Close()
+
+	const workers = 3
+	const requests = 90
+
+	for i := 0; i < workers; i++ {
+		id := i
+		eb.Consumer("test.rr.workers").Handler(func(ctx FluxorContext, msg Message) error {
+			return msg.Reply(id)
+		})
+	}
+
+	counts := make([]int, workers)
+	for i := 0; i < requests; i++ {
+		reply, err := eb.Request("test.rr.workers", i, time.Second)
+		if err != nil {
+			t.Fatalf("Request() #%d error = %v", i, err)
+		}
+		var id int
+		if err := reply.DecodeBody(&id); err != nil {
+			t.Fatalf("DecodeBody() error = %v", err)
+		}
+		counts[id]++
+	}
+
+	for i, n := range counts {
+		if n < 25 || n > 35 {
+			t.Errorf("worker %d handled %d requests, want roughly %d (counts=%v)", i, n, requests/workers, counts)
+		}
+	}
+}
+
+// fillConsumer sends to address until the (handler-less) consumer mailbox
+// rejects a message and returns how many were buffered.
+func fillConsumer(t *testing.T, eb EventBus, address string, max int) int {
+	t.Helper()
+	for i := 0; i < max; i++ {
+		if err := eb.Send(address, i); err != nil {
+			if err != ErrTimeout {
+				t.Fatalf("Send() #%d error = %v, want ErrTimeout", i, err)
+			}
+			return i
+		}
+	}
+	return max
+}
+
+func TestConsumer_WithMailboxSize(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	// No handler: nothing drains the mailboxes
+	eb.Consumer("test.small", WithMailboxSize(2))
+	eb.Consumer("test.large", WithMailboxSize(500))
+	eb.Consumer("test.default")
+
+	if got := fillConsumer(t, eb, "test.small", 1000); got != 2 {
+		t.Errorf("small mailbox buffered %d messages, want 2", got)
+	}
+	if got := fillConsumer(t, eb, "test.large", 1000); got != 500 {
+		t.Errorf("large mailbox buffered %d messages, want 500", got)
+	}
+	if got := fillConsumer(t, eb, "test.default", 1000); got != DefaultConsumerMailboxSize {
+		t.Errorf("default mailbox buffered %d messages, want %d", got, DefaultConsumerMailboxSize)
+	}
+}
+
+func TestConsumer_WithMailboxSize_Invalid(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("WithMailboxSize(0) should panic")
+		}
+	}()
+	WithMailboxSize(0)
+}
+
+// newSlowConsumer registers a consumer with a one-slot mailbox whose handler
+// blocks until release is closed, and fills it: one message in the handler,
+// one in the mailbox.
+func newSlowConsumer(t *testing.T, eb EventBus, address string) (release chan struct{}, handled *sync.WaitGroup) {
+	t.Helper()
+	release = make(chan struct{})
+	started := make(chan struct{}, 10)
+	handled = &sync.WaitGroup{}
+
+	eb.Consumer(address, WithMailboxSize(1)).Handler(func(ctx FluxorContext, msg Message) error {
+		started <- struct{}{}
+		<-release
+		handled.Done()
+		return nil
+	})
+
+	handled.Add(2)
+	if err := eb.Send(address, "in-handler"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	<-started
+	if err := eb.Send(address, "in-mailbox"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := eb.Send(address, "overflow"); err != ErrTimeout {
+		t.Fatalf("Send() to full mailbox error = %v, want ErrTimeout", err)
+	}
+	return release, handled
+}
+
+func TestEventBus_SendWithTimeout_WaitsForSpace(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	release, handled := newSlowConsumer(t, eb, "test.slow")
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+
+	handled.Add(1)
+	start := time.Now()
+	if err := eb.SendWithTimeout("test.slow", "waited", time.Second); err != nil {
+		t.Fatalf("SendWithTimeout() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("SendWithTimeout() returned after %v, expected it to wait for space", elapsed)
+	}
+	handled.Wait()
+}
+
+func TestEventBus_SendWithTimeout_TimesOut(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	release, _ := newSlowConsumer(t, eb, "test.stuck")
+	defer close(release)
+
+	start := time.Now()
+	if err := eb.SendWithTimeout("test.stuck", "dropped", 50*time.Millisecond); err != ErrTimeout {
+		t.Fatalf("SendWithTimeout() error = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("SendWithTimeout() gave up after %v, want at least 50ms", elapsed)
+	}
+}
+
+func TestEventBus_SendWithTimeout_Validation(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	if err := eb.SendWithTimeout("test.none", "x", 0); err == nil {
+		t.Error("SendWithTimeout() with zero timeout should fail")
+	}
+	err := eb.SendWithTimeout("test.none", "x", time.Second)
+	if ce, ok := err.(*EventBusError); !ok || ce.Code != "NO_HANDLERS" {
+		t.Errorf("SendWithTimeout() without consumers error = %v, want NO_HANDLERS", err)
+	}
+}
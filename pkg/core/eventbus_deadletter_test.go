@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newDeadLetterEventBus(t *testing.T) (EventBus, <-chan Message) {
+	t.Helper()
+
+	gocmd := NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	eb := NewEventBusWithOptions(gocmd.Context(), gocmd, EventBusOptions{DeadLetterAddress: "test.dlq"})
+	t.Cleanup(func() { _ = eb.Close() })
+
+	dead := make(chan Message, 10)
+	eb.Consumer("test.dlq").Handler(func(ctx FluxorContext, msg Message) error {
+		dead <- msg
+		return nil
+	})
+	return eb, dead
+}
+
+func TestEventBus_DeadLetter_NoHandlers(t *testing.T) {
+	eb, dead := newDeadLetterEventBus(t)
+
+	err := eb.Send("test.missing", map[string]string{"id": "42"})
+	if ce, ok := err.(*EventBusError); !ok || ce.Code != "NO_HANDLERS" {
+		t.Fatalf("Send() error = %v, want NO_HANDLERS", err)
+	}
+
+	select {
+	case msg := <-dead:
+		headers := msg.Headers()
+		if headers[HeaderDeadLetterReason] != DeadLetterReasonNoHandlers {
+			t.Errorf("%s = %q, want %q", HeaderDeadLetterReason, headers[HeaderDeadLetterReason], DeadLetterReasonNoHandlers)
+		}
+		if headers[HeaderOriginalAddress] != "test.missing" {
+			t.Errorf("%s = %q, want %q", HeaderOriginalAddress, headers[HeaderOriginalAddress], "test.missing")
+		}
+		var body map[string]string
+		if err := msg.DecodeBody(&body); err != nil {
+			t.Fatalf("DecodeBody() error = %v", err)
+		}
+		if body["id"] != "42" {
+			t.Errorf("dead-letter body = %v, want original body", body)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no dead-letter message received")
+	}
+}
+
+func TestEventBus_DeadLetter_MailboxFull(t *testing.T) {
+	eb, dead := newDeadLetterEventBus(t)
+
+	// No handler: mailbox is never drained; fill it up front
+	stalled := eb.Consumer("test.stalled")
+	for stalled.(*consumer).mailbox.Send("filler") == nil {
+	}
+
+	if err := eb.Publish("test.stalled", "dropped"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := eb.Send("test.stalled", "dropped"); err != ErrTimeout {
+		t.Fatalf("Send() error = %v, want ErrTimeout", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case msg := <-dead:
+			headers := msg.Headers()
+			if headers[HeaderDeadLetterReason] != DeadLetterReasonMailboxFull {
+				t.Errorf("%s = %q, want %q", HeaderDeadLetterReason, headers[HeaderDeadLetterReason], DeadLetterReasonMailboxFull)
+			}
+			if headers[HeaderOriginalAddress] != "test.stalled" {
+				t.Errorf("%s = %q, want %q", HeaderOriginalAddress, headers[HeaderOriginalAddress], "test.stalled")
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("received %d dead-letter messages, want 2", i)
+		}
+	}
+}
+
+func TestEventBus_DeadLetter_Disabled(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	received := make(chan Message, 1)
+	eb.Consumer("test.dlq").Handler(func(ctx FluxorContext, msg Message) error {
+		received <- msg
+		return nil
+	})
+
+	_ = eb.Send("test.missing", "lost")
+
+	select {
+	case msg := <-received:
+		t.Fatalf("unexpected dead-letter delivery without DeadLetterAddress: %v", msg.Headers())
+	case <-time.After(100 * time.Millisecond):
+	}
+}
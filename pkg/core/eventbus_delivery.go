@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeliveryGuarantee says what the in-memory EventBus does when a consumer's
+// mailbox is full.
+type DeliveryGuarantee int
+
+const (
+	// AtMostOnce skips a consumer whose mailbox is full: Publish moves on to
+	// the next consumer and Send fails with ErrTimeout. Skipped messages are
+	// dead-lettered. This is the default.
+	AtMostOnce DeliveryGuarantee = iota
+
+	// AtLeastOnce waits for mailbox space, up to DeliveryPolicy.MaxWait,
+	// before giving up as AtMostOnce does. A slow consumer slows its senders
+	// down instead of losing messages.
+	AtLeastOnce
+)
+
+// DefaultDeliveryMaxWait bounds how long an AtLeastOnce address waits for
+// mailbox space when DeliveryPolicy.MaxWait is unset.
+const DefaultDeliveryMaxWait = 5 * time.Second
+
+// DeliveryPolicy is the delivery guarantee of one address.
+type DeliveryPolicy struct {
+	Guarantee DeliveryGuarantee
+	// MaxWait bounds the wait of each Publish, Send or Request for mailbox
+	// space (AtLeastOnce only; default DefaultDeliveryMaxWait). SendWithTimeout
+	// and Request never wait past their own timeout.
+	MaxWait time.Duration
+}
+
+// newDeliveryWaits validates policies and resolves them to how long each
+// address waits for mailbox space. Addresses not listed do not wait.
+func newDeliveryWaits(policies map[string]DeliveryPolicy) (map[string]time.Duration, error) {
+	waits := make(map[string]time.Duration)
+	for address, policy := range policies {
+		if err := ValidateAddress(address); err != nil {
+			return nil, err
+		}
+		switch policy.Guarantee {
+		case AtMostOnce:
+		case AtLeastOnce:
+			if policy.MaxWait < 0 {
+				return nil, fmt.Errorf("delivery policy for %s: MaxWait cannot be negative", address)
+			}
+			wait := policy.MaxWait
+			if wait == 0 {
+				wait = DefaultDeliveryMaxWait
+			}
+			waits[address] = wait
+		default:
+			return nil, fmt.Errorf("delivery policy for %s: unknown guarantee %d", address, policy.Guarantee)
+		}
+	}
+	return waits, nil
+}
+
+// deliveryWait is how long a message to address waits for mailbox space
+// (0: not at all).
+func (eb *eventBus) deliveryWait(address string) time.Duration {
+	return eb.deliveryWaits[address]
+}
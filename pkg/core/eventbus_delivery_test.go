@@ -0,0 +1,123 @@
+package core
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newDeliveryEventBus(t *testing.T, policies map[string]DeliveryPolicy) EventBus {
+	t.Helper()
+	gocmd := NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	eb := NewEventBusWithOptions(gocmd.Context(), gocmd, EventBusOptions{Delivery: policies})
+	t.Cleanup(func() { _ = eb.Close() })
+	return eb
+}
+
+func TestEventBus_Delivery_SlowConsumer(t *testing.T) {
+	eb := newDeliveryEventBus(t, map[string]DeliveryPolicy{
+		"payments.captured": {Guarantee: AtLeastOnce},
+	})
+
+	const messages = 20
+	slowConsumer := func(address string) *atomic.Int64 {
+		var handled atomic.Int64
+		eb.Consumer(address, WithMailboxSize(2)).Handler(func(ctx FluxorContext, msg Message) error {
+			time.Sleep(5 * time.Millisecond)
+			handled.Add(1)
+			return nil
+		})
+		return &handled
+	}
+	payments := slowConsumer("payments.captured")
+	samples := slowConsumer("metrics.sampled")
+
+	// A burst on the lossy address first, so nothing slows it down
+	sent := 0
+	for i := 0; i < messages; i++ {
+		_ = eb.Publish("metrics.sampled", i)
+		if eb.Send("metrics.sampled", i) == nil {
+			sent++
+		}
+	}
+	for i := 0; i < messages; i++ {
+		if err := eb.Publish("payments.captured", i); err != nil {
+			t.Fatalf("Publish(payments.captured) error = %v", err)
+		}
+		if err := eb.Send("payments.captured", i); err != nil {
+			t.Fatalf("Send(payments.captured) error = %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for payments.Load() < 2*messages && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := payments.Load(); got != 2*messages {
+		t.Errorf("AtLeastOnce consumer handled %d messages, want %d", got, 2*messages)
+	}
+	if sent == messages {
+		t.Error("every AtMostOnce Send succeeded; want some to fail on the full mailbox")
+	}
+	if got := samples.Load(); got >= 2*messages {
+		t.Errorf("AtMostOnce consumer handled %d messages, want some dropped", got)
+	}
+}
+
+func TestEventBus_Delivery_WaitIsBounded(t *testing.T) {
+	eb := newDeliveryEventBus(t, map[string]DeliveryPolicy{
+		"payments.captured": {Guarantee: AtLeastOnce, MaxWait: 50 * time.Millisecond},
+	})
+
+	// No handler: mailbox is never drained; fill it up front
+	stalled := eb.Consumer("payments.captured")
+	for stalled.(*consumer).mailbox.Send("filler") == nil {
+	}
+
+	start := time.Now()
+	if err := eb.Publish("payments.captured", "dropped"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := eb.Send("payments.captured", "dropped"); err != ErrTimeout {
+		t.Fatalf("Send() error = %v, want ErrTimeout", err)
+	}
+	if _, err := eb.Request("payments.captured", "dropped", time.Second); err != ErrTimeout {
+		t.Fatalf("Request() error = %v, want ErrTimeout", err)
+	}
+	elapsed := time.Since(start)
+	if elapsed < 150*time.Millisecond || elapsed > time.Second {
+		t.Errorf("three full-mailbox deliveries took %s, want about 3 x MaxWait", elapsed)
+	}
+	if dropped := eb.Metrics().Dropped; dropped != 2 {
+		t.Errorf("Dropped = %d, want 2 (Publish and Send)", dropped)
+	}
+}
+
+func TestNewDeliveryWaits_Validation(t *testing.T) {
+	waits, err := newDeliveryWaits(map[string]DeliveryPolicy{
+		"payments.captured": {Guarantee: AtLeastOnce},
+		"payments.refunded": {Guarantee: AtLeastOnce, MaxWait: time.Second},
+		"metrics.sampled":   {Guarantee: AtMostOnce},
+	})
+	if err != nil {
+		t.Fatalf("newDeliveryWaits() error = %v", err)
+	}
+	if waits["payments.captured"] != DefaultDeliveryMaxWait || waits["payments.refunded"] != time.Second {
+		t.Errorf("waits = %v, want the default and the configured MaxWait", waits)
+	}
+	if _, ok := waits["metrics.sampled"]; ok {
+		t.Error("AtMostOnce address should not wait")
+	}
+
+	for name, policies := range map[string]map[string]DeliveryPolicy{
+		"invalid address":   {"": {Guarantee: AtLeastOnce}},
+		"negative wait":     {"payments.captured": {Guarantee: AtLeastOnce, MaxWait: -time.Second}},
+		"unknown guarantee": {"payments.captured": {Guarantee: DeliveryGuarantee(7)}},
+	} {
+		if _, err := newDeliveryWaits(policies); err == nil {
+			t.Errorf("%s: newDeliveryWaits() error = nil", name)
+		}
+	}
+}
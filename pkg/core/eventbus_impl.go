@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/fluxorio/fluxor/pkg/core/concurrency"
@@ -20,22 +21,65 @@ import (
 //   - Both are cleaned up together in GoCMD.Close(), no memory leak
 //
 // Thread-safety:
-//   - mu protects the consumers map
+//   - mu protects the consumers, rrCounters and replies maps
+//   - rrCounters values are advanced atomically by Send/Request (round-robin)
 //   - Individual consumer has its own mutex for handler field
 //   - Publish/Send/Request use RLock (concurrent reads)
 //   - Consumer registration uses Lock (exclusive writes)
 type eventBus struct {
-	consumers map[string][]*consumer
-	mu        sync.RWMutex
-	ctx       context.Context      // derived from gocmd.rootCtx via WithCancel
-	cancel    context.CancelFunc   // cancels ctx; called in Close() (redundant but defense-in-depth)
-	gocmd     GoCMD                // back-reference to GoCMD for creating FluxorContext (circular ref)
-	executor  concurrency.Executor // Executor for processing messages (hides goroutines)
-	logger    Logger               // Logger for error and debug messages
+	consumers         map[string][]*consumer
+	rrCounters        map[string]*atomic.Uint64      // per-address round-robin position for point-to-point delivery
+	replies           map[string]concurrency.Mailbox // reply address -> mailbox of a pending Request
+	mu                sync.RWMutex
+	ctx               context.Context          // derived from gocmd.rootCtx via WithCancel
+	cancel            context.CancelFunc       // cancels ctx; called in Close() (redundant but defense-in-depth)
+	gocmd             GoCMD                    // back-reference to GoCMD for creating FluxorContext (circular ref)
+	executor          concurrency.Executor     // Executor for processing messages (hides goroutines)
+	logger            Logger                   // Logger for error and debug messages
+	deadLetterAddress string                   // optional; receives undeliverable messages (empty = disabled)
+	codecs            *codecRegistry           // body codecs; JSON is registered and default
+	metrics           *eventBusMetrics         // nil when GoCMD has no metrics backend
+	stats             eventBusStats            // counters behind Metrics()
+	deliveryWaits     map[string]time.Duration // AtLeastOnce addresses -> bound of the wait for mailbox space
+}
+
+// EventBusOptions configures the in-memory EventBus.
+type EventBusOptions struct {
+	// DeadLetterAddress, when set, receives messages that could not be delivered:
+	//   - Publish: a consumer's mailbox was full and the message was skipped for it
+	//   - Send: no handlers were registered, or every consumer's mailbox was full
+	//
+	// Dead-lettered messages carry the original body and headers plus
+	// HeaderDeadLetterReason and HeaderOriginalAddress. Delivery to the
+	// dead-letter address is best-effort and never dead-letters itself.
+	DeadLetterAddress string
+
+	// Delivery sets the delivery guarantee of individual addresses; the others
+	// are AtMostOnce. AtLeastOnce addresses wait for a full mailbox instead of
+	// skipping it, so a slow payments consumer does not lose messages while
+	// metrics addresses keep the non-blocking path.
+	Delivery map[string]DeliveryPolicy
 }
 
 // NewEventBus creates a new event bus
 func NewEventBus(ctx context.Context, gocmd GoCMD) EventBus {
+	return NewEventBusWithOptions(ctx, gocmd, EventBusOptions{})
+}
+
+// NewEventBusWithOptions creates a new event bus with the given options.
+// Use it with GoCMDOptions.EventBusFactory to install it on a GoCMD instance.
+func NewEventBusWithOptions(ctx context.Context, gocmd GoCMD, opts EventBusOptions) EventBus {
+	// Fail-fast: dead-letter address must be valid when configured
+	if opts.DeadLetterAddress != "" {
+		if err := ValidateAddress(opts.DeadLetterAddress); err != nil {
+			failfast.Err(err)
+		}
+	}
+	deliveryWaits, err := newDeliveryWaits(opts.Delivery)
+	if err != nil {
+		failfast.Err(err)
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 
 	// Create logger
@@ -49,12 +93,19 @@ func NewEventBus(ctx context.Context, gocmd GoCMD) EventBus {
 	executor := concurrency.NewExecutor(ctx, executorConfig)
 
 	return &eventBus{
-		consumers: make(map[string][]*consumer),
-		ctx:       ctx,
-		cancel:    cancel,
-		gocmd:     gocmd,
-		executor:  executor,
-		logger:    logger,
+		consumers:  make(map[string][]*consumer),
+		rrCounters: make(map[string]*atomic.Uint64),
+		replies:    make(map[string]concurrency.Mailbox),
+		ctx:        ctx,
+		cancel:     cancel,
+		gocmd:      gocmd,
+		executor:   executor,
+		logger:     logger,
+
+		deadLetterAddress: opts.DeadLetterAddress,
+		codecs:            newCodecRegistry(),
+		metrics:           newEventBusMetrics(metricsFor(gocmd)),
+		deliveryWaits:     deliveryWaits,
 	}
 }
 
@@ -67,9 +118,11 @@ func (eb *eventBus) Publish(address string, body interface{}) error {
 		return err
 	}
 
-	// Auto-encode to JSON if not already []byte
-	jsonBody, err := eb.encodeBody(body)
+	// Auto-encode with default codec if not already []byte
+	partitionKey, body := partitionKeyOf(body)
+	data, contentType, err := eb.codecs.encode(body)
 	if err != nil {
+		atomic.AddInt64(&eb.stats.encodeErrors, 1)
 		return fmt.Errorf("encode body failed: %w", err)
 	}
 
@@ -82,26 +135,80 @@ func (eb *eventBus) Publish(address string, body interface{}) error {
 	if requestID := GetRequestID(eb.ctx); requestID != "" {
 		headers["X-Request-ID"] = requestID
 	}
-	msg := newMessage(jsonBody, headers, "", eb)
+	if contentType != "" {
+		headers[HeaderContentType] = contentType
+	}
+	if partitionKey != "" {
+		headers[HeaderPartitionKey] = partitionKey
+	}
+	headers[HeaderMessageID] = generateUUID()
+	msg := newMessage(data, headers, "", eb)
+
+	// AtLeastOnce: one bounded wait shared by all consumers of this publish
+	var waitCtx context.Context
+	if wait := eb.deliveryWait(address); wait > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(eb.ctx, wait)
+		defer cancel()
+	}
 
 	for _, c := range consumers {
 		// Use Mailbox abstraction (hides channel operations)
-		if err := c.mailbox.Send(msg); err != nil {
+		err := c.mailbox.Send(msg)
+		if err == concurrency.ErrMailboxFull && waitCtx != nil {
+			err = c.mailbox.SendContext(waitCtx, msg)
+			if err == context.DeadlineExceeded {
+				err = concurrency.ErrMailboxFull
+			}
+		}
+		if err != nil {
 			if err == concurrency.ErrMailboxFull {
 				// Non-blocking: if handler is busy, skip
+				eb.deadLetter(address, msg, DeadLetterReasonMailboxFull)
 				continue
 			}
-			if err == concurrency.ErrMailboxClosed {
+			if err == concurrency.ErrMailboxClosed || err == context.Canceled {
 				return eb.ctx.Err()
 			}
 			return err
 		}
 	}
 
+	atomic.AddInt64(&eb.stats.published, 1)
+	eb.metrics.message(address, "publish")
+	eb.reportMailboxDepth(address)
 	return nil
 }
 
+// reportMailboxDepth sets the mailbox depth gauge of address to the messages
+// waiting in its consumers' mailboxes. It must be called without eb.mu held.
+func (eb *eventBus) reportMailboxDepth(address string) {
+	if eb.metrics == nil {
+		return
+	}
+	eb.mu.RLock()
+	depth := 0
+	for _, c := range eb.consumers[address] {
+		depth += c.mailbox.Size()
+	}
+	eb.mu.RUnlock()
+	eb.metrics.mailbox(address, depth)
+}
+
 func (eb *eventBus) Send(address string, body interface{}) error {
+	return eb.send(address, body, 0, nil)
+}
+
+func (eb *eventBus) SendWithTimeout(address string, body interface{}, timeout time.Duration) error {
+	if err := ValidateTimeout(timeout); err != nil {
+		return err
+	}
+	return eb.send(address, body, timeout, nil)
+}
+
+// send implements Send (wait == 0: non-blocking) and SendWithTimeout.
+// extraHeaders are added to the message (e.g. HeaderFailure for Message.Fail).
+func (eb *eventBus) send(address string, body interface{}, wait time.Duration, extraHeaders map[string]string) error {
 	// Fail-fast: validate inputs immediately
 	if err := ValidateAddress(address); err != nil {
 		return err
@@ -110,43 +217,72 @@ func (eb *eventBus) Send(address string, body interface{}) error {
 		return err
 	}
 
-	// Auto-encode to JSON if not already []byte
-	jsonBody, err := eb.encodeBody(body)
+	// Auto-encode with default codec if not already []byte
+	partitionKey, body := partitionKeyOf(body)
+	data, contentType, err := eb.codecs.encode(body)
 	if err != nil {
+		atomic.AddInt64(&eb.stats.encodeErrors, 1)
 		return fmt.Errorf("encode body failed: %w", err)
 	}
 
 	eb.mu.RLock()
+	replyMailbox := eb.replies[address]
 	consumers := eb.consumers[address]
+	counter := eb.rrCounters[address]
 	eb.mu.RUnlock()
 
-	// Fail-fast: no handlers registered
-	if len(consumers) == 0 {
-		return &EventBusError{Code: "NO_HANDLERS", Message: "No handlers registered for address: " + address}
-	}
-
-	// Round-robin to one consumer
-	consumer := consumers[0]
-
 	// Extract request ID from context if available
 	headers := make(map[string]string)
 	if requestID := GetRequestID(eb.ctx); requestID != "" {
 		headers["X-Request-ID"] = requestID
 	}
-	msg := newMessage(jsonBody, headers, "", eb)
+	if contentType != "" {
+		headers[HeaderContentType] = contentType
+	}
+	if partitionKey != "" {
+		headers[HeaderPartitionKey] = partitionKey
+	}
+	headers[HeaderMessageID] = generateUUID()
+	for k, v := range extraHeaders {
+		headers[k] = v
+	}
+	msg := newMessage(data, headers, "", eb)
 
-	// Use Mailbox abstraction (hides select statement)
-	// Note: Mailbox.Send() is non-blocking, so timeout is handled by backpressure
-	if err := consumer.mailbox.Send(msg); err != nil {
-		if err == concurrency.ErrMailboxFull {
-			return ErrTimeout
-		}
-		if err == concurrency.ErrMailboxClosed {
-			return eb.ctx.Err()
+	// A reply goes straight to the waiting Request
+	if replyMailbox != nil {
+		if err := replyMailbox.Send(msg); err != nil {
+			// Mailbox full: the request already has its reply
+			eb.logger.Info(fmt.Sprintf("reply mailbox full for address %s: %v", address, err))
+			return nil
 		}
-		return err
+		eb.stats.delivered(address)
+		eb.metrics.message(address, "send")
+		return nil
 	}
-	return nil
+
+	// Fail-fast: no handlers registered
+	if len(consumers) == 0 {
+		eb.deadLetter(address, msg, DeadLetterReasonNoHandlers)
+		return &EventBusError{Code: "NO_HANDLERS", Message: "No handlers registered for address: " + address}
+	}
+
+	// Round-robin to one consumer
+	if wait == 0 {
+		wait = eb.deliveryWait(address)
+	}
+	err = eb.sendRoundRobin(consumers, counter, msg)
+	if err == ErrTimeout && wait > 0 {
+		err = eb.waitRoundRobin(consumers, counter, msg, wait)
+	}
+	if err == ErrTimeout {
+		eb.deadLetter(address, msg, DeadLetterReasonMailboxFull)
+	}
+	if err == nil {
+		eb.stats.delivered(address)
+		eb.metrics.message(address, "send")
+		eb.reportMailboxDepth(address)
+	}
+	return err
 }
 
 func (eb *eventBus) Request(address string, body interface{}, timeout time.Duration) (Message, error) {
@@ -161,28 +297,27 @@ func (eb *eventBus) Request(address string, body interface{}, timeout time.Durat
 		return nil, err
 	}
 
-	// Auto-encode to JSON if not already []byte
-	jsonBody, err := eb.encodeBody(body)
+	// Auto-encode with default codec if not already []byte
+	partitionKey, body := partitionKeyOf(body)
+	data, contentType, err := eb.codecs.encode(body)
 	if err != nil {
+		atomic.AddInt64(&eb.stats.encodeErrors, 1)
 		return nil, fmt.Errorf("encode body failed: %w", err)
 	}
 
+	// The reply is delivered straight into a one-shot mailbox by send: no
+	// temporary consumer and no goroutine per request. A reply arriving after
+	// the request gave up finds no mailbox and is dead-lettered as NO_HANDLERS.
 	replyAddress := generateReplyAddress()
 	replyMailbox := concurrency.NewBoundedMailbox(1) // Hidden: channel creation
-
-	// Register temporary reply handler
-	replyConsumer := eb.Consumer(replyAddress)
-	replyConsumer.Handler(func(ctx FluxorContext, msg Message) error {
-		// Use Mailbox abstraction (hides channel send)
-		if err := replyMailbox.Send(msg); err != nil {
-			// Log if mailbox full (non-blocking) - this indicates reply arrived but couldn't be delivered
-			eb.logger.Info(fmt.Sprintf("reply mailbox full for address %s: %v", replyAddress, err))
-		}
-		return nil
-	})
-	// Unregister is called after timeout/response, error is intentionally ignored
-	// as the consumer cleanup is best-effort during request completion
-	defer func() { _ = replyConsumer.Unregister() }()
+	eb.mu.Lock()
+	eb.replies[replyAddress] = replyMailbox
+	eb.mu.Unlock()
+	defer func() {
+		eb.mu.Lock()
+		delete(eb.replies, replyAddress)
+		eb.mu.Unlock()
+	}()
 
 	// Send request with reply address
 	headers := map[string]string{"replyAddress": replyAddress}
@@ -190,10 +325,18 @@ func (eb *eventBus) Request(address string, body interface{}, timeout time.Durat
 	if requestID := GetRequestID(eb.ctx); requestID != "" {
 		headers["X-Request-ID"] = requestID
 	}
-	msg := newMessage(jsonBody, headers, replyAddress, eb)
+	if contentType != "" {
+		headers[HeaderContentType] = contentType
+	}
+	if partitionKey != "" {
+		headers[HeaderPartitionKey] = partitionKey
+	}
+	headers[HeaderMessageID] = generateUUID()
+	msg := newMessage(data, headers, replyAddress, eb)
 
 	eb.mu.RLock()
 	consumers := eb.consumers[address]
+	counter := eb.rrCounters[address]
 	eb.mu.RUnlock()
 
 	// Fail-fast: no handlers registered
@@ -201,19 +344,17 @@ func (eb *eventBus) Request(address string, body interface{}, timeout time.Durat
 		return nil, &EventBusError{Code: "NO_HANDLERS", Message: "No handlers registered for address: " + address}
 	}
 
-	consumer := consumers[0]
-
-	// Use Mailbox abstraction (hides select statement)
-	// Note: Mailbox.Send() is non-blocking, timeout handled by backpressure
-	if err := consumer.mailbox.Send(msg); err != nil {
-		if err == concurrency.ErrMailboxFull {
-			return nil, ErrTimeout
-		}
-		if err == concurrency.ErrMailboxClosed {
-			return nil, eb.ctx.Err()
-		}
+	// Round-robin to one consumer
+	err = eb.sendRoundRobin(consumers, counter, msg)
+	if wait := min(eb.deliveryWait(address), timeout); err == ErrTimeout && wait > 0 {
+		err = eb.waitRoundRobin(consumers, counter, msg, wait)
+	}
+	if err != nil {
 		return nil, err
 	}
+	atomic.AddInt64(&eb.stats.requests, 1)
+	eb.metrics.message(address, "request")
+	eb.reportMailboxDepth(address)
 
 	// Wait for reply using Mailbox abstraction (hides select statement)
 	replyCtx, replyCancel := context.WithTimeout(eb.ctx, timeout)
@@ -228,39 +369,153 @@ func (eb *eventBus) Request(address string, body interface{}, timeout time.Durat
 	}
 
 	if msg, ok := reply.(Message); ok {
+		if err := replyFailure(eb.codecs, msg.Headers(), msg.Body()); err != nil {
+			return nil, err
+		}
 		return msg, nil
 	}
 	return nil, fmt.Errorf("invalid reply message type")
 }
 
-func (eb *eventBus) Consumer(address string) Consumer {
+func (eb *eventBus) Consumer(address string, opts ...ConsumerOption) Consumer {
 	// Fail-fast: validate address immediately
 	if err := ValidateAddress(address); err != nil {
 		failfast.Err(err)
 	}
+	options := newConsumerOptions(opts)
 
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
 
+	// Each consumer gets its own context, cancelled by Unregister or Close,
+	// so long-running handlers can abort when their consumer goes away
+	consumerCtx, cancel := context.WithCancel(eb.ctx)
+
 	// Initialize fluxorCtx when creating consumer
 	// Create FluxorContext for the consumer using eventBus's GoCMD reference
 	var fluxorCtx FluxorContext
 	if eb.gocmd != nil {
-		fluxorCtx = newFluxorContext(eb.ctx, eb.gocmd)
+		fluxorCtx = newFluxorContext(consumerCtx, eb.gocmd)
 	}
 
 	c := &consumer{
 		address:  address,
-		mailbox:  concurrency.NewBoundedMailbox(100), // Hidden: channel creation
+		mailbox:  concurrency.NewBoundedMailbox(options.mailboxSize), // Hidden: channel creation
 		eventBus: eb,
-		ctx:      fluxorCtx,           // Initialize ctx to prevent nil pointer
+		ctx:      fluxorCtx, // Initialize ctx to prevent nil pointer
+		cancel:   cancel,
 		done:     make(chan struct{}), // Channel for Completion() notification (closed when mailbox processing stops)
+		dedup:    newDedupCache(options.dedupWindow, DefaultDedupMaxEntries),
+
+		partitions:  options.partitions,
+		mailboxSize: options.mailboxSize,
 	}
 
 	eb.consumers[address] = append(eb.consumers[address], c)
+	if _, ok := eb.rrCounters[address]; !ok {
+		eb.rrCounters[address] = &atomic.Uint64{}
+	}
 	return c
 }
 
+// sendRoundRobin delivers msg to exactly one of consumers, starting at the
+// position tracked by counter and advancing it on every call.
+// Consumers whose mailbox is full (or already closed) are skipped; ErrTimeout
+// is returned only when every consumer rejected the message.
+func (eb *eventBus) sendRoundRobin(consumers []*consumer, counter *atomic.Uint64, msg Message) error {
+	n := uint64(len(consumers))
+	start := uint64(0)
+	if counter != nil {
+		start = counter.Add(1) - 1
+	}
+
+	sawFull := false
+	for i := uint64(0); i < n; i++ {
+		c := consumers[(start+i)%n]
+
+		// Use Mailbox abstraction (hides select statement)
+		// Note: Mailbox.Send() is non-blocking, so timeout is handled by backpressure
+		err := c.mailbox.Send(msg)
+		if err == nil {
+			return nil
+		}
+		switch err {
+		case concurrency.ErrMailboxFull:
+			sawFull = true
+		case concurrency.ErrMailboxClosed:
+			// Consumer unregistered concurrently - try the next one
+		default:
+			return err
+		}
+	}
+
+	if !sawFull {
+		return eb.ctx.Err()
+	}
+	return ErrTimeout
+}
+
+// sendWaitSlice bounds how long waitRoundRobin blocks on one consumer before
+// checking the others for free space
+const sendWaitSlice = 10 * time.Millisecond
+
+// waitRoundRobin blocks up to timeout until one of consumers has mailbox space.
+// It waits on one consumer at a time (in round-robin order, for at most
+// sendWaitSlice when there are several), so space freed on any consumer is used.
+func (eb *eventBus) waitRoundRobin(consumers []*consumer, counter *atomic.Uint64, msg Message, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(eb.ctx, timeout)
+	defer cancel()
+
+	n := len(consumers)
+	start := 0
+	if counter != nil {
+		start = int(counter.Load() % uint64(n))
+	}
+	closed := make(map[int]bool)
+
+	for i := 0; ; i++ {
+		idx := (start + i) % n
+		if closed[idx] {
+			continue
+		}
+
+		waitCtx, waitCancel := ctx, context.CancelFunc(func() {})
+		if n > 1 {
+			waitCtx, waitCancel = context.WithTimeout(ctx, sendWaitSlice)
+		}
+		err := consumers[idx].mailbox.SendContext(waitCtx, msg)
+		waitCancel()
+
+		switch {
+		case err == nil:
+			return nil
+		case err == concurrency.ErrMailboxClosed:
+			// Consumer unregistered concurrently - stop considering it
+			closed[idx] = true
+			if len(closed) == n {
+				return eb.ctx.Err()
+			}
+		case ctx.Err() != nil:
+			if eb.ctx.Err() != nil {
+				return eb.ctx.Err()
+			}
+			return ErrTimeout
+		}
+	}
+}
+
+func (eb *eventBus) RegisterCodec(codec Codec) error {
+	return eb.codecs.register(codec)
+}
+
+func (eb *eventBus) Metrics() EventBusMetrics {
+	return eb.stats.snapshot()
+}
+
+func (eb *eventBus) SetDefaultCodec(name string) error {
+	return eb.codecs.setDefault(name)
+}
+
 func (eb *eventBus) Close() error {
 	eb.cancel()
 
@@ -278,9 +533,11 @@ func (eb *eventBus) Close() error {
 		for _, c := range consumers {
 			// Close mailbox (hides channel close operation)
 			c.mailbox.Close()
+			c.cancel()
 		}
 	}
 	eb.consumers = make(map[string][]*consumer)
+	eb.rrCounters = make(map[string]*atomic.Uint64)
 	return nil
 }
 
@@ -292,8 +549,14 @@ type consumer struct {
 	handler  MessageHandler
 	eventBus *eventBus
 	ctx      FluxorContext
+	cancel   context.CancelFunc // cancels ctx on Unregister or Close
 	mu       sync.RWMutex
 	done     chan struct{} // Channel for Completion() notification (closed when mailbox closes)
+	dedup    *dedupCache   // nil unless WithDedup
+
+	partitions  int // WithPartitions count; 0 handles messages in mailbox order
+	mailboxSize int
+	keyed       atomic.Pointer[consumerPartitions] // set while partitions run
 }
 
 func (c *consumer) Handler(handler MessageHandler) Consumer {
@@ -332,6 +595,13 @@ func (c *consumer) processMessages(ctx context.Context) error {
 		close(c.done)
 	}()
 
+	var partitions *consumerPartitions
+	if c.partitions > 0 {
+		partitions = newConsumerPartitions(c, c.partitions, c.mailboxSize)
+		c.keyed.Store(partitions)
+		defer partitions.close()
+	}
+
 	// Use Mailbox abstraction (hides select statement and channel operations)
 	for {
 		// Receive message using Mailbox (hides channel receive and select)
@@ -340,6 +610,7 @@ func (c *consumer) processMessages(ctx context.Context) error {
 			// Mailbox closed or context cancelled
 			return err
 		}
+		c.eventBus.reportMailboxDepth(c.address)
 
 		// Type assert to Message
 		message, ok := msg.(Message)
@@ -349,55 +620,94 @@ func (c *consumer) processMessages(ctx context.Context) error {
 			continue
 		}
 
-		if c.handler != nil {
-			// Use the consumer's context (now properly initialized)
-			fluxorCtx := c.ctx
-			if fluxorCtx == nil {
-				// Fallback: create context if somehow nil (shouldn't happen after fix)
-				if c.eventBus.gocmd != nil {
-					fluxorCtx = newFluxorContext(c.eventBus.ctx, c.eventBus.gocmd)
-				}
+		// Skip messages already seen within the dedup window
+		if c.dedup.isDuplicate(message.Headers()[HeaderMessageID]) {
+			continue
+		}
+
+		if partitions != nil {
+			if err := partitions.dispatch(ctx, message); err != nil {
+				return err
 			}
+			continue
+		}
+		c.handle(message)
+	}
+}
 
-			// Wrap handler call in panic recovery for individual messages (panic isolation)
-			func() {
-				defer func() {
-					if r := recover(); r != nil {
-						// Log handler panic but don't crash - maintain panic isolation
-						c.eventBus.logger.Error(fmt.Sprintf("handler panic for address %s (isolated): %v", c.address, r))
-					}
-				}()
-
-				// Call handler - errors are logged but don't crash
-				if err := c.handler(fluxorCtx, message); err != nil {
-					// Log handler error but don't panic - maintain system stability
-					// Try to extract request ID from message headers for better tracing
-					requestID := ""
-					if headers := message.Headers(); headers != nil {
-						if id, ok := headers["X-Request-ID"]; ok {
-							requestID = id
-						}
-					}
-					if requestID != "" {
-						c.eventBus.logger.Error(fmt.Sprintf("handler error for address %s (request_id=%s): %v", c.address, requestID, err))
-					} else {
-						c.eventBus.logger.Error(fmt.Sprintf("handler error for address %s: %v", c.address, err))
-					}
+// handle runs the handler for one message with panic isolation.
+func (c *consumer) handle(message Message) {
+	if c.handler != nil {
+		// Use the consumer's context (now properly initialized)
+		fluxorCtx := c.ctx
+		if fluxorCtx == nil {
+			// Fallback: create context if somehow nil (shouldn't happen after fix)
+			if c.eventBus.gocmd != nil {
+				fluxorCtx = newFluxorContext(c.eventBus.ctx, c.eventBus.gocmd)
+			}
+		}
+
+		// Wrap handler call in panic recovery for individual messages (panic isolation)
+		func() {
+			// Only read the clock when a metrics backend is configured
+			var start time.Time
+			var handlerErr error
+			if c.eventBus.metrics != nil {
+				start = time.Now()
+			}
+			defer func() {
+				if r := recover(); r != nil {
+					// Log handler panic but don't crash - maintain panic isolation
+					c.eventBus.logger.Error(fmt.Sprintf("handler panic for address %s (isolated): %v", c.address, r))
+					handlerErr = fmt.Errorf("handler panic: %v", r)
 				}
+				if handlerErr != nil {
+					atomic.AddInt64(&c.eventBus.stats.handlerErrors, 1)
+				}
+				c.eventBus.metrics.handled(c.address, start, handlerErr)
 			}()
-		} else {
-			// Handler is nil - log but don't panic (shouldn't happen in normal flow)
-			c.eventBus.logger.Info(fmt.Sprintf("handler is nil for address %s", c.address))
-		}
+
+			// Call handler - errors are logged but don't crash
+			if err := c.handler(fluxorCtx, message); err != nil {
+				handlerErr = err
+				// Log handler error but don't panic - maintain system stability
+				// Try to extract request ID from message headers for better tracing
+				requestID := ""
+				if headers := message.Headers(); headers != nil {
+					if id, ok := headers["X-Request-ID"]; ok {
+						requestID = id
+					}
+				}
+				if requestID != "" {
+					c.eventBus.logger.Error(fmt.Sprintf("handler error for address %s (request_id=%s): %v", c.address, requestID, err))
+				} else {
+					c.eventBus.logger.Error(fmt.Sprintf("handler error for address %s: %v", c.address, err))
+				}
+			}
+		}()
+	} else {
+		// Handler is nil - log but don't panic (shouldn't happen in normal flow)
+		c.eventBus.logger.Info(fmt.Sprintf("handler is nil for address %s", c.address))
 	}
 }
 
+func (c *consumer) Duplicates() uint64 {
+	return c.dedup.count()
+}
+
 func (c *consumer) Completion() <-chan struct{} {
-	// Return the done channel that will be closed when mailbox processing stops
-	// This is efficient - no polling, just channel notification
+	// Closed by processMessages once the closed mailbox is drained
 	return c.done
 }
 
+func (c *consumer) Pending() int {
+	pending := c.mailbox.Size()
+	if p := c.keyed.Load(); p != nil {
+		pending += p.pending()
+	}
+	return pending
+}
+
 func (c *consumer) Unregister() error {
 	c.eventBus.mu.Lock()
 	defer c.eventBus.mu.Unlock()
@@ -405,32 +715,54 @@ func (c *consumer) Unregister() error {
 	consumers := c.eventBus.consumers[c.address]
 	for i, cons := range consumers {
 		if cons == c {
-			c.eventBus.consumers[c.address] = append(consumers[:i], consumers[i+1:]...)
+			// Copy instead of shifting in place: Publish and Send iterate
+			// their snapshot of the slice without the lock
+			c.eventBus.consumers[c.address] = append(consumers[:i:i], consumers[i+1:]...)
 			break
 		}
 	}
 
 	// Close mailbox (hides channel close operation)
 	c.mailbox.Close()
+	// Signal handlers still running for this consumer
+	c.cancel()
 	return nil
 }
 
-func generateReplyAddress() string {
-	return "reply." + uuid.New().String()
-}
+// deadLetter republishes an undeliverable message to the configured dead-letter
+// address. It is best-effort: full dead-letter mailboxes are logged, not retried.
+func (eb *eventBus) deadLetter(address string, msg Message, reason string) {
+	if address == eb.deadLetterAddress && address != "" {
+		return
+	}
+	atomic.AddInt64(&eb.stats.dropped, 1)
+	eb.metrics.undeliverable(address, reason)
+	if eb.deadLetterAddress == "" {
+		return
+	}
 
-// encodeBody encodes body to JSON if needed - fail-fast
-func (eb *eventBus) encodeBody(body interface{}) (interface{}, error) {
-	// Fail-fast: validate body
-	if err := ValidateBody(body); err != nil {
-		return nil, err
+	eb.mu.RLock()
+	consumers := eb.consumers[eb.deadLetterAddress]
+	eb.mu.RUnlock()
+
+	if len(consumers) == 0 {
+		eb.logger.Info(fmt.Sprintf("dropped message for address %s (reason=%s): no dead-letter consumers on %s", address, reason, eb.deadLetterAddress))
+		return
 	}
 
-	// If already []byte, return as-is
-	if data, ok := body.([]byte); ok {
-		return data, nil
+	headers := msg.Headers()
+	headers[HeaderDeadLetterReason] = reason
+	headers[HeaderOriginalAddress] = address
+	dl := newMessage(msg.Body(), headers, "", eb)
+
+	for _, c := range consumers {
+		if err := c.mailbox.Send(dl); err != nil {
+			eb.logger.Error(fmt.Sprintf("dead-letter delivery failed for address %s (reason=%s): %v", address, reason, err))
+		}
 	}
+	eb.reportMailboxDepth(eb.deadLetterAddress)
+}
 
-	// Encode to JSON - errors are propagated immediately
-	return JSONEncode(body)
+func generateReplyAddress() string {
+	return "reply." + uuid.New().String()
 }
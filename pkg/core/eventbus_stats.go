@@ -0,0 +1,49 @@
+package core
+
+import "sync/atomic"
+
+// EventBusMetrics is a snapshot of an EventBus's delivery counters since it
+// was created. Counters are local to this process; on clustered buses they
+// count what this node published and handled, not cluster-wide totals.
+type EventBusMetrics struct {
+	Published     int64 // Publish calls accepted by the bus
+	Sent          int64 // Send/SendWithTimeout messages handed to a consumer
+	Requests      int64 // Request messages handed to a handler
+	Replies       int64 // Reply/Fail answers sent back to a requester
+	Dropped       int64 // Messages not delivered (no handlers, full mailbox)
+	EncodeErrors  int64 // Bodies the default codec failed to encode
+	HandlerErrors int64 // Handler calls that returned an error or panicked
+}
+
+// eventBusStats are the atomic counters behind EventBus.Metrics().
+type eventBusStats struct {
+	published     int64
+	sent          int64
+	requests      int64
+	replies       int64
+	dropped       int64
+	encodeErrors  int64
+	handlerErrors int64
+}
+
+// delivered counts a successful point-to-point delivery, telling replies apart
+// from regular sends by their address.
+func (s *eventBusStats) delivered(address string) {
+	if isReplyAddress(address) {
+		atomic.AddInt64(&s.replies, 1)
+		return
+	}
+	atomic.AddInt64(&s.sent, 1)
+}
+
+func (s *eventBusStats) snapshot() EventBusMetrics {
+	return EventBusMetrics{
+		Published:     atomic.LoadInt64(&s.published),
+		Sent:          atomic.LoadInt64(&s.sent),
+		Requests:      atomic.LoadInt64(&s.requests),
+		Replies:       atomic.LoadInt64(&s.replies),
+		Dropped:       atomic.LoadInt64(&s.dropped),
+		EncodeErrors:  atomic.LoadInt64(&s.encodeErrors),
+		HandlerErrors: atomic.LoadInt64(&s.handlerErrors),
+	}
+}
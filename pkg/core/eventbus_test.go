@@ -2,6 +2,9 @@ package core
 
 import (
 	"context"
+	"errors"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 )
@@ -133,6 +136,153 @@ func TestEventBus_Request(t *testing.T) {
 	}
 }
 
+func TestEventBus_Request_Fail(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	eb := gocmd.EventBus()
+	defer eb.Close()
+
+	eb.Consumer("test.fail").Handler(func(ctx FluxorContext, msg Message) error {
+		return msg.Fail(404, "not found")
+	})
+	// A genuine reply shaped like a failure must not be misinterpreted
+	eb.Consumer("test.failshape").Handler(func(ctx FluxorContext, msg Message) error {
+		return msg.Reply(map[string]interface{}{"failureCode": 500, "message": "just data"})
+	})
+
+	msg, err := eb.Request("test.fail", "lookup", time.Second)
+	if msg != nil {
+		t.Errorf("Request() returned a Message for a failed reply: %v", msg.Body())
+	}
+	var replyErr *ReplyError
+	if !errors.As(err, &replyErr) {
+		t.Fatalf("Request() error = %v (%T), want *ReplyError", err, err)
+	}
+	if replyErr.FailureCode != 404 || replyErr.Message != "not found" {
+		t.Errorf("ReplyError = %+v, want code 404 and message %q", replyErr, "not found")
+	}
+
+	msg, err = eb.Request("test.failshape", "lookup", time.Second)
+	if err != nil {
+		t.Fatalf("Request() error = %v for a plain map reply", err)
+	}
+	var body map[string]interface{}
+	if err := msg.DecodeBody(&body); err != nil || body["message"] != "just data" {
+		t.Errorf("reply body = %v (%v), want the map", body, err)
+	}
+}
+
+func TestEventBus_Request_OneShotReplies(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	eb := gocmd.EventBus()
+	defer eb.Close()
+	bus := eb.(*eventBus)
+
+	release := make(chan struct{})
+	eb.Consumer("test.echo").Handler(func(ctx FluxorContext, msg Message) error {
+		<-release
+		var body int
+		if err := msg.DecodeBody(&body); err != nil {
+			return err
+		}
+		return msg.Reply(body * 10)
+	})
+	time.Sleep(20 * time.Millisecond) // let the consumer start
+
+	const requests = 20
+	base := runtime.NumGoroutine()
+	replies := make(chan [2]int, requests)
+	for i := 0; i < requests; i++ {
+		go func(i int) {
+			msg, err := eb.Request("test.echo", i, 2*time.Second)
+			if err != nil {
+				t.Errorf("Request(%d) error = %v", i, err)
+				replies <- [2]int{i, -1}
+				return
+			}
+			var got int
+			_ = msg.DecodeBody(&got)
+			replies <- [2]int{i, got}
+		}(i)
+	}
+
+	// Waiting requests hold a mailbox each, not a consumer and its goroutine
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		bus.mu.RLock()
+		pending := len(bus.replies)
+		bus.mu.RUnlock()
+		if pending == requests {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d requests pending, want %d", pending, requests)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if extra := runtime.NumGoroutine() - base; extra > requests+2 {
+		t.Errorf("%d goroutines for %d pending requests, want about one each", extra, requests)
+	}
+	bus.mu.RLock()
+	for address := range bus.consumers {
+		if isReplyAddress(address) {
+			t.Errorf("reply consumer registered at %s", address)
+		}
+	}
+	bus.mu.RUnlock()
+
+	close(release)
+	for i := 0; i < requests; i++ {
+		if r := <-replies; r[1] != r[0]*10 {
+			t.Errorf("Request(%d) reply = %d, want %d", r[0], r[1], r[0]*10)
+		}
+	}
+	bus.mu.RLock()
+	defer bus.mu.RUnlock()
+	if len(bus.replies) != 0 {
+		t.Errorf("%d reply mailboxes left after the requests returned", len(bus.replies))
+	}
+}
+
+func TestEventBus_Request_LateReply(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	eb := gocmd.EventBus()
+	defer eb.Close()
+
+	late := make(chan error, 1)
+	eb.Consumer("test.slow").Handler(func(ctx FluxorContext, msg Message) error {
+		time.Sleep(100 * time.Millisecond)
+		late <- msg.Reply("too late")
+		return nil
+	})
+	if _, err := eb.Request("test.slow", "ping", 20*time.Millisecond); err != ErrTimeout {
+		t.Fatalf("Request() error = %v, want ErrTimeout", err)
+	}
+	// Nobody waits for the reply any more
+	if err := <-late; err == nil {
+		t.Error("late Reply() succeeded, want NO_HANDLERS")
+	}
+}
+
+// BenchmarkEventBus_Request measures a request/reply round trip; run with
+// -benchmem to see the per-request allocations.
+func BenchmarkEventBus_Request(b *testing.B) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+	eb.Consumer("bench.echo").Handler(func(ctx FluxorContext, msg Message) error {
+		return msg.Reply("pong")
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := eb.Request("bench.echo", "ping", time.Second); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestEventBus_Consumer(t *testing.T) {
 	ctx := context.Background()
 	gocmd := NewGoCMD(ctx)
@@ -163,3 +313,149 @@ func TestConsumer_Handler_FailFast_NilHandlerPanics(t *testing.T) {
 	}()
 	c.Handler(nil)
 }
+
+func TestEventBus_Metrics_DroppedOnFullMailbox(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	// No handler: mailbox is never drained; fill it up front
+	stalled := eb.Consumer("test.stalled")
+	for stalled.(*consumer).mailbox.Send("filler") == nil {
+	}
+
+	before := eb.Metrics()
+	for i := 0; i < 3; i++ {
+		if err := eb.Publish("test.stalled", i); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	got := eb.Metrics()
+	if got.Dropped-before.Dropped != 3 {
+		t.Errorf("Dropped = %d, want 3", got.Dropped-before.Dropped)
+	}
+	if got.Published-before.Published != 3 {
+		t.Errorf("Published = %d, want 3", got.Published-before.Published)
+	}
+}
+
+func TestEventBus_Metrics_Counts(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	eb.Consumer("test.failing").Handler(func(ctx FluxorContext, msg Message) error {
+		defer wg.Done()
+		return errors.New("rejected")
+	})
+	eb.Consumer("test.echo").Handler(func(ctx FluxorContext, msg Message) error {
+		return msg.Reply(msg.Body())
+	})
+
+	if err := eb.Send("test.failing", "a"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if _, err := eb.Request("test.echo", "ping", time.Second); err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	_ = eb.Send("test.nobody", "b")
+	if err := eb.Publish("test.echo", make(chan int)); err == nil {
+		t.Fatal("Publish() of an unencodable body should fail")
+	}
+	wg.Wait()
+
+	got := eb.Metrics()
+	want := EventBusMetrics{Sent: 1, Requests: 1, Replies: 1, Dropped: 1, EncodeErrors: 1, HandlerErrors: 1}
+	// The handler error is counted after the handler returns
+	deadline := time.Now().Add(time.Second)
+	for got != want && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		got = eb.Metrics()
+	}
+	if got != want {
+		t.Errorf("Metrics() = %+v, want %+v", got, want)
+	}
+}
+
+func TestConsumer_Unregister_CancelsHandlerContext(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	eb := gocmd.EventBus()
+	defer eb.Close()
+
+	started := make(chan struct{})
+	returned := make(chan error, 1)
+	consumer := eb.Consumer("test.cancel").Handler(func(ctx FluxorContext, msg Message) error {
+		close(started)
+		select {
+		case <-ctx.Context().Done():
+			returned <- ctx.Context().Err()
+		case <-time.After(5 * time.Second):
+			returned <- errors.New("handler was not cancelled")
+		}
+		return nil
+	})
+
+	if err := eb.Send("test.cancel", "work"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	<-started
+	if err := consumer.Unregister(); err != nil {
+		t.Fatalf("Unregister() error = %v", err)
+	}
+
+	select {
+	case err := <-returned:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("handler context error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler did not return promptly after Unregister")
+	}
+
+	// Other consumers keep a live context
+	other := eb.Consumer("test.cancel.other")
+	defer other.Unregister()
+	live := make(chan error, 1)
+	other.Handler(func(ctx FluxorContext, msg Message) error {
+		live <- ctx.Context().Err()
+		return nil
+	})
+	if err := eb.Send("test.cancel.other", "work"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := <-live; err != nil {
+		t.Errorf("unrelated consumer context error = %v, want nil", err)
+	}
+}
+
+func TestConsumer_Close_CancelsHandlerContext(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	eb := gocmd.EventBus()
+
+	started := make(chan struct{})
+	returned := make(chan struct{})
+	eb.Consumer("test.close").Handler(func(ctx FluxorContext, msg Message) error {
+		close(started)
+		<-ctx.Context().Done()
+		close(returned)
+		return nil
+	})
+	if err := eb.Send("test.close", "work"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	<-started
+
+	closeStart := time.Now()
+	_ = eb.Close()
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("handler did not return after Close")
+	}
+	if elapsed := time.Since(closeStart); elapsed > time.Second {
+		t.Errorf("Close() took %v waiting for the handler", elapsed)
+	}
+}
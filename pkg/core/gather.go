@@ -0,0 +1,81 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// GatherResult is what Gather collected, each address in exactly one list,
+// in the order the addresses were given.
+type GatherResult struct {
+	// Replies are the successful answers
+	Replies []GatherReply
+	// Failures are addresses whose request failed: the handler answered with
+	// Message.Fail (a *ReplyError), no consumer was registered, or the body
+	// could not be sent
+	Failures []GatherFailure
+	// TimedOut are addresses that sent no reply within the timeout
+	TimedOut []string
+}
+
+// GatherReply is a successful answer from Address.
+type GatherReply struct {
+	Address string
+	Message Message
+}
+
+// GatherFailure is a failed request to Address.
+type GatherFailure struct {
+	Address string
+	Err     error
+}
+
+// Complete reports whether every address replied successfully.
+func (r *GatherResult) Complete() bool {
+	return len(r.Failures) == 0 && len(r.TimedOut) == 0
+}
+
+// Gather sends body as a request to each address concurrently and waits up to
+// timeout for all of them. Unlike a single Request it is not all-or-nothing:
+// the result tells successful replies from failures and timeouts, so callers
+// can settle for a quorum or whatever answered in time.
+func Gather(bus EventBus, addresses []string, body interface{}, timeout time.Duration) *GatherResult {
+	type outcome struct {
+		msg Message
+		err error
+	}
+	outcomes := make([]outcome, len(addresses))
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msg, err := bus.Request(address, body, timeout)
+			outcomes[i] = outcome{msg, err}
+		}()
+	}
+	wg.Wait()
+
+	result := &GatherResult{}
+	for i, address := range addresses {
+		switch o := outcomes[i]; {
+		case o.err == nil:
+			result.Replies = append(result.Replies, GatherReply{Address: address, Message: o.msg})
+		case isRequestTimeout(o.err):
+			result.TimedOut = append(result.TimedOut, address)
+		default:
+			result.Failures = append(result.Failures, GatherFailure{Address: address, Err: o.err})
+		}
+	}
+	return result
+}
+
+// isRequestTimeout reports whether a Request error means no reply came in
+// time, on the local bus or a clustered one.
+func isRequestTimeout(err error) bool {
+	return errors.Is(err, ErrTimeout) || errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded)
+}
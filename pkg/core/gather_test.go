@@ -0,0 +1,62 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGather_ClassifiesReplies(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	eb := gocmd.EventBus()
+	defer eb.Close()
+
+	eb.Consumer("quote.fast").Handler(func(ctx FluxorContext, msg Message) error {
+		return msg.Reply(map[string]interface{}{"price": 42})
+	})
+	eb.Consumer("quote.broken").Handler(func(ctx FluxorContext, msg Message) error {
+		return msg.Fail(503, "pricing unavailable")
+	})
+	eb.Consumer("quote.slow").Handler(func(ctx FluxorContext, msg Message) error {
+		return nil // never replies
+	})
+
+	addresses := []string{"quote.fast", "quote.broken", "quote.slow", "quote.missing"}
+	start := time.Now()
+	result := Gather(eb, addresses, "AAPL", 200*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Gather() took %s; the requests should run concurrently", elapsed)
+	}
+
+	if len(result.Replies) != 1 || result.Replies[0].Address != "quote.fast" {
+		t.Fatalf("Replies = %+v, want quote.fast", result.Replies)
+	}
+	var quote map[string]interface{}
+	if err := result.Replies[0].Message.DecodeBody(&quote); err != nil || quote["price"] != float64(42) {
+		t.Errorf("reply body = %v (%v), want price 42", quote, err)
+	}
+
+	if len(result.Failures) != 2 {
+		t.Fatalf("Failures = %+v, want quote.broken and quote.missing", result.Failures)
+	}
+	var replyErr *ReplyError
+	if f := result.Failures[0]; f.Address != "quote.broken" || !errors.As(f.Err, &replyErr) || replyErr.FailureCode != 503 {
+		t.Errorf("Failures[0] = %+v, want quote.broken with a 503 *ReplyError", f)
+	}
+	var busErr *EventBusError
+	if f := result.Failures[1]; f.Address != "quote.missing" || !errors.As(f.Err, &busErr) || busErr.Code != "NO_HANDLERS" {
+		t.Errorf("Failures[1] = %+v, want quote.missing with NO_HANDLERS", f)
+	}
+
+	if len(result.TimedOut) != 1 || result.TimedOut[0] != "quote.slow" {
+		t.Errorf("TimedOut = %v, want quote.slow", result.TimedOut)
+	}
+	if result.Complete() {
+		t.Error("Complete() = true with failures and timeouts")
+	}
+
+	if all := Gather(eb, []string{"quote.fast", "quote.fast"}, "MSFT", time.Second); !all.Complete() || len(all.Replies) != 2 {
+		t.Errorf("Gather() of answering addresses = %+v, want two replies", all)
+	}
+}
@@ -28,6 +28,10 @@ type GoCMD interface {
 
 	// Context returns the root context
 	Context() context.Context
+
+	// Metrics returns the metrics backend components report through
+	// (NoopMetrics when none was configured)
+	Metrics() Metrics
 }
 
 // gocmd implements GoCMD
@@ -48,6 +52,8 @@ type gocmd struct {
 	rootCancel  context.CancelFunc // renamed from 'cancel' for clarity
 	logger      Logger
 	closed      bool // tracks if Close() has been called
+	supervision SupervisionPolicy
+	metrics     Metrics
 }
 
 // GoCMDOptions configures GoCMD construction.
@@ -57,6 +63,14 @@ type GoCMDOptions struct {
 	//
 	// The factory is called after the GoCMD struct is created so implementations can reference GoCMD.
 	EventBusFactory func(ctx context.Context, gocmd GoCMD) (EventBus, error)
+
+	// Supervision controls what happens when a verticle's Start fails or panics.
+	// The zero value is SupervisionStop (mark FAILED and remove the deployment).
+	Supervision SupervisionPolicy
+
+	// Metrics is the backend the EventBus, HTTP servers and workflow engines
+	// report through. Nil disables metrics at near-zero cost.
+	Metrics Metrics
 }
 
 // DeploymentState represents the lifecycle state of a deployed verticle.
@@ -67,7 +81,8 @@ type GoCMDOptions struct {
 //
 //	PENDING (initial state)
 //	  ├─> STARTED (on successful Start())
-//	  ├─> FAILED (on failed Start())
+//	  ├─> PENDING (restart after failed Start(), SupervisionRestart)
+//	  ├─> FAILED (on failed Start(), restarts exhausted)
 //	  └─> STOPPING (during shutdown/Close())
 //
 //	STARTED
@@ -124,6 +139,11 @@ func NewGoCMDWithOptions(ctx context.Context, opts GoCMDOptions) (GoCMD, error)
 		rootCtx:     rootCtx,
 		rootCancel:  rootCancel,
 		logger:      NewDefaultLogger(),
+		supervision: opts.Supervision.withDefaults(),
+		metrics:     opts.Metrics,
+	}
+	if g.metrics == nil {
+		g.metrics = NoopMetrics()
 	}
 
 	if opts.EventBusFactory != nil {
@@ -145,6 +165,10 @@ func (g *gocmd) EventBus() EventBus {
 	return g.eventBus
 }
 
+func (g *gocmd) Metrics() Metrics {
+	return g.metrics
+}
+
 func (g *gocmd) DeployVerticle(verticle Verticle) (string, error) {
 	// Fail-fast: validate verticle immediately
 	if err := ValidateVerticle(verticle); err != nil {
@@ -170,25 +194,63 @@ func (g *gocmd) DeployVerticle(verticle Verticle) (string, error) {
 
 	// Start verticle in goroutine - framework handles blocking operations
 	// Single Start() method - no need for AsyncStart
-	go func() {
-		if err := verticle.Start(fluxorCtx); err != nil {
-			// State machine transition: PENDING -> FAILED
-			// Remove from map on failure (FAILED is terminal state)
+	go g.superviseStart(dep)
+
+	return deploymentID, nil
+}
+
+// superviseStart runs verticle Start with panic isolation and applies the
+// supervision policy on failure.
+func (g *gocmd) superviseStart(dep *deployment) {
+	policy := g.supervision
+
+	for attempt := 0; ; attempt++ {
+		err := startVerticle(dep.verticle, dep.fluxorCtx)
+		if err == nil {
+			// State machine transition: PENDING -> STARTED
 			g.mu.Lock()
-			dep.state = DeploymentStateFailed
-			delete(g.deployments, deploymentID)
+			dep.state = DeploymentStateStarted
 			g.mu.Unlock()
-			g.logger.Error(fmt.Sprintf("verticle start failed for deployment %s: %v", deploymentID, err))
 			return
 		}
+		g.logger.Error(fmt.Sprintf("verticle start failed for deployment %s: %v", dep.id, err))
 
-		// State machine transition: PENDING -> STARTED
-		g.mu.Lock()
-		dep.state = DeploymentStateStarted
-		g.mu.Unlock()
-	}()
+		if policy.Strategy != SupervisionRestart || attempt >= policy.MaxRestarts || g.rootCtx.Err() != nil {
+			g.failDeployment(dep)
+			return
+		}
 
-	return deploymentID, nil
+		// Release whatever the failed Start acquired before trying again
+		if err := stopVerticle(dep.verticle, dep.fluxorCtx); err != nil {
+			g.logger.Error(fmt.Sprintf("verticle stop before restart failed for deployment %s: %v", dep.id, err))
+		}
+
+		backoff := policy.backoff(attempt)
+		g.logger.Info(fmt.Sprintf("restarting deployment %s in %v (restart %d/%d)", dep.id, backoff, attempt+1, policy.MaxRestarts))
+		select {
+		case <-g.rootCtx.Done():
+			g.failDeployment(dep)
+			return
+		case <-time.After(backoff):
+		}
+
+		// Undeployed/closed while waiting - do not restart
+		g.mu.RLock()
+		_, exists := g.deployments[dep.id]
+		g.mu.RUnlock()
+		if !exists {
+			return
+		}
+	}
+}
+
+// failDeployment transitions PENDING -> FAILED and removes the deployment
+// (FAILED is a terminal state).
+func (g *gocmd) failDeployment(dep *deployment) {
+	g.mu.Lock()
+	dep.state = DeploymentStateFailed
+	delete(g.deployments, dep.id)
+	g.mu.Unlock()
 }
 
 // canTransitionToStopping validates if a deployment can transition to STOPPING state.
@@ -257,7 +319,7 @@ func (g *gocmd) UndeployVerticle(deploymentID string) error {
 	// Stop verticle - framework handles blocking operations
 	// Single Stop() method - no need for AsyncStop
 	go func() {
-		if err := dep.verticle.Stop(dep.fluxorCtx); err != nil {
+		if err := stopVerticle(dep.verticle, dep.fluxorCtx); err != nil {
 			g.logger.Error(fmt.Sprintf("verticle stop failed for deployment %s: %v", deploymentID, err))
 		}
 		// State machine transition: STOPPING -> STOPPED (terminal state)
@@ -328,7 +390,7 @@ func (g *gocmd) Close() error {
 			g.mu.Unlock()
 
 			// Stop verticle
-			if err := d.verticle.Stop(d.fluxorCtx); err != nil {
+			if err := stopVerticle(d.verticle, d.fluxorCtx); err != nil {
 				g.logger.Error(fmt.Sprintf("verticle stop failed for deployment %s: %v", id, err))
 			}
 			// State machine transition: STOPPING -> STOPPED (terminal state)
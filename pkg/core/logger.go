@@ -18,12 +18,27 @@ type Logger interface {
 	// Error logs an error message
 	Error(args ...interface{})
 
+	// Warn logs a warning: something unexpected that the caller recovered from
+	Warn(args ...interface{})
+
 	// Info logs an informational message
 	Info(args ...interface{})
 
 	// Debug logs a debug message
 	Debug(args ...interface{})
 
+	// Errorf logs an error message formatted with fmt.Sprintf
+	Errorf(format string, args ...interface{})
+
+	// Warnf logs a warning formatted with fmt.Sprintf
+	Warnf(format string, args ...interface{})
+
+	// Infof logs an informational message formatted with fmt.Sprintf
+	Infof(format string, args ...interface{})
+
+	// Debugf logs a debug message formatted with fmt.Sprintf
+	Debugf(format string, args ...interface{})
+
 	// WithFields returns a new logger with structured fields
 	// This enables structured logging with key-value pairs
 	WithFields(fields map[string]interface{}) Logger
@@ -37,7 +52,7 @@ type Logger interface {
 type LoggerConfig struct {
 	// JSONOutput enables JSON structured output
 	JSONOutput bool
-	// Level sets the minimum log level (DEBUG, INFO, ERROR)
+	// Level sets the minimum log level (DEBUG, INFO, WARN, ERROR)
 	Level string
 	// AppendLogStore enables persistent logging to append-only log store
 	// If nil, logs are only written to console
@@ -51,6 +66,7 @@ type LoggerConfig struct {
 // Now supports optional append-only log persistence
 type defaultLogger struct {
 	errorLogger *log.Logger
+	warnLogger  *log.Logger
 	infoLogger  *log.Logger
 	debugLogger *log.Logger
 	config      LoggerConfig
@@ -71,6 +87,7 @@ func NewDefaultLogger() Logger {
 func NewLogger(config LoggerConfig) Logger {
 	return &defaultLogger{
 		errorLogger: log.New(os.Stderr, "[ERROR] ", log.LstdFlags|log.Lshortfile),
+		warnLogger:  log.New(os.Stderr, "[WARN] ", log.LstdFlags|log.Lshortfile),
 		infoLogger:  log.New(os.Stdout, "[INFO] ", log.LstdFlags|log.Lshortfile),
 		debugLogger: log.New(os.Stdout, "[DEBUG] ", log.LstdFlags|log.Lshortfile),
 		config:      config,
@@ -124,7 +141,7 @@ func (l *defaultLogger) log(level string, logger *log.Logger, message string) {
 	}
 
 	// Write to console (stdout/stderr)
-	// Use depth 2 to skip log() and the level-specific method (Error/Info/etc)
+	// Use depth 2 to skip log() and the level-specific method (Error/Infof/etc)
 	if l.config.JSONOutput {
 		jsonData, err := json.Marshal(entry)
 		if err == nil {
@@ -182,7 +199,8 @@ func (l *defaultLogger) shouldLog(level string) bool {
 	levels := map[string]int{
 		"DEBUG": 0,
 		"INFO":  1,
-		"ERROR": 2,
+		"WARN":  2,
+		"ERROR": 3,
 	}
 
 	configLevel, ok := levels[l.config.Level]
@@ -203,6 +221,11 @@ func (l *defaultLogger) Error(args ...interface{}) {
 	l.log("ERROR", l.errorLogger, fmt.Sprint(args...))
 }
 
+// Warn logs a warning
+func (l *defaultLogger) Warn(args ...interface{}) {
+	l.log("WARN", l.warnLogger, fmt.Sprint(args...))
+}
+
 // Info logs an informational message
 func (l *defaultLogger) Info(args ...interface{}) {
 	l.log("INFO", l.infoLogger, fmt.Sprint(args...))
@@ -213,6 +236,26 @@ func (l *defaultLogger) Debug(args ...interface{}) {
 	l.log("DEBUG", l.debugLogger, fmt.Sprint(args...))
 }
 
+// Errorf logs a formatted error message
+func (l *defaultLogger) Errorf(format string, args ...interface{}) {
+	l.log("ERROR", l.errorLogger, fmt.Sprintf(format, args...))
+}
+
+// Warnf logs a formatted warning
+func (l *defaultLogger) Warnf(format string, args ...interface{}) {
+	l.log("WARN", l.warnLogger, fmt.Sprintf(format, args...))
+}
+
+// Infof logs a formatted informational message
+func (l *defaultLogger) Infof(format string, args ...interface{}) {
+	l.log("INFO", l.infoLogger, fmt.Sprintf(format, args...))
+}
+
+// Debugf logs a formatted debug message
+func (l *defaultLogger) Debugf(format string, args ...interface{}) {
+	l.log("DEBUG", l.debugLogger, fmt.Sprintf(format, args...))
+}
+
 // WithFields returns a new logger with structured fields
 // Fields are included in all subsequent log entries
 func (l *defaultLogger) WithFields(fields map[string]interface{}) Logger {
@@ -227,6 +270,7 @@ func (l *defaultLogger) WithFields(fields map[string]interface{}) Logger {
 	}
 	return &defaultLogger{
 		errorLogger: l.errorLogger,
+		warnLogger:  l.warnLogger,
 		infoLogger:  l.infoLogger,
 		debugLogger: l.debugLogger,
 		config:      l.config,
@@ -251,6 +295,7 @@ func (l *defaultLogger) WithContext(ctx context.Context) Logger {
 
 	return &defaultLogger{
 		errorLogger: l.errorLogger,
+		warnLogger:  l.warnLogger,
 		infoLogger:  l.infoLogger,
 		debugLogger: l.debugLogger,
 		config:      l.config,
@@ -317,6 +362,26 @@ func Error(args ...interface{}) {
 	defaultLoggerInstance.Error(fmt.Sprint(args...))
 }
 
+// Warn logs a warning with format support
+// Supports both: core.Warn("message") and core.Warn("format %s", arg)
+func Warn(args ...interface{}) {
+	defaultLoggerOnce.Do(initDefaultLogger)
+	if len(args) == 0 {
+		return
+	}
+
+	// Smart detection: if first arg is string with format specifiers and has more args, use Sprintf
+	if len(args) > 1 {
+		if format, ok := args[0].(string); ok && hasFormatSpecifiers(format) {
+			defaultLoggerInstance.Warn(fmt.Sprintf(format, args[1:]...))
+			return
+		}
+	}
+
+	// Otherwise, use Sprint (works for plain messages and non-format cases)
+	defaultLoggerInstance.Warn(fmt.Sprint(args...))
+}
+
 // Info logs an informational message with format support
 // Supports both: core.Info("message") and core.Info("format %s", arg)
 func Info(args ...interface{}) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"strings"
 	"testing"
 )
@@ -136,3 +137,79 @@ func TestJSONLoggerOutput(t *testing.T) {
 		t.Error("JSON output should contain fields")
 	}
 }
+
+// captureLogger returns a plain-text logger at level whose output, for every
+// level, is written to the returned buffer.
+func captureLogger(level string) (Logger, *strings.Builder) {
+	var buf strings.Builder
+	logger := NewLogger(LoggerConfig{Level: level}).(*defaultLogger)
+	logger.errorLogger = log.New(&buf, "[ERROR] ", 0)
+	logger.warnLogger = log.New(&buf, "[WARN] ", 0)
+	logger.infoLogger = log.New(&buf, "[INFO] ", 0)
+	logger.debugLogger = log.New(&buf, "[DEBUG] ", 0)
+	return logger, &buf
+}
+
+func TestLoggerLevelFiltering(t *testing.T) {
+	tests := []struct {
+		level string
+		want  []string
+	}{
+		{"DEBUG", []string{"[ERROR]", "[WARN]", "[INFO]", "[DEBUG]"}},
+		{"INFO", []string{"[ERROR]", "[WARN]", "[INFO]"}},
+		{"WARN", []string{"[ERROR]", "[WARN]"}},
+		{"ERROR", []string{"[ERROR]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			logger, buf := captureLogger(tt.level)
+			logger.Error("e")
+			logger.Warn("w")
+			logger.Info("i")
+			logger.Debug("d")
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("level %s logged %q, want %d lines", tt.level, lines, len(tt.want))
+			}
+			for i, prefix := range tt.want {
+				if !strings.HasPrefix(lines[i], prefix) {
+					t.Errorf("line %d = %q, want prefix %s", i, lines[i], prefix)
+				}
+			}
+		})
+	}
+}
+
+func TestLoggerFormattedMethods(t *testing.T) {
+	logger, buf := captureLogger("DEBUG")
+	logger.Errorf("error %d", 1)
+	logger.Warnf("warn %s", "two")
+	logger.Infof("info %v", true)
+	logger.Debugf("debug %.1f", 4.0)
+
+	want := "[ERROR] error 1\n[WARN] warn two\n[INFO] info true\n[DEBUG] debug 4.0\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestLoggerFormattedMethods_Derived(t *testing.T) {
+	logger, buf := captureLogger("WARN")
+	ctx := WithRequestID(context.Background(), "req-1")
+	derived := logger.WithFields(map[string]interface{}{"user_id": "123"}).WithContext(ctx)
+
+	derived.Warnf("slow request: %dms", 900)
+	derived.Infof("filtered %s", "out")
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "[WARN] slow request: 900ms") {
+		t.Errorf("output = %q, want the warning", got)
+	}
+	if !strings.Contains(got, "user_id:123") || !strings.Contains(got, "request_id:req-1") {
+		t.Errorf("output = %q, want inherited fields", got)
+	}
+	if strings.Contains(got, "filtered") {
+		t.Errorf("output = %q, Infof should be filtered at WARN", got)
+	}
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"strings"
+	"time"
+)
+
+// Metrics is the backend the framework reports its metrics through.
+//
+// Inject one implementation via GoCMDOptions.Metrics and the EventBus, HTTP
+// servers and workflow engines created on that GoCMD all report through it.
+// Adapters for Prometheus and OpenTelemetry live in pkg/observability; any
+// other system can be bridged by implementing these three methods.
+//
+// Instruments are declared once with their label names and observed with
+// label values in the same order. Implementations must return the same
+// instrument when asked twice for the same name.
+type Metrics interface {
+	Counter(name, help string, labelNames ...string) Counter
+	Gauge(name, help string, labelNames ...string) Gauge
+	Histogram(name, help string, labelNames ...string) Histogram
+}
+
+// Counter is a monotonically increasing value.
+type Counter interface {
+	Add(delta float64, labelValues ...string)
+}
+
+// Gauge is a value that can go up and down.
+type Gauge interface {
+	Set(value float64, labelValues ...string)
+}
+
+// Histogram records a distribution of observations (durations in seconds).
+type Histogram interface {
+	Observe(value float64, labelValues ...string)
+}
+
+// NoopMetrics returns a Metrics that discards everything. It is the default
+// when no backend is configured.
+func NoopMetrics() Metrics {
+	return noopMetrics{}
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) Counter(string, string, ...string) Counter     { return noopInstrument{} }
+func (noopMetrics) Gauge(string, string, ...string) Gauge         { return noopInstrument{} }
+func (noopMetrics) Histogram(string, string, ...string) Histogram { return noopInstrument{} }
+
+type noopInstrument struct{}
+
+func (noopInstrument) Add(float64, ...string)     {}
+func (noopInstrument) Set(float64, ...string)     {}
+func (noopInstrument) Observe(float64, ...string) {}
+
+// IsNoopMetrics reports whether m discards everything (nil or NoopMetrics).
+// Instrumented components use it to skip label formatting entirely.
+func IsNoopMetrics(m Metrics) bool {
+	if m == nil {
+		return true
+	}
+	_, ok := m.(noopMetrics)
+	return ok
+}
+
+// eventBusMetrics are the instruments of an EventBus.
+// A nil *eventBusMetrics (no backend) makes every method a no-op.
+type eventBusMetrics struct {
+	messages        Counter   // fluxor_eventbus_deliveries_total{address,type}
+	undelivered     Counter   // fluxor_eventbus_undelivered_total{address,reason}
+	handlerDuration Histogram // fluxor_eventbus_handler_duration_seconds{address}
+	handlerErrors   Counter   // fluxor_eventbus_handler_errors_total{address}
+	mailboxDepth    Gauge     // fluxor_eventbus_mailbox_depth{address}
+}
+
+func newEventBusMetrics(m Metrics) *eventBusMetrics {
+	if IsNoopMetrics(m) {
+		return nil
+	}
+	return &eventBusMetrics{
+		messages:        m.Counter("fluxor_eventbus_deliveries_total", "EventBus messages sent, by address and type (publish, send, request)", "address", "type"),
+		undelivered:     m.Counter("fluxor_eventbus_undelivered_total", "EventBus messages that could not be delivered, by address and reason", "address", "reason"),
+		handlerDuration: m.Histogram("fluxor_eventbus_handler_duration_seconds", "EventBus handler duration in seconds", "address"),
+		handlerErrors:   m.Counter("fluxor_eventbus_handler_errors_total", "EventBus handlers that returned an error or panicked", "address"),
+		mailboxDepth:    m.Gauge("fluxor_eventbus_mailbox_depth", "Messages waiting in the mailboxes of an address's consumers", "address"),
+	}
+}
+
+// metricsFor returns the Metrics of gocmd (no-op when gocmd is nil).
+func metricsFor(gocmd GoCMD) Metrics {
+	if gocmd == nil {
+		return nil
+	}
+	return gocmd.Metrics()
+}
+
+// metricAddress collapses per-request reply addresses into one label value
+// so they do not explode metric cardinality.
+func metricAddress(address string) string {
+	if isReplyAddress(address) {
+		return "reply"
+	}
+	return address
+}
+
+// isReplyAddress reports whether address is a temporary Request reply address.
+func isReplyAddress(address string) bool {
+	return strings.HasPrefix(address, "reply.")
+}
+
+func (m *eventBusMetrics) message(address, kind string) {
+	if m == nil {
+		return
+	}
+	m.messages.Add(1, metricAddress(address), kind)
+}
+
+func (m *eventBusMetrics) undeliverable(address, reason string) {
+	if m == nil {
+		return
+	}
+	m.undelivered.Add(1, metricAddress(address), reason)
+}
+
+func (m *eventBusMetrics) handled(address string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	address = metricAddress(address)
+	m.handlerDuration.Observe(time.Since(start).Seconds(), address)
+	if err != nil {
+		m.handlerErrors.Add(1, address)
+	}
+}
+
+func (m *eventBusMetrics) mailbox(address string, depth int) {
+	if m == nil {
+		return
+	}
+	m.mailboxDepth.Set(float64(depth), metricAddress(address))
+}
@@ -0,0 +1,195 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMetrics keeps every observation keyed by "name{v1,v2}".
+type recordingMetrics struct {
+	mu     sync.Mutex
+	values map[string]float64
+	counts map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{values: make(map[string]float64), counts: make(map[string]int)}
+}
+
+func (r *recordingMetrics) record(name string, value float64, add bool, labelValues []string) {
+	key := name + "{" + strings.Join(labelValues, ",") + "}"
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if add {
+		r.values[key] += value
+	} else {
+		r.values[key] = value
+	}
+	r.counts[key]++
+}
+
+func (r *recordingMetrics) value(key string) float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.values[key]
+}
+
+func (r *recordingMetrics) count(key string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counts[key]
+}
+
+type recordingInstrument struct {
+	r    *recordingMetrics
+	name string
+}
+
+func (i recordingInstrument) Add(delta float64, lv ...string) { i.r.record(i.name, delta, true, lv) }
+func (i recordingInstrument) Set(value float64, lv ...string) { i.r.record(i.name, value, false, lv) }
+func (i recordingInstrument) Observe(value float64, lv ...string) {
+	i.r.record(i.name, value, false, lv)
+}
+
+func (r *recordingMetrics) Counter(name, _ string, _ ...string) Counter {
+	return recordingInstrument{r, name}
+}
+func (r *recordingMetrics) Gauge(name, _ string, _ ...string) Gauge {
+	return recordingInstrument{r, name}
+}
+func (r *recordingMetrics) Histogram(name, _ string, _ ...string) Histogram {
+	return recordingInstrument{r, name}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestEventBus_Metrics(t *testing.T) {
+	rec := newRecordingMetrics()
+	gocmd, err := NewGoCMDWithOptions(context.Background(), GoCMDOptions{Metrics: rec})
+	if err != nil {
+		t.Fatalf("NewGoCMDWithOptions() error = %v", err)
+	}
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	eb.Consumer("orders").Handler(func(ctx FluxorContext, msg Message) error {
+		return errors.New("rejected")
+	})
+	eb.Consumer("echo").Handler(func(ctx FluxorContext, msg Message) error {
+		return msg.Reply(msg.Body())
+	})
+
+	if err := eb.Publish("orders", "a"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := eb.Send("orders", "b"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if _, err := eb.Request("echo", "ping", time.Second); err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	_ = eb.Send("nobody", "c")
+
+	waitFor(t, func() bool { return rec.count("fluxor_eventbus_handler_duration_seconds{orders}") == 2 })
+
+	for key, want := range map[string]float64{
+		"fluxor_eventbus_deliveries_total{orders,publish}":                             1,
+		"fluxor_eventbus_deliveries_total{orders,send}":                                1,
+		"fluxor_eventbus_deliveries_total{echo,request}":                               1,
+		"fluxor_eventbus_handler_errors_total{orders}":                                 2,
+		"fluxor_eventbus_undelivered_total{nobody," + DeadLetterReasonNoHandlers + "}": 1,
+	} {
+		if got := rec.value(key); got != want {
+			t.Errorf("%s = %v, want %v", key, got, want)
+		}
+	}
+	if n := rec.count("fluxor_eventbus_handler_duration_seconds{echo}"); n != 1 {
+		t.Errorf("echo handler observed %d times, want 1", n)
+	}
+}
+
+func TestEventBusMetrics_NilIsFree(t *testing.T) {
+	if m := newEventBusMetrics(nil); m != nil {
+		t.Fatalf("newEventBusMetrics(nil) = %v, want nil", m)
+	}
+	if m := newEventBusMetrics(NoopMetrics()); m != nil {
+		t.Fatalf("newEventBusMetrics(NoopMetrics()) = %v, want nil", m)
+	}
+
+	var m *eventBusMetrics
+	start := time.Time{}
+	allocs := testing.AllocsPerRun(100, func() {
+		m.message("orders", "send")
+		m.undeliverable("orders", DeadLetterReasonNoHandlers)
+		m.handled("orders", start, nil)
+	})
+	if allocs != 0 {
+		t.Errorf("nil metrics allocate %v per call, want 0", allocs)
+	}
+}
+
+func TestGoCMD_MetricsDefault(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	if !IsNoopMetrics(gocmd.Metrics()) {
+		t.Errorf("default Metrics() = %T, want no-op", gocmd.Metrics())
+	}
+}
+
+// benchmarkSend measures Send plus handling through one consumer.
+func benchmarkSend(b *testing.B, metrics Metrics) {
+	gocmd, err := NewGoCMDWithOptions(context.Background(), GoCMDOptions{Metrics: metrics})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	var wg sync.WaitGroup
+	eb.Consumer("bench").Handler(func(ctx FluxorContext, msg Message) error {
+		wg.Done()
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		wg.Add(1)
+		_ = eb.Send("bench", i)
+		wg.Wait()
+	}
+}
+
+// BenchmarkEventBus_Send_NoMetrics is the baseline: no backend configured.
+func BenchmarkEventBus_Send_NoMetrics(b *testing.B) {
+	benchmarkSend(b, nil)
+}
+
+// BenchmarkEventBus_Send_Metrics shows the cost of a (trivial) backend.
+func BenchmarkEventBus_Send_Metrics(b *testing.B) {
+	benchmarkSend(b, newRecordingMetrics())
+}
+
+// BenchmarkEventBusMetrics_Nil is the per-call instrumentation overhead when unset.
+func BenchmarkEventBusMetrics_Nil(b *testing.B) {
+	var m *eventBusMetrics
+	start := time.Time{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m.message("bench", "send")
+		m.handled("bench", start, nil)
+	}
+}
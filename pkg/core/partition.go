@@ -0,0 +1,127 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core/concurrency"
+	"github.com/fluxorio/fluxor/pkg/core/failfast"
+)
+
+// HeaderPartitionKey carries the partition key of a message (see Partitioned).
+const HeaderPartitionKey = "x-partition-key"
+
+// Partitioned wraps body so the message is sent with HeaderPartitionKey set
+// to key. Consumers created WithPartitions handle messages with the same key
+// one at a time, in the order they were published:
+//
+//	eb.Publish("orders", core.Partitioned(order.CustomerID, order))
+//
+// An empty key sends body unchanged (no partition).
+func Partitioned(key string, body interface{}) interface{} {
+	if key == "" {
+		return body
+	}
+	return partitionedBody{key: key, body: body}
+}
+
+type partitionedBody struct {
+	key  string
+	body interface{}
+}
+
+// partitionKeyOf unwraps a Partitioned body, returning its key and the real body.
+func partitionKeyOf(body interface{}) (string, interface{}) {
+	if p, ok := body.(partitionedBody); ok {
+		return p.key, p.body
+	}
+	return "", body
+}
+
+// WithPartitions makes the consumer run n handlers in parallel while keeping
+// messages with the same HeaderPartitionKey in order: every key is hashed to
+// one of n partitions, and each partition handles its messages one at a time.
+// Messages without a key are spread round-robin and have no ordering.
+//
+// A busy partition applies backpressure to the consumer mailbox, so a slow key
+// delays the keys behind it rather than reordering them.
+// Fail-fast: panics if n is not positive.
+// Only the in-memory EventBus orders per key; clustered buses carry the header
+// but keep their own delivery order.
+func WithPartitions(n int) ConsumerOption {
+	if n <= 0 {
+		failfast.Err(&EventBusError{Code: "INVALID_INPUT", Message: "partition count must be positive"})
+	}
+	return func(o *consumerOptions) {
+		o.partitions = n
+	}
+}
+
+// consumerPartitions are the keyed sub-mailboxes of a partitioned consumer.
+// The consumer loop dispatches into them; one worker per partition handles.
+type consumerPartitions struct {
+	mailboxes []concurrency.Mailbox
+	executor  concurrency.Executor
+	next      uint32 // round-robin cursor for keyless messages (dispatcher only)
+}
+
+func newConsumerPartitions(c *consumer, n, mailboxSize int) *consumerPartitions {
+	p := &consumerPartitions{
+		mailboxes: make([]concurrency.Mailbox, n),
+		executor:  concurrency.NewExecutor(c.eventBus.ctx, concurrency.ExecutorConfig{Workers: n, QueueSize: n}),
+	}
+	for i := range p.mailboxes {
+		mailbox := concurrency.NewBoundedMailbox(mailboxSize)
+		p.mailboxes[i] = mailbox
+		task := concurrency.NewNamedTask(
+			fmt.Sprintf("eventbus-consumer-%s-partition-%d", c.address, i),
+			func(ctx context.Context) error {
+				for {
+					msg, err := mailbox.Receive(ctx)
+					if err != nil {
+						return err
+					}
+					c.handle(msg.(Message))
+				}
+			},
+		)
+		// The executor has exactly one worker and one queue slot per partition
+		_ = p.executor.Submit(task)
+	}
+	return p
+}
+
+// dispatch hands msg to its partition, waiting while that partition is full.
+func (p *consumerPartitions) dispatch(ctx context.Context, msg Message) error {
+	var i uint32
+	if key := msg.Headers()[HeaderPartitionKey]; key != "" {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		i = h.Sum32() % uint32(len(p.mailboxes))
+	} else {
+		i = p.next % uint32(len(p.mailboxes))
+		p.next++
+	}
+	return p.mailboxes[i].SendContext(ctx, msg)
+}
+
+// pending returns the messages waiting in the partitions.
+func (p *consumerPartitions) pending() int {
+	n := 0
+	for _, mailbox := range p.mailboxes {
+		n += mailbox.Size()
+	}
+	return n
+}
+
+// close stops the partition workers once their queued messages are handled.
+func (p *consumerPartitions) close() {
+	for _, mailbox := range p.mailboxes {
+		mailbox.Close()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = p.executor.Shutdown(ctx)
+}
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventBus_Partitioned_OrderPerKey(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	const perKey = 50
+	keys := []string{"customer-1", "customer-2", "customer-3", "customer-4"}
+
+	var mu sync.Mutex
+	seen := make(map[string][]int)
+	var wg sync.WaitGroup
+	wg.Add(perKey * len(keys))
+	eb.Consumer("orders", WithPartitions(4), WithMailboxSize(1000)).Handler(func(ctx FluxorContext, msg Message) error {
+		defer wg.Done()
+		var body struct{ Seq int }
+		if err := msg.DecodeBody(&body); err != nil {
+			return err
+		}
+		key := msg.Headers()[HeaderPartitionKey]
+		// Uneven handler times would reorder an unpartitioned parallel consumer
+		time.Sleep(time.Duration(body.Seq%3) * 100 * time.Microsecond)
+		mu.Lock()
+		seen[key] = append(seen[key], body.Seq)
+		mu.Unlock()
+		return nil
+	})
+
+	for seq := 0; seq < perKey; seq++ {
+		for _, key := range keys {
+			if err := eb.Publish("orders", Partitioned(key, map[string]int{"Seq": seq})); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+		}
+	}
+	waitGroupTimeout(t, &wg, 5*time.Second)
+
+	for _, key := range keys {
+		got := seen[key]
+		if len(got) != perKey {
+			t.Fatalf("%s: handled %d messages, want %d", key, len(got), perKey)
+		}
+		for i, seq := range got {
+			if seq != i {
+				t.Fatalf("%s: handled out of order: %v", key, got)
+			}
+		}
+	}
+}
+
+func TestEventBus_Partitioned_KeysRunInParallel(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	slow, fast := keysInDifferentPartitions(2)
+	fastDone := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	eb.Consumer("jobs", WithPartitions(2)).Handler(func(ctx FluxorContext, msg Message) error {
+		defer wg.Done()
+		if msg.Headers()[HeaderPartitionKey] == slow {
+			// Only finishes once the other key was handled concurrently
+			select {
+			case <-fastDone:
+			case <-time.After(2 * time.Second):
+				t.Error("the other key was blocked behind the slow one")
+			}
+			return nil
+		}
+		close(fastDone)
+		return nil
+	})
+
+	if err := eb.Send("jobs", Partitioned(slow, "slow")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := eb.Send("jobs", Partitioned(fast, "fast")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	waitGroupTimeout(t, &wg, 5*time.Second)
+}
+
+func TestPartitioned_EmptyKey(t *testing.T) {
+	if body := Partitioned("", "plain"); body != "plain" {
+		t.Errorf("Partitioned(\"\", body) = %v, want body unchanged", body)
+	}
+	key, body := partitionKeyOf(Partitioned("k", 42))
+	if key != "k" || body != 42 {
+		t.Errorf("partitionKeyOf() = %q, %v; want \"k\", 42", key, body)
+	}
+}
+
+// keysInDifferentPartitions returns two keys that hash to different partitions of n.
+func keysInDifferentPartitions(n uint32) (string, string) {
+	partition := func(key string) uint32 {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(key))
+		return h.Sum32() % n
+	}
+	first := "key-0"
+	for i := 1; ; i++ {
+		other := fmt.Sprintf("key-%d", i)
+		if partition(other) != partition(first) {
+			return first, other
+		}
+	}
+}
+
+func waitGroupTimeout(t *testing.T, wg *sync.WaitGroup, timeout time.Duration) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for handlers")
+	}
+}
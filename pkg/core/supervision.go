@@ -0,0 +1,90 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// SupervisionStrategy decides what happens to a deployment whose Start fails or panics.
+type SupervisionStrategy int
+
+const (
+	// SupervisionStop marks the deployment FAILED and removes it (default).
+	SupervisionStop SupervisionStrategy = iota
+
+	// SupervisionRestart stops the verticle and calls Start again after a backoff,
+	// up to MaxRestarts times; then the deployment is marked FAILED.
+	SupervisionRestart
+)
+
+// Supervision policy defaults (applied when the corresponding field is zero)
+const (
+	DefaultSupervisionMaxRestarts    = 3
+	DefaultSupervisionInitialBackoff = 100 * time.Millisecond
+	DefaultSupervisionMaxBackoff     = 10 * time.Second
+)
+
+// SupervisionPolicy configures how GoCMD reacts to verticle start failures.
+//
+// Panics in Start (and Stop) are always recovered so one verticle cannot take
+// down the process; a panic in Start counts as a start failure.
+type SupervisionPolicy struct {
+	Strategy SupervisionStrategy
+
+	// MaxRestarts is the number of restarts before giving up (SupervisionRestart only).
+	MaxRestarts int
+
+	// InitialBackoff is the delay before the first restart; it doubles per
+	// restart up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// withDefaults fills zero fields with the package defaults
+func (p SupervisionPolicy) withDefaults() SupervisionPolicy {
+	if p.MaxRestarts <= 0 {
+		p.MaxRestarts = DefaultSupervisionMaxRestarts
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultSupervisionInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultSupervisionMaxBackoff
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = p.InitialBackoff
+	}
+	return p
+}
+
+// backoff returns the delay before restart number attempt (0-based)
+func (p SupervisionPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 0; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// startVerticle calls verticle.Start, converting a panic into a VERTICLE_PANIC error
+func startVerticle(verticle Verticle, ctx FluxorContext) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &EventBusError{Code: "VERTICLE_PANIC", Message: fmt.Sprintf("verticle panicked in Start: %v", r)}
+		}
+	}()
+	return verticle.Start(ctx)
+}
+
+// stopVerticle calls verticle.Stop, converting a panic into a VERTICLE_PANIC error
+func stopVerticle(verticle Verticle, ctx FluxorContext) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &EventBusError{Code: "VERTICLE_PANIC", Message: fmt.Sprintf("verticle panicked in Stop: %v", r)}
+		}
+	}()
+	return verticle.Stop(ctx)
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// panickingVerticle panics in the first panics calls to Start, then starts normally.
+type panickingVerticle struct {
+	panics int32
+	starts atomic.Int32
+	stops  atomic.Int32
+}
+
+func (v *panickingVerticle) Start(ctx FluxorContext) error {
+	if v.starts.Add(1) <= v.panics {
+		panic("boom")
+	}
+	return nil
+}
+
+func (v *panickingVerticle) Stop(ctx FluxorContext) error {
+	v.stops.Add(1)
+	return nil
+}
+
+func waitForDeploymentCount(t *testing.T, gocmd GoCMD, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if gocmd.DeploymentCount() == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("DeploymentCount() = %d, want %d", gocmd.DeploymentCount(), want)
+}
+
+func TestGoCMD_StartPanic_StopStrategy(t *testing.T) {
+	gocmd := NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	verticle := &panickingVerticle{panics: 1}
+	if _, err := gocmd.DeployVerticle(verticle); err != nil {
+		t.Fatalf("DeployVerticle() error = %v", err)
+	}
+
+	// Process survives; deployment is marked failed and removed, no restart
+	waitForDeploymentCount(t, gocmd, 0)
+	time.Sleep(50 * time.Millisecond)
+	if got := verticle.starts.Load(); got != 1 {
+		t.Errorf("Start calls = %d, want 1 (no restart by default)", got)
+	}
+}
+
+func TestGoCMD_StartPanic_RestartStrategy(t *testing.T) {
+	gocmd, err := NewGoCMDWithOptions(context.Background(), GoCMDOptions{
+		Supervision: SupervisionPolicy{
+			Strategy:       SupervisionRestart,
+			MaxRestarts:    3,
+			InitialBackoff: 10 * time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewGoCMDWithOptions() error = %v", err)
+	}
+	defer gocmd.Close()
+
+	verticle := &panickingVerticle{panics: 2}
+	if _, err := gocmd.DeployVerticle(verticle); err != nil {
+		t.Fatalf("DeployVerticle() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) && verticle.starts.Load() < 3 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := verticle.starts.Load(); got != 3 {
+		t.Fatalf("Start calls = %d, want 3 (2 panics + successful restart)", got)
+	}
+	if got := verticle.stops.Load(); got != 2 {
+		t.Errorf("Stop calls before restarts = %d, want 2", got)
+	}
+	waitForDeploymentCount(t, gocmd, 1)
+}
+
+func TestGoCMD_StartPanic_RestartsExhausted(t *testing.T) {
+	gocmd, err := NewGoCMDWithOptions(context.Background(), GoCMDOptions{
+		Supervision: SupervisionPolicy{
+			Strategy:       SupervisionRestart,
+			MaxRestarts:    2,
+			InitialBackoff: 5 * time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewGoCMDWithOptions() error = %v", err)
+	}
+	defer gocmd.Close()
+
+	verticle := &panickingVerticle{panics: 100}
+	if _, err := gocmd.DeployVerticle(verticle); err != nil {
+		t.Fatalf("DeployVerticle() error = %v", err)
+	}
+
+	waitForDeploymentCount(t, gocmd, 0)
+	if got := verticle.starts.Load(); got != 3 {
+		t.Errorf("Start calls = %d, want 3 (initial + MaxRestarts)", got)
+	}
+}
+
+func TestSupervisionPolicy_Backoff(t *testing.T) {
+	p := SupervisionPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}.withDefaults()
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for attempt, w := range want {
+		if got := p.backoff(attempt); got != w {
+			t.Errorf("backoff(%d) = %v, want %v", attempt, got, w)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core/failfast"
+)
+
+// TenantAddressPrefix starts every address of a tenant-scoped event bus:
+// tenant "acme" publishing to "orders.created" uses "tenant.acme.orders.created".
+const TenantAddressPrefix = "tenant."
+
+// tenantIDKey is the context key for the tenant ID
+type tenantIDKey struct{}
+
+// WithTenant adds a tenant ID to the context (see ScopedEventBus)
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey{}, tenantID)
+}
+
+// GetTenantID retrieves the tenant ID from context
+func GetTenantID(ctx context.Context) string {
+	if id, ok := ctx.Value(tenantIDKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// ValidateTenantID validates a tenant ID. IDs cannot contain dots, so two
+// tenants never share an address.
+func ValidateTenantID(tenantID string) error {
+	if tenantID == "" {
+		return &EventBusError{Code: "INVALID_TENANT", Message: "tenant ID cannot be empty"}
+	}
+	if strings.Contains(tenantID, ".") {
+		return &EventBusError{Code: "INVALID_TENANT", Message: fmt.Sprintf("tenant ID %q cannot contain '.'", tenantID)}
+	}
+	return nil
+}
+
+// ScopedEventBus returns bus scoped to the tenant of ctx, or bus itself when
+// ctx carries no tenant. Returns error if the tenant ID is invalid.
+func ScopedEventBus(ctx context.Context, bus EventBus) (EventBus, error) {
+	tenantID := GetTenantID(ctx)
+	if tenantID == "" {
+		return bus, nil
+	}
+	return NewTenantEventBus(bus, tenantID)
+}
+
+// TenantEventBus isolates one tenant on a shared event bus. Publish, Send,
+// Request and Consumer addresses are namespaced under the tenant, so tenants
+// using the same address never receive each other's messages. Handlers of its
+// consumers get a FluxorContext whose EventBus is this bus and whose Context
+// carries the tenant ID.
+//
+// Cross-tenant system messages, codec changes and bus-wide metrics go
+// through Unscoped.
+type TenantEventBus struct {
+	EventBus
+	tenantID string
+}
+
+// NewTenantEventBus scopes bus to tenantID.
+// Returns error if bus is nil or tenantID is invalid.
+func NewTenantEventBus(bus EventBus, tenantID string) (*TenantEventBus, error) {
+	if bus == nil {
+		return nil, &EventBusError{Code: "INVALID_EVENTBUS", Message: "event bus cannot be nil"}
+	}
+	if err := ValidateTenantID(tenantID); err != nil {
+		return nil, err
+	}
+	// Scoping a scoped bus switches tenants rather than nesting them
+	if scoped, ok := bus.(*TenantEventBus); ok {
+		bus = scoped.EventBus
+	}
+	return &TenantEventBus{EventBus: bus, tenantID: tenantID}, nil
+}
+
+// TenantID returns the tenant the bus is scoped to.
+func (b *TenantEventBus) TenantID() string {
+	return b.tenantID
+}
+
+// Unscoped returns the shared event bus, for system messages that cross
+// tenants. Addresses are used as given.
+func (b *TenantEventBus) Unscoped() EventBus {
+	return b.EventBus
+}
+
+// Address returns the shared-bus address of a tenant address.
+func (b *TenantEventBus) Address(address string) string {
+	return TenantAddressPrefix + b.tenantID + "." + address
+}
+
+// Publish implements EventBus.
+func (b *TenantEventBus) Publish(address string, body interface{}) error {
+	if err := ValidateAddress(address); err != nil {
+		return err
+	}
+	return b.EventBus.Publish(b.Address(address), body)
+}
+
+// Send implements EventBus.
+func (b *TenantEventBus) Send(address string, body interface{}) error {
+	if err := ValidateAddress(address); err != nil {
+		return err
+	}
+	return b.EventBus.Send(b.Address(address), body)
+}
+
+// SendWithTimeout implements EventBus.
+func (b *TenantEventBus) SendWithTimeout(address string, body interface{}, timeout time.Duration) error {
+	if err := ValidateAddress(address); err != nil {
+		return err
+	}
+	return b.EventBus.SendWithTimeout(b.Address(address), body, timeout)
+}
+
+// Request implements EventBus.
+func (b *TenantEventBus) Request(address string, body interface{}, timeout time.Duration) (Message, error) {
+	if err := ValidateAddress(address); err != nil {
+		return nil, err
+	}
+	return b.EventBus.Request(b.Address(address), body, timeout)
+}
+
+// Consumer implements EventBus. Like the shared bus, it panics if address is
+// invalid.
+func (b *TenantEventBus) Consumer(address string, opts ...ConsumerOption) Consumer {
+	if err := ValidateAddress(address); err != nil {
+		panic(err)
+	}
+	return &tenantConsumer{Consumer: b.EventBus.Consumer(b.Address(address), opts...), bus: b}
+}
+
+// Close is not allowed on a tenant's view of the shared bus: close the bus
+// returned by Unscoped instead.
+func (b *TenantEventBus) Close() error {
+	return &EventBusError{Code: "TENANT_SCOPED", Message: "cannot close a tenant-scoped event bus"}
+}
+
+// RegisterCodec is not allowed on a tenant's view: codecs are shared by every
+// tenant, so register them on the bus returned by Unscoped instead.
+func (b *TenantEventBus) RegisterCodec(codec Codec) error {
+	return &EventBusError{Code: "TENANT_SCOPED", Message: "cannot register a codec on a tenant-scoped event bus; use Unscoped()"}
+}
+
+// SetDefaultCodec is not allowed on a tenant's view, like RegisterCodec.
+func (b *TenantEventBus) SetDefaultCodec(name string) error {
+	return &EventBusError{Code: "TENANT_SCOPED", Message: "cannot set the default codec of a tenant-scoped event bus; use Unscoped()"}
+}
+
+// Metrics returns empty counters: the shared bus counts the messages of all
+// tenants together, which one tenant must not see. Read them through Unscoped.
+func (b *TenantEventBus) Metrics() EventBusMetrics {
+	return EventBusMetrics{}
+}
+
+// tenantConsumer runs handlers in the tenant's scope.
+type tenantConsumer struct {
+	Consumer
+	bus *TenantEventBus
+}
+
+func (c *tenantConsumer) Handler(handler MessageHandler) Consumer {
+	failfast.NotNil(handler, "handler")
+	c.Consumer.Handler(func(ctx FluxorContext, msg Message) error {
+		return handler(&tenantContext{FluxorContext: ctx, bus: c.bus}, msg)
+	})
+	return c
+}
+
+// tenantContext is a handler's FluxorContext within a tenant scope.
+type tenantContext struct {
+	FluxorContext
+	bus *TenantEventBus
+}
+
+func (c *tenantContext) Context() context.Context {
+	return WithTenant(c.FluxorContext.Context(), c.bus.tenantID)
+}
+
+func (c *tenantContext) EventBus() EventBus {
+	return c.bus
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTenantBuses(t *testing.T, tenantIDs ...string) (EventBus, []*TenantEventBus) {
+	t.Helper()
+	gocmd := NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	shared := gocmd.EventBus()
+	buses := make([]*TenantEventBus, len(tenantIDs))
+	for i, id := range tenantIDs {
+		bus, err := NewTenantEventBus(shared, id)
+		if err != nil {
+			t.Fatalf("NewTenantEventBus(%q) error = %v", id, err)
+		}
+		buses[i] = bus
+	}
+	return shared, buses
+}
+
+func TestTenantEventBus_NoCrossDelivery(t *testing.T) {
+	_, buses := newTenantBuses(t, "acme", "globex")
+	acme, globex := buses[0], buses[1]
+
+	received := map[string]chan string{"acme": make(chan string, 4), "globex": make(chan string, 4)}
+	for _, bus := range buses {
+		bus.Consumer("orders.created").Handler(func(ctx FluxorContext, msg Message) error {
+			var body string
+			if err := msg.DecodeBody(&body); err != nil {
+				return err
+			}
+			received[GetTenantID(ctx.Context())] <- body
+			return nil
+		})
+	}
+	time.Sleep(50 * time.Millisecond) // let the consumers start
+
+	if err := acme.Publish("orders.created", "acme-1"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if err := globex.Send("orders.created", "globex-1"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	for tenant, want := range map[string]string{"acme": "acme-1", "globex": "globex-1"} {
+		select {
+		case got := <-received[tenant]:
+			if got != want {
+				t.Errorf("%s received %q, want %q", tenant, got, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%s did not receive %q", tenant, want)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	for tenant, ch := range received {
+		if len(ch) > 0 {
+			t.Errorf("%s received another tenant's message: %q", tenant, <-ch)
+		}
+	}
+}
+
+func TestTenantEventBus_SendWithoutTenantConsumer(t *testing.T) {
+	shared, buses := newTenantBuses(t, "acme", "globex")
+	buses[0].Consumer("jobs").Handler(func(ctx FluxorContext, msg Message) error { return nil })
+	shared.Consumer("jobs").Handler(func(ctx FluxorContext, msg Message) error { return nil })
+	time.Sleep(50 * time.Millisecond)
+
+	// globex has no consumer of its own, whoever else listens on "jobs"
+	if err := buses[1].Send("jobs", "work"); err == nil {
+		t.Error("Send() reached another tenant's consumer")
+	}
+}
+
+func TestTenantEventBus_RequestReplyInScope(t *testing.T) {
+	_, buses := newTenantBuses(t, "acme")
+	acme := buses[0]
+
+	acme.Consumer("users.lookup").Handler(func(ctx FluxorContext, msg Message) error {
+		// The handler's bus is the tenant's: the nested request stays in scope
+		reply, err := ctx.EventBus().Request("users.tier", "tier?", time.Second)
+		if err != nil {
+			return msg.Fail(500, err.Error())
+		}
+		var tier string
+		if err := reply.DecodeBody(&tier); err != nil {
+			return err
+		}
+		return msg.Reply(GetTenantID(ctx.Context()) + ":" + tier)
+	})
+	acme.Consumer("users.tier").Handler(func(ctx FluxorContext, msg Message) error {
+		return msg.Reply("gold")
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	reply, err := acme.Request("users.lookup", "ada", 2*time.Second)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	var body string
+	if err := reply.DecodeBody(&body); err != nil {
+		t.Fatalf("DecodeBody() error = %v", err)
+	}
+	if body != "acme:gold" {
+		t.Errorf("reply = %q, want %q", body, "acme:gold")
+	}
+}
+
+func TestTenantEventBus_UnscopedSystemMessages(t *testing.T) {
+	shared, buses := newTenantBuses(t, "acme", "globex")
+
+	audit := make(chan string, 4)
+	shared.Consumer("system.audit").Handler(func(ctx FluxorContext, msg Message) error {
+		var body string
+		_ = msg.DecodeBody(&body)
+		audit <- body
+		return nil
+	})
+	time.Sleep(50 * time.Millisecond)
+
+	for _, bus := range buses {
+		if err := bus.Unscoped().Publish("system.audit", bus.TenantID()); err != nil {
+			t.Fatalf("Unscoped().Publish() error = %v", err)
+		}
+	}
+	got := map[string]bool{}
+	for range buses {
+		select {
+		case tenant := <-audit:
+			got[tenant] = true
+		case <-time.After(2 * time.Second):
+			t.Fatalf("system consumer received %v, want both tenants", got)
+		}
+	}
+	if !got["acme"] || !got["globex"] {
+		t.Errorf("system consumer received %v, want both tenants", got)
+	}
+	if err := buses[0].Close(); err == nil {
+		t.Error("Close() on a tenant bus should fail")
+	}
+}
+
+func TestTenantEventBus_SharedSettingsNotExposed(t *testing.T) {
+	shared, buses := newTenantBuses(t, "acme")
+	acme := buses[0]
+
+	for name, err := range map[string]error{
+		"RegisterCodec":   acme.RegisterCodec(jsonCodec{}),
+		"SetDefaultCodec": acme.SetDefaultCodec(CodecNameJSON),
+	} {
+		if ee, ok := err.(*EventBusError); !ok || ee.Code != "TENANT_SCOPED" || !strings.Contains(ee.Message, "Unscoped()") {
+			t.Errorf("%s() on a tenant bus error = %v, want TENANT_SCOPED pointing at Unscoped()", name, err)
+		}
+	}
+	if err := acme.Unscoped().SetDefaultCodec(CodecNameJSON); err != nil {
+		t.Errorf("Unscoped().SetDefaultCodec() error = %v", err)
+	}
+
+	// Another tenant's traffic shows on the shared bus only
+	if err := shared.Publish("tenant.globex.orders", "o-1"); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if got := shared.Metrics().Published; got == 0 {
+		t.Error("shared Metrics().Published = 0, want the publish counted")
+	}
+	if got := acme.Metrics(); got != (EventBusMetrics{}) {
+		t.Errorf("tenant Metrics() = %+v, want no shared counters", got)
+	}
+}
+
+func TestScopedEventBus(t *testing.T) {
+	shared, _ := newTenantBuses(t)
+
+	bus, err := ScopedEventBus(context.Background(), shared)
+	if err != nil || bus != shared {
+		t.Errorf("ScopedEventBus(no tenant) = %v, %v, want the shared bus", bus, err)
+	}
+
+	bus, err = ScopedEventBus(WithTenant(context.Background(), "acme"), shared)
+	if err != nil {
+		t.Fatalf("ScopedEventBus() error = %v", err)
+	}
+	scoped, ok := bus.(*TenantEventBus)
+	if !ok || scoped.TenantID() != "acme" || scoped.Address("a.b") != "tenant.acme.a.b" {
+		t.Errorf("ScopedEventBus() = %#v, want a bus for tenant acme", bus)
+	}
+
+	for _, id := range []string{"acme.eu", ""} {
+		if _, err := NewTenantEventBus(shared, id); err == nil || !strings.Contains(err.Error(), "tenant ID") {
+			t.Errorf("NewTenantEventBus(%q) error = %v, want invalid tenant", id, err)
+		}
+	}
+	if _, err := ScopedEventBus(WithTenant(context.Background(), "a.b"), shared); err == nil {
+		t.Error("ScopedEventBus() accepted a tenant ID with a dot")
+	}
+}
@@ -0,0 +1,111 @@
+package fluxor
+
+import (
+	"errors"
+	"sync"
+)
+
+// AllOf returns a future that completes once every future has succeeded,
+// with their results as a []interface{} in input order, and fails with the
+// first failure without waiting for the rest (Vert.x CompositeFuture.all).
+//
+// Unlike the generic All, it works on untyped Futures such as those of
+// ExecuteReactive, and it waits on completion handlers rather than on
+// goroutines:
+//
+//	all := fluxor.AllOf(rv.ExecuteReactive(ctx, "user.get", id), rv.ExecuteReactive(ctx, "orders.list", id))
+//	results, err := all.Await(ctx)
+func AllOf(futures ...Future) Future {
+	all := NewFuture()
+	results := make([]interface{}, len(futures))
+	if len(futures) == 0 {
+		all.Complete(results)
+		return all
+	}
+
+	var mu sync.Mutex
+	remaining := len(futures)
+	for i, f := range futures {
+		f.OnSuccess(func(result interface{}) {
+			mu.Lock()
+			results[i] = result
+			remaining--
+			done := remaining == 0
+			mu.Unlock()
+			if done {
+				all.Complete(results)
+			}
+		})
+		f.OnFailure(all.Fail)
+	}
+	return all
+}
+
+// AnyOf returns a future that completes with the result of the first future
+// to succeed (Vert.x CompositeFuture.any). It fails only once every future
+// has failed, with their errors joined in input order; with no futures it
+// fails at once.
+func AnyOf(futures ...Future) Future {
+	first := NewFuture()
+	if len(futures) == 0 {
+		first.Fail(&Error{Message: "AnyOf: no futures"})
+		return first
+	}
+
+	var mu sync.Mutex
+	errs := make([]error, len(futures))
+	remaining := len(futures)
+	for i, f := range futures {
+		f.OnSuccess(first.Complete)
+		f.OnFailure(func(err error) {
+			mu.Lock()
+			errs[i] = err
+			remaining--
+			done := remaining == 0
+			mu.Unlock()
+			if done {
+				first.Fail(errors.Join(errs...))
+			}
+		})
+	}
+	return first
+}
+
+// JoinAll returns a future that completes once every future has completed
+// (Vert.x CompositeFuture.join). It succeeds with the results in input order
+// when all succeeded; otherwise it fails with the failures joined in input
+// order, after the slowest future, so no work is left running unobserved.
+func JoinAll(futures ...Future) Future {
+	joined := NewFuture()
+	results := make([]interface{}, len(futures))
+	if len(futures) == 0 {
+		joined.Complete(results)
+		return joined
+	}
+
+	var mu sync.Mutex
+	errs := make([]error, len(futures))
+	failed := false
+	remaining := len(futures)
+	settle := func(i int, result interface{}, err error) {
+		mu.Lock()
+		results[i], errs[i] = result, err
+		failed = failed || err != nil
+		remaining--
+		done := remaining == 0
+		mu.Unlock()
+		if !done {
+			return
+		}
+		if failed {
+			joined.Fail(errors.Join(errs...))
+		} else {
+			joined.Complete(results)
+		}
+	}
+	for i, f := range futures {
+		f.OnSuccess(func(result interface{}) { settle(i, result, nil) })
+		f.OnFailure(func(err error) { settle(i, nil, err) })
+	}
+	return joined
+}
@@ -0,0 +1,160 @@
+package fluxor
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	goruntime "runtime"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func futures(n int) []Future {
+	fs := make([]Future, n)
+	for i := range fs {
+		fs[i] = NewFuture()
+	}
+	return fs
+}
+
+func awaitFuture(t *testing.T, f Future) (interface{}, error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return f.Await(ctx)
+}
+
+// pending reports whether f has not completed yet.
+func pending(f Future) bool {
+	select {
+	case <-f.Result():
+		return false
+	default:
+		return true
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	before := goruntime.NumGoroutine()
+
+	fs := futures(3)
+	all := AllOf(fs...)
+	fs[2].Complete("c") // completion order does not matter
+	fs[0].Complete("a")
+	if !pending(all) {
+		t.Fatal("AllOf completed before every future did")
+	}
+	fs[1].Complete("b")
+	result, err := awaitFuture(t, all)
+	if err != nil || !reflect.DeepEqual(result, []interface{}{"a", "b", "c"}) {
+		t.Errorf("AllOf() = %v, %v; want [a b c] in input order", result, err)
+	}
+
+	// The first failure fails it without waiting for the others
+	fs = futures(3)
+	all = AllOf(fs...)
+	fs[0].Complete("a")
+	boom := errors.New("orders unavailable")
+	fs[1].Fail(boom)
+	if _, err := awaitFuture(t, all); !errors.Is(err, boom) {
+		t.Errorf("AllOf() error = %v, want %v", err, boom)
+	}
+	fs[2].Complete("late") // ignored
+
+	if result, err := awaitFuture(t, AllOf()); err != nil || len(result.([]interface{})) != 0 {
+		t.Errorf("AllOf() of nothing = %v, %v; want an empty result", result, err)
+	}
+
+	if after := goruntime.NumGoroutine(); after > before {
+		t.Errorf("goroutines: %d before, %d after", before, after)
+	}
+}
+
+func TestAnyOf(t *testing.T) {
+	before := goruntime.NumGoroutine()
+
+	fs := futures(3)
+	first := AnyOf(fs...)
+	fs[0].Fail(errors.New("replica a down"))
+	fs[2].Complete("from c")
+	fs[1].Complete("from b")
+	if result, err := awaitFuture(t, first); err != nil || result != "from c" {
+		t.Errorf("AnyOf() = %v, %v; want the first success, from c", result, err)
+	}
+
+	// Only when all fail, with every error
+	fs = futures(2)
+	first = AnyOf(fs...)
+	errA, errB := errors.New("replica a down"), errors.New("replica b down")
+	fs[1].Fail(errB)
+	if !pending(first) {
+		t.Fatal("AnyOf failed while a future was still pending")
+	}
+	fs[0].Fail(errA)
+	if _, err := awaitFuture(t, first); !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("AnyOf() error = %v, want both failures", err)
+	}
+
+	if _, err := awaitFuture(t, AnyOf()); err == nil {
+		t.Error("AnyOf() of nothing succeeded")
+	}
+
+	if after := goruntime.NumGoroutine(); after > before {
+		t.Errorf("goroutines: %d before, %d after", before, after)
+	}
+}
+
+func TestJoinAll(t *testing.T) {
+	fs := futures(3)
+	joined := JoinAll(fs...)
+	boom := errors.New("orders unavailable")
+	fs[1].Fail(boom)
+	fs[0].Complete("a")
+	if !pending(joined) {
+		t.Fatal("JoinAll completed before every future did")
+	}
+	fs[2].Complete("c")
+	if _, err := awaitFuture(t, joined); !errors.Is(err, boom) {
+		t.Errorf("JoinAll() error = %v, want %v", err, boom)
+	}
+
+	fs = futures(2)
+	joined = JoinAll(fs...)
+	fs[1].Complete("b")
+	fs[0].Complete("a")
+	if result, err := awaitFuture(t, joined); err != nil || !reflect.DeepEqual(result, []interface{}{"a", "b"}) {
+		t.Errorf("JoinAll() = %v, %v; want [a b]", result, err)
+	}
+}
+
+func TestAllOf_ExecuteReactiveRunsConcurrently(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	for _, address := range []string{"user.get", "orders.list", "prefs.get"} {
+		gocmd.EventBus().Consumer(address).Handler(func(ctx core.FluxorContext, msg core.Message) error {
+			time.Sleep(200 * time.Millisecond)
+			return msg.Reply(address)
+		})
+	}
+
+	rv := NewReactiveVerticle(gocmd)
+	ctx := context.Background()
+	start := time.Now()
+	all := AllOf(
+		rv.ExecuteReactive(ctx, "user.get", "42"),
+		rv.ExecuteReactive(ctx, "orders.list", "42"),
+		rv.ExecuteReactive(ctx, "prefs.get", "42"),
+	)
+	result, err := awaitFuture(t, all)
+	if err != nil {
+		t.Fatalf("AllOf() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("three 200ms requests took %s; they should run concurrently", elapsed)
+	}
+	if results := result.([]interface{}); len(results) != 3 {
+		t.Errorf("AllOf() = %v, want three replies", results)
+	}
+}
@@ -2,6 +2,7 @@ package fluxor
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -53,6 +54,10 @@ type Future interface {
 	// Catch chains an error handler (Node.js Promise style)
 	// Returns a new Future that completes with the result of the error handler
 	Catch(fn func(error) (interface{}, error)) Future
+
+	// Timeout returns a Future that mirrors this one but fails with
+	// ErrTimeout if it has not completed within d
+	Timeout(d time.Duration) Future
 }
 
 // Promise is a writable Future
@@ -81,6 +86,9 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// ErrTimeout is the error Future.Timeout fails with; match it with errors.Is.
+var ErrTimeout = &Error{Message: "future timed out"}
+
 // future implements Future
 type future struct {
 	resultChan      chan FutureResult
@@ -252,6 +260,47 @@ func (f *future) Catch(fn func(error) (interface{}, error)) Future {
 	return mapped
 }
 
+// Timeout returns a Future that completes like f, or fails with ErrTimeout
+// once d has passed. The timer is a time.AfterFunc that is stopped as soon as
+// f completes, so no goroutine outlives the future. The work behind f is not
+// cancelled; use FutureFromContext or a context for that.
+func (f *future) Timeout(d time.Duration) Future {
+	timed := NewFuture()
+	timer := time.AfterFunc(d, func() {
+		timed.Fail(fmt.Errorf("%w after %s", ErrTimeout, d))
+	})
+
+	f.OnSuccess(func(result interface{}) {
+		timer.Stop()
+		timed.Complete(result)
+	})
+
+	f.OnFailure(func(err error) {
+		timer.Stop()
+		timed.Fail(err)
+	})
+
+	return timed
+}
+
+// FutureFromContext returns a Future that fails with ctx.Err() when ctx is
+// cancelled or its deadline passes, unless it is completed first. The
+// context watch is released when the future completes.
+//
+//	f := fluxor.FutureFromContext(ctx)
+//	go func() { f.Complete(work()) }()
+func FutureFromContext(ctx context.Context) Future {
+	f := NewFuture()
+	stop := context.AfterFunc(ctx, func() {
+		f.Fail(ctx.Err())
+	})
+
+	f.OnSuccess(func(interface{}) { stop() })
+	f.OnFailure(func(error) { stop() })
+
+	return f
+}
+
 // promise implements Promise
 type promise struct {
 	Future
@@ -287,21 +336,32 @@ func NewReactiveVerticle(gocmd core.GoCMD) *ReactiveVerticle {
 	}
 }
 
-// ExecuteReactive executes a task reactively using the event bus
+// ExecuteReactive sends a request via the event bus and returns at once; the
+// future completes with the reply body. The request waits up to 5 seconds, or
+// until ctx's deadline when that is sooner, so several calls run concurrently
+// and can be combined with AllOf or AnyOf.
 func (rv *ReactiveVerticle) ExecuteReactive(ctx context.Context, address string, data interface{}) Future {
 	promise := NewPromise()
 
-	// Send request via event bus
-	msg, err := rv.gocmd.EventBus().Request(address, data, 5*time.Second)
-	if err != nil {
+	timeout := 5 * time.Second
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline))
+	}
+	if err := ctx.Err(); err != nil {
 		promise.Fail(err)
 		return promise
 	}
+	if timeout <= 0 {
+		promise.Fail(context.DeadlineExceeded)
+		return promise
+	}
 
-	// Handle reply asynchronously
 	go func() {
-		// In a real implementation, we'd wait for the reply message
-		// For now, we'll complete with the message body
+		msg, err := rv.gocmd.EventBus().Request(address, data, timeout)
+		if err != nil {
+			promise.Fail(err)
+			return
+		}
 		if msg.Body() != nil {
 			promise.Complete(msg.Body())
 		} else {
@@ -0,0 +1,73 @@
+package fluxor
+
+import (
+	"context"
+	"errors"
+	goruntime "runtime"
+	"testing"
+	"time"
+)
+
+func TestFuture_Timeout(t *testing.T) {
+	f := NewFuture()
+	start := time.Now()
+	_, err := awaitFuture(t, f.Timeout(50*time.Millisecond))
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Timeout() error = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Timeout() fired after %s, before 50ms", elapsed)
+	}
+
+	// Failures pass through
+	f = NewFuture()
+	timed := f.Timeout(time.Second)
+	boom := errors.New("boom")
+	f.Fail(boom)
+	if _, err := awaitFuture(t, timed); !errors.Is(err, boom) {
+		t.Errorf("Timeout() error = %v, want %v", err, boom)
+	}
+}
+
+func TestFuture_TimeoutStopsOnCompletion(t *testing.T) {
+	before := goruntime.NumGoroutine()
+
+	f := NewFuture()
+	timed := f.Timeout(30 * time.Millisecond)
+	f.Complete("done")
+	time.Sleep(60 * time.Millisecond) // past the timeout
+
+	if result, err := awaitFuture(t, timed); err != nil || result != "done" {
+		t.Errorf("Timeout() = %v, %v; want done", result, err)
+	}
+	if after := goruntime.NumGoroutine(); after > before {
+		t.Errorf("goroutines: %d before, %d after", before, after)
+	}
+}
+
+func TestFutureFromContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f := FutureFromContext(ctx)
+	if !pending(f) {
+		t.Fatal("FutureFromContext() completed before cancellation")
+	}
+	cancel()
+	if _, err := awaitFuture(t, f); !errors.Is(err, context.Canceled) {
+		t.Errorf("FutureFromContext() error = %v, want context.Canceled", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := awaitFuture(t, FutureFromContext(ctx)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("FutureFromContext() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	// Completing first wins over a later cancellation
+	ctx, cancel = context.WithCancel(context.Background())
+	f = FutureFromContext(ctx)
+	f.Complete("done")
+	cancel()
+	if result, err := awaitFuture(t, f); err != nil || result != "done" {
+		t.Errorf("FutureFromContext() = %v, %v; want done", result, err)
+	}
+}
@@ -0,0 +1,95 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// NewCoreMetrics adapts an OpenTelemetry meter to core.Metrics so the
+// EventBus, HTTP servers and workflow engines report through it. A nil meter
+// uses the global MeterProvider's "fluxor" meter.
+//
+// Label names become attribute keys; the meter provider's exporter decides
+// where the measurements end up (OTLP, Prometheus, stdout, ...).
+func NewCoreMetrics(meter metric.Meter) core.Metrics {
+	if meter == nil {
+		meter = otel.Meter("fluxor")
+	}
+	return &coreMetrics{meter: meter}
+}
+
+type coreMetrics struct {
+	meter metric.Meter
+}
+
+// The meter returns a no-op instrument alongside an error (e.g. an invalid
+// name), so creation errors are not fatal; they are reported through the
+// global OTEL error handler.
+
+func (c *coreMetrics) Counter(name, help string, labelNames ...string) core.Counter {
+	counter, err := c.meter.Float64Counter(name, metric.WithDescription(help))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return otelCounter{counter: counter, labels: labelNames}
+}
+
+func (c *coreMetrics) Gauge(name, help string, labelNames ...string) core.Gauge {
+	gauge, err := c.meter.Float64Gauge(name, metric.WithDescription(help))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return otelGauge{gauge: gauge, labels: labelNames}
+}
+
+func (c *coreMetrics) Histogram(name, help string, labelNames ...string) core.Histogram {
+	histogram, err := c.meter.Float64Histogram(name, metric.WithDescription(help))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return otelHistogram{histogram: histogram, labels: labelNames}
+}
+
+// attributes pairs label names with values; extra values are ignored.
+func attributes(names, values []string) metric.MeasurementOption {
+	n := len(names)
+	if len(values) < n {
+		n = len(values)
+	}
+	attrs := make([]attribute.KeyValue, n)
+	for i := 0; i < n; i++ {
+		attrs[i] = attribute.String(names[i], values[i])
+	}
+	return metric.WithAttributes(attrs...)
+}
+
+type otelCounter struct {
+	counter metric.Float64Counter
+	labels  []string
+}
+
+func (c otelCounter) Add(delta float64, labelValues ...string) {
+	c.counter.Add(context.Background(), delta, attributes(c.labels, labelValues))
+}
+
+type otelGauge struct {
+	gauge  metric.Float64Gauge
+	labels []string
+}
+
+func (g otelGauge) Set(value float64, labelValues ...string) {
+	g.gauge.Record(context.Background(), value, attributes(g.labels, labelValues))
+}
+
+type otelHistogram struct {
+	histogram metric.Float64Histogram
+	labels    []string
+}
+
+func (h otelHistogram) Observe(value float64, labelValues ...string) {
+	h.histogram.Record(context.Background(), value, attributes(h.labels, labelValues))
+}
@@ -1,6 +1,7 @@
 package otel
 
 import (
+	"context"
 	"strconv"
 
 	"github.com/fluxorio/fluxor/pkg/web"
@@ -30,13 +31,20 @@ func HTTPMiddleware() web.FastMiddleware {
 			carrier := newHeaderCarrier(&ctx.RequestCtx.Request.Header)
 			parentCtx := propagator.Extract(ctx.Context(), carrier)
 
+			// Label the span with the route pattern, not the raw path, when
+			// a FastRouter route matched
+			route := ctx.Route()
+			if route == "" {
+				route = string(ctx.Path())
+			}
+
 			// Start span
 			spanCtx, span := StartSpan(parentCtx, "http.request",
 				trace.WithSpanKind(trace.SpanKindServer),
 				trace.WithAttributes(
 					semconv.HTTPMethodKey.String(string(ctx.Method())),
 					semconv.HTTPURLKey.String(string(ctx.Path())),
-					semconv.HTTPRouteKey.String(string(ctx.Path())),
+					semconv.HTTPRouteKey.String(route),
 					attribute.String("http.request_id", ctx.RequestID()),
 				),
 			)
@@ -123,17 +131,10 @@ func (c *responseHeaderCarrier) Keys() []string {
 
 // SpanFromRequest extracts the span context from a request
 func SpanFromRequest(ctx *web.FastRequestContext) (trace.SpanContext, bool) {
-	spanCtx, ok := ctx.Get("span_context").(contextWithSpan)
+	spanCtx, ok := ctx.Get("span_context").(context.Context)
 	if !ok {
 		return trace.SpanContext{}, false
 	}
-	return spanCtx.SpanContext(), true
+	sc := trace.SpanContextFromContext(spanCtx)
+	return sc, sc.IsValid()
 }
-
-// contextWithSpan is a helper interface for extracting span context
-type contextWithSpan interface {
-	SpanContext() trace.SpanContext
-}
-
-// Note: The actual context.Context returned from StartSpan already contains the span,
-// so we can use trace.SpanFromContext() directly. This interface is for type checking.
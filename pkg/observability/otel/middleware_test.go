@@ -0,0 +1,78 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/fluxorio/fluxor/pkg/web"
+	"github.com/valyala/fasthttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// recordSpans points the package tracer at a recorder for the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	mu.Lock()
+	globalTracer, initialized = tp.Tracer("test"), true
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		globalTracer, initialized = nil, false
+		mu.Unlock()
+		_ = tp.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func TestHTTPMiddleware_WrapsRouteHandler(t *testing.T) {
+	recorder := recordSpans(t)
+
+	router := web.NewFastRouter()
+	router.UseFast(HTTPMiddleware())
+	var inHandler bool
+	router.GETFast("/orders/{orderId}", func(c *web.FastRequestContext) error {
+		// The span is started before the handler runs
+		spanCtx, ok := SpanFromRequest(c)
+		inHandler = ok && spanCtx.IsValid()
+		return c.Text(201, "created "+c.Param("orderId"))
+	})
+
+	rc := &fasthttp.RequestCtx{}
+	rc.Request.Header.SetMethod("GET")
+	rc.Request.SetRequestURI("/orders/o-42")
+	router.ServeFastHTTP(&web.FastRequestContext{
+		BaseRequestContext: core.NewBaseRequestContext(),
+		RequestCtx:         rc,
+		Params:             make(map[string]string),
+	})
+
+	if !inHandler {
+		t.Error("handler ran without the middleware's span")
+	}
+	if string(rc.Response.Body()) != "created o-42" {
+		t.Errorf("body = %q", rc.Response.Body())
+	}
+	if len(rc.Response.Header.Peek("traceparent")) == 0 {
+		t.Error("response carries no traceparent header")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	attrs := map[string]interface{}{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	if attrs[string(semconv.HTTPRouteKey)] != "/orders/{orderId}" {
+		t.Errorf("http.route = %v, want the route pattern", attrs[string(semconv.HTTPRouteKey)])
+	}
+	if attrs[string(semconv.HTTPStatusCodeKey)] != int64(201) {
+		t.Errorf("http.status_code = %v, want 201", attrs[string(semconv.HTTPStatusCodeKey)])
+	}
+}
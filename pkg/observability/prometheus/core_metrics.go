@@ -0,0 +1,53 @@
+package prometheus
+
+import (
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NewCoreMetrics adapts m to core.Metrics so the EventBus, HTTP servers and
+// workflow engines report into it. A nil m uses the global GetMetrics().
+//
+//	gocmd, _ := core.NewGoCMDWithOptions(ctx, core.GoCMDOptions{
+//		Metrics: prometheus.NewCoreMetrics(nil),
+//	})
+func NewCoreMetrics(m *Metrics) core.Metrics {
+	if m == nil {
+		m = GetMetrics()
+	}
+	return &coreMetrics{m: m}
+}
+
+type coreMetrics struct {
+	m *Metrics
+}
+
+func (c *coreMetrics) Counter(name, help string, labelNames ...string) core.Counter {
+	return coreCounter{c.m.Counter(name, help, labelNames...)}
+}
+
+func (c *coreMetrics) Gauge(name, help string, labelNames ...string) core.Gauge {
+	return coreGauge{c.m.Gauge(name, help, labelNames...)}
+}
+
+func (c *coreMetrics) Histogram(name, help string, labelNames ...string) core.Histogram {
+	return coreHistogram{c.m.Histogram(name, help, nil, labelNames...)}
+}
+
+type coreCounter struct{ vec *prometheus.CounterVec }
+
+func (c coreCounter) Add(delta float64, labelValues ...string) {
+	c.vec.WithLabelValues(labelValues...).Add(delta)
+}
+
+type coreGauge struct{ vec *prometheus.GaugeVec }
+
+func (g coreGauge) Set(value float64, labelValues ...string) {
+	g.vec.WithLabelValues(labelValues...).Set(value)
+}
+
+type coreHistogram struct{ vec *prometheus.HistogramVec }
+
+func (h coreHistogram) Observe(value float64, labelValues ...string) {
+	h.vec.WithLabelValues(labelValues...).Observe(value)
+}
@@ -0,0 +1,164 @@
+package prometheus_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/fluxorio/fluxor/pkg/observability/prometheus"
+	"github.com/fluxorio/fluxor/pkg/web"
+	promclient "github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCoreMetrics_ScrapeServerAndEventBus(t *testing.T) {
+	registry := promclient.NewRegistry()
+	gocmd, err := core.NewGoCMDWithOptions(context.Background(), core.GoCMDOptions{
+		Metrics: prometheus.NewCoreMetrics(prometheus.NewMetrics(registry)),
+	})
+	if err != nil {
+		t.Fatalf("NewGoCMDWithOptions() error = %v", err)
+	}
+	defer gocmd.Close()
+
+	handled := make(chan struct{}, 1)
+	gocmd.EventBus().Consumer("orders.created").Handler(func(ctx core.FluxorContext, msg core.Message) error {
+		handled <- struct{}{}
+		return nil
+	})
+
+	// A consumer stuck on its first message leaves the next ones in its mailbox
+	auditing, releaseAudit := make(chan struct{}, 1), make(chan struct{})
+	defer close(releaseAudit)
+	gocmd.EventBus().Consumer("orders.audit").Handler(func(ctx core.FluxorContext, msg core.Message) error {
+		auditing <- struct{}{}
+		<-releaseAudit
+		return nil
+	})
+
+	// Normal capacity is MaxQueue + Workers = 2 concurrent requests
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+	config := web.DefaultFastHTTPServerConfig(addr)
+	config.MaxQueue, config.Workers = 1, 1
+	server := web.NewFastHTTPServer(gocmd, config)
+
+	release := make(chan struct{})
+	router := server.FastRouter()
+	router.GETFast("/metrics", prometheus.FastHTTPHandlerFor(registry))
+	router.GETFast("/orders", func(c *web.FastRequestContext) error {
+		if err := c.EventBus.Publish("orders.created", map[string]string{"id": "42"}); err != nil {
+			return err
+		}
+		return c.JSON(202, map[string]string{"status": "accepted"})
+	})
+	router.GETFast("/slow", func(c *web.FastRequestContext) error {
+		<-release
+		return c.Text(200, "done")
+	})
+	go func() { _ = server.Start() }()
+	defer server.Stop()
+	waitListening(t, addr)
+
+	base := "http://" + addr
+	client := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{DisableKeepAlives: true}}
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := client.Get(base + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	if status, _ := get("/orders"); status != 202 {
+		t.Fatalf("GET /orders status = %d, want 202", status)
+	}
+	select {
+	case <-handled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("orders.created consumer was not called")
+	}
+
+	// Saturate the server so the next request is rejected by backpressure
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			get("/slow")
+		}()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for server.Metrics().CurrentCCU < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("slow requests did not reach the handler")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if status, _ := get("/orders"); status != 503 {
+		t.Errorf("GET /orders at capacity status = %d, want 503", status)
+	}
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < 3; i++ {
+		if err := gocmd.EventBus().Send("orders.audit", i); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+		if i == 0 {
+			select {
+			case <-auditing:
+			case <-time.After(2 * time.Second):
+				t.Fatal("orders.audit consumer was not called")
+			}
+		}
+	}
+
+	status, body := get("/metrics")
+	if status != 200 {
+		t.Fatalf("GET /metrics status = %d, want 200", status)
+	}
+	for _, want := range []string{
+		`fluxor_http_server_requests_total{method="GET",status="202"} 1`,
+		`fluxor_http_server_requests_total{method="GET",status="503"} 1`,
+		`fluxor_http_server_rejected_requests_total{reason="backpressure"} 1`,
+		`fluxor_http_server_request_duration_seconds_bucket`,
+		`fluxor_http_server_in_flight_requests`,
+		`fluxor_http_server_ccu_utilization`,
+		`fluxor_eventbus_mailbox_depth{address="orders.audit"} 2`,
+		`fluxor_eventbus_deliveries_total{address="orders.created",type="publish"} 1`,
+		`fluxor_eventbus_handler_duration_seconds_count{address="orders.created"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("/metrics is missing %s", want)
+		}
+	}
+}
+
+func waitListening(t *testing.T, addr string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err := net.Dial("tcp4", addr)
+		if err == nil {
+			_ = conn.Close()
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not start listening: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -41,6 +41,17 @@ func FastHTTPHandler() web.FastRequestHandler {
 	}
 }
 
+// FastHTTPHandlerFor returns a FastRequestHandler serving a custom registry,
+// such as the one given to NewMetrics
+func FastHTTPHandlerFor(registry *prometheus.Registry) web.FastRequestHandler {
+	adaptor := fasthttpadaptor.NewFastHTTPHandler(HandlerFor(registry))
+
+	return func(ctx *web.FastRequestContext) error {
+		adaptor(ctx.RequestCtx)
+		return nil
+	}
+}
+
 // Handler returns an HTTP handler for the metrics endpoint (for standard http)
 func Handler() http.Handler {
 	return promhttp.HandlerFor(DefaultRegistry, promhttp.HandlerOpts{
@@ -52,6 +52,7 @@ type Metrics struct {
 	VerticleCount prometheus.Gauge
 
 	// Custom metrics registry
+	registerer       prometheus.Registerer
 	CustomCounters   map[string]*prometheus.CounterVec
 	CustomGauges     map[string]*prometheus.GaugeVec
 	CustomHistograms map[string]*prometheus.HistogramVec
@@ -210,6 +211,7 @@ func NewMetrics(registerer prometheus.Registerer) *Metrics {
 		),
 
 		// Custom metrics
+		registerer:       registerer,
 		CustomCounters:   make(map[string]*prometheus.CounterVec),
 		CustomGauges:     make(map[string]*prometheus.GaugeVec),
 		CustomHistograms: make(map[string]*prometheus.HistogramVec),
@@ -266,7 +268,8 @@ func (m *Metrics) UpdateVerticleCount(count int) {
 	m.VerticleCount.Set(float64(count))
 }
 
-// Counter creates or returns a custom counter metric
+// Counter creates or returns a custom counter metric, registered with the
+// registerer m was created with
 func (m *Metrics) Counter(name, help string, labels ...string) *prometheus.CounterVec {
 	m.customMu.RLock()
 	if counter, exists := m.CustomCounters[name]; exists {
@@ -283,7 +286,7 @@ func (m *Metrics) Counter(name, help string, labels ...string) *prometheus.Count
 		return counter
 	}
 
-	counter := promauto.With(DefaultRegisterer).NewCounterVec(
+	counter := promauto.With(m.registerer).NewCounterVec(
 		prometheus.CounterOpts{
 			Name: name,
 			Help: help,
@@ -311,7 +314,7 @@ func (m *Metrics) Gauge(name, help string, labels ...string) *prometheus.GaugeVe
 		return gauge
 	}
 
-	gauge := promauto.With(DefaultRegisterer).NewGaugeVec(
+	gauge := promauto.With(m.registerer).NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: name,
 			Help: help,
@@ -348,7 +351,7 @@ func (m *Metrics) Histogram(name, help string, buckets []float64, labels ...stri
 		opts.Buckets = prometheus.DefBuckets
 	}
 
-	histogram := promauto.With(DefaultRegisterer).NewHistogramVec(opts, labels)
+	histogram := promauto.With(m.registerer).NewHistogramVec(opts, labels)
 	m.CustomHistograms[name] = histogram
 	return histogram
 }
@@ -0,0 +1,174 @@
+package web
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BindError is a query parameter BindQuery could not bind.
+type BindError struct {
+	Param  string // query parameter name
+	Reason string // e.g. `"abc" is not a valid int`
+}
+
+func (e *BindError) Error() string {
+	return fmt.Sprintf("query parameter %q: %s", e.Param, e.Reason)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// BindQuery fills the struct v points to from the query string.
+//
+// Fields are bound by their `query:"name"` tag (untagged fields and "-" are
+// skipped; embedded structs are bound too). Supported types are string, bool,
+// ints, uints, floats, time.Duration and slices of them; a slice takes every
+// value of a repeated parameter (?tag=a&tag=b). A `default:"..."` tag is used
+// when the parameter is absent (comma-separated for slices), and
+// `validate:"required"` makes an absent parameter an error. An empty value of
+// a non-string parameter counts as absent.
+//
+//	var q struct {
+//	    Status []string `query:"status"`
+//	    Limit  int      `query:"limit" default:"20"`
+//	    Owner  string   `query:"owner" validate:"required"`
+//	}
+//	if err := c.BindQuery(&q); err != nil {
+//	    return c.JSON(400, map[string]string{"error": err.Error()})
+//	}
+//
+// Errors are *BindError for parameters that are missing or malformed.
+func (c *FastRequestContext) BindQuery(v interface{}) error {
+	// Fail-fast: validate target
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("BindQuery needs a non-nil pointer to a struct, got %T", v)
+	}
+	if c.RequestCtx == nil {
+		return fmt.Errorf("RequestCtx is nil")
+	}
+
+	args := c.RequestCtx.QueryArgs()
+	return bindQueryStruct(rv.Elem(), func(name string) []string {
+		var values []string
+		for _, value := range args.PeekMulti(name) {
+			values = append(values, string(value))
+		}
+		return values
+	})
+}
+
+// bindQueryStruct binds the tagged fields of the struct sv from lookup.
+func bindQueryStruct(sv reflect.Value, lookup func(name string) []string) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		fv := sv.Field(i)
+		name, tagged := field.Tag.Lookup("query")
+		if !tagged && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := bindQueryStruct(fv, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+		if !tagged || name == "-" || !field.IsExported() {
+			continue
+		}
+
+		values := lookup(name)
+		elemType := field.Type
+		if elemType.Kind() == reflect.Slice {
+			elemType = elemType.Elem()
+		}
+		if elemType.Kind() != reflect.String {
+			values = dropEmpty(values)
+		}
+		if len(values) == 0 {
+			if field.Tag.Get("validate") == "required" {
+				return &BindError{Param: name, Reason: "is required"}
+			}
+			def, ok := field.Tag.Lookup("default")
+			if !ok {
+				continue
+			}
+			values = []string{def}
+			if fv.Kind() == reflect.Slice {
+				values = strings.Split(def, ",")
+			}
+		}
+
+		if fv.Kind() == reflect.Slice {
+			slice := reflect.MakeSlice(fv.Type(), 0, len(values))
+			for _, value := range values {
+				elem := reflect.New(fv.Type().Elem()).Elem()
+				if err := setQueryValue(elem, value); err != nil {
+					return &BindError{Param: name, Reason: err.Error()}
+				}
+				slice = reflect.Append(slice, elem)
+			}
+			fv.Set(slice)
+			continue
+		}
+		if err := setQueryValue(fv, values[0]); err != nil {
+			return &BindError{Param: name, Reason: err.Error()}
+		}
+	}
+	return nil
+}
+
+// dropEmpty removes empty values, which count as absent for non-strings.
+func dropEmpty(values []string) []string {
+	kept := values[:0:0]
+	for _, value := range values {
+		if value != "" {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
+
+// setQueryValue parses value into fv.
+func setQueryValue(fv reflect.Value, value string) error {
+	invalid := func() error {
+		return fmt.Errorf("%q is not a valid %s", value, fv.Type())
+	}
+	switch {
+	case fv.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return invalid()
+		}
+		fv.SetInt(int64(d))
+	case fv.Kind() == reflect.String:
+		fv.SetString(value)
+	case fv.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return invalid()
+		}
+		fv.SetBool(b)
+	case fv.CanInt():
+		n, err := strconv.ParseInt(value, 10, fv.Type().Bits())
+		if err != nil {
+			return invalid()
+		}
+		fv.SetInt(n)
+	case fv.CanUint():
+		n, err := strconv.ParseUint(value, 10, fv.Type().Bits())
+		if err != nil {
+			return invalid()
+		}
+		fv.SetUint(n)
+	case fv.CanFloat():
+		f, err := strconv.ParseFloat(value, fv.Type().Bits())
+		if err != nil {
+			return invalid()
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package web
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+type pageQuery struct {
+	Limit  int `query:"limit" default:"20"`
+	Offset int `query:"offset"`
+}
+
+type listQuery struct {
+	pageQuery
+	Owner   string        `query:"owner" validate:"required"`
+	Status  []string      `query:"status" default:"open,pending"`
+	IDs     []int64       `query:"id"`
+	Archive bool          `query:"archived"`
+	MinCost float64       `query:"min_cost"`
+	Retries uint8         `query:"retries"`
+	Timeout time.Duration `query:"timeout" default:"30s"`
+	Sort    string        `query:"-"`
+	Note    string
+}
+
+func bindQuery(t *testing.T, uri string, v interface{}) error {
+	t.Helper()
+	rc := &fasthttp.RequestCtx{}
+	rc.Request.SetRequestURI(uri)
+	return (&FastRequestContext{RequestCtx: rc}).BindQuery(v)
+}
+
+func TestFastRequestContext_BindQuery(t *testing.T) {
+	var q listQuery
+	uri := "/orders?owner=ana&status=paid&status=shipped&id=7&id=9&archived=true&min_cost=2.5&retries=3&timeout=1m&offset=40&sort=desc&Note=x"
+	if err := bindQuery(t, uri, &q); err != nil {
+		t.Fatalf("BindQuery() error = %v", err)
+	}
+	want := listQuery{
+		pageQuery: pageQuery{Limit: 20, Offset: 40},
+		Owner:     "ana",
+		Status:    []string{"paid", "shipped"},
+		IDs:       []int64{7, 9},
+		Archive:   true,
+		MinCost:   2.5,
+		Retries:   3,
+		Timeout:   time.Minute,
+	}
+	if !reflect.DeepEqual(q, want) {
+		t.Errorf("BindQuery() = %+v, want %+v", q, want)
+	}
+
+	// Defaults fill absent and empty parameters
+	var defaults listQuery
+	if err := bindQuery(t, "/orders?owner=ana&limit=&timeout=", &defaults); err != nil {
+		t.Fatalf("BindQuery() error = %v", err)
+	}
+	if defaults.Limit != 20 || defaults.Timeout != 30*time.Second || !reflect.DeepEqual(defaults.Status, []string{"open", "pending"}) {
+		t.Errorf("BindQuery() defaults = %+v", defaults)
+	}
+}
+
+func TestFastRequestContext_BindQueryErrors(t *testing.T) {
+	tests := []struct {
+		uri   string
+		param string
+		want  string
+	}{
+		{"/orders", "owner", "is required"},
+		{"/orders?owner=ana&limit=ten", "limit", `"ten" is not a valid int`},
+		{"/orders?owner=ana&id=7&id=x", "id", `"x" is not a valid int64`},
+		{"/orders?owner=ana&archived=maybe", "archived", `"maybe" is not a valid bool`},
+		{"/orders?owner=ana&retries=300", "retries", `"300" is not a valid uint8`},
+		{"/orders?owner=ana&timeout=soon", "timeout", `"soon" is not a valid time.Duration`},
+	}
+	for _, tt := range tests {
+		var q listQuery
+		err := bindQuery(t, tt.uri, &q)
+		var bindErr *BindError
+		if !errors.As(err, &bindErr) {
+			t.Errorf("%s: error = %v, want *BindError", tt.uri, err)
+			continue
+		}
+		if bindErr.Param != tt.param || bindErr.Reason != tt.want {
+			t.Errorf("%s: error = %v, want %s %s", tt.uri, err, tt.param, tt.want)
+		}
+	}
+
+	var notStruct []string
+	for _, v := range []interface{}{nil, listQuery{}, &notStruct} {
+		if err := bindQuery(t, "/orders", v); err == nil || !strings.Contains(err.Error(), "pointer to a struct") {
+			t.Errorf("BindQuery(%T) error = %v, want pointer to a struct", v, err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/valyala/fasthttp"
+)
+
+// DefaultMaxRequestBodySize is the request body limit when
+// FastHTTPServerConfig.MaxRequestBodySize is unset.
+const DefaultMaxRequestBodySize = 4 << 20
+
+// SetBodyLimit sets the request body limit of the route registered for method
+// and path, e.g. to let an upload endpoint take more than
+// FastHTTPServerConfig.MaxRequestBodySize, or a small JSON endpoint less.
+// Larger bodies are answered 413 before the handler runs.
+//
+// The server reads no body beyond its largest limit, taken when it starts:
+// raise limits before Start. Panics if limit is not positive.
+func (r *FastRouter) SetBodyLimit(method, path string, limit int) {
+	if limit <= 0 {
+		panic(fmt.Sprintf("body limit for %s %s must be positive", method, path))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.bodyLimits == nil {
+		r.bodyLimits = make(map[string]int)
+	}
+	r.bodyLimits[method+" "+path] = limit
+}
+
+// routeBodyLimit is the body limit of route: its own, else the server's
+// (0: none). r.mu must be held.
+func (r *FastRouter) routeBodyLimit(route *fastRoute) int {
+	if limit, ok := r.bodyLimits[route.method+" "+route.path]; ok {
+		return limit
+	}
+	return r.bodyLimit
+}
+
+// readBodyLimit is how much body the server reads at most: the largest of
+// the server's and the routes' limits.
+func (r *FastRouter) readBodyLimit() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	limit := r.bodyLimit
+	for _, l := range r.bodyLimits {
+		limit = max(limit, l)
+	}
+	return limit
+}
+
+// bodyTooLargeHandler answers 413 for a body over limit.
+func (r *FastRouter) bodyTooLargeHandler(limit int) FastRequestHandler {
+	return func(ctx *FastRequestContext) error {
+		r.metrics.reject("body_too_large")
+		writeBodyTooLarge(ctx.RequestCtx, limit)
+		return nil
+	}
+}
+
+func writeBodyTooLarge(ctx *fasthttp.RequestCtx, limit int) {
+	ctx.SetStatusCode(fasthttp.StatusRequestEntityTooLarge)
+	ctx.SetContentType("application/json")
+	ctx.SetBodyString(fmt.Sprintf(`{"error":"body_too_large","message":"Request body exceeds %d bytes","code":"BODY_TOO_LARGE"}`, limit))
+}
+
+// handleReadError answers requests fasthttp could not read: 413 for a body
+// over the read limit, otherwise what fasthttp answers by default.
+func (s *FastHTTPServer) handleReadError(ctx *fasthttp.RequestCtx, err error) {
+	var smallBuffer *fasthttp.ErrSmallBuffer
+	var netErr *net.OpError
+	switch {
+	case errors.Is(err, fasthttp.ErrBodyTooLarge):
+		s.metrics.reject("body_too_large")
+		writeBodyTooLarge(ctx, s.server.MaxRequestBodySize)
+	case errors.As(err, &smallBuffer):
+		ctx.Error("Too big request header", fasthttp.StatusRequestHeaderFieldsTooLarge)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		ctx.Error("Request timeout", fasthttp.StatusRequestTimeout)
+	default:
+		ctx.Error("Error when parsing request", fasthttp.StatusBadRequest)
+	}
+}
@@ -0,0 +1,97 @@
+package web
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/valyala/fasthttp"
+)
+
+func servePost(server *FastHTTPServer, path string, body string) *fasthttp.Response {
+	rc := &fasthttp.RequestCtx{}
+	rc.Request.Header.SetMethod("POST")
+	rc.Request.SetRequestURI(path)
+	rc.Request.SetBodyString(body)
+	server.handleRequest(rc)
+	return &rc.Response
+}
+
+func TestFastRouter_BodyLimits(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	config := DefaultFastHTTPServerConfig(":0")
+	config.MaxRequestBodySize = 1024
+	server := NewFastHTTPServer(gocmd, config)
+
+	handled := 0
+	echo := func(c *FastRequestContext) error {
+		handled++
+		return c.Text(200, "ok")
+	}
+	router := server.FastRouter()
+	router.POSTFast("/orders", echo)
+	router.POSTFast("/uploads", echo)
+	router.SetBodyLimit("POST", "/uploads", 4096)
+
+	tests := []struct {
+		path   string
+		size   int
+		status int
+	}{
+		{"/orders", 1024, 200},
+		{"/orders", 1025, 413},
+		{"/uploads", 4096, 200},
+		{"/uploads", 4097, 413},
+	}
+	for _, tt := range tests {
+		handled = 0
+		resp := servePost(server, tt.path, strings.Repeat("x", tt.size))
+		if resp.StatusCode() != tt.status {
+			t.Errorf("POST %s with %d bytes: status = %d, want %d", tt.path, tt.size, resp.StatusCode(), tt.status)
+			continue
+		}
+		if tt.status == 413 {
+			if handled != 0 {
+				t.Errorf("POST %s with %d bytes reached the handler", tt.path, tt.size)
+			}
+			if !strings.Contains(string(resp.Body()), `"code":"BODY_TOO_LARGE"`) {
+				t.Errorf("POST %s with %d bytes: body = %s, want BODY_TOO_LARGE", tt.path, tt.size, resp.Body())
+			}
+		}
+	}
+
+	if got := router.readBodyLimit(); got != 4096 {
+		t.Errorf("readBodyLimit() = %d, want the upload route's 4096", got)
+	}
+	if got := newRouterServer(t).FastRouter().readBodyLimit(); got != DefaultMaxRequestBodySize {
+		t.Errorf("default readBodyLimit() = %d, want %d", got, DefaultMaxRequestBodySize)
+	}
+}
+
+func TestFastHTTPServer_RejectsBodyOverReadLimit(t *testing.T) {
+	config := DefaultFastHTTPServerConfig("")
+	config.MaxRequestBodySize = 1024
+	server, url, _ := startServer(t, config)
+	defer server.Stop()
+	server.FastRouter().POSTFast("/orders", func(c *FastRequestContext) error {
+		return c.Text(200, "ok")
+	})
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	for size, want := range map[int]int{1024: 200, 1025: 413} {
+		resp, err := client.Post(url+"/orders", "application/json", strings.NewReader(strings.Repeat("x", size)))
+		if err != nil {
+			t.Fatalf("POST %d bytes: %v", size, err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("POST %d bytes: status = %d, want %d", size, resp.StatusCode, want)
+		}
+		if want == 413 && resp.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("413 Content-Type = %q, want application/json", resp.Header.Get("Content-Type"))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package web
+
+import (
+	"crypto/x509"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ClientCertMatcher decides whether a verified client certificate is authorized.
+type ClientCertMatcher func(cert *x509.Certificate) bool
+
+// ClientCertificate returns the leaf certificate presented by the client during
+// the TLS handshake (mTLS).
+//
+// Returns (nil, false) when the connection is not TLS (TLS disabled on the
+// server), or when the client did not present a certificate.
+func (c *FastRequestContext) ClientCertificate() (*x509.Certificate, bool) {
+	if c.RequestCtx == nil || !c.RequestCtx.IsTLS() {
+		return nil, false
+	}
+	state := c.RequestCtx.TLSConnectionState()
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil, false
+	}
+	return state.PeerCertificates[0], true
+}
+
+// RequireClientCert rejects requests that do not carry a client certificate
+// accepted by matcher.
+//
+//   - no TLS / no client certificate: 401 Unauthorized
+//   - certificate present but matcher returns false: 403 Forbidden
+//
+// A nil matcher accepts any client certificate. Certificate chain verification
+// is the TLS layer's job (tls.Config.ClientAuth / ClientCAs); this middleware
+// only authorizes the already-presented certificate.
+func RequireClientCert(matcher ClientCertMatcher) FastMiddleware {
+	return func(next FastRequestHandler) FastRequestHandler {
+		return func(ctx *FastRequestContext) error {
+			cert, ok := ctx.ClientCertificate()
+			if !ok {
+				return ctx.JSON(fasthttp.StatusUnauthorized, map[string]interface{}{
+					"error":   "client_certificate_required",
+					"message": "A client certificate is required",
+				})
+			}
+			if matcher != nil && !matcher(cert) {
+				return ctx.JSON(fasthttp.StatusForbidden, map[string]interface{}{
+					"error":   "client_certificate_rejected",
+					"message": "Client certificate is not authorized",
+				})
+			}
+			return next(ctx)
+		}
+	}
+}
+
+// MatchClientCertNames returns a matcher accepting certificates whose subject
+// common name or any DNS SAN equals one of names.
+func MatchClientCertNames(names ...string) ClientCertMatcher {
+	allowed := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		allowed[n] = struct{}{}
+	}
+	return func(cert *x509.Certificate) bool {
+		if _, ok := allowed[cert.Subject.CommonName]; ok {
+			return true
+		}
+		for _, dns := range cert.DNSNames {
+			if _, ok := allowed[dns]; ok {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,154 @@
+package web
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/valyala/fasthttp"
+)
+
+// newTestCert creates a self-signed certificate for cn with the given DNS SANs.
+func newTestCert(t *testing.T, cn string, dnsNames []string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// newMTLSRequestContext performs an in-memory TLS handshake and returns a
+// FastRequestContext bound to the server side of the connection.
+func newMTLSRequestContext(t *testing.T, gocmd core.GoCMD, clientCert *tls.Certificate) *FastRequestContext {
+	t.Helper()
+
+	serverCert := newTestCert(t, "server", []string{"localhost"}, x509.ExtKeyUsageServerAuth)
+	serverRaw, clientRaw := net.Pipe()
+	t.Cleanup(func() { serverRaw.Close(); clientRaw.Close() })
+
+	serverConn := tls.Server(serverRaw, &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequestClientCert,
+	})
+	clientCfg := &tls.Config{InsecureSkipVerify: true}
+	if clientCert != nil {
+		clientCfg.Certificates = []tls.Certificate{*clientCert}
+	}
+	clientConn := tls.Client(clientRaw, clientCfg)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- clientConn.Handshake() }()
+	if err := serverConn.Handshake(); err != nil {
+		t.Fatalf("server Handshake() error = %v", err)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("client Handshake() error = %v", err)
+	}
+
+	reqCtx := &fasthttp.RequestCtx{}
+	reqCtx.Init2(serverConn, nil, false)
+
+	return &FastRequestContext{
+		BaseRequestContext: core.NewBaseRequestContext(),
+		RequestCtx:         reqCtx,
+		GoCMD:              gocmd,
+		EventBus:           gocmd.EventBus(),
+		Params:             make(map[string]string),
+	}
+}
+
+func TestFastRequestContext_ClientCertificate(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	clientCert := newTestCert(t, "billing-service", []string{"billing.internal"}, x509.ExtKeyUsageClientAuth)
+	ctx := newMTLSRequestContext(t, gocmd, &clientCert)
+
+	cert, ok := ctx.ClientCertificate()
+	if !ok {
+		t.Fatal("ClientCertificate() ok = false, want true")
+	}
+	if cert.Subject.CommonName != "billing-service" {
+		t.Errorf("CommonName = %q, want %q", cert.Subject.CommonName, "billing-service")
+	}
+}
+
+func TestFastRequestContext_ClientCertificate_NoTLS(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	ctx := &FastRequestContext{
+		BaseRequestContext: core.NewBaseRequestContext(),
+		RequestCtx:         &fasthttp.RequestCtx{},
+		GoCMD:              gocmd,
+		EventBus:           gocmd.EventBus(),
+		Params:             make(map[string]string),
+	}
+
+	if cert, ok := ctx.ClientCertificate(); ok || cert != nil {
+		t.Errorf("ClientCertificate() without TLS = (%v, %v), want (nil, false)", cert, ok)
+	}
+}
+
+func TestRequireClientCert(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	mw := RequireClientCert(MatchClientCertNames("billing.internal"))
+	handler := mw(func(ctx *FastRequestContext) error {
+		return ctx.Text(200, "ok")
+	})
+
+	allowed := newTestCert(t, "billing-service", []string{"billing.internal"}, x509.ExtKeyUsageClientAuth)
+	denied := newTestCert(t, "other-service", []string{"other.internal"}, x509.ExtKeyUsageClientAuth)
+
+	tests := []struct {
+		name       string
+		cert       *tls.Certificate
+		wantStatus int
+	}{
+		{"matching cert", &allowed, fasthttp.StatusOK},
+		{"non-matching cert", &denied, fasthttp.StatusForbidden},
+		{"no cert", nil, fasthttp.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newMTLSRequestContext(t, gocmd, tt.cert)
+			if err := handler(ctx); err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if got := ctx.RequestCtx.Response.StatusCode(); got != tt.wantStatus {
+				t.Errorf("status = %d, want %d", got, tt.wantStatus)
+			}
+		})
+	}
+}
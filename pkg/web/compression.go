@@ -0,0 +1,114 @@
+package web
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// DefaultCompressionMinSize is the smallest body compressed when
+// FastHTTPServerConfig.CompressionMinSize is unset: below about 1 KB the
+// encoding overhead outweighs the savings.
+const DefaultCompressionMinSize = 1024
+
+// CompressResponse is the server's response compression (see
+// FastHTTPServerConfig.EnableCompression) for middleware that picks the
+// responses to compress itself: the body is encoded whatever its content
+// type, at the given level (fasthttp.CompressBestSpeed to
+// CompressBestCompression).
+func CompressResponse(ctx *fasthttp.RequestCtx, minSize, level int) {
+	encodeResponse(ctx, minSize, level)
+}
+
+// compressResponse encodes the response (see encodeResponse) when its body
+// is of a compressible type.
+func compressResponse(ctx *fasthttp.RequestCtx, minSize, level int) {
+	if !compressibleType(ctx.Response.Header.ContentType()) {
+		return
+	}
+	encodeResponse(ctx, minSize, level)
+}
+
+// encodeResponse gzip- or deflate-encodes the response body when the client
+// accepts it, the body is at least minSize bytes, and the handler has not
+// encoded or streamed it itself.
+func encodeResponse(ctx *fasthttp.RequestCtx, minSize, level int) {
+	resp := &ctx.Response
+	if ctx.IsHead() || resp.IsBodyStream() || len(resp.Header.ContentEncoding()) > 0 ||
+		len(resp.Body()) < minSize {
+		return
+	}
+	// Whether the body is encoded depends on the request from here on
+	resp.Header.Add("Vary", "Accept-Encoding")
+
+	var compressed []byte
+	encoding := negotiateEncoding(ctx.Request.Header.Peek("Accept-Encoding"))
+	switch encoding {
+	case "gzip":
+		compressed = fasthttp.AppendGzipBytesLevel(nil, resp.Body(), level)
+	case "deflate":
+		compressed = fasthttp.AppendDeflateBytesLevel(nil, resp.Body(), level)
+	default:
+		return
+	}
+	if len(compressed) >= len(resp.Body()) {
+		return
+	}
+	resp.SetBodyRaw(compressed)
+	resp.Header.SetContentEncoding(encoding)
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header,
+// the one with the higher q-value, gzip on a tie. "*" stands for the codings
+// not listed. It returns "" when the client accepts neither.
+func negotiateEncoding(acceptEncoding []byte) string {
+	qualities := make(map[string]float64, 2)
+	wildcard := -1.0
+	for _, part := range strings.Split(string(acceptEncoding), ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		switch name {
+		case "gzip", "deflate":
+			qualities[name] = q
+		case "*":
+			wildcard = q
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, name := range []string{"gzip", "deflate"} {
+		q, listed := qualities[name]
+		if !listed {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressibleType reports whether a body of contentType shrinks when
+// compressed: text, JSON, XML and JavaScript, not images or archives.
+func compressibleType(contentType []byte) bool {
+	mediaType, _, _ := bytes.Cut(contentType, []byte(";"))
+	mediaType = bytes.ToLower(bytes.TrimSpace(mediaType))
+	switch {
+	case bytes.HasPrefix(mediaType, []byte("text/")),
+		bytes.HasSuffix(mediaType, []byte("json")),
+		bytes.HasSuffix(mediaType, []byte("xml")),
+		bytes.HasSuffix(mediaType, []byte("javascript")):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/valyala/fasthttp"
+)
+
+func newCompressionServer(t *testing.T) (*FastHTTPServer, string) {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	config := DefaultFastHTTPServerConfig(":0")
+	config.EnableCompression = true
+	config.CompressionMinSize = 256
+	server := NewFastHTTPServer(gocmd, config)
+
+	large := strings.Repeat("node fetch completed; ", 60)
+	router := server.FastRouter()
+	router.GETFast("/outputs", func(c *FastRequestContext) error {
+		return c.JSON(200, map[string]string{"outputs": large})
+	})
+	router.GETFast("/small", func(c *FastRequestContext) error { return c.Text(200, "ok") })
+	router.GETFast("/image", func(c *FastRequestContext) error {
+		c.RequestCtx.SetContentType("image/png")
+		c.RequestCtx.SetBodyString(large)
+		return nil
+	})
+	router.GETFast("/stream", func(c *FastRequestContext) error {
+		c.RequestCtx.SetContentType("text/plain")
+		c.RequestCtx.SetBodyStreamWriter(func(w *bufio.Writer) { _, _ = w.WriteString(large) })
+		return nil
+	})
+	return server, large
+}
+
+func TestFastHTTPServer_Compression(t *testing.T) {
+	server, large := newCompressionServer(t)
+
+	for _, tt := range []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{"gzip", "gzip"},
+		{"deflate, gzip;q=0.5", "deflate"},
+		{"br, deflate", "deflate"},
+		{"*", "gzip"},
+		{"*, gzip;q=0", "deflate"},
+		{"", ""},
+		{"identity", ""},
+	} {
+		resp := serveWithHeaders(server, "GET", "/outputs", map[string]string{"Accept-Encoding": tt.acceptEncoding})
+		if got := string(resp.Header.ContentEncoding()); got != tt.want {
+			t.Errorf("Accept-Encoding %q: Content-Encoding = %q, want %q", tt.acceptEncoding, got, tt.want)
+		}
+		if string(resp.Header.Peek("Vary")) != "Accept-Encoding" {
+			t.Errorf("Accept-Encoding %q: Vary = %q, want Accept-Encoding", tt.acceptEncoding, resp.Header.Peek("Vary"))
+		}
+
+		body := resp.Body()
+		var err error
+		switch tt.want {
+		case "gzip":
+			body, err = fasthttp.AppendGunzipBytes(nil, body)
+		case "deflate":
+			body, err = fasthttp.AppendInflateBytes(nil, body)
+		}
+		if err != nil {
+			t.Fatalf("Accept-Encoding %q: decoding body: %v", tt.acceptEncoding, err)
+		}
+		if tt.want != "" && len(resp.Body()) >= len(body) {
+			t.Errorf("Accept-Encoding %q: encoded body is %d bytes, plain %d", tt.acceptEncoding, len(resp.Body()), len(body))
+		}
+		if !strings.Contains(string(body), large) {
+			t.Errorf("Accept-Encoding %q: body does not carry the JSON output", tt.acceptEncoding)
+		}
+	}
+}
+
+func TestFastHTTPServer_CompressionSkips(t *testing.T) {
+	server, _ := newCompressionServer(t)
+	gzip := map[string]string{"Accept-Encoding": "gzip"}
+
+	// Below the threshold, not compressible, or streamed
+	for _, path := range []string{"/small", "/image", "/stream"} {
+		resp := serveWithHeaders(server, "GET", path, gzip)
+		if len(resp.Header.ContentEncoding()) > 0 {
+			t.Errorf("GET %s: Content-Encoding = %q, want none", path, resp.Header.ContentEncoding())
+		}
+	}
+
+	// Compression is off unless enabled
+	plain := newRouterServer(t)
+	plain.FastRouter().GETFast("/outputs", func(c *FastRequestContext) error {
+		return c.Text(200, strings.Repeat("completed ", 500))
+	})
+	if resp := serveWithHeaders(plain, "GET", "/outputs", gzip); len(resp.Header.ContentEncoding()) > 0 {
+		t.Errorf("Content-Encoding = %q without EnableCompression", resp.Header.ContentEncoding())
+	}
+}
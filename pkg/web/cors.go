@@ -0,0 +1,143 @@
+package web
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CORSConfig configures CORS (Cross-Origin Resource Sharing)
+type CORSConfig struct {
+	// AllowedOrigins is a list of allowed origins: "*" allows all, and a
+	// "*" in an origin matches any subdomain, e.g. "https://*.example.com"
+	AllowedOrigins []string
+
+	// AllowedMethods is a list of allowed HTTP methods
+	// (default GET, HEAD, POST, PUT, PATCH, DELETE)
+	AllowedMethods []string
+
+	// AllowedHeaders is a list of allowed request headers
+	// (default: the headers the preflight asks for)
+	AllowedHeaders []string
+
+	// ExposedHeaders is a list of headers that can be exposed to the client
+	ExposedHeaders []string
+
+	// AllowCredentials indicates whether credentials can be included.
+	// Credentialed responses name the origin rather than "*".
+	AllowCredentials bool
+
+	// MaxAge is the maximum age for preflight requests (in seconds)
+	MaxAge int
+}
+
+// DefaultCORSConfig returns a default CORS configuration
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins:   []string{"*"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Request-ID"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: false,
+		MaxAge:           86400, // 24 hours
+	}
+}
+
+// CORSMiddleware sets the Access-Control-Allow-* headers for requests from
+// allowed origins and answers preflight requests (OPTIONS with an
+// Access-Control-Request-Method header) itself: 204 for an allowed origin,
+// 403 otherwise. Requests from other origins reach the handler without CORS
+// headers, so the browser withholds the response.
+//
+// Add it with UseFast to cover every route, preflights included, or per route
+// with RouteFastWith.
+func CORSMiddleware(config CORSConfig) FastMiddleware {
+	allowAll := false
+	exact := make(map[string]bool)
+	var wildcards [][2]string // prefix and suffix around the "*"
+	for _, origin := range config.AllowedOrigins {
+		origin = strings.ToLower(origin)
+		if origin == "*" {
+			allowAll = true
+		} else if i := strings.IndexByte(origin, '*'); i >= 0 {
+			wildcards = append(wildcards, [2]string{origin[:i], origin[i+1:]})
+		} else {
+			exact[origin] = true
+		}
+	}
+	allowed := func(origin string) bool {
+		origin = strings.ToLower(origin)
+		if allowAll || exact[origin] {
+			return true
+		}
+		for _, w := range wildcards {
+			if len(origin) > len(w[0])+len(w[1]) && strings.HasPrefix(origin, w[0]) && strings.HasSuffix(origin, w[1]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	methods := config.AllowedMethods
+	if len(methods) == 0 {
+		methods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}
+	}
+	allowedMethods := strings.Join(methods, ", ")
+	allowedHeaders := strings.Join(config.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := ""
+	if config.MaxAge > 0 {
+		maxAge = strconv.Itoa(config.MaxAge)
+	}
+
+	return func(next FastRequestHandler) FastRequestHandler {
+		return func(ctx *FastRequestContext) error {
+			header := &ctx.RequestCtx.Response.Header
+			origin := string(ctx.RequestCtx.Request.Header.Peek("Origin"))
+			if origin == "" {
+				return next(ctx)
+			}
+			if !allowAll || config.AllowCredentials {
+				// The response depends on the origin; keep caches from mixing them
+				header.Add("Vary", "Origin")
+			}
+
+			preflight := string(ctx.Method()) == "OPTIONS" &&
+				len(ctx.RequestCtx.Request.Header.Peek("Access-Control-Request-Method")) > 0
+			if !allowed(origin) {
+				if preflight {
+					ctx.RequestCtx.SetStatusCode(403)
+					return nil
+				}
+				return next(ctx)
+			}
+
+			if allowAll && !config.AllowCredentials {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+			if config.AllowCredentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if exposedHeaders != "" {
+					header.Set("Access-Control-Expose-Headers", exposedHeaders)
+				}
+				return next(ctx)
+			}
+
+			header.Set("Access-Control-Allow-Methods", allowedMethods)
+			if allowedHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", allowedHeaders)
+			} else if requested := ctx.RequestCtx.Request.Header.Peek("Access-Control-Request-Headers"); len(requested) > 0 {
+				header.Set("Access-Control-Allow-Headers", string(requested))
+			}
+			if maxAge != "" {
+				header.Set("Access-Control-Max-Age", maxAge)
+			}
+			ctx.RequestCtx.SetStatusCode(204) // No Content
+			return nil
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package web
+
+import "testing"
+
+func newCORSServer(t *testing.T, config CORSConfig) *FastHTTPServer {
+	t.Helper()
+	server := newRouterServer(t)
+	router := server.FastRouter()
+	router.UseFast(CORSMiddleware(config))
+	router.GETFast("/orders", func(c *FastRequestContext) error { return c.Text(200, "orders") })
+	return server
+}
+
+func TestCORSMiddleware_Origins(t *testing.T) {
+	server := newCORSServer(t, CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.partner.io"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+	})
+	tests := []struct {
+		origin string
+		want   string
+	}{
+		{"https://app.example.com", "https://app.example.com"},
+		{"https://eu.partner.io", "https://eu.partner.io"},
+		{"https://partner.io", ""},
+		{"https://evil.example.com", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		resp := serveWithHeaders(server, "GET", "/orders", map[string]string{"Origin": tt.origin})
+		if resp.StatusCode() != 200 || string(resp.Body()) != "orders" {
+			t.Errorf("origin %q: status = %d, body = %q", tt.origin, resp.StatusCode(), resp.Body())
+		}
+		if got := string(resp.Header.Peek("Access-Control-Allow-Origin")); got != tt.want {
+			t.Errorf("origin %q: Allow-Origin = %q, want %q", tt.origin, got, tt.want)
+		}
+		allowed := tt.want != ""
+		if got := string(resp.Header.Peek("Access-Control-Allow-Credentials")) == "true"; got != allowed {
+			t.Errorf("origin %q: Allow-Credentials set = %v, want %v", tt.origin, got, allowed)
+		}
+		if got := string(resp.Header.Peek("Access-Control-Expose-Headers")); allowed && got != "X-Request-ID" {
+			t.Errorf("origin %q: Expose-Headers = %q", tt.origin, got)
+		}
+		if tt.origin != "" && string(resp.Header.Peek("Vary")) != "Origin" {
+			t.Errorf("origin %q: Vary = %q, want Origin", tt.origin, resp.Header.Peek("Vary"))
+		}
+	}
+
+	// Credentials rule out "*": the origin is named instead
+	server = newCORSServer(t, CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+	resp := serveWithHeaders(server, "GET", "/orders", map[string]string{"Origin": "https://any.test"})
+	if got := string(resp.Header.Peek("Access-Control-Allow-Origin")); got != "https://any.test" {
+		t.Errorf("credentialed wildcard Allow-Origin = %q, want the origin", got)
+	}
+	server = newCORSServer(t, CORSConfig{AllowedOrigins: []string{"*"}})
+	resp = serveWithHeaders(server, "GET", "/orders", map[string]string{"Origin": "https://any.test"})
+	if got := string(resp.Header.Peek("Access-Control-Allow-Origin")); got != "*" {
+		t.Errorf("wildcard Allow-Origin = %q, want *", got)
+	}
+}
+
+func TestCORSMiddleware_Preflight(t *testing.T) {
+	server := newCORSServer(t, CORSConfig{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		MaxAge:         600,
+	})
+
+	// The preflight is answered even though only GET /orders is routed
+	resp := serveWithHeaders(server, "OPTIONS", "/orders", map[string]string{
+		"Origin":                         "https://app.example.com",
+		"Access-Control-Request-Method":  "POST",
+		"Access-Control-Request-Headers": "Content-Type, X-Trace",
+	})
+	if resp.StatusCode() != 204 {
+		t.Fatalf("preflight status = %d, want 204", resp.StatusCode())
+	}
+	for header, want := range map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": "GET, POST",
+		"Access-Control-Allow-Headers": "Content-Type, X-Trace",
+		"Access-Control-Max-Age":       "600",
+	} {
+		if got := string(resp.Header.Peek(header)); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+
+	resp = serveWithHeaders(server, "OPTIONS", "/orders", map[string]string{
+		"Origin":                        "https://evil.example.com",
+		"Access-Control-Request-Method": "POST",
+	})
+	if resp.StatusCode() != 403 || len(resp.Header.Peek("Access-Control-Allow-Origin")) > 0 {
+		t.Errorf("denied preflight status = %d, Allow-Origin = %q, want 403 without CORS headers",
+			resp.StatusCode(), resp.Header.Peek("Access-Control-Allow-Origin"))
+	}
+
+	// Without Access-Control-Request-Method it is a plain OPTIONS request
+	if resp := serveWithHeaders(server, "OPTIONS", "/orders", map[string]string{"Origin": "https://app.example.com"}); resp.StatusCode() != 404 {
+		t.Errorf("plain OPTIONS status = %d, want 404", resp.StatusCode())
+	}
+}
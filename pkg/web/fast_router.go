@@ -1,7 +1,6 @@
 package web
 
 import (
-	"strings"
 	"sync"
 
 	"github.com/fluxorio/fluxor/pkg/core"
@@ -10,9 +9,18 @@ import (
 
 // FastRouter implements Router for fasthttp
 type FastRouter struct {
-	routes     []*fastRoute
+	trees      map[string]*routeNode // route tree per method
 	middleware []FastMiddleware
 	mu         sync.RWMutex
+
+	// Open connections of WS routes, closed by FastHTTPServer on stop
+	websockets wsConnections
+
+	// Request body limits: the server's (0: none) and per route ("METHOD path")
+	bodyLimit  int
+	bodyLimits map[string]int
+	// Instruments of the server the router belongs to (nil: not counted)
+	metrics *httpMetrics
 }
 
 type fastRoute struct {
@@ -21,6 +29,10 @@ type fastRoute struct {
 	handler FastRequestHandler
 	// middleware is applied only for this route (in addition to any global middleware).
 	middleware []FastMiddleware
+	// params are the names of the route's path params, in path order
+	params []string
+	// websocket marks WS routes, whose upgrades bypass the server's backpressure
+	websocket bool
 }
 
 // FastRequestHandler handles fasthttp requests
@@ -32,45 +44,52 @@ type FastMiddleware func(handler FastRequestHandler) FastRequestHandler
 // NewFastRouter creates a new fasthttp router
 func NewFastRouter() *FastRouter {
 	return &FastRouter{
-		routes:     make([]*fastRoute, 0),
+		trees:      make(map[string]*routeNode),
 		middleware: make([]FastMiddleware, 0),
 	}
 }
 
 // ServeFastHTTP implements fasthttp request handler
 func (r *FastRouter) ServeFastHTTP(ctx *FastRequestContext) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
 	method := string(ctx.Method())
 	path := string(ctx.Path())
 
-	for _, route := range r.routes {
-		matched := route.method == method && r.matchPath(route.path, path)
-		if matched {
-			// Extract params
-			r.extractParams(route.path, path, ctx.Params)
-
-			// Apply middleware chain (route-specific then global).
-			// We apply route middleware first so global middleware remains outermost.
-			handler := route.handler
-			for i := len(route.middleware) - 1; i >= 0; i-- {
-				handler = route.middleware[i](handler)
-			}
-			for i := len(r.middleware) - 1; i >= 0; i-- {
-				handler = r.middleware[i](handler)
-			}
+	// Build the chain under the lock but run it without: handlers may register routes
+	r.mu.RLock()
+	handler := notFoundHandler
+	if route, values := r.lookup(method, path); route != nil {
+		// Extract params
+		ctx.route = route.path
+		for i, name := range route.params {
+			ctx.Params[name] = values[i]
+		}
 
-			// Execute handler
-			if err := handler(ctx); err != nil {
-				ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
-			}
-			return
+		// Apply middleware chain (route-specific then global).
+		// We apply route middleware first so global middleware remains outermost.
+		handler = route.handler
+		for i := len(route.middleware) - 1; i >= 0; i-- {
+			handler = route.middleware[i](handler)
+		}
+		if limit := r.routeBodyLimit(route); limit > 0 && len(ctx.RequestCtx.PostBody()) > limit {
+			handler = r.bodyTooLargeHandler(limit)
 		}
 	}
+	// Global middleware sees unmatched requests too, e.g. CORS preflights
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+	r.mu.RUnlock()
 
-	// Not found
+	// Execute handler
+	if err := handler(ctx); err != nil {
+		ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+	}
+}
+
+// notFoundHandler answers requests matching no route.
+func notFoundHandler(ctx *FastRequestContext) error {
 	ctx.Error("Not Found", fasthttp.StatusNotFound)
+	return nil
 }
 
 func (r *FastRouter) GETFast(path string, handler FastRequestHandler) {
@@ -145,11 +164,17 @@ func (r *FastRouter) RouteFast(method, path string, handler FastRequestHandler)
 }
 
 // RouteFastWith registers a fast handler with per-route middleware.
+// Route middleware runs inside the global middleware (see UseFast), the first
+// one outermost; a middleware that does not call next ends the request there.
+//
+// A path segment "{name}" (or ":name") matches any one segment and "{name...}"
+// (or "*name") as the last segment matches the rest of the path, both read
+// with Param; a bare "*" wildcard is read with Param("*").
+// Literal segments take precedence over params, so "/users/new" wins over
+// "/users/{id}"; a trailing slash is ignored. Panics if a catch-all is not the
+// last segment.
 func (r *FastRouter) RouteFastWith(method, path string, handler FastRequestHandler, middleware ...FastMiddleware) {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	r.routes = append(r.routes, &fastRoute{
+	r.addRoute(&fastRoute{
 		method:     method,
 		path:       path,
 		handler:    handler,
@@ -157,6 +182,36 @@ func (r *FastRouter) RouteFastWith(method, path string, handler FastRequestHandl
 	})
 }
 
+// addRoute inserts route into the tree of its method.
+func (r *FastRouter) addRoute(route *fastRoute) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tree, ok := r.trees[route.method]
+	if !ok {
+		tree = &routeNode{}
+		r.trees[route.method] = tree
+	}
+	tree.insert(route)
+}
+
+// lookup finds the route for a request (r.mu must be held).
+func (r *FastRouter) lookup(method, path string) (*fastRoute, []string) {
+	tree, ok := r.trees[method]
+	if !ok {
+		return nil, nil
+	}
+	return tree.lookup(path)
+}
+
+// isWebSocket reports whether method and path match a WS route.
+func (r *FastRouter) isWebSocket(method, path string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	route, _ := r.lookup(method, path)
+	return route != nil && route.websocket
+}
+
 func (r *FastRouter) Route(method, path string, handler RequestHandler) {
 	// Convert to FastRequestHandler
 	r.RouteFast(method, path, func(ctx *FastRequestContext) error {
@@ -172,6 +227,8 @@ func (r *FastRouter) Route(method, path string, handler RequestHandler) {
 	})
 }
 
+// Use registers global net/http-style middleware; prefer UseFast for
+// FastMiddleware such as the ones in pkg/web/middleware.
 func (r *FastRouter) Use(middleware Middleware) {
 	// Convert middleware to FastMiddleware
 	r.mu.Lock()
@@ -194,43 +251,12 @@ func (r *FastRouter) Use(middleware Middleware) {
 	})
 }
 
-// UseFast registers global fasthttp middleware.
+// UseFast registers global fasthttp middleware, applied to every route
+// (including routes registered earlier) in registration order, the first one
+// outermost. The chain runs on the request's goroutine inside the server's
+// panic recovery, so a panicking middleware yields a 500 like a handler.
 func (r *FastRouter) UseFast(middleware ...FastMiddleware) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.middleware = append(r.middleware, middleware...)
 }
-
-func (r *FastRouter) matchPath(pattern, path string) bool {
-	patternParts := strings.Split(pattern, "/")
-	pathParts := strings.Split(path, "/")
-
-	if len(patternParts) != len(pathParts) {
-		return false
-	}
-
-	for i, part := range patternParts {
-		if strings.HasPrefix(part, ":") {
-			continue // Parameter
-		}
-		if part != pathParts[i] {
-			return false
-		}
-	}
-
-	return true
-}
-
-func (r *FastRouter) extractParams(pattern, path string, params map[string]string) {
-	patternParts := strings.Split(pattern, "/")
-	pathParts := strings.Split(path, "/")
-
-	for i, part := range patternParts {
-		if strings.HasPrefix(part, ":") {
-			paramName := strings.TrimPrefix(part, ":")
-			if i < len(pathParts) {
-				params[paramName] = pathParts[i]
-			}
-		}
-	}
-}
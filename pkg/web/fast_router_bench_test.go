@@ -0,0 +1,45 @@
+package web
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// BenchmarkFastRouter_Lookup looks up a param route among n routes: the cost
+// follows the path's segments, not n.
+func BenchmarkFastRouter_Lookup(b *testing.B) {
+	for _, n := range []int{10, 100, 1000, 10000} {
+		b.Run(fmt.Sprintf("routes=%d", n), func(b *testing.B) {
+			router := NewFastRouter()
+			for i := 0; i < n; i++ {
+				router.GETFast(fmt.Sprintf("/api/v1/resource%d/{id}", i), func(c *FastRequestContext) error { return nil })
+			}
+			path := fmt.Sprintf("/api/v1/resource%d/42", n-1)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if route, _ := router.lookup("GET", path); route == nil {
+					b.Fatal("no route")
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFastRouter_LookupDepth looks up paths of growing depth.
+func BenchmarkFastRouter_LookupDepth(b *testing.B) {
+	for _, depth := range []int{2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("segments=%d", depth), func(b *testing.B) {
+			pattern := strings.Repeat("/seg/{p}", depth/2)
+			router := NewFastRouter()
+			router.GETFast(pattern, func(c *FastRequestContext) error { return nil })
+			path := strings.Repeat("/seg/x", depth/2)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if route, _ := router.lookup("GET", path); route == nil {
+					b.Fatal("no route")
+				}
+			}
+		})
+	}
+}
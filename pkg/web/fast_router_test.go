@@ -0,0 +1,249 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/valyala/fasthttp"
+)
+
+// serveRouter runs one request through the server, as a fasthttp connection
+// would, and returns the response.
+func serveRouter(server *FastHTTPServer, method, path string) *fasthttp.Response {
+	rc := &fasthttp.RequestCtx{}
+	rc.Request.Header.SetMethod(method)
+	rc.Request.SetRequestURI(path)
+	server.handleRequest(rc)
+	return &rc.Response
+}
+
+// serveWithHeaders is serveRouter with the given request headers set.
+func serveWithHeaders(server *FastHTTPServer, method, path string, headers map[string]string) *fasthttp.Response {
+	rc := &fasthttp.RequestCtx{}
+	rc.Request.Header.SetMethod(method)
+	rc.Request.SetRequestURI(path)
+	for k, v := range headers {
+		rc.Request.Header.Set(k, v)
+	}
+	server.handleRequest(rc)
+	return &rc.Response
+}
+
+func newRouterServer(t *testing.T) *FastHTTPServer {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	return NewFastHTTPServer(gocmd, DefaultFastHTTPServerConfig(":0"))
+}
+
+// tracing returns middleware recording its name before and after next.
+func tracing(calls *[]string, name string) FastMiddleware {
+	return func(next FastRequestHandler) FastRequestHandler {
+		return func(c *FastRequestContext) error {
+			*calls = append(*calls, name+">")
+			err := next(c)
+			*calls = append(*calls, "<"+name)
+			return err
+		}
+	}
+}
+
+func TestFastRouter_MiddlewareOrder(t *testing.T) {
+	server := newRouterServer(t)
+	router := server.FastRouter()
+
+	var calls []string
+	router.UseFast(tracing(&calls, "global1"), tracing(&calls, "global2"))
+	router.GETFastWith("/orders/:id", func(c *FastRequestContext) error {
+		calls = append(calls, "handler "+c.Param("id"))
+		return c.Text(200, "ok")
+	}, tracing(&calls, "route1"), tracing(&calls, "route2"))
+	router.UseFast(tracing(&calls, "global3")) // registered later, still runs for every route
+
+	if resp := serveRouter(server, "GET", "/orders/7"); resp.StatusCode() != 200 {
+		t.Fatalf("status = %d", resp.StatusCode())
+	}
+	want := []string{
+		"global1>", "global2>", "global3>", "route1>", "route2>",
+		"handler 7",
+		"<route2", "<route1", "<global3", "<global2", "<global1",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+
+	// Route middleware is scoped to its route
+	calls = nil
+	router.POSTFast("/orders", func(c *FastRequestContext) error { return c.Text(201, "created") })
+	serveRouter(server, "POST", "/orders")
+	if want := []string{"global1>", "global2>", "global3>", "<global3", "<global2", "<global1"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want only the global middleware", calls)
+	}
+}
+
+func TestFastRouter_MiddlewareShortCircuit(t *testing.T) {
+	server := newRouterServer(t)
+	router := server.FastRouter()
+
+	handled := false
+	requireToken := func(next FastRequestHandler) FastRequestHandler {
+		return func(c *FastRequestContext) error {
+			if len(c.RequestCtx.Request.Header.Peek("Authorization")) == 0 {
+				return c.JSON(401, map[string]interface{}{"error": "unauthorized"})
+			}
+			return next(c)
+		}
+	}
+	router.GETFastWith("/private", func(c *FastRequestContext) error {
+		handled = true
+		return c.Text(200, "secret")
+	}, requireToken)
+
+	resp := serveRouter(server, "GET", "/private")
+	if resp.StatusCode() != 401 || handled {
+		t.Errorf("status = %d, handler ran = %v; want 401 without running the handler", resp.StatusCode(), handled)
+	}
+}
+
+func TestFastRouter_MiddlewarePanicIsolated(t *testing.T) {
+	server := newRouterServer(t)
+	router := server.FastRouter()
+	router.UseFast(func(next FastRequestHandler) FastRequestHandler {
+		return func(c *FastRequestContext) error {
+			if string(c.Path()) == "/boom" {
+				panic("middleware failure")
+			}
+			return next(c)
+		}
+	})
+	router.GETFast("/boom", func(c *FastRequestContext) error { return c.Text(200, "unreachable") })
+	router.GETFast("/ok", func(c *FastRequestContext) error { return c.Text(200, "ok") })
+
+	resp := serveRouter(server, "GET", "/boom")
+	if resp.StatusCode() != 500 || !strings.Contains(string(resp.Body()), "handler_panic") {
+		t.Errorf("panicking middleware: status = %d, body = %s; want a 500", resp.StatusCode(), resp.Body())
+	}
+	if resp := serveRouter(server, "GET", "/ok"); resp.StatusCode() != 200 {
+		t.Errorf("after a panic: status = %d, want 200", resp.StatusCode())
+	}
+}
+
+// paramsRouter registers a route per pattern whose handler answers with the
+// pattern and its params.
+func paramsRouter(t *testing.T, method string, patterns ...string) *FastHTTPServer {
+	t.Helper()
+	server := newRouterServer(t)
+	for _, pattern := range patterns {
+		server.FastRouter().RouteFast(method, pattern, func(c *FastRequestContext) error {
+			return c.JSON(200, map[string]interface{}{"route": c.Route(), "params": c.Params})
+		})
+	}
+	return server
+}
+
+func TestFastRouter_PathParams(t *testing.T) {
+	server := paramsRouter(t, "GET",
+		"/users/{id}",
+		"/users/new",
+		"/users/{id}/posts/{postID}",
+		"/users/:id/settings",
+		"/files/{path...}",
+		"/",
+	)
+	tests := []struct {
+		path   string
+		route  string
+		params map[string]interface{}
+	}{
+		{"/users/42", "/users/{id}", map[string]interface{}{"id": "42"}},
+		{"/users/new", "/users/new", map[string]interface{}{}},
+		{"/users/42/posts/7", "/users/{id}/posts/{postID}", map[string]interface{}{"id": "42", "postID": "7"}},
+		{"/users/new/posts/7", "/users/{id}/posts/{postID}", map[string]interface{}{"id": "new", "postID": "7"}},
+		{"/users/42/settings", "/users/:id/settings", map[string]interface{}{"id": "42"}},
+		{"/files/css/site.css", "/files/{path...}", map[string]interface{}{"path": "css/site.css"}},
+		{"/files", "/files/{path...}", map[string]interface{}{"path": ""}},
+		{"/users/42/", "/users/{id}", map[string]interface{}{"id": "42"}},
+		{"/", "/", map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp := serveRouter(server, "GET", tt.path)
+			if resp.StatusCode() != 200 {
+				t.Fatalf("status = %d, want 200", resp.StatusCode())
+			}
+			var got struct {
+				Route  string                 `json:"route"`
+				Params map[string]interface{} `json:"params"`
+			}
+			if err := json.Unmarshal(resp.Body(), &got); err != nil {
+				t.Fatalf("decode %s: %v", resp.Body(), err)
+			}
+			if got.Route != tt.route || !reflect.DeepEqual(got.Params, tt.params) {
+				t.Errorf("matched %s %v, want %s %v", got.Route, got.Params, tt.route, tt.params)
+			}
+		})
+	}
+
+	for _, path := range []string{"/users", "/users/42/posts", "/users//posts/7", "/other"} {
+		if resp := serveRouter(server, "GET", path); resp.StatusCode() != 404 {
+			t.Errorf("GET %s: status = %d, want 404", path, resp.StatusCode())
+		}
+	}
+}
+
+func TestFastRouter_Wildcards(t *testing.T) {
+	server := paramsRouter(t, "GET", "/orders/{orderId}", "/assets/*", "/docs/*page")
+	tests := []struct {
+		path   string
+		params map[string]interface{}
+	}{
+		{"/orders/o-17", map[string]interface{}{"orderId": "o-17"}},
+		{"/assets/img/logo.png", map[string]interface{}{"*": "img/logo.png"}},
+		{"/docs/guide/intro", map[string]interface{}{"page": "guide/intro"}},
+	}
+	for _, tt := range tests {
+		resp := serveRouter(server, "GET", tt.path)
+		var got struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		if err := json.Unmarshal(resp.Body(), &got); err != nil {
+			t.Fatalf("GET %s: decode %s: %v", tt.path, resp.Body(), err)
+		}
+		if !reflect.DeepEqual(got.Params, tt.params) {
+			t.Errorf("GET %s: params = %v, want %v", tt.path, got.Params, tt.params)
+		}
+	}
+	if resp := serveRouter(server, "GET", "/orders"); resp.StatusCode() != 404 {
+		t.Errorf("GET /orders: status = %d, want 404", resp.StatusCode())
+	}
+}
+
+func TestFastRouter_MethodSpecificRoutes(t *testing.T) {
+	server := newRouterServer(t)
+	router := server.FastRouter()
+	router.GETFast("/items/{id}", func(c *FastRequestContext) error { return c.Text(200, "get "+c.Param("id")) })
+	router.DELETEFast("/items/{itemID}", func(c *FastRequestContext) error { return c.Text(200, "delete "+c.Param("itemID")) })
+
+	if resp := serveRouter(server, "GET", "/items/3"); string(resp.Body()) != "get 3" {
+		t.Errorf("GET body = %q", resp.Body())
+	}
+	if resp := serveRouter(server, "DELETE", "/items/3"); string(resp.Body()) != "delete 3" {
+		t.Errorf("DELETE body = %q", resp.Body())
+	}
+	if resp := serveRouter(server, "PUT", "/items/3"); resp.StatusCode() != 404 {
+		t.Errorf("PUT status = %d, want 404", resp.StatusCode())
+	}
+}
+
+func TestFastRouter_CatchAllMustBeLast(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("registering /files/{path...}/meta did not panic")
+		}
+	}()
+	NewFastRouter().GETFast("/files/{path...}/meta", func(c *FastRequestContext) error { return nil })
+}
@@ -2,11 +2,14 @@ package web
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"mime/multipart"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/fasthttp/websocket"
 	"github.com/fluxorio/fluxor/pkg/core"
 	"github.com/fluxorio/fluxor/pkg/core/concurrency"
 	"github.com/valyala/fasthttp"
@@ -20,6 +23,8 @@ type FastHTTPServer struct {
 	router           *FastRouter
 	server           *fasthttp.Server
 	addr             string
+	certFile         string // PEM certificate and key for HTTPS (see FastHTTPServerConfig)
+	keyFile          string
 	requestMailbox   concurrency.Mailbox  // Abstracted: hides chan *fasthttp.RequestCtx
 	executor         concurrency.Executor // Abstracted: hides goroutine pool
 	maxQueue         int
@@ -34,8 +39,25 @@ type FastHTTPServer struct {
 	backpressure *BackpressureController
 	// Start workers once (constructor and Start() might both call it).
 	startWorkersOnce sync.Once
+	// Instruments reported through GoCMD.Metrics (nil when unset)
+	metrics *httpMetrics
+	// Graceful stop: requests being handled, and whether new ones get 503
+	inFlight     int64
+	draining     atomic.Bool
+	drainTimeout time.Duration
+	// Deadline of the Shutdown in progress (nil: Stop, bounded by drainTimeout)
+	stopMu  sync.Mutex
+	stopCtx context.Context
+	// Response compression; 0 disables it
+	compressionMinSize int
+	// Memory bound of parsed multipart forms
+	multipartMemory int64
 }
 
+// DefaultDrainTimeout bounds how long Stop waits for in-flight requests when
+// FastHTTPServerConfig.DrainTimeout is unset.
+const DefaultDrainTimeout = 5 * time.Second
+
 // FastHTTPServerConfig configures the fasthttp server
 type FastHTTPServerConfig struct {
 	Addr            string
@@ -46,6 +68,36 @@ type FastHTTPServerConfig struct {
 	MaxConns        int
 	ReadBufferSize  int
 	WriteBufferSize int
+
+	// CertFile and KeyFile (PEM) make the server serve HTTPS.
+	CertFile string
+	KeyFile  string
+	// TLSConfig also makes the server serve HTTPS, for certificates held in
+	// memory, mutual TLS (ClientAuth, ClientCAs) or custom cipher suites.
+	// Its GetCertificate is asked on every handshake, which lets certificates
+	// rotate without a restart. CertFile/KeyFile, when set too, are added to
+	// its certificates.
+	TLSConfig *tls.Config
+
+	// EnableCompression gzip- or deflate-encodes text, JSON and XML
+	// responses of at least CompressionMinSize bytes (default
+	// DefaultCompressionMinSize) for clients whose Accept-Encoding allows it.
+	EnableCompression  bool
+	CompressionMinSize int
+
+	// DrainTimeout bounds how long Stop lets in-flight requests finish
+	// (default DefaultDrainTimeout). See FastHTTPServer.Shutdown.
+	DrainTimeout time.Duration
+
+	// MaxRequestBodySize is the request body limit in bytes (default
+	// DefaultMaxRequestBodySize); larger bodies are answered 413 and never
+	// reach BindJSON. FastRouter.SetBodyLimit overrides it per route.
+	MaxRequestBodySize int
+
+	// MaxMultipartMemory is how much of a multipart form FormFile and
+	// FormValue keep in memory (default DefaultMaxMultipartMemory); bigger
+	// files are spooled to temporary files removed when the request ends.
+	MaxMultipartMemory int64
 }
 
 // DefaultFastHTTPServerConfig returns default configuration for 100k RPS
@@ -150,6 +202,10 @@ func NewFastHTTPServer(gocmd core.GoCMD, config *FastHTTPServerConfig) *FastHTTP
 	}
 
 	router := NewFastRouter()
+	router.bodyLimit = config.MaxRequestBodySize
+	if router.bodyLimit <= 0 {
+		router.bodyLimit = DefaultMaxRequestBodySize
+	}
 
 	// Calculate normal CCU capacity (queue + workers)
 	// This is the target utilization capacity (e.g., 67% of max)
@@ -171,6 +227,8 @@ func NewFastHTTPServer(gocmd core.GoCMD, config *FastHTTPServerConfig) *FastHTTP
 		BaseServer:     core.NewBaseServer("fasthttp-server", gocmd),
 		router:         router,
 		addr:           config.Addr,
+		certFile:       config.CertFile,
+		keyFile:        config.KeyFile,
 		requestMailbox: requestMailbox, // Abstracted: hides chan
 		executor:       executor,       // Abstracted: hides goroutines
 		maxQueue:       config.MaxQueue,
@@ -178,7 +236,10 @@ func NewFastHTTPServer(gocmd core.GoCMD, config *FastHTTPServerConfig) *FastHTTP
 		// Initialize backpressure controller with normal capacity
 		// This ensures 67% utilization under normal load
 		// Reset interval: 60 seconds (for metrics)
-		backpressure: NewBackpressureController(normalCapacity, 60),
+		backpressure:    NewBackpressureController(normalCapacity, 60),
+		metrics:         newHTTPMetrics(gocmd.Metrics()),
+		drainTimeout:    config.DrainTimeout,
+		multipartMemory: config.MaxMultipartMemory,
 		server: &fasthttp.Server{
 			ReadTimeout:                   config.ReadTimeout,
 			WriteTimeout:                  config.WriteTimeout,
@@ -188,6 +249,8 @@ func NewFastHTTPServer(gocmd core.GoCMD, config *FastHTTPServerConfig) *FastHTTP
 			DisableHeaderNamesNormalizing: false,
 			NoDefaultServerHeader:         true,
 			ReduceMemoryUsage:             false, // Must be false when RequestCtx is passed through channels
+			// Forms are parsed on demand by FastRequestContext.MultipartForm
+			DisablePreParseMultipartForm: true,
 		},
 	}
 
@@ -196,6 +259,24 @@ func NewFastHTTPServer(gocmd core.GoCMD, config *FastHTTPServerConfig) *FastHTTP
 
 	// Set handler after server is created
 	s.server.Handler = s.handleRequest
+	s.server.ErrorHandler = s.handleReadError
+	s.server.MaxRequestBodySize = router.bodyLimit
+	router.metrics = s.metrics
+
+	if s.drainTimeout <= 0 {
+		s.drainTimeout = DefaultDrainTimeout
+	}
+	if config.EnableCompression {
+		s.compressionMinSize = config.CompressionMinSize
+		if s.compressionMinSize <= 0 {
+			s.compressionMinSize = DefaultCompressionMinSize
+		}
+	}
+
+	// Copied: fasthttp adds CertFile/KeyFile to the config it is given
+	if config.TLSConfig != nil {
+		s.server.TLSConfig = config.TLSConfig.Clone()
+	}
 
 	// Start request processing workers using Executor (hides goroutine creation)
 	s.startRequestWorkers()
@@ -211,30 +292,104 @@ func (s *FastHTTPServer) doStart() error {
 	}
 	// Start request processing workers using Executor (hides goroutine creation)
 	s.startRequestWorkers()
-	s.Logger().Info(fmt.Sprintf("Starting FastHTTP server on %s", s.addr))
+	// Read bodies up to the largest route limit; routes enforce their own
+	s.server.MaxRequestBodySize = s.router.readBodyLimit()
 	// Start listening (blocking call)
-	err := s.server.ListenAndServe(s.addr)
+	var err error
+	if s.tlsEnabled() {
+		s.Logger().Info(fmt.Sprintf("Starting FastHTTP server with TLS on %s", s.addr))
+		err = s.server.ListenAndServeTLS(s.addr, s.certFile, s.keyFile)
+	} else {
+		s.Logger().Info(fmt.Sprintf("Starting FastHTTP server on %s", s.addr))
+		err = s.server.ListenAndServe(s.addr)
+	}
 	if err != nil {
 		s.Logger().Error(fmt.Sprintf("FastHTTP server error: %v", err))
 	}
 	return err
 }
 
+// tlsEnabled reports whether the server serves HTTPS.
+func (s *FastHTTPServer) tlsEnabled() bool {
+	return s.server.TLSConfig != nil || s.certFile != "" || s.keyFile != ""
+}
+
+// Shutdown stops the server gracefully: requests arriving from now on get
+// 503, those in flight may finish until ctx is done, then the server shuts
+// down. When ctx ends first the server still shuts down, and Shutdown
+// returns ctx's error. Stop is Shutdown bounded by the DrainTimeout.
+func (s *FastHTTPServer) Shutdown(ctx context.Context) error {
+	s.stopMu.Lock()
+	s.stopCtx = ctx
+	s.stopMu.Unlock()
+	defer func() {
+		s.stopMu.Lock()
+		s.stopCtx = nil
+		s.stopMu.Unlock()
+	}()
+	return s.BaseServer.Stop()
+}
+
 // doStop is called by BaseServer.Stop() - implements hook method
 func (s *FastHTTPServer) doStop() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	s.stopMu.Lock()
+	ctx := s.stopCtx
+	s.stopMu.Unlock()
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), s.drainTimeout)
+		defer cancel()
+	}
+
+	// Drain: refuse new requests, let those in flight finish
+	s.draining.Store(true)
+	drainErr := s.drain(ctx)
 
 	// Close request mailbox (hides channel close)
 	s.requestMailbox.Close()
 
+	// Past the drain deadline, the rest still gets a moment
+	stopCtx := ctx
+	if drainErr != nil {
+		var cancel context.CancelFunc
+		stopCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+		defer cancel()
+	}
+
 	// Shutdown executor (hides goroutine cleanup)
-	if err := s.executor.Shutdown(ctx); err != nil {
-		return err
+	if err := s.executor.Shutdown(stopCtx); err != nil {
+		s.Logger().Error(fmt.Sprintf("shutting down workers: %v", err))
+	}
+
+	// Close WebSocket connections; fasthttp's shutdown does not track the
+	// connections it hijacked. New upgrades are refused from now on.
+	if err := s.router.websockets.closeAll(stopCtx); err != nil {
+		s.Logger().Error(fmt.Sprintf("closing websocket connections: %v", err))
 	}
 
 	// Shutdown server
-	return s.server.ShutdownWithContext(ctx)
+	if err := s.server.ShutdownWithContext(stopCtx); err != nil && drainErr == nil {
+		return err
+	}
+	return drainErr
+}
+
+// drain waits until no request is in flight or ctx is done.
+func (s *FastHTTPServer) drain(ctx context.Context) error {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		n := atomic.LoadInt64(&s.inFlight)
+		if n == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			s.Logger().Error(fmt.Sprintf("drain deadline reached with %d requests in flight", n))
+			return fmt.Errorf("drain: %d requests still in flight: %w", n, ctx.Err())
+		case <-ticker.C:
+		}
+	}
 }
 
 // Router returns the router
@@ -294,17 +449,52 @@ func (s *FastHTTPServer) handleRequest(ctx *fasthttp.RequestCtx) {
 	method := string(ctx.Method())
 	path := string(ctx.Path())
 
+	// Record every outcome, including 503s and recovered panics below
+	if s.metrics != nil {
+		defer s.metrics.observe(ctx, time.Now())
+	}
+
 	// Check if GoCMD context is cancelled
 	gocmdCtx := s.GoCMD().Context()
 	select {
 	case <-gocmdCtx.Done():
 		s.Logger().Info(fmt.Sprintf("GoCMD context cancelled: %v", gocmdCtx.Err()))
+		s.metrics.reject("shutting_down")
 		ctx.Error("Service Unavailable", fasthttp.StatusServiceUnavailable)
 		return
 	default:
 		// Context is still active
 	}
 
+	// Upgrades to WS routes bypass backpressure: the connection is long-lived
+	// and leaves the request path as soon as it is upgraded. Upgrade headers
+	// on any other route do not.
+	if websocket.FastHTTPIsWebSocketUpgrade(ctx) && s.router.isWebSocket(method, path) {
+		s.processUpgrade(ctx)
+		return
+	}
+
+	// Counted before the draining check, so drain never misses a request
+	atomic.AddInt64(&s.inFlight, 1)
+	defer func() {
+		// Requests finishing during a drain close their connection rather
+		// than leaving it idle for the shutdown to cut
+		if s.draining.Load() {
+			ctx.SetConnectionClose()
+		}
+		atomic.AddInt64(&s.inFlight, -1)
+		s.reportLoad()
+	}()
+	if s.draining.Load() {
+		s.metrics.reject("shutting_down")
+		ctx.Error("Service Unavailable", fasthttp.StatusServiceUnavailable)
+		ctx.SetContentType("application/json")
+		if _, err := ctx.WriteString(`{"error":"shutting_down","message":"Server is shutting down","code":"SHUTTING_DOWN"}`); err != nil {
+			s.Logger().Error(fmt.Sprintf("failed to write shutdown response: %v", err))
+		}
+		return
+	}
+
 	// Step 1: Check backpressure controller (normal capacity limiting)
 	// Normal capacity = target utilization (e.g., 67% of max)
 	// This ensures system operates at target utilization under normal load
@@ -313,6 +503,7 @@ func (s *FastHTTPServer) handleRequest(ctx *fasthttp.RequestCtx) {
 		// This maintains target utilization (e.g., 67%) under normal conditions
 		s.Logger().Info(fmt.Sprintf("backpressure: capacity exceeded for %s %s", method, path))
 		atomic.AddInt64(&s.rejectedRequests, 1)
+		s.metrics.reject("backpressure")
 		ctx.Error("Service Unavailable", fasthttp.StatusServiceUnavailable)
 		ctx.SetContentType("application/json")
 		if _, err := ctx.WriteString(`{"error":"capacity_exceeded","message":"Server at normal capacity - backpressure applied","code":"BACKPRESSURE"}`); err != nil {
@@ -326,27 +517,46 @@ func (s *FastHTTPServer) handleRequest(ctx *fasthttp.RequestCtx) {
 	// We still use backpressure for rate limiting, but process in same goroutine
 	// Use defer to ensure backpressure is always released, even on panic
 	defer s.backpressure.Release()
+	s.reportLoad()
 
 	// Process with panic recovery to ensure backpressure is released
-	defer func() {
-		if r := recover(); r != nil {
-			// Handler panic: return 500 error instead of crashing
-			ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
-			ctx.SetContentType("application/json")
-			requestID := string(ctx.Request.Header.Peek("X-Request-ID"))
-			if requestID == "" {
-				requestID = "unknown"
-			}
-			s.Logger().Error(fmt.Sprintf("handler panic (request_id=%s): %v", requestID, r))
-			if _, err := ctx.WriteString(fmt.Sprintf(`{"error":"handler_panic","message":"Request handler failed","request_id":"%s"}`, requestID)); err != nil {
-				s.Logger().Error(fmt.Sprintf("failed to write panic response: %v", err))
-			}
-		}
-	}()
+	defer s.recoverHandlerPanic(ctx)
+
+	s.processRequest(ctx)
+}
+
+// reportLoad updates the load gauges (in flight, CCU utilization).
+func (s *FastHTTPServer) reportLoad() {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.load(atomic.LoadInt64(&s.inFlight), s.backpressure.GetMetrics().Utilization)
+}
 
+// processUpgrade routes a WebSocket upgrade request outside backpressure.
+func (s *FastHTTPServer) processUpgrade(ctx *fasthttp.RequestCtx) {
+	defer s.recoverHandlerPanic(ctx)
 	s.processRequest(ctx)
 }
 
+// recoverHandlerPanic answers a request whose handler panicked with a 500.
+// It must be deferred.
+func (s *FastHTTPServer) recoverHandlerPanic(ctx *fasthttp.RequestCtx) {
+	if r := recover(); r != nil {
+		// Handler panic: return 500 error instead of crashing
+		ctx.Error("Internal Server Error", fasthttp.StatusInternalServerError)
+		ctx.SetContentType("application/json")
+		requestID := string(ctx.Request.Header.Peek("X-Request-ID"))
+		if requestID == "" {
+			requestID = "unknown"
+		}
+		s.Logger().Error(fmt.Sprintf("handler panic (request_id=%s): %v", requestID, r))
+		if _, err := ctx.WriteString(fmt.Sprintf(`{"error":"handler_panic","message":"Request handler failed","request_id":"%s"}`, requestID)); err != nil {
+			s.Logger().Error(fmt.Sprintf("failed to write panic response: %v", err))
+		}
+	}
+}
+
 // SetHandler sets the request handler
 func (s *FastHTTPServer) SetHandler(handler func(*fasthttp.RequestCtx)) {
 	s.server.Handler = handler
@@ -466,7 +676,13 @@ func (s *FastHTTPServer) processRequest(ctx *fasthttp.RequestCtx) {
 		EventBus:           s.EventBus(),
 		Params:             make(map[string]string),
 		requestID:          requestID,
+		multipartMemory:    s.multipartMemory,
 	}
+	defer func() {
+		if err := reqCtx.removeForm(); err != nil {
+			s.Logger().Error(fmt.Sprintf("removing multipart temp files (request_id=%s): %v", requestID, err))
+		}
+	}()
 
 	// Set request ID in response header for tracing
 	ctx.Response.Header.Set("X-Request-ID", requestID)
@@ -476,13 +692,23 @@ func (s *FastHTTPServer) processRequest(ctx *fasthttp.RequestCtx) {
 
 	// Route request - errors are propagated immediately (fail-fast)
 	s.router.ServeFastHTTP(reqCtx)
+	if s.compressionMinSize > 0 {
+		compressResponse(ctx, s.compressionMinSize, fasthttp.CompressDefaultCompression)
+	}
 
-	// Track response status
+	// Track response status. Body() of a streamed response (SSE, JSONStream)
+	// would run the stream to its end here, so its length is not logged.
 	statusCode := ctx.Response.StatusCode()
-	bodyLen := len(ctx.Response.Body())
-	s.Logger().Info(fmt.Sprintf("request completed: %s %s -> status=%d body_len=%d (request_id=%s)", method, path, statusCode, bodyLen, requestID))
+	streaming := ctx.Response.IsBodyStream()
+	bodyLen := 0
+	if streaming {
+		s.Logger().Info(fmt.Sprintf("request completed: %s %s -> status=%d streaming (request_id=%s)", method, path, statusCode, requestID))
+	} else {
+		bodyLen = len(ctx.Response.Body())
+		s.Logger().Info(fmt.Sprintf("request completed: %s %s -> status=%d body_len=%d (request_id=%s)", method, path, statusCode, bodyLen, requestID))
+	}
 
-	if bodyLen == 0 && statusCode == 200 {
+	if !streaming && bodyLen == 0 && statusCode == 200 {
 		s.Logger().Info(fmt.Sprintf("response body is empty but status is 200 for %s %s (request_id=%s)", method, path, requestID))
 	}
 
@@ -501,7 +727,10 @@ type FastRequestContext struct {
 	GoCMD                    core.GoCMD
 	EventBus                 core.EventBus
 	Params                   map[string]string
-	requestID                string // Request ID for tracing
+	requestID                string          // Request ID for tracing
+	route                    string          // Pattern of the matched FastRouter route
+	multipartMemory          int64           // Memory bound of MultipartForm (0: default)
+	form                     *multipart.Form // Parsed by MultipartForm, removed after the request
 }
 
 // JSON writes JSON response (default format) - fail-fast
@@ -582,6 +811,13 @@ func (c *FastRequestContext) Param(key string) string {
 	return c.Params[key]
 }
 
+// Route returns the pattern of the FastRouter route that matched the
+// request, e.g. "/users/{id}", or "" when none did. Unlike the path it names
+// all requests to one route alike, as metrics and trace labels need.
+func (c *FastRequestContext) Route() string {
+	return c.route
+}
+
 // Method returns HTTP method
 func (c *FastRequestContext) Method() []byte {
 	return c.RequestCtx.Method()
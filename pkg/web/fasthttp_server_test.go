@@ -2,7 +2,19 @@ package web
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/fluxorio/fluxor/pkg/core"
 )
@@ -85,3 +97,296 @@ func TestDefaultFastHTTPServerConfig(t *testing.T) {
 		t.Error("Workers should be positive")
 	}
 }
+
+// startServer starts a server from config on a free loopback port and
+// returns its base URL (https when config enables TLS) once it accepts
+// connections. The server is stopped by the caller.
+func startServer(t *testing.T, config *FastHTTPServerConfig) (*FastHTTPServer, string, <-chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	config.Addr = ln.Addr().String()
+	_ = ln.Close()
+
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	server := NewFastHTTPServer(gocmd, config)
+	server.FastRouter().GETFast("/hello", func(c *FastRequestContext) error {
+		return c.Text(200, "hello over tls")
+	})
+	url := "http://" + config.Addr
+	if server.tlsEnabled() {
+		url = "https://" + config.Addr
+	}
+	started := make(chan error, 1)
+	go func() { started <- server.Start() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err := net.Dial("tcp4", config.Addr)
+		if err == nil {
+			_ = conn.Close()
+			return server, url, started
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not start listening: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// stopTLSServer stops server once its connections are gone: fasthttp's
+// per-IP TLS connections race when shutdown closes one a handler is closing.
+func stopTLSServer(t *testing.T, server *FastHTTPServer, clients ...*http.Client) {
+	t.Helper()
+	for _, client := range clients {
+		client.CloseIdleConnections()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for server.server.GetOpenConnectionsCount() > 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := server.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+}
+
+func TestFastHTTPServer_TLSCertFiles(t *testing.T) {
+	cert := newTestCert(t, "localhost", []string{"localhost"}, x509.ExtKeyUsageServerAuth)
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	keyDER, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert.Leaf)
+	client := &http.Client{Timeout: 2 * time.Second, Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: roots, ServerName: "localhost"},
+	}}
+
+	config := DefaultFastHTTPServerConfig("")
+	config.CertFile, config.KeyFile = certFile, keyFile
+	server, url, started := startServer(t, config)
+
+	resp, err := client.Get(url + "/hello")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != 200 || string(body) != "hello over tls" || resp.TLS == nil {
+		t.Errorf("response = %d %q (TLS %v), want 200 over TLS", resp.StatusCode, body, resp.TLS != nil)
+	}
+
+	// Plain HTTP is not served on the TLS listener
+	if resp, err := http.Get("http://" + config.Addr + "/hello"); err == nil {
+		resp.Body.Close()
+		if resp.StatusCode == 200 {
+			t.Error("plain HTTP request succeeded on the TLS listener")
+		}
+	}
+
+	stopTLSServer(t, server, client)
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Start() did not return after Stop()")
+	}
+	if _, err := net.Dial("tcp4", config.Addr); err == nil {
+		t.Error("TLS listener still accepts connections after Stop()")
+	}
+}
+
+func TestFastHTTPServer_TLSConfigMutualTLS(t *testing.T) {
+	serverCert := newTestCert(t, "localhost", []string{"localhost"}, x509.ExtKeyUsageServerAuth)
+	clientCert := newTestCert(t, "billing-service", nil, x509.ExtKeyUsageClientAuth)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCert.Leaf)
+	roots := x509.NewCertPool()
+	roots.AddCert(serverCert.Leaf)
+
+	config := DefaultFastHTTPServerConfig("")
+	config.TLSConfig = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	newClient := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Timeout: 2 * time.Second, Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: certs},
+		}}
+	}
+	withCert, withoutCert := newClient(clientCert), newClient()
+	server, url, _ := startServer(t, config)
+	defer stopTLSServer(t, server, withCert, withoutCert)
+
+	resp, err := withCert.Get(url + "/hello")
+	if err != nil {
+		t.Fatalf("GET with client certificate error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 200 {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if resp, err := withoutCert.Get(url + "/hello"); err == nil {
+		resp.Body.Close()
+		t.Error("GET without a client certificate succeeded")
+	}
+	if len(config.TLSConfig.Certificates) != 1 {
+		t.Error("server modified the caller's TLSConfig")
+	}
+}
+
+func TestFastHTTPServer_TLSConfigRotatesCertificates(t *testing.T) {
+	first := newTestCert(t, "gateway-2025", []string{"localhost"}, x509.ExtKeyUsageServerAuth)
+	second := newTestCert(t, "gateway-2026", []string{"localhost"}, x509.ExtKeyUsageServerAuth)
+	var current atomic.Pointer[tls.Certificate]
+	current.Store(&first)
+
+	config := DefaultFastHTTPServerConfig("")
+	config.TLSConfig = &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return current.Load(), nil },
+	}
+	server, url, _ := startServer(t, config)
+	server.FastRouter().GETFast("/health", func(c *FastRequestContext) error {
+		return c.JSON(200, map[string]string{"status": "UP"})
+	})
+
+	roots := x509.NewCertPool()
+	roots.AddCert(first.Leaf)
+	roots.AddCert(second.Leaf)
+	// A handshake per request, so each one sees the certificate in use
+	client := &http.Client{Timeout: 2 * time.Second, Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots, ServerName: "localhost"},
+		DisableKeepAlives: true,
+	}}
+	defer stopTLSServer(t, server, client)
+
+	for _, want := range []*tls.Certificate{&first, &second} {
+		current.Store(want)
+		resp, err := client.Get(url + "/health")
+		if err != nil {
+			t.Fatalf("GET /health error = %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != 200 || !strings.Contains(string(body), `"UP"`) {
+			t.Errorf("GET /health = %d %q", resp.StatusCode, body)
+		}
+		if got := resp.TLS.PeerCertificates[0].Subject.CommonName; got != want.Leaf.Subject.CommonName {
+			t.Errorf("served certificate %q, want %q", got, want.Leaf.Subject.CommonName)
+		}
+	}
+}
+
+func TestFastHTTPServer_StopDrainsInFlightRequests(t *testing.T) {
+	config := DefaultFastHTTPServerConfig("")
+	config.DrainTimeout = 5 * time.Second
+	server, url, _ := startServer(t, config)
+
+	const requests = 5
+	entered, release := make(chan struct{}, requests), make(chan struct{})
+	server.FastRouter().GETFast("/slow", func(c *FastRequestContext) error {
+		entered <- struct{}{}
+		<-release
+		return c.Text(200, "done")
+	})
+
+	type result struct {
+		status int
+		body   string
+		err    error
+	}
+	results := make(chan result, requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			resp, err := http.Get(url + "/slow")
+			if err != nil {
+				results <- result{err: err}
+				return
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			results <- result{resp.StatusCode, string(body), err}
+		}()
+	}
+	for i := 0; i < requests; i++ {
+		<-entered
+	}
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- server.Stop() }()
+	deadline := time.Now().Add(2 * time.Second)
+	for !server.draining.Load() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// New requests are refused while the backlog drains
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get(url + "/hello")
+	if err != nil {
+		t.Fatalf("GET during drain error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 503 {
+		t.Errorf("GET during drain status = %d, want 503", resp.StatusCode)
+	}
+	select {
+	case err := <-stopped:
+		t.Fatalf("Stop() returned %v before in-flight requests finished", err)
+	default:
+	}
+
+	close(release)
+	for i := 0; i < requests; i++ {
+		if r := <-results; r.err != nil || r.status != 200 || r.body != "done" {
+			t.Errorf("in-flight request = %d %q, %v; want 200 done", r.status, r.body, r.err)
+		}
+	}
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Errorf("Stop() error = %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Stop() did not return after the drain")
+	}
+}
+
+func TestFastHTTPServer_ShutdownDrainDeadline(t *testing.T) {
+	server, url, _ := startServer(t, DefaultFastHTTPServerConfig(""))
+	entered, release := make(chan struct{}, 1), make(chan struct{})
+	defer close(release)
+	server.FastRouter().GETFast("/stuck", func(c *FastRequestContext) error {
+		entered <- struct{}{}
+		<-release
+		return c.Text(200, "late")
+	})
+	go func() {
+		if resp, err := http.Get(url + "/stuck"); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := server.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "1 requests still in flight") {
+		t.Errorf("Shutdown() error = %v, want the drain deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Shutdown() took %s past its 100ms deadline", elapsed)
+	}
+}
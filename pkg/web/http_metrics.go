@@ -0,0 +1,60 @@
+package web
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/valyala/fasthttp"
+)
+
+// httpMetrics are the instruments of a FastHTTPServer.
+// A nil *httpMetrics (no backend configured on GoCMD) records nothing.
+type httpMetrics struct {
+	requests       core.Counter   // fluxor_http_server_requests_total{method,status}
+	duration       core.Histogram // fluxor_http_server_request_duration_seconds{method,status}
+	rejected       core.Counter   // fluxor_http_server_rejected_requests_total{reason}
+	inFlight       core.Gauge     // fluxor_http_server_in_flight_requests
+	ccuUtilization core.Gauge     // fluxor_http_server_ccu_utilization
+}
+
+func newHTTPMetrics(m core.Metrics) *httpMetrics {
+	if core.IsNoopMetrics(m) {
+		return nil
+	}
+	return &httpMetrics{
+		requests:       m.Counter("fluxor_http_server_requests_total", "HTTP requests served, by method and status code", "method", "status"),
+		duration:       m.Histogram("fluxor_http_server_request_duration_seconds", "HTTP request duration in seconds, by method and status code", "method", "status"),
+		rejected:       m.Counter("fluxor_http_server_rejected_requests_total", "HTTP requests rejected without reaching a handler, by reason (503 backpressure/shutting_down, 413 body_too_large)", "reason"),
+		inFlight:       m.Gauge("fluxor_http_server_in_flight_requests", "HTTP requests being handled"),
+		ccuUtilization: m.Gauge("fluxor_http_server_ccu_utilization", "Concurrent requests as a percentage of the normal CCU capacity"),
+	}
+}
+
+// observe records a finished request, including rejected (503) and panicked (500) ones.
+func (m *httpMetrics) observe(ctx *fasthttp.RequestCtx, start time.Time) {
+	if m == nil {
+		return
+	}
+	method := string(ctx.Method())
+	status := strconv.Itoa(ctx.Response.StatusCode())
+	m.requests.Add(1, method, status)
+	m.duration.Observe(time.Since(start).Seconds(), method, status)
+}
+
+// reject counts a request turned away before routing.
+func (m *httpMetrics) reject(reason string) {
+	if m == nil {
+		return
+	}
+	m.rejected.Add(1, reason)
+}
+
+// load records the server's current load.
+func (m *httpMetrics) load(inFlight int64, utilization float64) {
+	if m == nil {
+		return
+	}
+	m.inFlight.Set(float64(inFlight))
+	m.ccuUtilization.Set(utilization)
+}
@@ -0,0 +1,85 @@
+package web
+
+import (
+	"bufio"
+	"fmt"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// jsonStreamFlushEvery is the number of items written between flushes of a JSON stream.
+const jsonStreamFlushEvery = 100
+
+// JSONStream writes a JSON array response whose elements are read from items.
+//
+// Elements are encoded and written one by one while the producer is still
+// running, flushing every jsonStreamFlushEvery items, so the full array is
+// never held in memory. The producer must close items when done.
+//
+// Status and headers are sent before the first element; an element that
+// fails to encode (or a client disconnect) ends the array early and the
+// remaining items are drained and discarded so the producer never blocks.
+func (c *FastRequestContext) JSONStream(statusCode int, items <-chan interface{}) error {
+	// Fail-fast: validate status code and source
+	if statusCode < 100 || statusCode > 599 {
+		return fmt.Errorf("invalid status code: %d", statusCode)
+	}
+	if c.RequestCtx == nil {
+		return fmt.Errorf("RequestCtx is nil")
+	}
+	if items == nil {
+		return fmt.Errorf("items channel cannot be nil")
+	}
+
+	c.RequestCtx.SetStatusCode(statusCode)
+	c.RequestCtx.SetContentType("application/json")
+
+	requestID := c.requestID
+	c.RequestCtx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := writeJSONArray(w, items); err != nil {
+			core.NewDefaultLogger().Error(fmt.Sprintf("json stream aborted (request_id=%s): %v", requestID, err))
+			// Drain so the producer can finish and close the channel
+			for range items {
+			}
+		}
+	})
+	return nil
+}
+
+// writeJSONArray writes items as a JSON array to w, flushing periodically.
+// The closing bracket is written even when an element fails to encode.
+func writeJSONArray(w *bufio.Writer, items <-chan interface{}) error {
+	if err := w.WriteByte('['); err != nil {
+		return err
+	}
+
+	count := 0
+	for item := range items {
+		data, err := core.JSONEncode(item)
+		if err != nil {
+			_ = w.WriteByte(']')
+			_ = w.Flush()
+			return fmt.Errorf("json encode error at element %d: %w", count, err)
+		}
+		if count > 0 {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+
+		count++
+		if count%jsonStreamFlushEvery == 0 {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := w.WriteByte(']'); err != nil {
+		return err
+	}
+	return w.Flush()
+}
@@ -0,0 +1,219 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/valyala/fasthttp"
+)
+
+// chunkRecorder records how the streamed body arrives.
+type chunkRecorder struct {
+	data            []byte
+	writes          int
+	maxChunk        int
+	producedAtFirst int64
+	produced        *atomic.Int64
+}
+
+func (r *chunkRecorder) Write(p []byte) (int, error) {
+	if r.writes == 0 {
+		r.producedAtFirst = r.produced.Load()
+	}
+	r.writes++
+	if len(p) > r.maxChunk {
+		r.maxChunk = len(p)
+	}
+	r.data = append(r.data, p...)
+	return len(p), nil
+}
+
+func newStreamRequestContext(gocmd core.GoCMD) *FastRequestContext {
+	return &FastRequestContext{
+		BaseRequestContext: core.NewBaseRequestContext(),
+		RequestCtx:         &fasthttp.RequestCtx{},
+		GoCMD:              gocmd,
+		EventBus:           gocmd.EventBus(),
+		Params:             make(map[string]string),
+	}
+}
+
+func TestFastRequestContext_JSONStream(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	const total = 10000
+	var produced atomic.Int64
+	items := make(chan interface{})
+	go func() {
+		defer close(items)
+		for i := 0; i < total; i++ {
+			items <- map[string]interface{}{"id": i, "name": "item"}
+			produced.Add(1)
+		}
+	}()
+
+	ctx := newStreamRequestContext(gocmd)
+	if err := ctx.JSONStream(fasthttp.StatusOK, items); err != nil {
+		t.Fatalf("JSONStream() error = %v", err)
+	}
+	if got := string(ctx.RequestCtx.Response.Header.ContentType()); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+
+	rec := &chunkRecorder{produced: &produced}
+	if err := ctx.RequestCtx.Response.BodyWriteTo(rec); err != nil {
+		t.Fatalf("BodyWriteTo() error = %v", err)
+	}
+
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(rec.data, &decoded); err != nil {
+		t.Fatalf("streamed body is not valid JSON: %v", err)
+	}
+	if len(decoded) != total {
+		t.Fatalf("decoded %d items, want %d", len(decoded), total)
+	}
+	if decoded[total-1]["id"] != float64(total-1) {
+		t.Errorf("last item = %v, want id %d", decoded[total-1], total-1)
+	}
+
+	// Body arrived incrementally while the producer was still running
+	if rec.writes < 2 || rec.maxChunk >= len(rec.data) {
+		t.Errorf("body written in %d chunks (max %d of %d bytes), want incremental writes", rec.writes, rec.maxChunk, len(rec.data))
+	}
+	if rec.producedAtFirst >= total {
+		t.Errorf("first chunk written after all %d items were produced, want streaming", rec.producedAtFirst)
+	}
+}
+
+func TestFastRequestContext_JSONStream_EncodeError(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	items := make(chan interface{})
+	go func() {
+		defer close(items)
+		items <- map[string]interface{}{"id": 1}
+		items <- func() {} // not JSON-encodable
+		items <- map[string]interface{}{"id": 3}
+	}()
+
+	ctx := newStreamRequestContext(gocmd)
+	if err := ctx.JSONStream(fasthttp.StatusOK, items); err != nil {
+		t.Fatalf("JSONStream() error = %v", err)
+	}
+
+	var produced atomic.Int64
+	rec := &chunkRecorder{produced: &produced}
+	if err := ctx.RequestCtx.Response.BodyWriteTo(rec); err != nil {
+		t.Fatalf("BodyWriteTo() error = %v", err)
+	}
+
+	// Array is closed at the failing element; remaining items are drained
+	var decoded []map[string]interface{}
+	if err := json.Unmarshal(rec.data, &decoded); err != nil {
+		t.Fatalf("truncated stream is not valid JSON: %v (%s)", err, rec.data)
+	}
+	if len(decoded) != 1 {
+		t.Errorf("decoded %d items, want 1 before the encode error", len(decoded))
+	}
+}
+
+func TestFastRequestContext_JSONStream_Validation(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	ctx := newStreamRequestContext(gocmd)
+	if err := ctx.JSONStream(42, make(chan interface{})); err == nil {
+		t.Error("JSONStream() with invalid status should fail")
+	}
+	if err := ctx.JSONStream(fasthttp.StatusOK, nil); err == nil {
+		t.Error("JSONStream() with nil channel should fail")
+	}
+}
+
+// startStreamServer runs a FastHTTPServer with the routes added by register
+// on a free loopback port and returns its base URL once it accepts
+// connections. The server is stopped when the test ends, once its
+// connections are gone: fasthttp races when shutdown closes a connection
+// that is still being read.
+func startStreamServer(t *testing.T, register func(r *FastRouter)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	gocmd := core.NewGoCMD(context.Background())
+	server := NewFastHTTPServer(gocmd, DefaultFastHTTPServerConfig(addr))
+	register(server.FastRouter())
+	go func() { _ = server.Start() }()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(2 * time.Second)
+		for server.server.GetOpenConnectionsCount() > 0 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		_ = server.Stop()
+		_ = gocmd.Close()
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		conn, err := net.Dial("tcp4", addr)
+		if err == nil {
+			_ = conn.Close()
+			return "http://" + addr
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not start listening: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFastHTTPServer_JSONStreamReachesClientIncrementally(t *testing.T) {
+	release := make(chan struct{})
+	url := startStreamServer(t, func(r *FastRouter) {
+		r.GETFast("/items", func(c *FastRequestContext) error {
+			items := make(chan interface{})
+			go func() {
+				defer close(items)
+				// One flush worth of items, then hold until the client has read
+				for i := 0; i < jsonStreamFlushEvery; i++ {
+					items <- map[string]interface{}{"id": i}
+				}
+				<-release
+				items <- map[string]interface{}{"id": "last"}
+			}()
+			return c.JSONStream(fasthttp.StatusOK, items)
+		})
+	})
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+	resp, err := client.Get(url + "/items")
+	if err != nil {
+		close(release)
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	// The first element arrives while the producer is still blocked
+	first, err := bufio.NewReader(resp.Body).ReadString('}')
+	close(release)
+	if err != nil {
+		t.Fatalf("reading first element: %v", err)
+	}
+	if first != `[{"id":0}` {
+		t.Errorf("first element = %q, want [{\"id\":0}", first)
+	}
+}
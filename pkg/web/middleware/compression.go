@@ -40,7 +40,9 @@ func DefaultCompressionConfig() CompressionConfig {
 	}
 }
 
-// Compression middleware compresses HTTP responses
+// Compression middleware gzip- or deflate-encodes responses of the configured
+// content types, like FastHTTPServerConfig.EnableCompression does for the
+// whole server (see web.CompressResponse).
 func Compression(config CompressionConfig) web.FastMiddleware {
 	level := config.Level
 	if level < 1 || level > 9 {
@@ -92,15 +94,8 @@ func Compression(config CompressionConfig) web.FastMiddleware {
 					}
 				}
 
-				// Check response size and if client accepts gzip
-				bodySize := len(ctx.RequestCtx.Response.Body())
-				acceptEncoding := string(ctx.RequestCtx.Request.Header.Peek("Accept-Encoding"))
-				if shouldCompress && bodySize >= minSize && strings.Contains(acceptEncoding, "gzip") {
-					// fasthttp automatically handles gzip compression
-					// when Content-Encoding header is set before writing body
-					// Note: This is a simplified implementation
-					// For full compression, you may need to use fasthttp's compression features
-					ctx.RequestCtx.Response.Header.Set("Content-Encoding", "gzip")
+				if shouldCompress {
+					web.CompressResponse(ctx.RequestCtx, minSize, level)
 				}
 			}
 
@@ -1,10 +1,14 @@
 package middleware_test
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/fluxorio/fluxor/pkg/web"
 	"github.com/fluxorio/fluxor/pkg/web/middleware"
+	"github.com/valyala/fasthttp"
 )
 
 func TestLoggingMiddleware(t *testing.T) {
@@ -39,7 +43,58 @@ func TestCompressionMiddleware(t *testing.T) {
 
 	mw := middleware.Compression(config)
 	if mw == nil {
-		t.Error("Compression should return middleware")
+		t.Fatal("Compression should return middleware")
+	}
+
+	body := strings.Repeat(`{"status":"ok"}`, 200)
+	handler := mw(func(ctx *web.FastRequestContext) error {
+		return ctx.JSON(200, body)
+	})
+	ctx := &web.FastRequestContext{BaseRequestContext: core.NewBaseRequestContext(), RequestCtx: &fasthttp.RequestCtx{}}
+	ctx.RequestCtx.Request.Header.Set("Accept-Encoding", "gzip")
+	if err := handler(ctx); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+	resp := &ctx.RequestCtx.Response
+	if got := string(resp.Header.ContentEncoding()); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	decoded, err := resp.BodyGunzip()
+	if err != nil {
+		t.Fatalf("body is not gzip: %v", err)
+	}
+	if !strings.Contains(string(decoded), `{\"status\":\"ok\"}`) {
+		t.Errorf("decoded body = %.40q..., want the handler's JSON", decoded)
+	}
+}
+
+func TestCompressionMiddleware_ConfiguredContentTypes(t *testing.T) {
+	// Only the configured types are compressed, including ones the server's
+	// own compression would leave alone
+	mw := middleware.Compression(middleware.CompressionConfig{
+		MinSize:      1,
+		ContentTypes: []string{"application/octet-stream"},
+	})
+	for _, tt := range []struct {
+		contentType string
+		want        string
+	}{
+		{"application/octet-stream", "gzip"},
+		{"text/plain", ""},
+	} {
+		handler := mw(func(ctx *web.FastRequestContext) error {
+			ctx.RequestCtx.SetContentType(tt.contentType)
+			ctx.RequestCtx.SetBodyString(strings.Repeat("fluxor ", 200))
+			return nil
+		})
+		ctx := &web.FastRequestContext{BaseRequestContext: core.NewBaseRequestContext(), RequestCtx: &fasthttp.RequestCtx{}}
+		ctx.RequestCtx.Request.Header.Set("Accept-Encoding", "gzip")
+		if err := handler(ctx); err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+		if got := string(ctx.RequestCtx.Response.Header.ContentEncoding()); got != tt.want {
+			t.Errorf("%s: Content-Encoding = %q, want %q", tt.contentType, got, tt.want)
+		}
 	}
 }
 
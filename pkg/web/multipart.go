@@ -0,0 +1,101 @@
+package web
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+
+	"github.com/valyala/fasthttp"
+)
+
+// DefaultMaxMultipartMemory is how much of a multipart form is kept in
+// memory when FastHTTPServerConfig.MaxMultipartMemory is unset; larger files
+// are spooled to temporary files.
+const DefaultMaxMultipartMemory = 32 << 20
+
+// MultipartForm parses the request's multipart/form-data body, once.
+//
+// Up to the server's MaxMultipartMemory stays in memory, the rest of the
+// files go to temporary files that are removed when the request ends; the
+// body as a whole is bounded by the request body limit (see SetBodyLimit).
+// Returns fasthttp.ErrNoMultipartForm for other content types.
+func (c *FastRequestContext) MultipartForm() (*multipart.Form, error) {
+	if c.form != nil {
+		return c.form, nil
+	}
+	if c.RequestCtx == nil {
+		return nil, fmt.Errorf("RequestCtx is nil")
+	}
+	boundary := string(c.RequestCtx.Request.Header.MultipartFormBoundary())
+	if boundary == "" {
+		return nil, fasthttp.ErrNoMultipartForm
+	}
+
+	maxMemory := c.multipartMemory
+	if maxMemory <= 0 {
+		maxMemory = DefaultMaxMultipartMemory
+	}
+	form, err := multipart.NewReader(bytes.NewReader(c.RequestCtx.PostBody()), boundary).ReadForm(maxMemory)
+	if err != nil {
+		return nil, fmt.Errorf("parse multipart form: %w", err)
+	}
+	c.form = form
+	return form, nil
+}
+
+// FormValue returns the first value of the form field name, from a
+// multipart or URL-encoded body or the query string ("" when absent).
+func (c *FastRequestContext) FormValue(name string) string {
+	if len(c.RequestCtx.Request.Header.MultipartFormBoundary()) == 0 {
+		return string(c.RequestCtx.FormValue(name))
+	}
+	if form, err := c.MultipartForm(); err == nil {
+		if values := form.Value[name]; len(values) > 0 {
+			return values[0]
+		}
+	}
+	return string(c.RequestCtx.QueryArgs().Peek(name))
+}
+
+// FormFile returns the first file uploaded as the form field name. The
+// caller closes the file.
+func (c *FastRequestContext) FormFile(name string) (multipart.File, *multipart.FileHeader, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, nil, err
+	}
+	files := form.File[name]
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no file uploaded as %q: %w", name, fasthttp.ErrMissingFile)
+	}
+	file, err := files[0].Open()
+	if err != nil {
+		return nil, nil, fmt.Errorf("open uploaded file %q: %w", name, err)
+	}
+	return file, files[0], nil
+}
+
+// SaveUploadedFile writes the first file uploaded as the form field name to
+// dst, which is created or truncated.
+func (c *FastRequestContext) SaveUploadedFile(name, dst string) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		return err
+	}
+	files := form.File[name]
+	if len(files) == 0 {
+		return fmt.Errorf("no file uploaded as %q: %w", name, fasthttp.ErrMissingFile)
+	}
+	if err := fasthttp.SaveMultipartFile(files[0], dst); err != nil {
+		return fmt.Errorf("save uploaded file %q: %w", name, err)
+	}
+	return nil
+}
+
+// removeForm deletes the temporary files of a parsed multipart form.
+func (c *FastRequestContext) removeForm() error {
+	if c.form == nil {
+		return nil
+	}
+	return c.form.RemoveAll()
+}
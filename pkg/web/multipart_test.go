@@ -0,0 +1,141 @@
+package web
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/valyala/fasthttp"
+)
+
+// multipartBody builds a form with a "title" field and a "report" file.
+func multipartBody(t *testing.T, report []byte) (string, []byte) {
+	t.Helper()
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if err := w.WriteField("title", "Q3 numbers"); err != nil {
+		t.Fatal(err)
+	}
+	part, err := w.CreateFormFile("report", "q3.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := part.Write(report); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return w.FormDataContentType(), body.Bytes()
+}
+
+func serveForm(server *FastHTTPServer, path, contentType string, body []byte) *fasthttp.Response {
+	rc := &fasthttp.RequestCtx{}
+	rc.Request.Header.SetMethod("POST")
+	rc.Request.SetRequestURI(path)
+	rc.Request.Header.SetContentType(contentType)
+	rc.Request.SetBody(body)
+	server.handleRequest(rc)
+	return &rc.Response
+}
+
+func TestFastRequestContext_MultipartUpload(t *testing.T) {
+	server := newRouterServer(t)
+	saved := filepath.Join(t.TempDir(), "saved.csv")
+	report := []byte("region,total\nemea,42\n")
+
+	var title, filename string
+	var content []byte
+	var missingErr error
+	server.FastRouter().POSTFast("/reports", func(c *FastRequestContext) error {
+		title = c.FormValue("title")
+		file, header, err := c.FormFile("report")
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		filename = header.Filename
+		if content, err = io.ReadAll(file); err != nil {
+			return err
+		}
+		_, _, missingErr = c.FormFile("attachment")
+		if err := c.SaveUploadedFile("report", saved); err != nil {
+			return err
+		}
+		return c.Text(201, "stored")
+	})
+
+	contentType, body := multipartBody(t, report)
+	if resp := serveForm(server, "/reports", contentType, body); resp.StatusCode() != 201 {
+		t.Fatalf("status = %d (%s), want 201", resp.StatusCode(), resp.Body())
+	}
+	if title != "Q3 numbers" {
+		t.Errorf("FormValue(title) = %q, want %q", title, "Q3 numbers")
+	}
+	if filename != "q3.csv" || !bytes.Equal(content, report) {
+		t.Errorf("FormFile(report) = %q with %q, want q3.csv with the upload", filename, content)
+	}
+	if !errors.Is(missingErr, fasthttp.ErrMissingFile) {
+		t.Errorf("FormFile(attachment) error = %v, want ErrMissingFile", missingErr)
+	}
+	if got, err := os.ReadFile(saved); err != nil || !bytes.Equal(got, report) {
+		t.Errorf("SaveUploadedFile wrote %q (%v), want the upload", got, err)
+	}
+}
+
+func TestFastRequestContext_MultipartRemovesTempFiles(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	config := DefaultFastHTTPServerConfig(":0")
+	config.MaxMultipartMemory = 1 // every file goes to disk
+	server := NewFastHTTPServer(gocmd, config)
+
+	spooled := 0
+	server.FastRouter().POSTFast("/reports", func(c *FastRequestContext) error {
+		if _, err := c.MultipartForm(); err != nil {
+			return err
+		}
+		entries, _ := os.ReadDir(tmp)
+		spooled = len(entries)
+		return c.Text(201, "stored")
+	})
+
+	contentType, body := multipartBody(t, bytes.Repeat([]byte("emea,42\n"), 512))
+	if resp := serveForm(server, "/reports", contentType, body); resp.StatusCode() != 201 {
+		t.Fatalf("status = %d (%s), want 201", resp.StatusCode(), resp.Body())
+	}
+	if spooled == 0 {
+		t.Fatal("the upload was not spooled to a temporary file")
+	}
+	if entries, _ := os.ReadDir(tmp); len(entries) != 0 {
+		t.Errorf("%d temporary files left after the request", len(entries))
+	}
+}
+
+func TestFastRequestContext_FormValueWithoutMultipart(t *testing.T) {
+	server := newRouterServer(t)
+	var title string
+	var formErr error
+	server.FastRouter().POSTFast("/reports", func(c *FastRequestContext) error {
+		title = c.FormValue("title")
+		_, _, formErr = c.FormFile("report")
+		return c.Text(200, "ok")
+	})
+
+	serveForm(server, "/reports?ignored=1", "application/x-www-form-urlencoded", []byte("title=Q3+numbers"))
+	if title != "Q3 numbers" {
+		t.Errorf("FormValue(title) = %q, want %q", title, "Q3 numbers")
+	}
+	if !errors.Is(formErr, fasthttp.ErrNoMultipartForm) {
+		t.Errorf("FormFile() error = %v, want ErrNoMultipartForm", formErr)
+	}
+}
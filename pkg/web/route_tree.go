@@ -0,0 +1,138 @@
+package web
+
+import (
+	"fmt"
+	"strings"
+)
+
+// routeNode is one path segment of a FastRouter route tree. Lookup walks one
+// node per request path segment, so it costs O(path length) however many
+// routes are registered.
+type routeNode struct {
+	static   map[string]*routeNode // literal segments
+	param    *routeNode            // {name} or :name: any non-empty segment
+	catchAll *fastRoute            // {name...} or *name: the rest of the path
+	route    *fastRoute            // route ending at this node
+}
+
+// insert adds route under its pattern. The first route registered for a
+// pattern wins, as routes were matched in registration order before.
+// Panics if the pattern is malformed.
+func (n *routeNode) insert(route *fastRoute) {
+	var params []string
+	segments := splitRoutePath(route.path)
+	for i, segment := range segments {
+		name, kind := parseRouteSegment(segment)
+		switch kind {
+		case segmentCatchAll:
+			if i != len(segments)-1 {
+				panic(fmt.Sprintf("route %s: catch-all {%s...} must be the last segment", route.path, name))
+			}
+			route.params = append(params, name)
+			if n.catchAll == nil {
+				n.catchAll = route
+			}
+			return
+		case segmentParam:
+			params = append(params, name)
+			if n.param == nil {
+				n.param = &routeNode{}
+			}
+			n = n.param
+		default:
+			if n.static == nil {
+				n.static = make(map[string]*routeNode)
+			}
+			child, ok := n.static[segment]
+			if !ok {
+				child = &routeNode{}
+				n.static[segment] = child
+			}
+			n = child
+		}
+	}
+	route.params = params
+	if n.route == nil {
+		n.route = route
+	}
+}
+
+// lookup returns the route matching path and the values of its params, in
+// pattern order. Static segments take precedence over params, and params
+// over a catch-all.
+func (n *routeNode) lookup(path string) (*fastRoute, []string) {
+	return n.match(trimRoutePath(path), nil)
+}
+
+func (n *routeNode) match(path string, values []string) (*fastRoute, []string) {
+	if path == "" {
+		if n.route != nil {
+			return n.route, values
+		}
+		if n.catchAll != nil {
+			return n.catchAll, append(values, "")
+		}
+		return nil, nil
+	}
+
+	segment, rest := path, ""
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		segment, rest = path[:i], path[i+1:]
+	}
+	if child := n.static[segment]; child != nil {
+		if route, matched := child.match(rest, values); route != nil {
+			return route, matched
+		}
+	}
+	if n.param != nil && segment != "" {
+		if route, matched := n.param.match(rest, append(values, segment)); route != nil {
+			return route, matched
+		}
+	}
+	if n.catchAll != nil {
+		return n.catchAll, append(values, path)
+	}
+	return nil, nil
+}
+
+type segmentKind int
+
+const (
+	segmentStatic segmentKind = iota
+	segmentParam
+	segmentCatchAll
+)
+
+// parseRouteSegment classifies a pattern segment: "{name}" and ":name" are
+// params, "{name...}" and "*name" are catch-alls (a bare "*" is named "*"),
+// anything else is literal.
+func parseRouteSegment(segment string) (string, segmentKind) {
+	switch {
+	case segment == "*":
+		return "*", segmentCatchAll
+	case strings.HasPrefix(segment, "*"):
+		return segment[1:], segmentCatchAll
+	case strings.HasPrefix(segment, ":") && len(segment) > 1:
+		return segment[1:], segmentParam
+	case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "...}") && len(segment) > 5:
+		return segment[1 : len(segment)-4], segmentCatchAll
+	case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && len(segment) > 2:
+		return segment[1 : len(segment)-1], segmentParam
+	}
+	return segment, segmentStatic
+}
+
+// trimRoutePath drops the leading slash and one trailing slash, so "/users/"
+// and "/users" are the same path.
+func trimRoutePath(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	return strings.TrimSuffix(path, "/")
+}
+
+func splitRoutePath(path string) []string {
+	path = trimRoutePath(path)
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
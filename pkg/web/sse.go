@@ -0,0 +1,259 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// sseHeartbeatInterval is how often an SSE stream sends a comment line,
+// which keeps proxies from timing it out and detects disconnected clients.
+var sseHeartbeatInterval = 15 * time.Second
+
+// sseBufferSize is the number of messages buffered for a slow SSE client
+// before the consumer blocks.
+const sseBufferSize = 64
+
+// errSSEStreamEnded is the cause of an SSEWriter's context once the stream
+// function has returned.
+var errSSEStreamEnded = errors.New("sse stream ended")
+
+// SSEEvent is one Server-Sent Event.
+type SSEEvent struct {
+	// ID becomes the client's Last-Event-ID for reconnects (omitted if empty)
+	ID string
+	// Event names the event type; empty means "message"
+	Event string
+	// Data is sent as is when it is a string or []byte and JSON-encoded
+	// otherwise; a multi-line value takes one "data:" line per line
+	Data interface{}
+	// Retry asks the client to wait this long before reconnecting (0: unset)
+	Retry time.Duration
+}
+
+// SSEWriter writes Server-Sent Events to one client. Its methods are safe
+// for concurrent use; each flushes, so the client sees events as they are
+// sent.
+type SSEWriter struct {
+	mu     sync.Mutex
+	w      *bufio.Writer
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+// Context is done once the client has disconnected (noticed on the next
+// write or heartbeat), the server's GoCMD stops, or the stream has ended.
+// context.Cause tells which.
+func (s *SSEWriter) Context() context.Context {
+	return s.ctx
+}
+
+// Send writes ev to the client. After a failed write, or once Context is
+// done, it returns the reason the stream is gone.
+func (s *SSEWriter) Send(ev SSEEvent) error {
+	if strings.ContainsAny(ev.ID, "\r\n") || strings.ContainsAny(ev.Event, "\r\n") {
+		return fmt.Errorf("sse event id and name cannot contain newlines")
+	}
+	var data string
+	switch d := ev.Data.(type) {
+	case string:
+		data = d
+	case []byte:
+		data = string(d)
+	default:
+		encoded, err := core.JSONEncode(d)
+		if err != nil {
+			return fmt.Errorf("json encode error: %w", err)
+		}
+		data = string(encoded)
+	}
+
+	var b strings.Builder
+	if ev.ID != "" {
+		b.WriteString("id: " + ev.ID + "\n")
+	}
+	if ev.Event != "" {
+		b.WriteString("event: " + ev.Event + "\n")
+	}
+	if ev.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(ev.Retry.Milliseconds(), 10) + "\n")
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+	return s.write(b.String())
+}
+
+// Comment writes a comment line, which clients ignore.
+func (s *SSEWriter) Comment(text string) error {
+	return s.write(": " + strings.ReplaceAll(text, "\n", " ") + "\n\n")
+}
+
+func (s *SSEWriter) write(text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.Err() != nil {
+		return context.Cause(s.ctx)
+	}
+	if _, err := s.w.WriteString(text); err != nil {
+		s.cancel(err)
+		return err
+	}
+	if err := s.w.Flush(); err != nil {
+		s.cancel(err)
+		return err
+	}
+	return nil
+}
+
+// heartbeat writes a comment every interval until ctx is done.
+func (s *SSEWriter) heartbeat(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.Comment("heartbeat")
+		}
+	}
+}
+
+// end stops the writer: fasthttp reuses the underlying buffer once the
+// stream function returns.
+func (s *SSEWriter) end() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancel(errSSEStreamEnded)
+}
+
+// SSE answers with a Server-Sent Events stream and runs stream to fill it.
+//
+// fasthttp buffers responses, so stream runs after the handler has returned,
+// inside the response's body stream writer; the response ends when stream
+// returns. It should return once w.Context() is done, i.e. the client went
+// away. Heartbeat comments keep idle streams open through proxies.
+//
+//	return c.SSE(func(w *web.SSEWriter) error {
+//	    for progress := range updates {
+//	        if err := w.Send(web.SSEEvent{Event: "progress", Data: progress}); err != nil {
+//	            return err
+//	        }
+//	    }
+//	    return nil
+//	})
+func (c *FastRequestContext) SSE(stream func(w *SSEWriter) error) error {
+	return c.sse(stream, nil)
+}
+
+// sse implements SSE. cleanup, when set, runs once the response's body
+// stream writer exits, including when stream never ran because the client
+// was already gone or the response was reset.
+func (c *FastRequestContext) sse(stream func(w *SSEWriter) error, cleanup func()) error {
+	// Fail-fast: validate inputs
+	if c.RequestCtx == nil {
+		return fmt.Errorf("RequestCtx is nil")
+	}
+	if stream == nil {
+		return fmt.Errorf("stream function cannot be nil")
+	}
+
+	c.RequestCtx.SetStatusCode(200)
+	c.RequestCtx.SetContentType("text/event-stream")
+	c.RequestCtx.Response.Header.Set("Cache-Control", "no-cache")
+	c.RequestCtx.Response.Header.Set("Connection", "keep-alive")
+	c.RequestCtx.Response.Header.Set("X-Accel-Buffering", "no")
+
+	parent := context.Background()
+	if c.GoCMD != nil {
+		parent = c.GoCMD.Context()
+	}
+	requestID := c.requestID
+	c.RequestCtx.SetBodyStreamWriter(func(bw *bufio.Writer) {
+		ctx, cancel := context.WithCancelCause(parent)
+		w := &SSEWriter{w: bw, ctx: ctx, cancel: cancel}
+		if cleanup != nil {
+			defer cleanup()
+		}
+		defer w.end()
+
+		// An initial comment sends the headers right away
+		interval := sseHeartbeatInterval
+		err := w.Comment("connected")
+		if err == nil {
+			go w.heartbeat(interval)
+			err = stream(w)
+		}
+		if err != nil {
+			core.NewDefaultLogger().Info(fmt.Sprintf("sse stream closed (request_id=%s): %v", requestID, err))
+		}
+	})
+	return nil
+}
+
+// BridgeEventBusToSSE streams the messages sent or published to address to
+// the client as Server-Sent Events until the client disconnects.
+//
+// Each message becomes a "data:" line holding its JSON-encoded body, with the
+// message ID as the event "id". The consumer is registered before the
+// response starts and unregistered when the stream ends: the client goes away
+// (noticed on the next write or heartbeat), the EventBus closes, or the
+// response is reset before streaming.
+func BridgeEventBusToSSE(c *FastRequestContext, eventBus core.EventBus, address string) error {
+	// Fail-fast: validate inputs
+	if c.RequestCtx == nil {
+		return fmt.Errorf("RequestCtx is nil")
+	}
+	if eventBus == nil {
+		return fmt.Errorf("eventBus cannot be nil")
+	}
+	if err := core.ValidateAddress(address); err != nil {
+		return err
+	}
+
+	events := make(chan SSEEvent, sseBufferSize)
+	done := make(chan struct{})
+	consumer := eventBus.Consumer(address).Handler(func(ctx core.FluxorContext, msg core.Message) error {
+		var body interface{}
+		if err := msg.DecodeBody(&body); err != nil {
+			return err
+		}
+		data, err := core.JSONEncode(body)
+		if err != nil {
+			return err
+		}
+		select {
+		case events <- SSEEvent{ID: msg.Headers()[core.HeaderMessageID], Data: data}:
+		case <-done:
+		case <-ctx.Context().Done():
+		}
+		return nil
+	})
+
+	return c.sse(func(w *SSEWriter) error {
+		for {
+			select {
+			case <-w.Context().Done():
+				return context.Cause(w.Context())
+			case <-consumer.Completion():
+				return nil
+			case ev := <-events:
+				if err := w.Send(ev); err != nil {
+					return err
+				}
+			}
+		}
+	}, func() {
+		close(done)
+		_ = consumer.Unregister()
+	})
+}
@@ -0,0 +1,337 @@
+package web
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+// dialSSE serves BridgeEventBusToSSE for address and opens a client stream,
+// returning once the stream's headers and initial comment have arrived.
+func dialSSE(t *testing.T, bus core.EventBus, address string) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	return dialStream(t, func(c *FastRequestContext) error {
+		return BridgeEventBusToSSE(c, bus, address)
+	})
+}
+
+// dialStream serves handler and opens a client event stream, like dialSSE.
+func dialStream(t *testing.T, handler FastRequestHandler) (net.Conn, *bufio.Reader) {
+	t.Helper()
+	ln := fasthttputil.NewInmemoryListener()
+	server := &fasthttp.Server{Handler: func(rc *fasthttp.RequestCtx) {
+		c := &FastRequestContext{BaseRequestContext: core.NewBaseRequestContext(), RequestCtx: rc}
+		if err := handler(c); err != nil {
+			rc.Error(err.Error(), fasthttp.StatusInternalServerError)
+		}
+	}}
+	go func() { _ = server.Serve(ln) }()
+	t.Cleanup(func() { _ = ln.Close() })
+
+	conn, err := ln.Dial()
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	if _, err := conn.Write([]byte("GET /events HTTP/1.1\r\nHost: test\r\n\r\n")); err != nil {
+		t.Fatalf("write request: %v", err)
+	}
+
+	r := bufio.NewReader(conn)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var headers []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read headers: %v", err)
+		}
+		if line == "\r\n" {
+			break
+		}
+		headers = append(headers, strings.TrimSpace(line))
+	}
+	if !containsLine(headers, "Content-Type: text/event-stream") {
+		t.Fatalf("headers = %v, want an event stream", headers)
+	}
+	// Chunked encoding: skip to the initial comment
+	readUntil(t, r, ": connected")
+	return conn, r
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, l := range lines {
+		if l == want {
+			return true
+		}
+	}
+	return false
+}
+
+// readUntil reads lines until one starts with prefix and returns it.
+func readUntil(t *testing.T, r *bufio.Reader, prefix string) string {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream for %q: %v", prefix, err)
+		}
+		if line = strings.TrimRight(line, "\r\n"); strings.HasPrefix(line, prefix) {
+			return line
+		}
+	}
+}
+
+func TestBridgeEventBusToSSE_DeliversEvents(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	bus := gocmd.EventBus()
+
+	_, r := dialSSE(t, bus, "dashboard.ticks")
+	for i := 1; i <= 3; i++ {
+		if err := bus.Publish("dashboard.ticks", map[string]interface{}{"n": i}); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+
+	for i := 1; i <= 3; i++ {
+		if id := readUntil(t, r, "id: "); len(id) <= len("id: ") {
+			t.Errorf("event %d has an empty id", i)
+		}
+		data := strings.TrimPrefix(readUntil(t, r, "data: "), "data: ")
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(data), &got); err != nil {
+			t.Fatalf("event %d data %q is not JSON: %v", i, data, err)
+		}
+		if got["n"] != float64(i) {
+			t.Errorf("event %d = %v, want n=%d", i, got, i)
+		}
+	}
+}
+
+func TestFastHTTPServer_BridgeEventBusToSSE(t *testing.T) {
+	// Short heartbeats so the stream notices the client leaving before stop
+	old := sseHeartbeatInterval
+	sseHeartbeatInterval = 20 * time.Millisecond
+	t.Cleanup(func() { sseHeartbeatInterval = old })
+
+	url := startStreamServer(t, func(r *FastRouter) {
+		r.GETFast("/events", func(c *FastRequestContext) error {
+			return BridgeEventBusToSSE(c, c.EventBus, "dashboard.live")
+		})
+		r.POSTFast("/publish", func(c *FastRequestContext) error {
+			if err := c.EventBus.Publish("dashboard.live", map[string]interface{}{"n": 1}); err != nil {
+				return err
+			}
+			return c.Text(202, "published")
+		})
+	})
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	defer client.CloseIdleConnections()
+	resp, err := client.Get(url + "/events")
+	if err != nil {
+		t.Fatalf("GET /events error = %v", err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", got)
+	}
+	r := bufio.NewReader(resp.Body)
+	readUntil(t, r, ": connected")
+
+	// The stream stays open while the server handles other requests
+	published, err := client.Post(url+"/publish", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST /publish error = %v", err)
+	}
+	_ = published.Body.Close()
+
+	data := strings.TrimPrefix(readUntil(t, r, "data: "), "data: ")
+	if data != `{"n":1}` {
+		t.Errorf("event data = %q, want {\"n\":1}", data)
+	}
+}
+
+func TestBridgeEventBusToSSE_DisconnectUnregisters(t *testing.T) {
+	old := sseHeartbeatInterval
+	sseHeartbeatInterval = 20 * time.Millisecond
+	defer func() { sseHeartbeatInterval = old }()
+
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	bus := gocmd.EventBus()
+
+	conn, r := dialSSE(t, bus, "dashboard.idle")
+	readUntil(t, r, ": heartbeat")
+	if err := bus.Send("dashboard.idle", "ping"); err != nil {
+		t.Fatalf("Send() while connected error = %v", err)
+	}
+
+	_ = conn.Close()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		err := bus.Send("dashboard.idle", "ping")
+		if ce, ok := err.(*core.EventBusError); ok && ce.Code == "NO_HANDLERS" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("consumer still registered after disconnect (Send() error = %v)", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBridgeEventBusToSSE_ResetResponseUnregisters(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	bus := gocmd.EventBus()
+
+	// A middleware replacing the response closes the stream, usually before
+	// its initial comment is written
+	for i := 0; i < 20; i++ {
+		c := newStreamRequestContext(gocmd)
+		if err := BridgeEventBusToSSE(c, bus, "dashboard.reset"); err != nil {
+			t.Fatalf("BridgeEventBusToSSE() error = %v", err)
+		}
+		c.RequestCtx.Response.ResetBody()
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		err := bus.Send("dashboard.reset", "ping")
+		if ce, ok := err.(*core.EventBusError); ok && ce.Code == "NO_HANDLERS" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("consumers still registered after the responses were reset (Send() error = %v)", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBridgeEventBusToSSE_Validation(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	if err := BridgeEventBusToSSE(&FastRequestContext{}, gocmd.EventBus(), "a"); err == nil {
+		t.Error("BridgeEventBusToSSE() without RequestCtx should fail")
+	}
+	c := newStreamRequestContext(gocmd)
+	if err := BridgeEventBusToSSE(c, nil, "a"); err == nil {
+		t.Error("BridgeEventBusToSSE() with nil EventBus should fail")
+	}
+	if err := BridgeEventBusToSSE(c, gocmd.EventBus(), ""); err == nil {
+		t.Error("BridgeEventBusToSSE() with empty address should fail")
+	}
+}
+
+func TestFastRequestContext_SSE_WritesEvents(t *testing.T) {
+	_, r := dialStream(t, func(c *FastRequestContext) error {
+		return c.SSE(func(w *SSEWriter) error {
+			for _, ev := range []SSEEvent{
+				{ID: "1", Event: "node.started", Data: map[string]string{"nodeId": "fetch"}},
+				{ID: "2", Data: "line one\nline two", Retry: 3 * time.Second},
+				{Event: "done", Data: []byte("ok")},
+			} {
+				if err := w.Send(ev); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream: %v (so far %q)", err, lines)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "0" {
+			break // last chunk: the stream function returned
+		}
+		if strings.HasPrefix(line, "id:") || strings.HasPrefix(line, "event:") ||
+			strings.HasPrefix(line, "data:") || strings.HasPrefix(line, "retry:") {
+			lines = append(lines, line)
+		}
+	}
+	want := []string{
+		"id: 1", "event: node.started", `data: {"nodeId":"fetch"}`,
+		"id: 2", "retry: 3000", "data: line one", "data: line two",
+		"event: done", "data: ok",
+	}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("stream lines = %q, want %q", lines, want)
+	}
+}
+
+func TestFastRequestContext_SSE_DisconnectEndsContext(t *testing.T) {
+	old := sseHeartbeatInterval
+	sseHeartbeatInterval = 20 * time.Millisecond
+	defer func() { sseHeartbeatInterval = old }()
+
+	ended := make(chan error, 1)
+	conn, r := dialStream(t, func(c *FastRequestContext) error {
+		return c.SSE(func(w *SSEWriter) error {
+			<-w.Context().Done()
+			ended <- w.Send(SSEEvent{Data: "too late"})
+			return nil
+		})
+	})
+	readUntil(t, r, ": heartbeat")
+	_ = conn.Close()
+
+	select {
+	case err := <-ended:
+		if err == nil {
+			t.Error("Send() after disconnect succeeded")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stream context not done after the client disconnected")
+	}
+}
+
+func TestFastRequestContext_SSE_Validation(t *testing.T) {
+	if err := (&FastRequestContext{}).SSE(func(*SSEWriter) error { return nil }); err == nil {
+		t.Error("SSE() without RequestCtx should fail")
+	}
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	if err := newStreamRequestContext(gocmd).SSE(nil); err == nil {
+		t.Error("SSE() with a nil stream function should fail")
+	}
+}
+
+func TestFastHTTPServer_StreamsSSEUnbuffered(t *testing.T) {
+	server := newRouterServer(t)
+	release := make(chan struct{})
+	defer close(release)
+	server.FastRouter().GETFast("/events", func(c *FastRequestContext) error {
+		return c.SSE(func(w *SSEWriter) error {
+			<-release
+			return nil
+		})
+	})
+
+	// The server hands the stream to fasthttp rather than running it to the end
+	served := make(chan *fasthttp.Response, 1)
+	go func() { served <- serveRouter(server, "GET", "/events") }()
+	select {
+	case resp := <-served:
+		if !resp.IsBodyStream() || string(resp.Header.ContentType()) != "text/event-stream" {
+			t.Errorf("response is not an event stream (Content-Type %q)", resp.Header.ContentType())
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("request handling ran the event stream instead of returning")
+	}
+}
@@ -0,0 +1,272 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// WebSocket message types (RFC 6455), as returned by WSConn.ReadMessage.
+const (
+	TextMessage   = websocket.TextMessage
+	BinaryMessage = websocket.BinaryMessage
+)
+
+// wsCloseTimeout bounds writing the close frame when a connection is closed.
+const wsCloseTimeout = time.Second
+
+// WSHandler handles an upgraded WebSocket connection. The connection is
+// closed when the handler returns; a returned error is logged.
+type WSHandler func(conn *WSConn) error
+
+// WSConn is a WebSocket connection accepted by FastRouter.WS.
+// ReadMessage must be called from one goroutine at a time; WriteMessage and
+// Close are safe for concurrent use (e.g. from EventBus consumers).
+type WSConn struct {
+	conn      *websocket.Conn
+	GoCMD     core.GoCMD
+	EventBus  core.EventBus
+	Params    map[string]string
+	requestID string
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+}
+
+// ReadMessage blocks for the next data message and returns its type
+// (TextMessage or BinaryMessage) and payload. It returns an error once the
+// connection is closed by either side.
+func (c *WSConn) ReadMessage() (int, []byte, error) {
+	return c.conn.ReadMessage()
+}
+
+// WriteMessage sends a data message of the given type.
+func (c *WSConn) WriteMessage(messageType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.conn.WriteMessage(messageType, data)
+}
+
+// WriteJSON sends v as a JSON text message.
+func (c *WSConn) WriteJSON(v interface{}) error {
+	data, err := core.JSONEncode(v)
+	if err != nil {
+		return fmt.Errorf("json encode error: %w", err)
+	}
+	return c.WriteMessage(TextMessage, data)
+}
+
+// Close sends a normal-closure close frame and closes the connection.
+func (c *WSConn) Close() error {
+	return c.closeWith(websocket.CloseNormalClosure, "")
+}
+
+// closeWith sends a close frame with code and closes the connection, once.
+func (c *WSConn) closeWith(code int, reason string) error {
+	var err error
+	c.closeOnce.Do(func() {
+		frame := websocket.FormatCloseMessage(code, reason)
+		_ = c.conn.WriteControl(websocket.CloseMessage, frame, time.Now().Add(wsCloseTimeout))
+		// fasthttp closes a hijacked connection only once the handler returns,
+		// so also wake up a ReadMessage blocked in the handler
+		_ = c.conn.SetReadDeadline(time.Now())
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// Param returns a path parameter of the upgraded request.
+func (c *WSConn) Param(key string) string {
+	return c.Params[key]
+}
+
+// RequestID returns the request ID of the upgraded request.
+func (c *WSConn) RequestID() string {
+	return c.requestID
+}
+
+// wsConnections tracks open WebSocket connections so the server can close them
+// on Stop.
+type wsConnections struct {
+	mu     sync.Mutex
+	conns  map[*WSConn]struct{}
+	closed bool
+	wg     sync.WaitGroup // running handlers
+}
+
+// add tracks conn; it reports false once the server is stopping.
+func (t *wsConnections) add(conn *WSConn) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return false
+	}
+	if t.conns == nil {
+		t.conns = make(map[*WSConn]struct{})
+	}
+	t.conns[conn] = struct{}{}
+	t.wg.Add(1)
+	return true
+}
+
+func (t *wsConnections) remove(conn *WSConn) {
+	t.mu.Lock()
+	delete(t.conns, conn)
+	t.mu.Unlock()
+	t.wg.Done()
+}
+
+// closeAll sends every connection a going-away close frame and waits for
+// their handlers to return or ctx to expire.
+func (t *wsConnections) closeAll(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	conns := make([]*WSConn, 0, len(t.conns))
+	for conn := range t.conns {
+		conns = append(conns, conn)
+	}
+	t.mu.Unlock()
+
+	for _, conn := range conns {
+		_ = conn.closeWith(websocket.CloseGoingAway, "server shutting down")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("websocket handlers still running: %w", ctx.Err())
+	}
+}
+
+// WS registers a WebSocket endpoint: GET requests to path are upgraded and
+// handler runs with the connection. Route middleware (e.g. auth) runs before
+// the upgrade. The FastHTTPServer lets upgrades bypass its request queue and
+// backpressure, since the connections are long-lived, and closes open
+// connections when it stops.
+func (r *FastRouter) WS(path string, handler WSHandler, middleware ...FastMiddleware) {
+	upgrader := websocket.FastHTTPUpgrader{}
+	upgrade := func(ctx *FastRequestContext) error {
+		params := make(map[string]string, len(ctx.Params))
+		for k, v := range ctx.Params {
+			params[k] = v
+		}
+		gocmd, eventBus, requestID := ctx.GoCMD, ctx.EventBus, ctx.requestID
+
+		// handler runs on the hijacked connection after this request returns
+		err := upgrader.Upgrade(ctx.RequestCtx, func(c *websocket.Conn) {
+			conn := &WSConn{conn: c, GoCMD: gocmd, EventBus: eventBus, Params: params, requestID: requestID}
+			if !r.websockets.add(conn) {
+				_ = conn.closeWith(websocket.CloseGoingAway, "server shutting down")
+				return
+			}
+			defer r.websockets.remove(conn)
+			defer conn.Close()
+			defer func() {
+				// Panic isolation: the hijacked connection runs outside the request workers
+				if p := recover(); p != nil {
+					core.NewDefaultLogger().Error(fmt.Sprintf("websocket handler panic for %s (request_id=%s): %v", path, requestID, p))
+				}
+			}()
+
+			if err := handler(conn); err != nil {
+				core.NewDefaultLogger().Error(fmt.Sprintf("websocket handler for %s failed (request_id=%s): %v", path, requestID, err))
+			}
+		})
+		if err != nil {
+			// The upgrader already answered the failed handshake (e.g. 400)
+			core.NewDefaultLogger().Info(fmt.Sprintf("websocket upgrade for %s rejected (request_id=%s): %v", path, requestID, err))
+		}
+		return nil
+	}
+	r.addRoute(&fastRoute{
+		method:     "GET",
+		path:       path,
+		handler:    upgrade,
+		middleware: append([]FastMiddleware(nil), middleware...),
+		websocket:  true,
+	})
+}
+
+// wsBridgeBufferSize is the number of messages buffered for a slow WebSocket
+// client before the consumer blocks.
+const wsBridgeBufferSize = 64
+
+// BridgeEventBusToWS pushes the messages sent or published to address to the
+// client, each as a text message holding its JSON-encoded body, until the
+// client disconnects or the EventBus closes. Call it from a WS handler:
+//
+//	router.WS("/live", func(conn *web.WSConn) error {
+//		return web.BridgeEventBusToWS(conn, conn.EventBus, "workflow.orders.execution.finished")
+//	})
+//
+// The bridge reads the connection itself (client messages are discarded), so
+// the handler must not call ReadMessage while it runs.
+func BridgeEventBusToWS(conn *WSConn, eventBus core.EventBus, address string) error {
+	// Fail-fast: validate inputs
+	if conn == nil {
+		return fmt.Errorf("conn cannot be nil")
+	}
+	if eventBus == nil {
+		return fmt.Errorf("eventBus cannot be nil")
+	}
+	if err := core.ValidateAddress(address); err != nil {
+		return err
+	}
+
+	messages := make(chan []byte, wsBridgeBufferSize)
+	done := make(chan struct{})
+	consumer := eventBus.Consumer(address).Handler(func(ctx core.FluxorContext, msg core.Message) error {
+		var body interface{}
+		if err := msg.DecodeBody(&body); err != nil {
+			return err
+		}
+		data, err := core.JSONEncode(body)
+		if err != nil {
+			return err
+		}
+		select {
+		case messages <- data:
+		case <-done:
+		case <-ctx.Context().Done():
+		}
+		return nil
+	})
+	defer func() {
+		close(done)
+		_ = consumer.Unregister()
+	}()
+
+	// A failed read means the client closed the connection (or it was closed)
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-disconnected:
+			return nil
+		case <-consumer.Completion():
+			return nil
+		case data := <-messages:
+			if err := conn.WriteMessage(TextMessage, data); err != nil {
+				return fmt.Errorf("websocket bridge for %s: %w", address, err)
+			}
+		}
+	}
+}
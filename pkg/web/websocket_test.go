@@ -0,0 +1,236 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// serveWS serves a FastHTTPServer on a loopback port (the in-memory listener
+// ignores read deadlines, which closing a connection relies on) and returns
+// its address.
+func serveWS(t *testing.T, server *FastHTTPServer) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	go func() { _ = server.server.Serve(ln) }()
+	t.Cleanup(func() { _ = ln.Close() })
+	return ln.Addr().String()
+}
+
+func dialWS(t *testing.T, addr, path string) *websocket.Conn {
+	t.Helper()
+	dialer := &websocket.Dialer{HandshakeTimeout: 2 * time.Second}
+	conn, _, err := dialer.Dial("ws://"+addr+path, nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func newWSServer(t *testing.T) *FastHTTPServer {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	config := DefaultFastHTTPServerConfig(":0")
+	config.Workers, config.MaxQueue = 1, 1
+	return NewFastHTTPServer(gocmd, config)
+}
+
+func TestFastRouter_WS_Echo(t *testing.T) {
+	server := newWSServer(t)
+	server.FastRouter().WS("/ws/:room", func(conn *WSConn) error {
+		if conn.EventBus == nil || conn.GoCMD == nil {
+			return errors.New("connection without EventBus")
+		}
+		for {
+			messageType, data, err := conn.ReadMessage()
+			if err != nil {
+				return nil
+			}
+			if err := conn.WriteMessage(messageType, append([]byte(conn.Param("room")+": "), data...)); err != nil {
+				return err
+			}
+		}
+	})
+	addr := serveWS(t, server)
+
+	// Hold the whole backpressure capacity: upgrades must still be accepted
+	for server.backpressure.TryAcquire() {
+	}
+
+	client := dialWS(t, addr, "/ws/lobby")
+
+	if err := client.WriteMessage(websocket.TextMessage, []byte("hello")); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	_ = client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	messageType, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	if messageType != websocket.TextMessage || string(data) != "lobby: hello" {
+		t.Errorf("echo = %d %q, want text %q", messageType, data, "lobby: hello")
+	}
+}
+
+func TestFastRouter_WS_PlainRequestRejected(t *testing.T) {
+	server := newWSServer(t)
+	server.FastRouter().WS("/ws", func(conn *WSConn) error { return nil })
+	conn, err := net.Dial("tcp", serveWS(t, server))
+	if err != nil {
+		t.Fatalf("dial error = %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("GET /ws HTTP/1.1\r\nHost: test\r\n\r\n")); err != nil {
+		t.Fatalf("write request: %v", err)
+	}
+	buf := make([]byte, 64)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _ := conn.Read(buf)
+	if got := string(buf[:n]); len(got) < 12 || got[9:12] != "400" {
+		t.Errorf("response = %q, want 400", got)
+	}
+}
+
+func TestFastHTTPServer_UpgradeHeadersOnPlainRouteApplyBackpressure(t *testing.T) {
+	server := newWSServer(t)
+	server.FastRouter().WS("/ws", func(conn *WSConn) error { return nil })
+	server.FastRouter().GETFast("/plain", func(c *FastRequestContext) error {
+		return c.Text(200, "ok")
+	})
+	conn, err := net.Dial("tcp", serveWS(t, server))
+	if err != nil {
+		t.Fatalf("dial error = %v", err)
+	}
+	defer conn.Close()
+
+	// Hold the whole backpressure capacity: only WS routes may skip it
+	for server.backpressure.TryAcquire() {
+	}
+	request := "GET /plain HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n" +
+		"Sec-WebSocket-Version: 13\r\nSec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatalf("write request: %v", err)
+	}
+	buf := make([]byte, 64)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _ := conn.Read(buf)
+	if got := string(buf[:n]); len(got) < 12 || got[9:12] != "503" {
+		t.Errorf("response = %q, want 503 from backpressure", got)
+	}
+}
+
+func TestFastHTTPServer_StopClosesWebSockets(t *testing.T) {
+	server := newWSServer(t)
+	handlerDone := make(chan struct{})
+	server.FastRouter().WS("/ws", func(conn *WSConn) error {
+		defer close(handlerDone)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return nil
+			}
+		}
+	})
+	client := dialWS(t, serveWS(t, server), "/ws")
+
+	// Wait until the handler runs, then stop the server
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		server.router.websockets.mu.Lock()
+		open := len(server.router.websockets.conns)
+		server.router.websockets.mu.Unlock()
+		if open == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("websocket connection was not tracked")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := server.doStop(); err != nil {
+		t.Fatalf("doStop() error = %v", err)
+	}
+
+	select {
+	case <-handlerDone:
+	default:
+		t.Error("Stop returned before the websocket handler")
+	}
+	_ = client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, _, err := client.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Errorf("ReadMessage() error = %v, want a going-away close", err)
+	}
+}
+
+func TestBridgeEventBusToWS(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	bus := gocmd.EventBus()
+
+	server := newWSServer(t)
+	bridged := make(chan error, 1)
+	server.FastRouter().WS("/live", func(conn *WSConn) error {
+		err := BridgeEventBusToWS(conn, bus, "dashboard.executions")
+		bridged <- err
+		return err
+	})
+	client := dialWS(t, serveWS(t, server), "/live")
+
+	// The consumer exists once Send finds a handler; that first message is delivered too
+	deadline := time.Now().Add(2 * time.Second)
+	for bus.Send("dashboard.executions", map[string]interface{}{"n": 1}) != nil {
+		if time.Now().After(deadline) {
+			t.Fatal("bridge consumer was not registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := bus.Publish("dashboard.executions", map[string]interface{}{"n": 2}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	for i := 1; i <= 2; i++ {
+		_ = client.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var got map[string]interface{}
+		if err := client.ReadJSON(&got); err != nil {
+			t.Fatalf("ReadJSON() error = %v", err)
+		}
+		if got["n"] != float64(i) {
+			t.Errorf("message %d = %v, want n=%d", i, got, i)
+		}
+	}
+
+	// Client goes away: the bridge returns and unregisters its consumer
+	_ = client.Close()
+	select {
+	case err := <-bridged:
+		if err != nil {
+			t.Errorf("BridgeEventBusToWS() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("bridge did not return after the client disconnected")
+	}
+	deadline = time.Now().Add(2 * time.Second)
+	for {
+		err := bus.Send("dashboard.executions", "ping")
+		if ce, ok := err.(*core.EventBusError); ok && ce.Code == "NO_HANDLERS" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("consumer still registered after disconnect (Send() error = %v)", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := BridgeEventBusToWS(&WSConn{}, bus, ""); err == nil {
+		t.Error("BridgeEventBusToWS() accepted an empty address")
+	}
+}
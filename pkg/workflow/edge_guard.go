@@ -0,0 +1,428 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Edge guards are "when" expressions attached to workflow edges:
+//
+//	"next": [{"node": "approve", "when": "$.amount > 100"}, "log"]
+//
+// An edge with a guard is only followed when the expression holds for the
+// data the node emits. Expressions compare a path against a literal:
+//
+//	$.amount > 100
+//	$.customer.tier == "gold" && $.items[0].qty >= 2
+//	$.flagged || $.status != "ok"
+//
+// Supported operators are ==, !=, >, >=, <, <=, contains, && and ||
+// (&& binds tighter; there are no parentheses). A bare path is true when
+// the value exists and is not false, zero or empty.
+
+// edgeRef is a single entry of an edge list: a plain node ID or {"node", "when"}.
+type edgeRef struct {
+	Node string `json:"node"`
+	When string `json:"when,omitempty"`
+}
+
+func (r *edgeRef) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		r.Node = id
+		return nil
+	}
+	type plain edgeRef
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return fmt.Errorf("edge must be a node ID or {\"node\", \"when\"}: %w", err)
+	}
+	*r = edgeRef(p)
+	return nil
+}
+
+// nodeDefinitionAlias has NodeDefinition's fields without its JSON methods.
+type nodeDefinitionAlias NodeDefinition
+
+// UnmarshalJSON accepts edge lists of plain node IDs or {"node", "when"} objects.
+func (n *NodeDefinition) UnmarshalJSON(data []byte) error {
+	*n = NodeDefinition{}
+	raw := struct {
+		*nodeDefinitionAlias
+		Next      []edgeRef `json:"next,omitempty"`
+		OnError   []edgeRef `json:"onError,omitempty"`
+		TrueNext  []edgeRef `json:"trueNext,omitempty"`
+		FalseNext []edgeRef `json:"falseNext,omitempty"`
+	}{nodeDefinitionAlias: (*nodeDefinitionAlias)(n)}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	n.Next, n.Guards.Next = collectEdges(raw.Next)
+	n.OnError, n.Guards.OnError = collectEdges(raw.OnError)
+	n.TrueNext, n.Guards.TrueNext = collectEdges(raw.TrueNext)
+	n.FalseNext, n.Guards.FalseNext = collectEdges(raw.FalseNext)
+	return nil
+}
+
+// MarshalJSON emits guarded edges as {"node", "when"} objects and others as plain IDs.
+func (n NodeDefinition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		nodeDefinitionAlias
+		Next      []interface{} `json:"next,omitempty"`
+		OnError   []interface{} `json:"onError,omitempty"`
+		TrueNext  []interface{} `json:"trueNext,omitempty"`
+		FalseNext []interface{} `json:"falseNext,omitempty"`
+	}{
+		nodeDefinitionAlias: nodeDefinitionAlias(n),
+		Next:                edgeList(n.Next, n.Guards.Next),
+		OnError:             edgeList(n.OnError, n.Guards.OnError),
+		TrueNext:            edgeList(n.TrueNext, n.Guards.TrueNext),
+		FalseNext:           edgeList(n.FalseNext, n.Guards.FalseNext),
+	})
+}
+
+// collectEdges splits an edge list into node IDs and the guards by position.
+func collectEdges(refs []edgeRef) ([]string, map[int]string) {
+	if refs == nil {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(refs))
+	var guards map[int]string
+	for i, ref := range refs {
+		ids = append(ids, ref.Node)
+		if ref.When != "" {
+			if guards == nil {
+				guards = make(map[int]string)
+			}
+			guards[i] = ref.When
+		}
+	}
+	return ids, guards
+}
+
+func edgeList(ids []string, guards map[int]string) []interface{} {
+	if ids == nil {
+		return nil
+	}
+	list := make([]interface{}, 0, len(ids))
+	for i, id := range ids {
+		if when, ok := guards[i]; ok {
+			list = append(list, edgeRef{Node: id, When: when})
+		} else {
+			list = append(list, id)
+		}
+	}
+	return list
+}
+
+// validateGuards checks that every guard belongs to an edge of the node and
+// compiles, and that the node's runIf compiles.
+func validateGuards(node *NodeDefinition) error {
+	if node.RunIf != "" {
+		if _, err := compileGuard(node.RunIf); err != nil {
+			return fmt.Errorf("node %s: invalid runIf: %w", node.ID, err)
+		}
+	}
+	lists := []struct {
+		name   string
+		ids    []string
+		guards map[int]string
+	}{
+		{"next", node.Next, node.Guards.Next},
+		{"onError", node.OnError, node.Guards.OnError},
+		{"trueNext", node.TrueNext, node.Guards.TrueNext},
+		{"falseNext", node.FalseNext, node.Guards.FalseNext},
+	}
+	for _, list := range lists {
+		for i, when := range list.guards {
+			if i < 0 || i >= len(list.ids) {
+				return fmt.Errorf("node %s has a guard for %s edge %d, which does not exist", node.ID, list.name, i)
+			}
+			if _, err := compileGuard(when); err != nil {
+				return fmt.Errorf("node %s: invalid guard for %s edge to %s: %w", node.ID, list.name, list.ids[i], err)
+			}
+		}
+	}
+	return nil
+}
+
+// guardData returns the data edge guards are evaluated against.
+// Condition nodes wrap their input; guards see the original data.
+func guardData(data interface{}) interface{} {
+	if m, ok := data.(map[string]interface{}); ok {
+		if _, isCond := m["_conditionResult"]; isCond {
+			return m["_originalData"]
+		}
+	}
+	return data
+}
+
+// edgeGuard is a compiled guard: an OR of AND-ed comparisons.
+type edgeGuard struct {
+	anyOf [][]guardComparison
+}
+
+type guardComparison struct {
+	path     []interface{} // string keys and int indexes
+	operator string        // empty for a bare-path truthiness test
+	value    interface{}
+}
+
+func (g *edgeGuard) eval(data interface{}) bool {
+	for _, allOf := range g.anyOf {
+		matched := true
+		for _, cmp := range allOf {
+			if !cmp.eval(data) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (c guardComparison) eval(data interface{}) bool {
+	actual, found := resolvePath(data, c.path)
+	if c.operator == "" {
+		return found && isTruthy(actual)
+	}
+	if !found && c.operator != "!=" {
+		return false
+	}
+	return evaluateCondition(actual, c.operator, c.value)
+}
+
+func isTruthy(v interface{}) bool {
+	switch x := v.(type) {
+	case bool:
+		return x
+	case nil:
+		return false
+	case int, int64, float32, float64:
+		return toFloat(x) != 0
+	default:
+		return !isEmpty(v)
+	}
+}
+
+// resolvePath walks map keys and slice indexes; found is false if any step is missing.
+func resolvePath(data interface{}, path []interface{}) (interface{}, bool) {
+	current := data
+	for _, step := range path {
+		switch key := step.(type) {
+		case string:
+			// "items.0" indexes an array like "items[0]"
+			if s, ok := current.([]interface{}); ok {
+				index, err := strconv.Atoi(key)
+				if err != nil || index < 0 || index >= len(s) {
+					return nil, false
+				}
+				current = s[index]
+				continue
+			}
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = m[key]; !ok {
+				return nil, false
+			}
+		case int:
+			s, ok := current.([]interface{})
+			if !ok || key < 0 || key >= len(s) {
+				return nil, false
+			}
+			current = s[key]
+		}
+	}
+	return current, true
+}
+
+// compileGuard parses a guard expression.
+func compileGuard(expr string) (*edgeGuard, error) {
+	tokens, err := tokenizeGuard(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	guard := &edgeGuard{}
+	var allOf []guardComparison
+	for i := 0; i < len(tokens); {
+		if tokens[i].kind != guardTokenPath {
+			return nil, fmt.Errorf("expected a path starting with $, got %q", tokens[i].text)
+		}
+		path, err := parseGuardPath(tokens[i].text)
+		if err != nil {
+			return nil, err
+		}
+		cmp := guardComparison{path: path}
+		i++
+
+		if i < len(tokens) && tokens[i].kind == guardTokenOperator {
+			cmp.operator = tokens[i].text
+			i++
+			if i >= len(tokens) || tokens[i].kind != guardTokenLiteral {
+				return nil, fmt.Errorf("operator %s must be followed by a literal", cmp.operator)
+			}
+			cmp.value = tokens[i].value
+			i++
+		}
+		allOf = append(allOf, cmp)
+
+		if i == len(tokens) {
+			break
+		}
+		switch tokens[i].text {
+		case "&&":
+		case "||":
+			guard.anyOf = append(guard.anyOf, allOf)
+			allOf = nil
+		default:
+			return nil, fmt.Errorf("expected && or ||, got %q", tokens[i].text)
+		}
+		i++
+		if i == len(tokens) {
+			return nil, fmt.Errorf("expression ends with %s", tokens[i-1].text)
+		}
+	}
+	guard.anyOf = append(guard.anyOf, allOf)
+	return guard, nil
+}
+
+type guardTokenKind int
+
+const (
+	guardTokenPath guardTokenKind = iota
+	guardTokenOperator
+	guardTokenLogical
+	guardTokenLiteral
+)
+
+type guardToken struct {
+	kind  guardTokenKind
+	text  string
+	value interface{}
+}
+
+func tokenizeGuard(expr string) ([]guardToken, error) {
+	var tokens []guardToken
+	for i := 0; i < len(expr); {
+		ch := expr[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			i++
+		case ch == '$':
+			j := i + 1
+			for j < len(expr) && !strings.ContainsRune(" \t\n=!<>&|", rune(expr[j])) {
+				j++
+			}
+			tokens = append(tokens, guardToken{kind: guardTokenPath, text: expr[i:j]})
+			i = j
+		case ch == '"' || ch == '\'':
+			j := i + 1
+			for j < len(expr) && expr[j] != ch {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			text := expr[i+1 : j]
+			if ch == '"' {
+				unquoted, err := strconv.Unquote(expr[i : j+1])
+				if err != nil {
+					return nil, fmt.Errorf("invalid string at offset %d: %w", i, err)
+				}
+				text = unquoted
+			}
+			tokens = append(tokens, guardToken{kind: guardTokenLiteral, text: text, value: text})
+			i = j + 1
+		case strings.HasPrefix(expr[i:], "&&") || strings.HasPrefix(expr[i:], "||"):
+			tokens = append(tokens, guardToken{kind: guardTokenLogical, text: expr[i : i+2]})
+			i += 2
+		case strings.HasPrefix(expr[i:], "==") || strings.HasPrefix(expr[i:], "!=") ||
+			strings.HasPrefix(expr[i:], ">=") || strings.HasPrefix(expr[i:], "<="):
+			tokens = append(tokens, guardToken{kind: guardTokenOperator, text: expr[i : i+2]})
+			i += 2
+		case ch == '>' || ch == '<':
+			tokens = append(tokens, guardToken{kind: guardTokenOperator, text: expr[i : i+1]})
+			i++
+		default:
+			j := i
+			for j < len(expr) && !strings.ContainsRune(" \t\n=!<>&|", rune(expr[j])) {
+				j++
+			}
+			word := expr[i:j]
+			if word == "" {
+				return nil, fmt.Errorf("unexpected %q at offset %d", ch, i)
+			}
+			tokens = append(tokens, wordToken(word))
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+func wordToken(word string) guardToken {
+	switch word {
+	case "contains":
+		return guardToken{kind: guardTokenOperator, text: word}
+	case "true":
+		return guardToken{kind: guardTokenLiteral, text: word, value: true}
+	case "false":
+		return guardToken{kind: guardTokenLiteral, text: word, value: false}
+	case "null":
+		return guardToken{kind: guardTokenLiteral, text: word, value: nil}
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return guardToken{kind: guardTokenLiteral, text: word, value: f}
+	}
+	// Bare words are treated as strings: $.status == ok
+	return guardToken{kind: guardTokenLiteral, text: word, value: word}
+}
+
+// parseGuardPath parses $, $.a.b and $.items[0].name into path steps.
+func parseGuardPath(text string) ([]interface{}, error) {
+	rest := strings.TrimPrefix(text, "$")
+	var path []interface{}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty field name in path %s", text)
+			}
+			path = append(path, rest[:end])
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index in path %s", text)
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid index in path %s", text)
+			}
+			path = append(path, index)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid path %s", text)
+		}
+	}
+	return path, nil
+}
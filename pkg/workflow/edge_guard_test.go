@@ -0,0 +1,380 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func TestCompileGuard(t *testing.T) {
+	data := map[string]interface{}{
+		"amount":   150.0,
+		"status":   "ok",
+		"flagged":  false,
+		"customer": map[string]interface{}{"tier": "gold"},
+		"items":    []interface{}{map[string]interface{}{"qty": 3}},
+		"tags":     []interface{}{"vip", "eu"},
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"$.amount > 100", true},
+		{"$.amount <= 100", false},
+		{"$.amount == 150", true},
+		{`$.status == "ok"`, true},
+		{"$.status != 'ok'", false},
+		{`$.customer.tier == "gold" && $.items[0].qty >= 2`, true},
+		{`$.customer.tier == "gold" && $.amount < 10`, false},
+		{"$.amount < 10 || $.status == ok", true},
+		{"$.tags contains vip", true},
+		{"$.flagged", false},
+		{"$.customer", true},
+		{"$.missing", false},
+		{"$.missing > 0", false},
+		{"$.missing != 1", true},
+		{"$.items[5].qty > 0", false},
+	}
+	for _, tt := range tests {
+		guard, err := compileGuard(tt.expr)
+		if err != nil {
+			t.Errorf("compileGuard(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if got := guard.eval(data); got != tt.want {
+			t.Errorf("%q = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestCompileGuard_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"amount > 100",
+		"$.amount >",
+		"$.amount > 100 &&",
+		"$.amount 100",
+		`$.name == "unterminated`,
+		"$.items[x] > 1",
+		"$..amount > 1",
+	} {
+		if _, err := compileGuard(expr); err == nil {
+			t.Errorf("compileGuard(%q) should fail", expr)
+		}
+	}
+}
+
+func TestNodeDefinition_JSONGuards(t *testing.T) {
+	var def WorkflowDefinition
+	err := json.Unmarshal([]byte(`{
+		"id": "guarded",
+		"nodes": [
+			{"id": "start", "type": "noop", "runIf": "$.amount", "next": [{"node": "big", "when": "$.amount > 100"}, "audit"],
+			 "onError": [{"node": "audit", "when": "$.retry"}]},
+			{"id": "big", "type": "noop"},
+			{"id": "audit", "type": "noop"}
+		]
+	}`), &def)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	start := def.Nodes[0]
+	if len(start.Next) != 2 || start.Next[0] != "big" || start.Next[1] != "audit" {
+		t.Fatalf("Next = %v, want [big audit]", start.Next)
+	}
+	if start.Guards.Next[0] != "$.amount > 100" || len(start.Guards.Next) != 1 {
+		t.Errorf("Guards.Next = %v, want only big guarded", start.Guards.Next)
+	}
+	// A guard belongs to its own edge list only
+	if start.Guards.OnError[0] != "$.retry" || len(start.Guards.OnError) != 1 {
+		t.Errorf("Guards.OnError = %v, want audit guarded", start.Guards.OnError)
+	}
+	if _, leaked := start.Guards.Next[1]; leaked {
+		t.Error("onError guard for audit also guards its next edge")
+	}
+
+	// Round trip keeps guarded edges as objects and plain edges as IDs
+	encoded, err := json.Marshal(start)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var raw map[string]interface{}
+	_ = json.Unmarshal(encoded, &raw)
+	next := raw["next"].([]interface{})
+	if edge, ok := next[0].(map[string]interface{}); !ok || edge["when"] != "$.amount > 100" {
+		t.Errorf("next[0] = %v, want guarded edge object", next[0])
+	}
+	if next[1] != "audit" {
+		t.Errorf("next[1] = %v, want plain ID", next[1])
+	}
+
+	// Fields other than the edge lists round-trip unchanged
+	var decoded NodeDefinition
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal() round trip error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, start) {
+		t.Errorf("round trip = %+v, want %+v", decoded, start)
+	}
+}
+
+func newGuardEngine(t *testing.T, def *WorkflowDefinition) *Engine {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	engine := NewEngine(gocmd.EventBus())
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return engine
+}
+
+func runGuarded(t *testing.T, engine *Engine, workflowID string, input interface{}) *ExecutionState {
+	t.Helper()
+	execID, err := engine.ExecuteWorkflow(context.Background(), workflowID, input)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		state, err := engine.GetExecutionState(execID)
+		if err == nil {
+			engine.mu.RLock()
+			status := state.Status
+			engine.mu.RUnlock()
+			if status != ExecutionStatusRunning {
+				return state
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("execution %s did not finish", execID)
+	return nil
+}
+
+func TestEngine_EdgeGuards(t *testing.T) {
+	def := NewWorkflowBuilder("routing", "Routing").
+		AddNode("start", string(NodeTypeNoOp)).
+		Next("start-audit").
+		NextWhen("high", "$.amount > 100").
+		NextWhen("low", "$.amount <= 100").
+		Done().
+		AddNode("high", string(NodeTypeNoOp)).Done().
+		AddNode("low", string(NodeTypeNoOp)).Done().
+		AddNode("start-audit", string(NodeTypeNoOp)).Done().
+		Build()
+	engine := newGuardEngine(t, def)
+
+	state := runGuarded(t, engine, "routing", map[string]interface{}{"amount": 150})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("Status = %s, want completed", state.Status)
+	}
+	outputs := state.Context.NodeOutputs
+	if _, ok := outputs["high"]; !ok {
+		t.Error("high was not executed for amount 150")
+	}
+	if _, ok := outputs["low"]; ok {
+		t.Error("low was executed although its guard is false")
+	}
+	if _, ok := outputs["start-audit"]; !ok {
+		t.Error("unguarded edge was not followed")
+	}
+}
+
+func TestEngine_EdgeGuards_PerEdge(t *testing.T) {
+	def := &WorkflowDefinition{
+		ID: "per-edge",
+		Nodes: []NodeDefinition{
+			// audit is unguarded on next; its onError guard must not apply here.
+			// notify is reached by either of two guarded edges.
+			{ID: "start", Type: string(NodeTypeNoOp),
+				Next:    []string{"audit", "notify", "notify"},
+				OnError: []string{"audit"},
+				Guards: EdgeGuards{
+					Next:    map[int]string{1: "$.amount > 1000", 2: `$.region == "eu"`},
+					OnError: map[int]string{0: "$.retry"},
+				}},
+			{ID: "audit", Type: string(NodeTypeNoOp)},
+			{ID: "notify", Type: string(NodeTypeNoOp)},
+		},
+	}
+	engine := newGuardEngine(t, def)
+
+	state := runGuarded(t, engine, "per-edge", map[string]interface{}{"amount": 5000, "region": "us"})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("Status = %s, want completed", state.Status)
+	}
+	if _, ok := state.Context.NodeOutputs["audit"]; !ok {
+		t.Error("unguarded next edge to audit was not followed")
+	}
+	if _, ok := state.Context.NodeOutputs["notify"]; !ok {
+		t.Error("guarded edge to notify was not followed; a later edge to it overrode its guard")
+	}
+}
+
+func TestEngine_EdgeGuards_AllFalse(t *testing.T) {
+	def := &WorkflowDefinition{
+		ID: "dead-end",
+		Nodes: []NodeDefinition{
+			{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"a", "b"},
+				Guards: EdgeGuards{Next: map[int]string{0: "$.amount > 1000", 1: `$.region == "eu"`}}},
+			{ID: "a", Type: string(NodeTypeNoOp)},
+			{ID: "b", Type: string(NodeTypeNoOp)},
+		},
+	}
+	engine := newGuardEngine(t, def)
+
+	state := runGuarded(t, engine, "dead-end", map[string]interface{}{"amount": 5, "region": "us"})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("Status = %s, want completed when every guard is false", state.Status)
+	}
+	if len(state.Context.NodeOutputs) != 1 {
+		t.Errorf("NodeOutputs = %v, want only start", state.Context.NodeOutputs)
+	}
+}
+
+func TestEngine_EdgeGuards_IntoWaitAllMerge(t *testing.T) {
+	def := &WorkflowDefinition{
+		ID: "guarded-merge",
+		Nodes: []NodeDefinition{
+			{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"a", "b", "c"}},
+			{ID: "a", Type: string(NodeTypeNoOp), Next: []string{"join"}},
+			{ID: "b", Type: string(NodeTypeNoOp), Next: []string{"join"},
+				Guards: EdgeGuards{Next: map[int]string{0: "$.amount > 1000"}}},
+			// c only feeds lonely, and its guard is false too
+			{ID: "c", Type: string(NodeTypeNoOp), Next: []string{"lonely"},
+				Guards: EdgeGuards{Next: map[int]string{0: "$.amount > 1000"}}},
+			{ID: "join", Type: string(NodeTypeMerge), Next: []string{"done"}},
+			{ID: "lonely", Type: string(NodeTypeMerge)},
+			{ID: "done", Type: string(NodeTypeNoOp)},
+		},
+	}
+	engine := newGuardEngine(t, def)
+
+	state := runGuarded(t, engine, "guarded-merge", map[string]interface{}{"amount": 5})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("Status = %s, want completed", state.Status)
+	}
+	// The skipped edge from b counts as delivered: join runs with a's input
+	join, ok := state.Context.NodeOutputs["join"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("waitAll merge did not run after its guarded input was skipped (outputs %v)", state.Context.NodeOutputs)
+	}
+	if inputs, _ := join["_originalData"].([]interface{}); len(inputs) != 1 {
+		t.Errorf("merge inputs = %v, want only the one from a", join["_originalData"])
+	}
+	if _, ok := state.Context.NodeOutputs["done"]; !ok {
+		t.Error("node after the merge did not run")
+	}
+	if _, ok := state.Context.NodeOutputs["lonely"]; ok {
+		t.Error("merge whose only input was skipped ran")
+	}
+}
+
+func TestEngine_EdgeGuards_ConditionNode(t *testing.T) {
+	def := &WorkflowDefinition{
+		ID: "cond-guard",
+		Nodes: []NodeDefinition{
+			{ID: "check", Type: string(NodeTypeCondition),
+				Config:   map[string]interface{}{"field": "paid", "operator": "eq", "value": true},
+				TrueNext: []string{"ship", "express"},
+				Guards:   EdgeGuards{TrueNext: map[int]string{1: `$.shipping == "express"`}}},
+			{ID: "ship", Type: string(NodeTypeNoOp)},
+			{ID: "express", Type: string(NodeTypeNoOp)},
+		},
+	}
+	engine := newGuardEngine(t, def)
+
+	// Guards on condition branches see the original data, not the wrapper
+	state := runGuarded(t, engine, "cond-guard", map[string]interface{}{"paid": true, "shipping": "express"})
+	if _, ok := state.Context.NodeOutputs["express"]; !ok {
+		t.Error("guarded trueNext edge was not followed")
+	}
+	state = runGuarded(t, engine, "cond-guard", map[string]interface{}{"paid": true, "shipping": "ground"})
+	if _, ok := state.Context.NodeOutputs["express"]; ok {
+		t.Error("guarded trueNext edge was followed although its guard is false")
+	}
+	if _, ok := state.Context.NodeOutputs["ship"]; !ok {
+		t.Error("unguarded trueNext edge was not followed")
+	}
+}
+
+func TestEngine_RegisterWorkflow_InvalidGuard(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	engine := NewEngine(gocmd.EventBus())
+
+	bad := &WorkflowDefinition{
+		ID: "bad",
+		Nodes: []NodeDefinition{
+			{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"a"}, Guards: EdgeGuards{Next: map[int]string{0: "$.amount >"}}},
+			{ID: "a", Type: string(NodeTypeNoOp)},
+		},
+	}
+	if err := engine.RegisterWorkflow(bad); err == nil {
+		t.Error("RegisterWorkflow() with a malformed guard should fail")
+	}
+
+	orphan := &WorkflowDefinition{
+		ID: "orphan",
+		Nodes: []NodeDefinition{
+			{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"a"}, Guards: EdgeGuards{Next: map[int]string{1: "$.x > 1"}}},
+			{ID: "a", Type: string(NodeTypeNoOp)},
+		},
+	}
+	if err := engine.RegisterWorkflow(orphan); err == nil {
+		t.Error("RegisterWorkflow() with a guard on a missing edge should fail")
+	}
+}
+
+func TestEngine_RunIf(t *testing.T) {
+	var calls atomic.Int32
+	def := NewWorkflowBuilder("run-if", "RunIf").
+		AddNode("start", string(NodeTypeNoOp)).Next("discount").Done().
+		AddNode("discount", "apply-discount").RunIf(`$.tier == "gold"`).Next("audit").Done().
+		AddNode("audit", string(NodeTypeSet)).Config(map[string]interface{}{"values": map[string]interface{}{"audited": true}}).Done().
+		Build()
+	engine := newGuardEngine(t, def)
+	engine.RegisterNodeHandler("apply-discount", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		calls.Add(1)
+		data := input.Data.(map[string]interface{})
+		return &NodeOutput{Data: map[string]interface{}{"tier": data["tier"], "discount": 10}}, nil
+	})
+
+	state := runGuarded(t, engine, "run-if", map[string]interface{}{"tier": "silver"})
+	if got := calls.Load(); got != 0 {
+		t.Errorf("handler called %d times with runIf false, want 0", got)
+	}
+	skipped, _ := state.Context.NodeOutputs["discount"].(map[string]interface{})
+	if len(skipped) != 1 || skipped["tier"] != "silver" {
+		t.Errorf("skipped node output = %v, want its input", state.Context.NodeOutputs["discount"])
+	}
+	audit, _ := state.Context.NodeOutputs["audit"].(map[string]interface{})
+	if audit["audited"] != true || audit["discount"] != nil {
+		t.Errorf("audit output = %v, want the passed-through input", audit)
+	}
+
+	state = runGuarded(t, engine, "run-if", map[string]interface{}{"tier": "gold"})
+	if got := calls.Load(); got != 1 {
+		t.Errorf("handler called %d times with runIf true, want 1", got)
+	}
+	audit, _ = state.Context.NodeOutputs["audit"].(map[string]interface{})
+	if audit["discount"] != 10 {
+		t.Errorf("audit output = %v, want the discount node output", audit)
+	}
+}
+
+func TestEngine_RegisterWorkflow_InvalidRunIf(t *testing.T) {
+	def := &WorkflowDefinition{ID: "bad-run-if", Nodes: []NodeDefinition{{ID: "a", Type: "set", RunIf: "$.x >"}}}
+	if err := NewEngine(nil).RegisterWorkflow(def); err == nil {
+		t.Error("RegisterWorkflow() should reject an invalid runIf")
+	}
+}
@@ -3,7 +3,9 @@ package workflow
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -12,6 +14,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// ErrExecutionCancelled is returned by AwaitExecution for cancelled executions.
+var ErrExecutionCancelled = errors.New("workflow execution cancelled")
+
+// ErrNodeTimeout wraps the error of a node that ran past its Timeout.
+var ErrNodeTimeout = errors.New("node timed out")
+
 // Engine implements WorkflowEngine using EventBus.
 type Engine struct {
 	eventBus   core.EventBus
@@ -26,31 +34,153 @@ type Engine struct {
 	mergeMu     sync.Mutex
 
 	// Active node tracking for better completion detection
-	activeNodes map[string]map[string]bool // executionID -> nodeID -> true
+	activeNodes map[string]map[string]int // executionID -> nodeID -> runs in flight
 	activeMu    sync.Mutex
 
 	// Context cancellation for executions
-	execContexts map[string]context.CancelFunc // executionID -> cancel function
+	execContexts map[string]execContextEntry // executionID -> node context and its cancel
 	execCtxMu    sync.Mutex
+
+	// Optional persistence; persistMu keeps snapshots saved in order
+	store     ExecutionStore
+	persistMu sync.Mutex
+
+	// Retention of finished executions; stopRetention stops the background sweep
+	retention     ExecutionRetention
+	stopRetention context.CancelFunc
+
+	// Per-execution HTTP clients (cookie jar + connections) for HTTP nodes
+	httpSessions *httpSessions
+
+	// Instruments; nil when EngineOptions.Metrics is unset
+	metrics *engineMetrics
+
+	// Lifecycle event publisher; nil unless EngineOptions.LifecycleEvents is set
+	events *engineEvents
+
+	// Admission of executions; nil unless EngineOptions.Concurrency caps them
+	limiter *executionLimiter
+
+	// Wraps every handler invocation, outermost first (guarded by mu)
+	middleware []NodeMiddleware
+
+	// Log every HTTP node's exchange (EngineOptions.DebugHTTP)
+	debugHTTP bool
+}
+
+// EngineOptions configures a workflow engine.
+type EngineOptions struct {
+	// Store persists execution state after each node completes.
+	// Nil disables persistence (and ResumeExecutions).
+	Store ExecutionStore
+
+	// Retention bounds the finished executions kept in memory.
+	// The zero value keeps them until CleanupOldExecutions/PurgeExecution.
+	Retention ExecutionRetention
+
+	// Metrics receives execution and node metrics. Nil disables them.
+	Metrics core.Metrics
+
+	// LifecycleEvents publishes execution and node start/finish events to the
+	// EventBus (see ExecutionStartedAddress and friends).
+	LifecycleEvents bool
+
+	// Concurrency caps the running executions and bounds those waiting for a
+	// slot. The zero value runs every execution immediately.
+	Concurrency ExecutionConcurrency
+
+	// Logger receives the engine's logs. Nil uses core.NewDefaultLogger.
+	Logger core.Logger
+
+	// DebugHTTP logs every HTTP node's request and response, as the node's
+	// "debug" config does for one node (credentials redacted).
+	DebugHTTP bool
+}
+
+// ExecutionRetention evicts completed/failed/cancelled executions.
+// Running executions are never evicted.
+type ExecutionRetention struct {
+	// MaxCount caps the number of finished executions kept; the oldest are
+	// evicted first as soon as an execution finishes. 0 means unlimited.
+	MaxCount int
+
+	// MaxAge evicts finished executions whose EndTime is older than MaxAge.
+	// 0 disables age-based eviction.
+	MaxAge time.Duration
+
+	// Interval between background MaxAge sweeps (default: min(MaxAge, 1m)).
+	Interval time.Duration
 }
 
 type mergeState struct {
 	expectedInputs int
 	receivedInputs int
 	data           []interface{}
+	timer          *time.Timer // set when the merge node has a timeout
 }
 
-// NewEngine creates a new workflow engine.
+// NewEngine creates a new workflow engine without persistence.
 func NewEngine(eventBus core.EventBus) *Engine {
-	return &Engine{
+	return NewEngineWithOptions(eventBus, EngineOptions{})
+}
+
+// NewEngineWithOptions creates a new workflow engine with options.
+func NewEngineWithOptions(eventBus core.EventBus, opts EngineOptions) *Engine {
+	e := &Engine{
 		eventBus:     eventBus,
 		registry:     NewNodeRegistry(),
 		workflows:    make(map[string]*WorkflowDefinition),
 		executions:   make(map[string]*ExecutionState),
 		mergeStates:  make(map[string]*mergeState),
-		activeNodes:  make(map[string]map[string]bool),
-		execContexts: make(map[string]context.CancelFunc),
-		logger:       core.NewDefaultLogger(),
+		activeNodes:  make(map[string]map[string]int),
+		execContexts: make(map[string]execContextEntry),
+		logger:       opts.Logger,
+		store:        opts.Store,
+		retention:    opts.Retention,
+		httpSessions: newHTTPSessions(),
+		metrics:      newEngineMetrics(opts.Metrics),
+		events:       newEngineEvents(eventBus, opts.LifecycleEvents),
+		limiter:      newExecutionLimiter(opts.Concurrency),
+		debugHTTP:    opts.DebugHTTP,
+	}
+	if e.logger == nil {
+		e.logger = core.NewDefaultLogger()
+	}
+
+	if opts.Retention.MaxAge > 0 {
+		interval := opts.Retention.Interval
+		if interval <= 0 {
+			interval = time.Minute
+			if opts.Retention.MaxAge < interval {
+				interval = opts.Retention.MaxAge
+			}
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		e.stopRetention = cancel
+		go e.retentionLoop(ctx, interval)
+	}
+	return e
+}
+
+// Close stops background work (the retention sweep). Running executions are not cancelled.
+func (e *Engine) Close() {
+	if e.stopRetention != nil {
+		e.stopRetention()
+	}
+}
+
+// retentionLoop evicts expired executions every interval until ctx is done.
+func (e *Engine) retentionLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.evictExecutions(e.retention.MaxAge, e.retention.MaxCount)
+		}
 	}
 }
 
@@ -59,6 +189,26 @@ func (e *Engine) RegisterNodeHandler(nodeType NodeType, handler NodeHandler) {
 	e.registry.Register(nodeType, handler)
 }
 
+// UseNodeMiddleware adds middleware around every node handler invocation,
+// including each retry attempt. The first middleware added runs outermost.
+// Middleware sees the node's input (NodeInput.NodeID identifies the node) and
+// output, and returns without calling next to short-circuit the handler.
+func (e *Engine) UseNodeMiddleware(middleware NodeMiddleware) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.middleware = append(e.middleware, middleware)
+}
+
+// withMiddleware wraps handler in the registered middleware.
+func (e *Engine) withMiddleware(handler NodeHandler) NodeHandler {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for i := len(e.middleware) - 1; i >= 0; i-- {
+		handler = e.middleware[i](handler)
+	}
+	return handler
+}
+
 // Registry returns the node registry for external registration
 func (e *Engine) Registry() NodeRegistry {
 	return e.registry
@@ -98,6 +248,46 @@ func (e *Engine) RegisterWorkflow(def *WorkflowDefinition) error {
 				return fmt.Errorf("node %s references unknown node %s in falseNext", node.ID, next)
 			}
 		}
+		for _, next := range node.OnError {
+			if !nodeIDs[next] {
+				return fmt.Errorf("node %s references unknown node %s in onError", node.ID, next)
+			}
+		}
+		if err := validateGuards(&node); err != nil {
+			return err
+		}
+		if _, err := retryPolicyOf(&node); err != nil {
+			return err
+		}
+		switch node.OnTimeout {
+		case "", TimeoutPolicyOnError, TimeoutPolicyFail:
+		default:
+			return fmt.Errorf("node %s: invalid onTimeout %q (want onError or fail)", node.ID, node.OnTimeout)
+		}
+		if NodeType(node.Type) == NodeTypeMerge {
+			if _, _, err := mergeTimeoutOf(&node); err != nil {
+				return err
+			}
+		}
+		if NodeType(node.Type) == NodeTypeEncode {
+			if _, err := encodeOperationOf(&node); err != nil {
+				return err
+			}
+		}
+		if NodeType(node.Type) == NodeTypeMergeData {
+			if err := validateMergeData(&node); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Reject runaway cycles; unreachable nodes are allowed but never run
+	unreachable, err := CheckWorkflowGraph(def)
+	if err != nil {
+		return err
+	}
+	if len(unreachable) > 0 {
+		e.logger.Warnf("workflow %s: nodes unreachable from any start node: %s", def.ID, strings.Join(unreachable, ", "))
 	}
 
 	e.mu.Lock()
@@ -143,7 +333,17 @@ func (e *Engine) registerWorkflowConsumers(def *WorkflowDefinition) {
 	}
 }
 
-// ExecuteWorkflow starts a workflow execution.
+// hasWorkflow reports whether workflowID is registered.
+func (e *Engine) hasWorkflow(workflowID string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, ok := e.workflows[workflowID]
+	return ok
+}
+
+// ExecuteWorkflow starts a workflow execution. With EngineOptions.Concurrency
+// set, an execution over the limit is queued as pending, or rejected with
+// ErrEngineOverloaded when the queue is full.
 func (e *Engine) ExecuteWorkflow(ctx context.Context, workflowID string, input interface{}) (string, error) {
 	return e.startExecution(ctx, workflowID, input)
 }
@@ -163,7 +363,7 @@ func (e *Engine) startExecution(ctx context.Context, workflowID string, input in
 	execCtx, cancel := context.WithCancel(ctx)
 
 	e.execCtxMu.Lock()
-	e.execContexts[executionID] = cancel
+	e.execContexts[executionID] = execContextEntry{ctx: execCtx, cancel: cancel, done: make(chan struct{})}
 	e.execCtxMu.Unlock()
 
 	execCtxData := &ExecutionContext{
@@ -174,6 +374,7 @@ func (e *Engine) startExecution(ctx context.Context, workflowID string, input in
 		NodeOutputs: make(map[string]interface{}),
 		Variables:   make(map[string]interface{}),
 	}
+	execCtxData.Depth, _ = ctx.Value(subWorkflowDepthKey{}).(int)
 
 	// Store input
 	if inputMap, ok := input.(map[string]interface{}); ok {
@@ -183,132 +384,125 @@ func (e *Engine) startExecution(ctx context.Context, workflowID string, input in
 	}
 
 	state := &ExecutionState{
-		ExecutionID: executionID,
-		WorkflowID:  workflowID,
-		Status:      ExecutionStatusRunning,
-		StartTime:   time.Now(),
-		Context:     execCtxData,
+		ExecutionID:  executionID,
+		WorkflowID:   workflowID,
+		Status:       ExecutionStatusPending,
+		StartTime:    execCtxData.StartTime,
+		Context:      execCtxData,
+		PendingNodes: make(map[string]interface{}),
 	}
 
 	e.mu.Lock()
 	e.executions[executionID] = state
 	e.mu.Unlock()
 
-	// Initialize active nodes tracking
-	e.activeMu.Lock()
-	e.activeNodes[executionID] = make(map[string]bool)
-	e.activeMu.Unlock()
+	run := func() bool { return e.runExecution(execCtx, def, state, input) }
 
-	// Find and execute trigger/start nodes
-	for _, node := range def.Nodes {
-		if e.isStartNode(&node, def) {
-			e.markNodeActive(executionID, node.ID)
-			go e.executeNode(execCtx, def, &node, execCtxData, input)
-		}
+	// Sub-workflows never wait: their parent holds a slot until they finish
+	if execCtxData.Depth > 0 {
+		e.limiter.acquire()
+		run()
+		return executionID, nil
 	}
 
+	startNow, err := e.limiter.admit(queuedExecution{executionID: executionID, start: run})
+	if err != nil {
+		e.mu.Lock()
+		delete(e.executions, executionID)
+		e.mu.Unlock()
+		e.execCtxMu.Lock()
+		delete(e.execContexts, executionID)
+		e.execCtxMu.Unlock()
+		cancel()
+		e.metrics.shed(workflowID)
+		return "", fmt.Errorf("%w: cannot start workflow %s", err, workflowID)
+	}
+	if startNow {
+		run()
+	}
 	return executionID, nil
 }
 
-func (e *Engine) isStartNode(node *NodeDefinition, def *WorkflowDefinition) bool {
-	// A start node is either a trigger type or has no incoming connections
-	switch NodeType(node.Type) {
-	case NodeTypeWebhook, NodeTypeSchedule, NodeTypeEvent, NodeTypeManual:
-		return true
+// runExecution moves a pending execution to running and dispatches its start
+// nodes. Returns false if the execution is no longer pending.
+func (e *Engine) runExecution(execCtx context.Context, def *WorkflowDefinition, state *ExecutionState, input interface{}) bool {
+	executionID, workflowID := state.ExecutionID, state.WorkflowID
+	e.mu.Lock()
+	if state.Status != ExecutionStatusPending {
+		e.mu.Unlock()
+		return false
 	}
+	state.Status = ExecutionStatusRunning
+	state.StartTime = time.Now()
+	e.mu.Unlock()
 
-	// Check if any node points to this node
-	for _, n := range def.Nodes {
-		for _, next := range n.Next {
-			if next == node.ID {
-				return false
-			}
-		}
-		for _, next := range n.TrueNext {
-			if next == node.ID {
-				return false
-			}
-		}
-		for _, next := range n.FalseNext {
-			if next == node.ID {
-				return false
-			}
+	// Initialize active nodes tracking
+	e.activeMu.Lock()
+	e.activeNodes[executionID] = make(map[string]int)
+	e.activeMu.Unlock()
+
+	// Find trigger/start nodes; mark them all before running any so an
+	// early finisher cannot complete the execution
+	var startNodes []*NodeDefinition
+	for i := range def.Nodes {
+		if isStartNode(&def.Nodes[i], def) {
+			startNodes = append(startNodes, &def.Nodes[i])
+			e.trackPendingNode(executionID, def.Nodes[i].ID, input)
+			e.markNodeActive(executionID, def.Nodes[i].ID)
 		}
 	}
+	e.persistExecution(executionID)
+	e.events.executionStarted(workflowID, executionID, state.StartTime)
 
+	for _, node := range startNodes {
+		go e.executeNode(execCtx, def, node, state.Context, input)
+	}
 	return true
 }
 
 func (e *Engine) executeNode(ctx context.Context, def *WorkflowDefinition, node *NodeDefinition, execCtx *ExecutionContext, input interface{}) {
-	// Check if execution was cancelled
+	// Check if execution was cancelled or already finished
 	select {
 	case <-ctx.Done():
 		e.markNodeInactive(execCtx.ExecutionID, node.ID)
 		return
 	default:
 	}
+	if e.holdNode(execCtx.ExecutionID, node.ID, input) {
+		e.markNodeInactive(execCtx.ExecutionID, node.ID)
+		return
+	}
+	if !e.isRunning(execCtx.ExecutionID) {
+		e.markNodeInactive(execCtx.ExecutionID, node.ID)
+		return
+	}
 
 	nodeType := NodeType(node.Type)
 	handler, ok := e.registry.Get(nodeType)
 	if !ok {
-		e.logger.Error(fmt.Sprintf("unknown node type: %s", node.Type))
+		e.nodeLogger(def.ID, execCtx.ExecutionID, node.ID).Error(fmt.Sprintf("unknown node type: %s", node.Type))
 		e.recordError(execCtx, node.ID, fmt.Sprintf("unknown node type: %s", node.Type))
 		e.markNodeInactive(execCtx.ExecutionID, node.ID)
 		return
 	}
 
-	// Apply timeout if configured
-	nodeCtx := ctx
-	if node.Timeout != "" {
-		if timeout, err := time.ParseDuration(node.Timeout); err == nil {
-			var cancel context.CancelFunc
-			nodeCtx, cancel = context.WithTimeout(ctx, timeout)
-			defer cancel()
-		}
-	}
-
-	// Add engine to context for sub-workflow nodes
-	nodeCtx = context.WithValue(nodeCtx, "workflow_engine", e)
-
-	// Prepare input
-	nodeInput := &NodeInput{
-		Data:        input,
-		Context:     execCtx,
-		Config:      node.Config,
-		TriggerData: execCtx.Data["input"],
+	// A false runIf skips the handler and passes the input straight through
+	skipped := !e.shouldRun(node, input)
+	if !skipped && nodeType == NodeTypeLoop {
+		e.executeLoop(ctx, def, node, execCtx, input)
+		return
 	}
 
-	// Execute with retry
-	var output *NodeOutput
+	output := &NodeOutput{Data: input}
 	var err error
-	retries := node.RetryCount
-	if retries == 0 {
-		retries = 1
+	if !skipped {
+		output, err = e.runHandler(ctx, def, node, handler, execCtx, input)
 	}
 
-	for i := 0; i < retries; i++ {
-		// Check cancellation before each retry
-		select {
-		case <-ctx.Done():
-			e.markNodeInactive(execCtx.ExecutionID, node.ID)
-			return
-		default:
-		}
-
-		output, err = handler(nodeCtx, nodeInput)
-		if err == nil {
-			break
-		}
-		if i < retries-1 {
-			// Exponential backoff with jitter
-			backoff := time.Duration(i+1) * time.Second
-			select {
-			case <-ctx.Done():
-				e.markNodeInactive(execCtx.ExecutionID, node.ID)
-				return
-			case <-time.After(backoff):
-			}
-		}
+	// Execution cancelled while the handler ran: drop its result
+	if ctx.Err() != nil && !e.isRunning(execCtx.ExecutionID) {
+		e.markNodeInactive(execCtx.ExecutionID, node.ID)
+		return
 	}
 
 	// Mark node as completed
@@ -316,13 +510,16 @@ func (e *Engine) executeNode(ctx context.Context, def *WorkflowDefinition, node
 
 	// Handle error
 	if err != nil {
-		e.recordError(execCtx, node.ID, err.Error())
-		if len(node.OnError) > 0 {
-			for _, nextID := range node.OnError {
+		e.recordNodeError(execCtx, node.ID, err.Error(), err)
+		if errors.Is(err, ErrNodeTimeout) && node.OnTimeout == TimeoutPolicyFail {
+			e.completeExecution(execCtx.ExecutionID, err)
+			return
+		}
+		if errorNodes, _ := e.followGuards(node, node.OnError, node.Guards.OnError, input); len(errorNodes) > 0 {
+			for _, nextID := range errorNodes {
 				nextNode := e.findNode(def, nextID)
 				if nextNode != nil {
-					e.markNodeActive(execCtx.ExecutionID, nextID)
-					go e.executeNode(ctx, def, nextNode, execCtx, input)
+					e.dispatchNode(ctx, def, nextNode, execCtx, input)
 				}
 			}
 		} else {
@@ -332,8 +529,12 @@ func (e *Engine) executeNode(ctx context.Context, def *WorkflowDefinition, node
 		return
 	}
 
-	// Store output
+	// Store output, unless the execution was cancelled while the handler ran
 	e.mu.Lock()
+	if state, ok := e.executions[execCtx.ExecutionID]; !ok || !inProgress(state.Status) {
+		e.mu.Unlock()
+		return
+	}
 	execCtx.NodeOutputs[node.ID] = output.Data
 	e.mu.Unlock()
 
@@ -343,8 +544,13 @@ func (e *Engine) executeNode(ctx context.Context, def *WorkflowDefinition, node
 		return
 	}
 
-	// Determine next nodes
-	nextNodes := e.determineNextNodes(node, output)
+	// Determine next nodes; skipped nodes always continue on Next
+	nextNodes, guards := node.Next, node.Guards.Next
+	if !skipped {
+		nextNodes, guards = e.determineNextNodes(node, output)
+	}
+	nextNodes, skippedEdges := e.followGuards(node, nextNodes, guards, output.Data)
+	e.skipMergeInputs(ctx, def, execCtx, skippedEdges)
 
 	// Execute next nodes
 	for _, nextID := range nextNodes {
@@ -361,8 +567,7 @@ func (e *Engine) executeNode(ctx context.Context, def *WorkflowDefinition, node
 			if NodeType(nextNode.Type) == NodeTypeMerge {
 				e.handleMergeInput(ctx, def, nextNode, execCtx, output.Data)
 			} else {
-				e.markNodeActive(execCtx.ExecutionID, nextID)
-				go e.executeNode(ctx, def, nextNode, execCtx, output.Data)
+				e.dispatchNode(ctx, def, nextNode, execCtx, output.Data)
 			}
 		}
 	}
@@ -373,24 +578,152 @@ func (e *Engine) executeNode(ctx context.Context, def *WorkflowDefinition, node
 	}
 }
 
-func (e *Engine) determineNextNodes(node *NodeDefinition, output *NodeOutput) []string {
-	// If output specifies next nodes, use those
+// runHandler calls a node's handler with its timeout and retry policy applied.
+// If the execution is cancelled between attempts it returns ctx.Err().
+func (e *Engine) runHandler(ctx context.Context, def *WorkflowDefinition, node *NodeDefinition, handler NodeHandler, execCtx *ExecutionContext, input interface{}) (*NodeOutput, error) {
+	// Apply timeout if configured
+	nodeCtx := ctx
+	var timeout time.Duration
+	if node.Timeout != "" {
+		if d, err := time.ParseDuration(node.Timeout); err == nil {
+			timeout = d
+			var cancel context.CancelFunc
+			nodeCtx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+	}
+
+	// Add engine to context for sub-workflow nodes, and the node's logger
+	nodeCtx = context.WithValue(nodeCtx, "workflow_engine", e)
+	nodeCtx = context.WithValue(nodeCtx, loggerKey{}, e.nodeLogger(def.ID, execCtx.ExecutionID, node.ID))
+
+	// Prepare input
+	nodeInput := &NodeInput{
+		Data:        input,
+		Context:     execCtx,
+		Config:      node.Config,
+		TriggerData: execCtx.Data["input"],
+		NodeID:      node.ID,
+		NodeType:    NodeType(node.Type),
+	}
+	handler = e.withMiddleware(handler)
+
+	// Execute with retry
+	var output *NodeOutput
+	var err error
+	retries := node.RetryCount
+	if retries == 0 {
+		retries = 1
+	}
+	policy, _ := retryPolicyOf(node) // validated by RegisterWorkflow
+
+	start := time.Now()
+	e.events.nodeStarted(def.ID, execCtx.ExecutionID, node)
+	for i := 0; i < retries; i++ {
+		// Check cancellation before each retry
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		output, err = handler(nodeCtx, nodeInput)
+		if err == nil {
+			break
+		}
+		if i < retries-1 {
+			if waitRetry(nodeCtx, policy.waitAfter(i+1)) != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				// Node timeout expired while waiting: fail with the last error
+				break
+			}
+		}
+	}
+
+	// The node's own deadline passed (not the execution's): report a timeout
+	if err != nil && timeout > 0 && ctx.Err() == nil && errors.Is(nodeCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w after %s: %v", ErrNodeTimeout, timeout, err)
+	}
+
+	e.metrics.node(def.ID, node.Type, start, err)
+	e.events.nodeFinished(def.ID, execCtx.ExecutionID, node, start, err)
+	if err == nil && output == nil {
+		output = &NodeOutput{}
+	}
+	return output, err
+}
+
+func (e *Engine) determineNextNodes(node *NodeDefinition, output *NodeOutput) ([]string, map[int]string) {
+	// If output specifies next nodes, use those (unguarded)
 	if len(output.NextNodes) > 0 {
-		return output.NextNodes
+		return output.NextNodes, nil
 	}
 
 	// Check for condition result
 	if data, ok := output.Data.(map[string]interface{}); ok {
 		if condResult, ok := data["_conditionResult"].(bool); ok {
 			if condResult {
-				return node.TrueNext
+				return node.TrueNext, node.Guards.TrueNext
 			}
-			return node.FalseNext
+			return node.FalseNext, node.Guards.FalseNext
 		}
 	}
 
 	// Default to configured next nodes
-	return node.Next
+	return node.Next, node.Guards.Next
+}
+
+// followGuards filters an edge list down to the edges whose guard holds for
+// data; guards is the list's entry in node.Guards. Unguarded edges are always
+// followed; if every guard is false the branch ends. The targets of the
+// edges not followed are returned as skipped.
+func (e *Engine) followGuards(node *NodeDefinition, nextNodes []string, guards map[int]string, data interface{}) (followed, skipped []string) {
+	if len(guards) == 0 {
+		return nextNodes, nil
+	}
+
+	guardInput := guardData(data)
+	followed = make([]string, 0, len(nextNodes))
+	for i, nextID := range nextNodes {
+		when, guarded := guards[i]
+		if !guarded {
+			followed = append(followed, nextID)
+			continue
+		}
+		guard, err := compileGuard(when)
+		if err != nil {
+			e.logger.Error(fmt.Sprintf("node %s: invalid guard for edge to %s: %v", node.ID, nextID, err))
+		} else if guard.eval(guardInput) {
+			followed = append(followed, nextID)
+			continue
+		}
+		skipped = append(skipped, nextID)
+	}
+	return followed, skipped
+}
+
+// skipMergeInputs tells the merge nodes among skipped, edges whose guard was
+// false, that those inputs will not arrive.
+func (e *Engine) skipMergeInputs(ctx context.Context, def *WorkflowDefinition, execCtx *ExecutionContext, skipped []string) {
+	for _, nextID := range skipped {
+		if nextNode := e.findNode(def, nextID); nextNode != nil && NodeType(nextNode.Type) == NodeTypeMerge {
+			e.mergeInput(ctx, def, nextNode, execCtx, nil, true)
+		}
+	}
+}
+
+// shouldRun evaluates the node's runIf expression against its input.
+// Nodes without runIf always run.
+func (e *Engine) shouldRun(node *NodeDefinition, input interface{}) bool {
+	if node.RunIf == "" {
+		return true
+	}
+	guard, err := compileGuard(node.RunIf)
+	if err != nil {
+		e.logger.Error(fmt.Sprintf("node %s: invalid runIf: %v", node.ID, err))
+		return true
+	}
+	return guard.eval(guardData(input))
 }
 
 func (e *Engine) findNode(def *WorkflowDefinition, nodeID string) *NodeDefinition {
@@ -403,11 +736,28 @@ func (e *Engine) findNode(def *WorkflowDefinition, nodeID string) *NodeDefinitio
 }
 
 func (e *Engine) handleMergeInput(ctx context.Context, def *WorkflowDefinition, node *NodeDefinition, execCtx *ExecutionContext, data interface{}) {
+	e.mergeInput(ctx, def, node, execCtx, data, false)
+}
+
+// mergeInput records one input of a merge node and runs the merge once its
+// mode is satisfied. A skipped input (its edge's guard was false) counts
+// towards a waitAll merge without adding data; a merge whose inputs were all
+// skipped does not run.
+func (e *Engine) mergeInput(ctx context.Context, def *WorkflowDefinition, node *NodeDefinition, execCtx *ExecutionContext, data interface{}, skipped bool) {
 	key := fmt.Sprintf("%s:%s", execCtx.ExecutionID, node.ID)
 
+	// Check mode from config
+	mode := "waitAll"
+	if m, ok := node.Config["mode"].(string); ok {
+		mode = m
+	}
+	if skipped && mode != "waitAll" {
+		return
+	}
+
 	e.mergeMu.Lock()
-	state, ok := e.mergeStates[key]
-	if !ok {
+	state, exists := e.mergeStates[key]
+	if !exists {
 		// Count expected inputs
 		expected := 0
 		for _, n := range def.Nodes {
@@ -434,13 +784,18 @@ func (e *Engine) handleMergeInput(ctx context.Context, def *WorkflowDefinition,
 		e.mergeStates[key] = state
 	}
 
-	state.data = append(state.data, data)
+	if !skipped {
+		state.data = append(state.data, data)
+	}
 	state.receivedInputs++
 
-	// Check mode from config
-	mode := "waitAll"
-	if m, ok := node.Config["mode"].(string); ok {
-		mode = m
+	// A merge with a timeout keeps the execution open while it waits, and
+	// resolves on its own if the remaining inputs never arrive
+	if timeout, _, _ := mergeTimeoutOf(node); !exists && timeout > 0 && mode == "waitAll" && state.receivedInputs < state.expectedInputs {
+		e.markNodeActive(execCtx.ExecutionID, node.ID)
+		state.timer = time.AfterFunc(timeout, func() {
+			e.mergeTimedOut(ctx, def, node, execCtx, key, state, timeout)
+		})
 	}
 
 	shouldProceed := false
@@ -451,26 +806,94 @@ func (e *Engine) handleMergeInput(ctx context.Context, def *WorkflowDefinition,
 		shouldProceed = state.receivedInputs >= 1
 	}
 
+	if shouldProceed && len(state.data) == 0 {
+		// Every input was skipped: the merge is skipped with them
+		delete(e.mergeStates, key)
+		e.mergeMu.Unlock()
+		if state.timer != nil {
+			state.timer.Stop()
+			e.markNodeInactive(execCtx.ExecutionID, node.ID)
+		}
+		return
+	}
 	if shouldProceed {
 		delete(e.mergeStates, key)
 		e.mergeMu.Unlock()
-		// Continue execution with merged data
-		go e.executeNode(ctx, def, node, execCtx, state.data)
+		// Continue execution with merged data; dispatching marks the merge
+		// node active before the branch that completed it finishes
+		e.dispatchNode(ctx, def, node, execCtx, state.data)
+		if state.timer != nil {
+			state.timer.Stop()
+			e.markNodeInactive(execCtx.ExecutionID, node.ID)
+		}
 	} else {
 		e.mergeMu.Unlock()
 	}
 }
 
-func (e *Engine) handleNodeExecution(ctx context.Context, def *WorkflowDefinition, node *NodeDefinition, msg core.Message) error {
-	var req struct {
-		ExecutionID string      `json:"executionId"`
-		Data        interface{} `json:"data"`
+// mergeTimedOut resolves a merge whose timeout passed before every input
+// arrived: it runs with the inputs so far, or fails the execution.
+func (e *Engine) mergeTimedOut(ctx context.Context, def *WorkflowDefinition, node *NodeDefinition, execCtx *ExecutionContext, key string, state *mergeState, timeout time.Duration) {
+	e.mergeMu.Lock()
+	// The last input or the end of the execution got here first
+	if e.mergeStates[key] != state {
+		e.mergeMu.Unlock()
+		return
 	}
+	delete(e.mergeStates, key)
+	data := state.data
+	received := state.receivedInputs
+	e.mergeMu.Unlock()
 
-	if body, ok := msg.Body().([]byte); ok {
-		if err := json.Unmarshal(body, &req); err != nil {
-			return err
-		}
+	if !e.inProgress(execCtx.ExecutionID) {
+		return
+	}
+
+	_, onTimeout, _ := mergeTimeoutOf(node)
+	if onTimeout == TimeoutPolicyFail {
+		err := fmt.Errorf("%w after %s: merge received %d of %d inputs", ErrNodeTimeout, timeout, received, state.expectedInputs)
+		e.recordNodeError(execCtx, node.ID, err.Error(), err)
+		e.completeExecution(execCtx.ExecutionID, err)
+		return
+	}
+
+	e.logger.Info(fmt.Sprintf("merge %s timed out after %s with %d of %d inputs; proceeding", node.ID, timeout, received, state.expectedInputs))
+	e.dispatchNode(ctx, def, node, execCtx, data)
+	e.markNodeInactive(execCtx.ExecutionID, node.ID)
+}
+
+// mergeTimeoutOf reads a merge node's Config["timeout"] (0 when unset) and
+// Config["onTimeout"] policy.
+func mergeTimeoutOf(node *NodeDefinition) (time.Duration, string, error) {
+	var timeout time.Duration
+	if s, ok := node.Config["timeout"].(string); ok && s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return 0, "", fmt.Errorf("merge node %s: invalid timeout %q", node.ID, s)
+		}
+		timeout = d
+	}
+	onTimeout, _ := node.Config["onTimeout"].(string)
+	switch onTimeout {
+	case "":
+		onTimeout = MergeTimeoutProceed
+	case MergeTimeoutProceed, TimeoutPolicyFail:
+	default:
+		return 0, "", fmt.Errorf("merge node %s: invalid onTimeout %q (want proceed or fail)", node.ID, onTimeout)
+	}
+	return timeout, onTimeout, nil
+}
+
+func (e *Engine) handleNodeExecution(ctx context.Context, def *WorkflowDefinition, node *NodeDefinition, msg core.Message) error {
+	var req struct {
+		ExecutionID string      `json:"executionId"`
+		Data        interface{} `json:"data"`
+	}
+
+	if body, ok := msg.Body().([]byte); ok {
+		if err := json.Unmarshal(body, &req); err != nil {
+			return err
+		}
 	}
 
 	e.mu.RLock()
@@ -481,17 +904,36 @@ func (e *Engine) handleNodeExecution(ctx context.Context, def *WorkflowDefinitio
 		return fmt.Errorf("execution not found: %s", req.ExecutionID)
 	}
 
-	go e.executeNode(ctx, def, node, state.Context, req.Data)
+	// Run on the execution's context so CancelExecution stops the node too
+	e.execCtxMu.Lock()
+	ec, ok := e.execContexts[req.ExecutionID]
+	e.execCtxMu.Unlock()
+	if !ok {
+		return fmt.Errorf("execution is not running: %s", req.ExecutionID)
+	}
+
+	e.dispatchNode(ec.ctx, def, node, state.Context, req.Data)
 	return nil
 }
 
 func (e *Engine) recordError(execCtx *ExecutionContext, nodeID, message string) {
+	e.recordNodeError(execCtx, nodeID, message, nil)
+}
+
+// recordNodeError records a failure of nodeID, typed by its cause err.
+func (e *Engine) recordNodeError(execCtx *ExecutionContext, nodeID, message string, err error) {
+	var errType string
+	if errors.Is(err, ErrNodeTimeout) {
+		errType = ErrorTypeTimeout
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
 	execCtx.Errors = append(execCtx.Errors, ExecutionError{
 		NodeID:    nodeID,
 		Message:   message,
+		Type:      errType,
 		Timestamp: time.Now(),
 	})
 }
@@ -506,6 +948,8 @@ func (e *Engine) completeExecution(executionID string, err error) {
 
 	now := time.Now()
 	state.EndTime = &now
+	wasRunning := inProgress(state.Status)
+	holdsSlot := state.Status == ExecutionStatusRunning
 
 	if err != nil {
 		state.Status = ExecutionStatusFailed
@@ -513,33 +957,42 @@ func (e *Engine) completeExecution(executionID string, err error) {
 	} else {
 		state.Status = ExecutionStatusCompleted
 	}
+	state.PendingNodes = nil
+	state.held = nil
+	status, started, errMsg := state.Status, state.StartTime, state.Error
 	e.mu.Unlock()
 
-	// Clean up execution resources
-	e.execCtxMu.Lock()
-	delete(e.execContexts, executionID)
-	e.execCtxMu.Unlock()
+	if wasRunning {
+		e.metrics.finished(state.WorkflowID, status, started, now)
+		e.events.executionFinished(state.WorkflowID, executionID, status, errMsg, started, now)
+	}
 
-	e.activeMu.Lock()
-	delete(e.activeNodes, executionID)
-	e.activeMu.Unlock()
+	e.persistExecution(executionID)
 
-	// Clean up merge states for this execution
-	e.mergeMu.Lock()
-	for key := range e.mergeStates {
-		if strings.HasPrefix(key, executionID+":") {
-			delete(e.mergeStates, key)
-		}
+	// Free the slot before releasing so AwaitExecution callers see it free
+	if holdsSlot {
+		e.limiter.release()
+	}
+
+	// Clean up execution resources
+	e.releaseExecution(executionID)
+
+	if e.retention.MaxCount > 0 {
+		e.evictExecutions(-1, e.retention.MaxCount)
 	}
-	e.mergeMu.Unlock()
 }
 
 func (e *Engine) checkExecutionComplete(executionID string) {
 	e.mu.RLock()
 	state, ok := e.executions[executionID]
+	running := ok && state.Status == ExecutionStatusRunning
+	var errCount int
+	if running {
+		errCount = len(state.Context.Errors)
+	}
 	e.mu.RUnlock()
 
-	if !ok || state.Status != ExecutionStatusRunning {
+	if !running {
 		return
 	}
 
@@ -551,36 +1004,279 @@ func (e *Engine) checkExecutionComplete(executionID string) {
 
 	// Only complete if no active nodes remain
 	if !hasActiveNodes {
-		if len(state.Context.Errors) == 0 {
+		if errCount == 0 {
 			e.completeExecution(executionID, nil)
 		} else {
-			e.completeExecution(executionID, fmt.Errorf("workflow had %d errors", len(state.Context.Errors)))
+			e.completeExecution(executionID, fmt.Errorf("workflow had %d errors", errCount))
 		}
 	}
 }
 
-// markNodeActive marks a node as active in an execution.
+// markNodeActive records one more in-flight run of a node. Every goroutine
+// running executeNode must be marked before it starts, so the execution cannot
+// complete while it is scheduled; a node reached by several branches is counted
+// once per run.
 func (e *Engine) markNodeActive(executionID, nodeID string) {
 	e.activeMu.Lock()
 	defer e.activeMu.Unlock()
 	if e.activeNodes[executionID] == nil {
-		e.activeNodes[executionID] = make(map[string]bool)
+		e.activeNodes[executionID] = make(map[string]int)
 	}
-	e.activeNodes[executionID][nodeID] = true
+	e.activeNodes[executionID][nodeID]++
 }
 
-// markNodeInactive marks a node as inactive and checks completion.
+// markNodeInactive records that a run of a node finished, persists progress and
+// checks completion.
 func (e *Engine) markNodeInactive(executionID, nodeID string) {
 	e.activeMu.Lock()
+	finished := true
 	if nodes, ok := e.activeNodes[executionID]; ok {
-		delete(nodes, nodeID)
+		if nodes[nodeID] > 1 {
+			nodes[nodeID]--
+			finished = false
+		} else {
+			delete(nodes, nodeID)
+		}
 	}
 	e.activeMu.Unlock()
 
+	// Node output and newly dispatched next nodes are recorded by now; a
+	// node held by a pause stays pending
+	if finished {
+		e.mu.Lock()
+		if state, ok := e.executions[executionID]; ok {
+			if _, held := state.held[nodeID]; !held {
+				delete(state.PendingNodes, nodeID)
+			}
+		}
+		e.mu.Unlock()
+	}
+	e.persistExecution(executionID)
+
 	// Check completion after marking inactive
 	e.checkExecutionComplete(executionID)
 }
 
+// execContextEntry is the context every node of an execution runs on.
+type execContextEntry struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{} // closed when the execution terminates (AwaitExecution)
+}
+
+// isRunning reports whether the execution exists and is still running.
+func (e *Engine) isRunning(executionID string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	state, ok := e.executions[executionID]
+	return ok && state.Status == ExecutionStatusRunning
+}
+
+// unfinished reports whether the execution exists and has not reached a
+// terminal status: it is pending (queued for a slot), running or paused.
+func (e *Engine) unfinished(executionID string) (ExecutionStatus, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	state, ok := e.executions[executionID]
+	if !ok || !(inProgress(state.Status) || state.Status == ExecutionStatusPending) {
+		return "", false
+	}
+	return state.Status, true
+}
+
+// inProgress reports whether the execution exists and is running or paused.
+func (e *Engine) inProgress(executionID string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	state, ok := e.executions[executionID]
+	return ok && inProgress(state.Status)
+}
+
+// inProgress reports whether an execution with status has started and not
+// finished: nodes still running record their output.
+func inProgress(status ExecutionStatus) bool {
+	return status == ExecutionStatusRunning || status == ExecutionStatusPaused
+}
+
+// holdNode records a node dispatched while the execution is paused, for
+// ResumeExecution to run. Returns false, leaving the node to run, otherwise.
+func (e *Engine) holdNode(executionID, nodeID string, input interface{}) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	state, ok := e.executions[executionID]
+	if !ok || state.Status != ExecutionStatusPaused {
+		return false
+	}
+	state.held[nodeID] = input
+	if state.PendingNodes == nil {
+		state.PendingNodes = make(map[string]interface{})
+	}
+	state.PendingNodes[nodeID] = input
+	return true
+}
+
+// dispatchNode records a node as pending/active and executes it
+// asynchronously, or holds it while the execution is paused.
+func (e *Engine) dispatchNode(ctx context.Context, def *WorkflowDefinition, node *NodeDefinition, execCtx *ExecutionContext, input interface{}) {
+	if e.holdNode(execCtx.ExecutionID, node.ID, input) {
+		return
+	}
+	e.trackPendingNode(execCtx.ExecutionID, node.ID, input)
+	e.markNodeActive(execCtx.ExecutionID, node.ID)
+	go e.executeNode(ctx, def, node, execCtx, input)
+}
+
+// trackPendingNode records the input a node was dispatched with.
+func (e *Engine) trackPendingNode(executionID, nodeID string, input interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	state, ok := e.executions[executionID]
+	if !ok {
+		return
+	}
+	if state.PendingNodes == nil {
+		state.PendingNodes = make(map[string]interface{})
+	}
+	state.PendingNodes[nodeID] = input
+}
+
+// persistExecution saves a snapshot of the execution to the store (if configured).
+// Failures are logged, not returned: persistence must not break a running workflow.
+func (e *Engine) persistExecution(executionID string) {
+	if e.store == nil {
+		return
+	}
+
+	// Serialize snapshot+save so an older snapshot never overwrites a newer one
+	e.persistMu.Lock()
+	defer e.persistMu.Unlock()
+
+	e.mu.RLock()
+	state, ok := e.executions[executionID]
+	if !ok {
+		e.mu.RUnlock()
+		return
+	}
+	snapshot, err := cloneExecutionState(state)
+	e.mu.RUnlock()
+
+	if err == nil {
+		err = e.store.SaveState(snapshot)
+	}
+	if err != nil {
+		e.logger.Error(fmt.Sprintf("failed to persist execution %s: %v", executionID, err))
+	}
+}
+
+// ResumeExecutions reloads running executions from the configured store and
+// re-dispatches their pending nodes. Call it on startup after the workflows
+// have been registered; executions of unknown workflows are skipped.
+//
+// Pending nodes run again from the start (at-least-once). In-flight merge
+// buffers are not persisted, so a merge waiting on inputs will not resume.
+// Returns the number of executions resumed.
+func (e *Engine) ResumeExecutions(ctx context.Context) (int, error) {
+	if e.store == nil {
+		return 0, fmt.Errorf("no execution store configured")
+	}
+
+	states, err := e.store.ListRunning()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list running executions: %w", err)
+	}
+
+	resumed := 0
+	for _, state := range states {
+		err := e.restoreExecution(ctx, state)
+		if err == nil {
+			resumed++
+		} else if !errors.Is(err, errExecutionLoaded) {
+			e.logger.Error(fmt.Sprintf("cannot resume execution %s: %v", state.ExecutionID, err))
+		}
+	}
+
+	return resumed, nil
+}
+
+// errExecutionLoaded is returned by restoreExecution for an execution the
+// engine already has.
+var errExecutionLoaded = errors.New("execution already loaded")
+
+// restoreExecution loads a stored execution into the engine and re-dispatches
+// its pending nodes on ctx.
+func (e *Engine) restoreExecution(ctx context.Context, state *ExecutionState) error {
+	e.mu.Lock()
+	def, ok := e.workflows[state.WorkflowID]
+	if !ok {
+		e.mu.Unlock()
+		return fmt.Errorf("workflow not found: %s", state.WorkflowID)
+	}
+	if _, exists := e.executions[state.ExecutionID]; exists {
+		e.mu.Unlock()
+		return errExecutionLoaded
+	}
+
+	if state.Context == nil {
+		state.Context = &ExecutionContext{WorkflowID: state.WorkflowID, ExecutionID: state.ExecutionID, StartTime: state.StartTime}
+	}
+	if state.Context.Data == nil {
+		state.Context.Data = make(map[string]interface{})
+	}
+	if state.Context.NodeOutputs == nil {
+		state.Context.NodeOutputs = make(map[string]interface{})
+	}
+	if state.Context.Variables == nil {
+		state.Context.Variables = make(map[string]interface{})
+	}
+	pending := state.PendingNodes
+	state.PendingNodes = make(map[string]interface{})
+	e.executions[state.ExecutionID] = state
+	e.mu.Unlock()
+
+	execCtx, cancel := context.WithCancel(ctx)
+	e.execCtxMu.Lock()
+	e.execContexts[state.ExecutionID] = execContextEntry{ctx: execCtx, cancel: cancel, done: make(chan struct{})}
+	e.execCtxMu.Unlock()
+	e.limiter.acquire()
+
+	e.activeMu.Lock()
+	e.activeNodes[state.ExecutionID] = make(map[string]int)
+	e.activeMu.Unlock()
+
+	e.runPendingNodes(execCtx, def, state, pending)
+	return nil
+}
+
+// runPendingNodes dispatches nodes (nodeID -> input) of a resumed execution.
+// Every node is marked before any runs (same as startExecution).
+func (e *Engine) runPendingNodes(ctx context.Context, def *WorkflowDefinition, state *ExecutionState, pending map[string]interface{}) {
+	type resumeNode struct {
+		node  *NodeDefinition
+		input interface{}
+	}
+	var toRun []resumeNode
+	for nodeID, input := range pending {
+		node := e.findNode(def, nodeID)
+		if node == nil {
+			e.recordError(state.Context, nodeID, fmt.Sprintf("node not found on resume: %s", nodeID))
+			continue
+		}
+		e.trackPendingNode(state.ExecutionID, nodeID, input)
+		e.markNodeActive(state.ExecutionID, nodeID)
+		toRun = append(toRun, resumeNode{node: node, input: input})
+	}
+
+	for _, r := range toRun {
+		go e.executeNode(ctx, def, r.node, state.Context, r.input)
+	}
+
+	// Nothing left to run (e.g. stopped right before completion was saved, or
+	// paused after the last node)
+	if len(toRun) == 0 {
+		e.checkExecutionComplete(state.ExecutionID)
+	}
+}
+
 // GetExecution returns execution status.
 func (e *Engine) GetExecution(executionID string) (*ExecutionContext, error) {
 	e.mu.RLock()
@@ -594,7 +1290,41 @@ func (e *Engine) GetExecution(executionID string) (*ExecutionContext, error) {
 	return state.Context, nil
 }
 
-// CancelExecution cancels a running execution.
+// AwaitExecution blocks until the execution completes, fails or is cancelled,
+// or ctx is done. It returns the execution context with a nil error on
+// completion, ErrExecutionCancelled if it was cancelled, and an error carrying
+// the failure message if it failed. Finished executions return immediately.
+func (e *Engine) AwaitExecution(ctx context.Context, executionID string) (*ExecutionContext, error) {
+	e.execCtxMu.Lock()
+	ec, running := e.execContexts[executionID]
+	e.execCtxMu.Unlock()
+	if running {
+		select {
+		case <-ec.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	state, ok := e.executions[executionID]
+	if !ok {
+		return nil, fmt.Errorf("execution not found: %s", executionID)
+	}
+	switch state.Status {
+	case ExecutionStatusCompleted:
+		return state.Context, nil
+	case ExecutionStatusCancelled:
+		return state.Context, ErrExecutionCancelled
+	case ExecutionStatusFailed:
+		return state.Context, fmt.Errorf("execution %s failed: %s", executionID, state.Error)
+	default:
+		return nil, fmt.Errorf("execution %s is %s and not running in this engine", executionID, state.Status)
+	}
+}
+
+// CancelExecution cancels a running, paused or pending execution.
 func (e *Engine) CancelExecution(executionID string) error {
 	e.mu.Lock()
 	state, ok := e.executions[executionID]
@@ -603,7 +1333,9 @@ func (e *Engine) CancelExecution(executionID string) error {
 		return fmt.Errorf("execution not found: %s", executionID)
 	}
 
-	if state.Status != ExecutionStatusRunning {
+	wasRunning := inProgress(state.Status)
+	holdsSlot := state.Status == ExecutionStatusRunning
+	if !wasRunning && state.Status != ExecutionStatusPending {
 		e.mu.Unlock()
 		return fmt.Errorf("execution is not running")
 	}
@@ -611,34 +1343,113 @@ func (e *Engine) CancelExecution(executionID string) error {
 	now := time.Now()
 	state.EndTime = &now
 	state.Status = ExecutionStatusCancelled
+	state.PendingNodes = nil
+	state.held = nil
+	started := state.StartTime
 	e.mu.Unlock()
 
-	// Cancel the execution context to stop all running nodes
-	e.execCtxMu.Lock()
-	cancel, ok := e.execContexts[executionID]
-	if ok {
-		cancel()
-		delete(e.execContexts, executionID)
+	if !wasRunning {
+		// Never started: drop it from the queue, nothing ran or was persisted
+		e.limiter.remove(executionID)
+		e.releaseExecution(executionID)
+		return nil
 	}
-	e.execCtxMu.Unlock()
 
-	// Clean up active nodes tracking
-	e.activeMu.Lock()
-	delete(e.activeNodes, executionID)
-	e.activeMu.Unlock()
+	e.metrics.finished(state.WorkflowID, ExecutionStatusCancelled, started, now)
+	e.events.executionFinished(state.WorkflowID, executionID, ExecutionStatusCancelled, "", started, now)
 
-	// Clean up merge states
-	e.mergeMu.Lock()
-	for key := range e.mergeStates {
-		if strings.HasPrefix(key, executionID+":") {
-			delete(e.mergeStates, key)
-		}
+	e.persistExecution(executionID)
+
+	// Cancel the execution context to stop all running nodes, then clean up
+	// active nodes tracking and merge states
+	if holdsSlot {
+		e.limiter.release()
+	}
+	e.releaseExecution(executionID)
+
+	if e.retention.MaxCount > 0 {
+		e.evictExecutions(-1, e.retention.MaxCount)
 	}
-	e.mergeMu.Unlock()
 
 	return nil
 }
 
+// PauseExecution pauses a running execution, e.g. for a manual approval.
+// Nodes already running finish and record their output, but the nodes they
+// lead to are held, and persisted as pending, until ResumeExecution. A paused
+// execution does not count against EngineOptions.Concurrency.
+func (e *Engine) PauseExecution(executionID string) error {
+	e.mu.Lock()
+	state, ok := e.executions[executionID]
+	if !ok {
+		e.mu.Unlock()
+		return fmt.Errorf("execution not found: %s", executionID)
+	}
+	if state.Status != ExecutionStatusRunning {
+		e.mu.Unlock()
+		return fmt.Errorf("execution is %s, not running: %s", state.Status, executionID)
+	}
+	state.Status = ExecutionStatusPaused
+	state.held = make(map[string]interface{})
+	e.mu.Unlock()
+
+	e.persistExecution(executionID)
+	e.limiter.release()
+	return nil
+}
+
+// ResumeExecution continues a paused execution from the nodes held while it
+// was paused. An execution paused before a restart is loaded from the
+// configured store, and its pending nodes run again from the start (as with
+// ResumeExecutions).
+func (e *Engine) ResumeExecution(executionID string) error {
+	e.mu.Lock()
+	state, ok := e.executions[executionID]
+	if !ok {
+		e.mu.Unlock()
+		return e.resumeStoredExecution(executionID)
+	}
+	if state.Status != ExecutionStatusPaused {
+		e.mu.Unlock()
+		return fmt.Errorf("execution is %s, not paused: %s", state.Status, executionID)
+	}
+	def := e.workflows[state.WorkflowID]
+	state.Status = ExecutionStatusRunning
+	held := state.held
+	state.held = nil
+	e.mu.Unlock()
+
+	e.limiter.acquire()
+	e.execCtxMu.Lock()
+	ec := e.execContexts[executionID]
+	e.execCtxMu.Unlock()
+
+	e.runPendingNodes(ec.ctx, def, state, held)
+	e.persistExecution(executionID)
+	return nil
+}
+
+// resumeStoredExecution resumes an execution paused in an earlier run of the
+// engine.
+func (e *Engine) resumeStoredExecution(executionID string) error {
+	if e.store == nil {
+		return fmt.Errorf("execution not found: %s", executionID)
+	}
+	state, err := e.store.LoadState(executionID)
+	if err != nil {
+		return fmt.Errorf("execution not found: %s", executionID)
+	}
+	if state.Status != ExecutionStatusPaused {
+		return fmt.Errorf("execution is %s, not paused: %s", state.Status, executionID)
+	}
+	state.Status = ExecutionStatusRunning
+	if err := e.restoreExecution(context.Background(), state); err != nil {
+		return fmt.Errorf("cannot resume execution %s: %w", executionID, err)
+	}
+	e.persistExecution(executionID)
+	return nil
+}
+
 // ListWorkflows returns all registered workflows.
 func (e *Engine) ListWorkflows() []*WorkflowDefinition {
 	e.mu.RLock()
@@ -667,39 +1478,117 @@ func (e *Engine) GetExecutionState(executionID string) (*ExecutionState, error)
 // CleanupOldExecutions removes executions older than the specified duration.
 // This helps prevent memory leaks from long-running workflows.
 func (e *Engine) CleanupOldExecutions(maxAge time.Duration) int {
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	return e.evictExecutions(maxAge, 0)
+}
+
+// PurgeExecution removes a finished execution (and its stored state) immediately.
+// Running executions must be cancelled first.
+func (e *Engine) PurgeExecution(executionID string) error {
 	e.mu.Lock()
-	defer e.mu.Unlock()
+	state, ok := e.executions[executionID]
+	if !ok {
+		e.mu.Unlock()
+		return fmt.Errorf("execution not found: %s", executionID)
+	}
+	if inProgress(state.Status) || state.Status == ExecutionStatusPending {
+		e.mu.Unlock()
+		return fmt.Errorf("execution is %s: %s", state.Status, executionID)
+	}
+	delete(e.executions, executionID)
+	e.mu.Unlock()
+
+	e.forgetExecution(executionID)
+	return nil
+}
 
+// evictExecutions removes finished executions whose EndTime is older than
+// maxAge (maxAge < 0 disables the age check) and then, if more than maxCount
+// (> 0) finished executions remain, the oldest of them.
+// Returns the number of executions removed.
+func (e *Engine) evictExecutions(maxAge time.Duration, maxCount int) int {
 	now := time.Now()
-	cleaned := 0
+	var evicted []string
 
+	e.mu.Lock()
+	finished := make([]*ExecutionState, 0)
 	for execID, state := range e.executions {
 		// Only clean up completed/failed/cancelled executions
-		if state.Status != ExecutionStatusRunning && state.Status != ExecutionStatusPending {
-			if state.EndTime != nil && now.Sub(*state.EndTime) > maxAge {
-				delete(e.executions, execID)
-				cleaned++
-
-				// Clean up related resources
-				e.execCtxMu.Lock()
-				delete(e.execContexts, execID)
-				e.execCtxMu.Unlock()
-
-				e.activeMu.Lock()
-				delete(e.activeNodes, execID)
-				e.activeMu.Unlock()
-
-				// Clean up merge states
-				e.mergeMu.Lock()
-				for key := range e.mergeStates {
-					if strings.HasPrefix(key, execID+":") {
-						delete(e.mergeStates, key)
-					}
-				}
-				e.mergeMu.Unlock()
+		if inProgress(state.Status) || state.Status == ExecutionStatusPending {
+			continue
+		}
+		if maxAge >= 0 && state.EndTime != nil && now.Sub(*state.EndTime) > maxAge {
+			delete(e.executions, execID)
+			evicted = append(evicted, execID)
+			continue
+		}
+		finished = append(finished, state)
+	}
+
+	if maxCount > 0 && len(finished) > maxCount {
+		sort.Slice(finished, func(i, j int) bool {
+			return executionEndTime(finished[i]).Before(executionEndTime(finished[j]))
+		})
+		for _, state := range finished[:len(finished)-maxCount] {
+			delete(e.executions, state.ExecutionID)
+			evicted = append(evicted, state.ExecutionID)
+		}
+	}
+	e.mu.Unlock()
+
+	for _, execID := range evicted {
+		e.forgetExecution(execID)
+	}
+	return len(evicted)
+}
+
+// executionEndTime orders finished executions; StartTime stands in for a missing EndTime
+func executionEndTime(state *ExecutionState) time.Time {
+	if state.EndTime != nil {
+		return *state.EndTime
+	}
+	return state.StartTime
+}
+
+// releaseExecution cancels the execution context and drops active node and
+// merge tracking. Called when an execution terminates.
+func (e *Engine) releaseExecution(executionID string) {
+	e.execCtxMu.Lock()
+	if ec, ok := e.execContexts[executionID]; ok {
+		ec.cancel()
+		close(ec.done)
+		delete(e.execContexts, executionID)
+	}
+	e.execCtxMu.Unlock()
+
+	e.activeMu.Lock()
+	delete(e.activeNodes, executionID)
+	e.activeMu.Unlock()
+
+	// Merge states are keyed executionID:nodeID
+	e.mergeMu.Lock()
+	for key, state := range e.mergeStates {
+		if strings.HasPrefix(key, executionID+":") {
+			if state.timer != nil {
+				state.timer.Stop()
 			}
+			delete(e.mergeStates, key)
 		}
 	}
+	e.mergeMu.Unlock()
 
-	return cleaned
+	e.httpSessions.release(executionID)
+}
+
+// forgetExecution releases everything still held for a removed execution,
+// including its persisted state.
+func (e *Engine) forgetExecution(executionID string) {
+	e.releaseExecution(executionID)
+	if e.store != nil {
+		if err := e.store.Delete(executionID); err != nil {
+			e.logger.Error(fmt.Sprintf("failed to delete stored execution %s: %v", executionID, err))
+		}
+	}
 }
@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func newAwaitEngine(t *testing.T) *Engine {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	engine := NewEngine(gocmd.EventBus())
+	for _, def := range []*WorkflowDefinition{
+		{ID: "quick", Nodes: []NodeDefinition{
+			{ID: "wait", Type: string(NodeTypeWait), Config: map[string]interface{}{"duration": "30ms"}, Next: []string{"tag"}},
+			{ID: "tag", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"done": true}}},
+		}},
+		{ID: "broken", Nodes: []NodeDefinition{
+			{ID: "fail", Type: string(NodeTypeError), Config: map[string]interface{}{"message": "boom"}},
+		}},
+		{ID: "slow", Nodes: []NodeDefinition{
+			{ID: "wait", Type: string(NodeTypeWait), Config: map[string]interface{}{"duration": "5s"}},
+		}},
+	} {
+		if err := engine.RegisterWorkflow(def); err != nil {
+			t.Fatalf("RegisterWorkflow() error = %v", err)
+		}
+	}
+	return engine
+}
+
+func awaitWithin(t *testing.T, engine *Engine, ctx context.Context, execID string) (*ExecutionContext, error) {
+	t.Helper()
+	start := time.Now()
+	execCtx, err := engine.AwaitExecution(ctx, execID)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("AwaitExecution() took %v", elapsed)
+	}
+	return execCtx, err
+}
+
+func TestEngine_AwaitExecution_Completed(t *testing.T) {
+	engine := newAwaitEngine(t)
+	execID, err := engine.ExecuteWorkflow(context.Background(), "quick", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+
+	execCtx, err := awaitWithin(t, engine, context.Background(), execID)
+	if err != nil {
+		t.Fatalf("AwaitExecution() error = %v", err)
+	}
+	tag, _ := execCtx.NodeOutputs["tag"].(map[string]interface{})
+	if tag["done"] != true {
+		t.Errorf("tag output = %v, want done", tag)
+	}
+
+	// Finished executions return straight away
+	if _, err := engine.AwaitExecution(context.Background(), execID); err != nil {
+		t.Errorf("AwaitExecution() on a finished execution error = %v", err)
+	}
+}
+
+func TestEngine_AwaitExecution_Failed(t *testing.T) {
+	engine := newAwaitEngine(t)
+	execID, _ := engine.ExecuteWorkflow(context.Background(), "broken", nil)
+
+	execCtx, err := awaitWithin(t, engine, context.Background(), execID)
+	if err == nil || errors.Is(err, ErrExecutionCancelled) {
+		t.Fatalf("AwaitExecution() error = %v, want a failure", err)
+	}
+	if execCtx == nil || len(execCtx.Errors) == 0 || !strings.Contains(execCtx.Errors[0].Message, "boom") {
+		t.Errorf("execution context = %+v, want the node error", execCtx)
+	}
+}
+
+func TestEngine_AwaitExecution_Cancelled(t *testing.T) {
+	engine := newAwaitEngine(t)
+	execID, _ := engine.ExecuteWorkflow(context.Background(), "slow", nil)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		_ = engine.CancelExecution(execID)
+	}()
+	if _, err := awaitWithin(t, engine, context.Background(), execID); !errors.Is(err, ErrExecutionCancelled) {
+		t.Errorf("AwaitExecution() error = %v, want ErrExecutionCancelled", err)
+	}
+}
+
+func TestEngine_AwaitExecution_ContextDone(t *testing.T) {
+	engine := newAwaitEngine(t)
+	execID, _ := engine.ExecuteWorkflow(context.Background(), "slow", nil)
+	defer engine.CancelExecution(execID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := awaitWithin(t, engine, ctx, execID); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("AwaitExecution() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	if _, err := engine.AwaitExecution(context.Background(), "missing"); err == nil {
+		t.Error("AwaitExecution() of an unknown execution should fail")
+	}
+}
@@ -0,0 +1,120 @@
+package workflow
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// startCancellable starts workflow def and cancels it once started reports true.
+func startCancellable(t *testing.T, engine *Engine, def *WorkflowDefinition, started func() bool) string {
+	t.Helper()
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	execID, err := engine.ExecuteWorkflow(context.Background(), def.ID, map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !started() {
+		if time.Now().After(deadline) {
+			t.Fatal("workflow did not reach the node to cancel")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := engine.CancelExecution(execID); err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+	return execID
+}
+
+func nodeOutputs(t *testing.T, engine *Engine, execID string) map[string]interface{} {
+	t.Helper()
+	state, err := engine.GetExecutionState(execID)
+	if err != nil {
+		t.Fatalf("GetExecutionState() error = %v", err)
+	}
+	engine.mu.RLock()
+	defer engine.mu.RUnlock()
+	outputs := make(map[string]interface{}, len(state.Context.NodeOutputs))
+	for k, v := range state.Context.NodeOutputs {
+		outputs[k] = v
+	}
+	return outputs
+}
+
+func TestEngine_CancelExecution_StopsWaitNode(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	engine := NewEngine(gocmd.EventBus())
+
+	var waiting atomic.Bool
+	engine.RegisterNodeHandler("observed-wait", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		waiting.Store(true)
+		return waitHandler(ctx, input)
+	})
+
+	def := &WorkflowDefinition{
+		ID: "cancel-wait",
+		Nodes: []NodeDefinition{
+			{ID: "wait", Type: "observed-wait", Config: map[string]interface{}{"duration": "5s"}, Next: []string{"after"}},
+			{ID: "after", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"done": true}}},
+		},
+	}
+	execID := startCancellable(t, engine, def, waiting.Load)
+
+	time.Sleep(100 * time.Millisecond)
+	outputs := nodeOutputs(t, engine, execID)
+	if _, ok := outputs["after"]; ok {
+		t.Error("downstream node ran after CancelExecution")
+	}
+	if _, ok := outputs["wait"]; ok {
+		t.Error("cancelled wait node recorded an output")
+	}
+}
+
+func TestEngine_CancelExecution_DropsLateOutput(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	engine := NewEngine(gocmd.EventBus())
+
+	// A handler that ignores its context and finishes after the cancel
+	var running, finished atomic.Bool
+	engine.RegisterNodeHandler("stubborn", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		running.Store(true)
+		time.Sleep(100 * time.Millisecond)
+		finished.Store(true)
+		return &NodeOutput{Data: map[string]interface{}{"late": true}}, nil
+	})
+
+	def := &WorkflowDefinition{
+		ID: "cancel-stubborn",
+		Nodes: []NodeDefinition{
+			{ID: "slow", Type: "stubborn", Next: []string{"after"}},
+			{ID: "after", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"done": true}}},
+		},
+	}
+	execID := startCancellable(t, engine, def, running.Load)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !finished.Load() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	outputs := nodeOutputs(t, engine, execID)
+	if len(outputs) != 0 {
+		t.Errorf("node outputs after cancel = %v, want none", outputs)
+	}
+	state, _ := engine.GetExecutionState(execID)
+	engine.mu.RLock()
+	status := state.Status
+	engine.mu.RUnlock()
+	if status != ExecutionStatusCancelled {
+		t.Errorf("status = %s, want cancelled", status)
+	}
+}
@@ -0,0 +1,171 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// eventRecorder collects lifecycle events published to a set of addresses.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []map[string]interface{}
+}
+
+func (r *eventRecorder) listen(t *testing.T, bus core.EventBus, addresses ...string) {
+	t.Helper()
+	for _, addr := range addresses {
+		consumer := bus.Consumer(addr).Handler(func(ctx core.FluxorContext, msg core.Message) error {
+			var body map[string]interface{}
+			if err := msg.DecodeBody(&body); err != nil {
+				return err
+			}
+			r.mu.Lock()
+			r.events = append(r.events, body)
+			r.mu.Unlock()
+			return nil
+		})
+		t.Cleanup(func() { _ = consumer.Unregister() })
+	}
+}
+
+// waitFor returns the recorded events ordered by seq once n have arrived.
+func (r *eventRecorder) waitFor(t *testing.T, n int) []map[string]interface{} {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		r.mu.Lock()
+		got := append([]map[string]interface{}(nil), r.events...)
+		r.mu.Unlock()
+		if len(got) >= n {
+			sort.Slice(got, func(i, j int) bool { return seqOf(got[i]) < seqOf(got[j]) })
+			return got
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("received %d events, want %d: %v", len(got), n, got)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func seqOf(ev map[string]interface{}) float64 {
+	switch v := ev["seq"].(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+func newEventsEngine(t *testing.T, enabled bool) (*Engine, core.EventBus) {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	engine := NewEngineWithOptions(gocmd.EventBus(), EngineOptions{LifecycleEvents: enabled})
+	engine.RegisterNodeHandler("broken", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		return nil, errors.New("boom")
+	})
+
+	def := &WorkflowDefinition{
+		ID: "events",
+		Nodes: []NodeDefinition{
+			{ID: "a", Type: string(NodeTypeNoOp), Next: []string{"b"}},
+			{ID: "b", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"x": 1}}, Next: []string{"c"}},
+			{ID: "c", Type: string(NodeTypeNoOp)},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return engine, gocmd.EventBus()
+}
+
+func TestEngine_LifecycleEvents_Sequence(t *testing.T) {
+	engine, bus := newEventsEngine(t, true)
+
+	// The in-memory EventBus runs at most 10 consumers at once and the engine
+	// holds 4, so node.started is covered by the failure test
+	rec := &eventRecorder{}
+	rec.listen(t, bus,
+		ExecutionStartedAddress("events"),
+		NodeCompletedAddress("events", "a"),
+		NodeCompletedAddress("events", "b"),
+		NodeCompletedAddress("events", "c"),
+		ExecutionFinishedAddress("events"),
+	)
+
+	state := runGuarded(t, engine, "events", map[string]interface{}{})
+	events := rec.waitFor(t, 5)
+
+	want := []string{
+		"execution.started",
+		"node.completed a",
+		"node.completed b",
+		"node.completed c",
+		"execution.finished",
+	}
+	for i, ev := range events {
+		got, _ := ev["event"].(string)
+		if node, ok := ev["nodeId"].(string); ok {
+			got += " " + node
+		}
+		if i >= len(want) || got != want[i] {
+			t.Fatalf("event %d = %q, want sequence %v (got %v)", i, got, want, events)
+		}
+		if ev["executionId"] != state.ExecutionID || ev["workflowId"] != "events" {
+			t.Errorf("event %d = %v, want execution %s", i, ev, state.ExecutionID)
+		}
+	}
+	if final := events[len(events)-1]; final["status"] != string(ExecutionStatusCompleted) {
+		t.Errorf("finished status = %v, want completed", final["status"])
+	}
+	if b := events[2]; b["nodeType"] != string(NodeTypeSet) || b["durationMs"] == nil {
+		t.Errorf("node.completed b = %v, want nodeType and durationMs", b)
+	}
+}
+
+func TestEngine_LifecycleEvents_NodeFailure(t *testing.T) {
+	engine, bus := newEventsEngine(t, true)
+	def := &WorkflowDefinition{ID: "failing", Nodes: []NodeDefinition{
+		{ID: "fail", Type: "broken"},
+	}}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	rec := &eventRecorder{}
+	rec.listen(t, bus, NodeStartedAddress("failing", "fail"), NodeFailedAddress("failing", "fail"), ExecutionFinishedAddress("failing"))
+	runGuarded(t, engine, "failing", map[string]interface{}{})
+
+	events := rec.waitFor(t, 3)
+	if events[0]["event"] != "node.started" || events[0]["status"] != string(ExecutionStatusRunning) {
+		t.Errorf("first event = %v, want node.started", events[0])
+	}
+	if events[1]["event"] != "node.failed" || events[1]["error"] != "boom" {
+		t.Errorf("second event = %v, want node.failed with the error", events[1])
+	}
+	if events[2]["event"] != "execution.finished" || events[2]["status"] != string(ExecutionStatusFailed) {
+		t.Errorf("third event = %v, want a failed execution.finished", events[2])
+	}
+}
+
+func TestEngine_LifecycleEvents_DisabledByDefault(t *testing.T) {
+	engine, bus := newEventsEngine(t, false)
+
+	rec := &eventRecorder{}
+	rec.listen(t, bus, ExecutionStartedAddress("events"), NodeStartedAddress("events", "a"), ExecutionFinishedAddress("events"))
+	runGuarded(t, engine, "events", map[string]interface{}{})
+
+	time.Sleep(50 * time.Millisecond)
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.events) != 0 {
+		t.Errorf("received %d events with LifecycleEvents unset", len(rec.events))
+	}
+}
@@ -0,0 +1,225 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// sleepFunctions registers function nodes that sleep for the given time and
+// return their name.
+func sleepFunctions(engine *Engine, delays map[string]time.Duration) {
+	functions := NewFunctionRegistry()
+	for name, delay := range delays {
+		name, delay := name, delay
+		functions.Register(name, func(ctx context.Context, data interface{}) (interface{}, error) {
+			time.Sleep(delay)
+			return map[string]interface{}{"from": name}, nil
+		})
+	}
+	engine.RegisterNodeHandler(NodeTypeFunction, CreateFunctionHandler(functions))
+}
+
+func TestEngine_SplitMerge_RunsNodesAfterMerge(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	engine := NewEngine(gocmd.EventBus())
+	sleepFunctions(engine, map[string]time.Duration{"fast": 5 * time.Millisecond, "slow": 40 * time.Millisecond})
+
+	def := &WorkflowDefinition{
+		ID: "split-merge",
+		Nodes: []NodeDefinition{
+			{ID: "split", Type: string(NodeTypeSplit), Next: []string{"fast", "slow"}},
+			{ID: "fast", Type: string(NodeTypeFunction), Config: map[string]interface{}{"function": "fast"}, Next: []string{"merge"}},
+			{ID: "slow", Type: string(NodeTypeFunction), Config: map[string]interface{}{"function": "slow"}, Next: []string{"merge"}},
+			{ID: "merge", Type: string(NodeTypeMerge), Next: []string{"format"}},
+			{ID: "format", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"formatted": true}}},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	// Repeat: the premature completion depended on goroutine scheduling
+	for i := 0; i < 5; i++ {
+		state := runGuarded(t, engine, "split-merge", map[string]interface{}{})
+		if state.Status != ExecutionStatusCompleted {
+			t.Fatalf("status = %s, errors = %v", state.Status, state.Context.Errors)
+		}
+		for _, id := range []string{"split", "fast", "slow", "merge", "format"} {
+			if _, ok := state.Context.NodeOutputs[id]; !ok {
+				t.Fatalf("run %d: no output for %s (outputs = %v)", i, id, state.Context.NodeOutputs)
+			}
+		}
+		if merged, _ := state.Context.NodeOutputs["merge"].(map[string]interface{}); len(merged["_originalData"].([]interface{})) != 2 {
+			t.Errorf("merge output = %v, want both branches", merged)
+		}
+	}
+}
+
+func TestEngine_FanIn_CountsEveryRun(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	engine := NewEngine(gocmd.EventBus())
+
+	// "shared" is reached from both branches and both runs overlap; the first
+	// run finishing must not complete the execution while the second is going
+	var runs, finished atomic.Int32
+	engine.RegisterNodeHandler("shared", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		time.Sleep(time.Duration(runs.Add(1)) * 20 * time.Millisecond)
+		finished.Add(1)
+		return &NodeOutput{Data: input.Data}, nil
+	})
+
+	def := &WorkflowDefinition{
+		ID: "fan-in",
+		Nodes: []NodeDefinition{
+			{ID: "split", Type: string(NodeTypeSplit), Next: []string{"a", "b"}},
+			{ID: "a", Type: string(NodeTypeNoOp), Next: []string{"shared"}},
+			{ID: "b", Type: string(NodeTypeNoOp), Next: []string{"shared"}},
+			{ID: "shared", Type: "shared"},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	state := runGuarded(t, engine, "fan-in", map[string]interface{}{})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("status = %s", state.Status)
+	}
+	if n := finished.Load(); n != 2 {
+		t.Errorf("execution completed with %d of 2 runs of shared finished", n)
+	}
+}
+
+// newMergeTimeoutEngine registers split -> ok/bad -> join -> after, where bad
+// fails so join only ever receives one of its two inputs.
+func newMergeTimeoutEngine(t *testing.T, config map[string]interface{}) *Engine {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	engine := NewEngine(gocmd.EventBus())
+	engine.RegisterNodeHandler("broken", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		return nil, errors.New("boom")
+	})
+
+	def := &WorkflowDefinition{
+		ID: "merge-timeout",
+		Nodes: []NodeDefinition{
+			{ID: "split", Type: string(NodeTypeSplit), Next: []string{"ok", "bad"}},
+			{ID: "ok", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"ok": true}}, Next: []string{"join"}},
+			{ID: "bad", Type: "broken", Next: []string{"join"}},
+			{ID: "join", Type: string(NodeTypeMerge), Config: config, Next: []string{"after"}},
+			{ID: "after", Type: string(NodeTypeNoOp)},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return engine
+}
+
+func TestEngine_MergeTimeout_ProceedsWithArrivedInputs(t *testing.T) {
+	engine := newMergeTimeoutEngine(t, map[string]interface{}{"timeout": "100ms"})
+
+	start := time.Now()
+	state := runGuarded(t, engine, "merge-timeout", map[string]interface{}{})
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > time.Second {
+		t.Errorf("execution took %v, want it to wait for the 100ms merge timeout", elapsed)
+	}
+	merged, _ := state.Context.NodeOutputs["join"].(map[string]interface{})
+	if inputs, _ := merged["_originalData"].([]interface{}); len(inputs) != 1 {
+		t.Fatalf("merge output = %v, want the one input that arrived", merged)
+	}
+	if _, ok := state.Context.NodeOutputs["after"]; !ok {
+		t.Error("node after the merge did not run")
+	}
+	// The failed branch still fails the execution
+	if state.Status != ExecutionStatusFailed || len(state.Context.Errors) != 1 {
+		t.Errorf("status = %s, errors = %v; want failed with bad's error", state.Status, state.Context.Errors)
+	}
+	engine.mergeMu.Lock()
+	defer engine.mergeMu.Unlock()
+	if n := len(engine.mergeStates); n != 0 {
+		t.Errorf("%d merge states left after the timeout", n)
+	}
+}
+
+func TestEngine_MergeTimeout_FailPolicy(t *testing.T) {
+	engine := newMergeTimeoutEngine(t, map[string]interface{}{"timeout": "50ms", "onTimeout": "fail"})
+
+	state := runGuarded(t, engine, "merge-timeout", map[string]interface{}{})
+	if state.Status != ExecutionStatusFailed || !strings.Contains(state.Error, "merge received 1 of 2 inputs") {
+		t.Errorf("status = %s, error = %q; want the merge timeout", state.Status, state.Error)
+	}
+	if _, ok := state.Context.NodeOutputs["after"]; ok {
+		t.Error("node after the merge ran despite onTimeout=fail")
+	}
+	var timeouts int
+	for _, e := range state.Context.Errors {
+		if e.NodeID == "join" && e.Type == ErrorTypeTimeout {
+			timeouts++
+		}
+	}
+	if timeouts != 1 {
+		t.Errorf("errors = %v, want one timeout for join", state.Context.Errors)
+	}
+}
+
+func TestEngine_MergeTimeout_AllInputsBeforeTimeout(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	engine := NewEngine(gocmd.EventBus())
+	var merges atomic.Int32
+	engine.UseNodeMiddleware(func(next NodeHandler) NodeHandler {
+		return func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+			if input.NodeType == NodeTypeMerge {
+				merges.Add(1)
+			}
+			return next(ctx, input)
+		}
+	})
+
+	def := &WorkflowDefinition{
+		ID: "merge-in-time",
+		Nodes: []NodeDefinition{
+			{ID: "split", Type: string(NodeTypeSplit), Next: []string{"a", "b"}},
+			{ID: "a", Type: string(NodeTypeNoOp), Next: []string{"join"}},
+			{ID: "b", Type: string(NodeTypeNoOp), Next: []string{"join"}},
+			{ID: "join", Type: string(NodeTypeMerge), Config: map[string]interface{}{"timeout": "50ms"}},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	state := runGuarded(t, engine, "merge-in-time", map[string]interface{}{})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("status = %s, errors = %v", state.Status, state.Context.Errors)
+	}
+	time.Sleep(100 * time.Millisecond) // past the timeout: the stopped timer must not run the merge again
+	if n := merges.Load(); n != 1 {
+		t.Errorf("merge ran %d times, want 1", n)
+	}
+}
+
+func TestEngine_MergeTimeout_InvalidConfig(t *testing.T) {
+	for _, config := range []map[string]interface{}{
+		{"timeout": "soon"},
+		{"timeout": "-1s"},
+		{"timeout": "1s", "onTimeout": "retry"},
+	} {
+		def := &WorkflowDefinition{ID: "bad", Nodes: []NodeDefinition{
+			{ID: "join", Type: string(NodeTypeMerge), Config: config},
+		}}
+		if err := NewEngine(nil).RegisterWorkflow(def); err == nil {
+			t.Errorf("RegisterWorkflow() accepted merge config %v", config)
+		}
+	}
+}
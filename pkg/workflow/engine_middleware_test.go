@@ -0,0 +1,95 @@
+package workflow
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestEngine_NodeMiddleware_ObservesEveryNode(t *testing.T) {
+	engine := newGuardEngine(t, &WorkflowDefinition{ID: "observed", Nodes: []NodeDefinition{
+		{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"tag"}},
+		{ID: "tag", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"tagged": true}}},
+	}})
+
+	type call struct {
+		node   string
+		input  interface{}
+		output interface{}
+	}
+	var mu sync.Mutex
+	var calls []call
+	var order []string
+	engine.UseNodeMiddleware(func(next NodeHandler) NodeHandler {
+		return func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+			mu.Lock()
+			order = append(order, "outer:"+input.NodeID)
+			mu.Unlock()
+			output, err := next(ctx, input)
+			mu.Lock()
+			calls = append(calls, call{input.NodeID, input.Data, output.Data})
+			mu.Unlock()
+			return output, err
+		}
+	})
+	engine.UseNodeMiddleware(func(next NodeHandler) NodeHandler {
+		return func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+			mu.Lock()
+			order = append(order, "inner:"+input.NodeID)
+			mu.Unlock()
+			return next(ctx, input)
+		}
+	})
+
+	state := runGuarded(t, engine, "observed", map[string]interface{}{"id": "a"})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("status = %s", state.Status)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []call{
+		{"start", map[string]interface{}{"id": "a"}, map[string]interface{}{"id": "a"}},
+		{"tag", map[string]interface{}{"id": "a"}, map[string]interface{}{"id": "a", "tagged": true}},
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("observed calls = %+v, want %+v", calls, want)
+	}
+	if wantOrder := []string{"outer:start", "inner:start", "outer:tag", "inner:tag"}; !reflect.DeepEqual(order, wantOrder) {
+		t.Errorf("middleware order = %v, want %v", order, wantOrder)
+	}
+}
+
+func TestEngine_NodeMiddleware_ShortCircuit(t *testing.T) {
+	engine := newGuardEngine(t, &WorkflowDefinition{ID: "blocked", Nodes: []NodeDefinition{
+		{ID: "charge", Type: "charge", Next: []string{"after"}},
+		{ID: "after", Type: string(NodeTypeNoOp)},
+	}})
+
+	var charged atomic.Int32
+	engine.RegisterNodeHandler("charge", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		charged.Add(1)
+		return &NodeOutput{Data: "charged"}, nil
+	})
+	engine.UseNodeMiddleware(func(next NodeHandler) NodeHandler {
+		return func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+			if input.NodeType == "charge" {
+				return &NodeOutput{Data: "dry run"}, nil
+			}
+			return next(ctx, input)
+		}
+	})
+
+	state := runGuarded(t, engine, "blocked", nil)
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("status = %s", state.Status)
+	}
+	if n := charged.Load(); n != 0 {
+		t.Errorf("handler ran %d times, want it short-circuited", n)
+	}
+	if got := state.Context.NodeOutputs["after"]; got != "dry run" {
+		t.Errorf("after output = %v, want the middleware's output", got)
+	}
+}
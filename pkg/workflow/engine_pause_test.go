@@ -0,0 +1,181 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// gateHandler blocks until release is closed, signalling entered first.
+func gateHandler(entered chan<- struct{}, release <-chan struct{}) NodeHandler {
+	return func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		entered <- struct{}{}
+		<-release
+		return &NodeOutput{Data: input.Data}, nil
+	}
+}
+
+// waitForPausedFrontier waits until nothing of the paused execution is
+// running any more and returns its pending nodes.
+func waitForPausedFrontier(t *testing.T, engine *Engine, executionID string) map[string]interface{} {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		engine.activeMu.Lock()
+		active := len(engine.activeNodes[executionID])
+		engine.activeMu.Unlock()
+		if active == 0 {
+			engine.mu.RLock()
+			defer engine.mu.RUnlock()
+			state := engine.executions[executionID]
+			if state.Status != ExecutionStatusPaused {
+				t.Fatalf("status = %s, want %s", state.Status, ExecutionStatusPaused)
+			}
+			pending := make(map[string]interface{}, len(state.PendingNodes))
+			for k, v := range state.PendingNodes {
+				pending[k] = v
+			}
+			return pending
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("paused execution still has running nodes")
+	return nil
+}
+
+func TestEngine_PauseExecution_HoldsDownstreamNodes(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	engine := NewEngine(gocmd.EventBus())
+
+	entered, release := make(chan struct{}, 1), make(chan struct{})
+	engine.RegisterNodeHandler("gate", gateHandler(entered, release))
+	def := &WorkflowDefinition{
+		ID: "approval",
+		Nodes: []NodeDefinition{
+			{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"gate"}},
+			{ID: "gate", Type: "gate", Next: []string{"after"}},
+			{ID: "after", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"approved": true}}, Next: []string{"done"}},
+			{ID: "done", Type: string(NodeTypeNoOp)},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	execID, err := engine.ExecuteWorkflow(context.Background(), "approval", map[string]interface{}{"order": "o-1"})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+
+	<-entered
+	if err := engine.PauseExecution(execID); err != nil {
+		t.Fatalf("PauseExecution() error = %v", err)
+	}
+	close(release)
+
+	// The running gate finishes; the node after it waits for the resume
+	pending := waitForPausedFrontier(t, engine, execID)
+	if _, ok := pending["after"]; !ok || len(pending) != 1 {
+		t.Errorf("PendingNodes = %v, want only after", pending)
+	}
+	time.Sleep(50 * time.Millisecond)
+	outputs := nodeOutputs(t, engine, execID)
+	if _, ok := outputs["gate"]; !ok {
+		t.Error("node running when paused did not record its output")
+	}
+	if _, ok := outputs["after"]; ok {
+		t.Error("downstream node ran while paused")
+	}
+
+	if err := engine.ResumeExecution(execID); err != nil {
+		t.Fatalf("ResumeExecution() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	result, err := engine.AwaitExecution(ctx, execID)
+	if err != nil {
+		t.Fatalf("AwaitExecution() error = %v", err)
+	}
+	done, _ := result.NodeOutputs["done"].(map[string]interface{})
+	if done["approved"] != true || done["order"] != "o-1" {
+		t.Errorf("done output = %v, want approved order o-1", result.NodeOutputs["done"])
+	}
+}
+
+func TestEngine_ResumeExecution_AfterRestart(t *testing.T) {
+	store := NewMemoryExecutionStore()
+	entered, release := make(chan struct{}, 1), make(chan struct{})
+	engine1 := newStoreEngine(t, store, gateHandler(entered, release))
+	execID, err := engine1.ExecuteWorkflow(context.Background(), "resumable", map[string]interface{}{"orderId": "o-1"})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+
+	<-entered
+	if err := engine1.PauseExecution(execID); err != nil {
+		t.Fatalf("PauseExecution() error = %v", err)
+	}
+	close(release)
+	waitForStoredState(t, store, execID, func(s *ExecutionState) bool {
+		_, held := s.PendingNodes["done"]
+		return s.Status == ExecutionStatusPaused && held && len(s.PendingNodes) == 1
+	})
+
+	// A paused execution is not resumed on startup, only on request
+	engine2 := newStoreEngine(t, store, func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		t.Error("checkpoint ran again after resume")
+		return &NodeOutput{Data: input.Data}, nil
+	})
+	if resumed, err := engine2.ResumeExecutions(context.Background()); err != nil || resumed != 0 {
+		t.Fatalf("ResumeExecutions() = %d, %v, want 0", resumed, err)
+	}
+	if err := engine2.ResumeExecution(execID); err != nil {
+		t.Fatalf("ResumeExecution() error = %v", err)
+	}
+
+	state := waitForStoredState(t, store, execID, func(s *ExecutionState) bool {
+		return s.Status != ExecutionStatusRunning
+	})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("status = %s, want %s (errors: %v)", state.Status, ExecutionStatusCompleted, state.Context.Errors)
+	}
+	if _, ok := state.Context.NodeOutputs["done"]; !ok {
+		t.Errorf("NodeOutputs = %v, want output for done", state.Context.NodeOutputs)
+	}
+}
+
+func TestEngine_PauseResume_InvalidStatus(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	engine := NewEngine(gocmd.EventBus())
+
+	entered, release := make(chan struct{}, 1), make(chan struct{})
+	engine.RegisterNodeHandler("gate", gateHandler(entered, release))
+	if err := engine.RegisterWorkflow(&WorkflowDefinition{ID: "gated", Nodes: []NodeDefinition{{ID: "gate", Type: "gate"}}}); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	execID, err := engine.ExecuteWorkflow(context.Background(), "gated", nil)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	<-entered
+
+	if err := engine.ResumeExecution(execID); err == nil || !strings.Contains(err.Error(), "running, not paused") {
+		t.Errorf("ResumeExecution(running) error = %v", err)
+	}
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := engine.AwaitExecution(ctx, execID); err != nil {
+		t.Fatalf("AwaitExecution() error = %v", err)
+	}
+	if err := engine.PauseExecution(execID); err == nil || !strings.Contains(err.Error(), "completed, not running") {
+		t.Errorf("PauseExecution(completed) error = %v", err)
+	}
+	if err := engine.ResumeExecution("missing"); err == nil || !strings.Contains(err.Error(), "execution not found") {
+		t.Errorf("ResumeExecution(missing) error = %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func newRetentionEngine(t *testing.T, retention ExecutionRetention) *Engine {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	engine := NewEngineWithOptions(gocmd.EventBus(), EngineOptions{Retention: retention})
+	t.Cleanup(engine.Close)
+
+	// split -> (a, b) -> merge exercises mergeStates as well
+	def := &WorkflowDefinition{
+		ID: "short",
+		Nodes: []NodeDefinition{
+			{ID: "start", Type: string(NodeTypeSplit), Next: []string{"a", "b"}},
+			{ID: "a", Type: string(NodeTypeNoOp), Next: []string{"join"}},
+			{ID: "b", Type: string(NodeTypeNoOp), Next: []string{"join"}},
+			{ID: "join", Type: string(NodeTypeMerge)},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return engine
+}
+
+// executionCounts returns the number of running and total executions held in memory.
+func executionCounts(e *Engine) (running, total int) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, state := range e.executions {
+		if state.Status == ExecutionStatusRunning {
+			running++
+		}
+	}
+	return running, len(e.executions)
+}
+
+func waitForNoRunning(t *testing.T, e *Engine) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if running, _ := executionCounts(e); running == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	running, _ := executionCounts(e)
+	t.Fatalf("%d executions still running", running)
+}
+
+func TestEngine_Retention_MaxCount(t *testing.T) {
+	const maxCount = 50
+	engine := newRetentionEngine(t, ExecutionRetention{MaxCount: maxCount})
+
+	maxSeen := 0
+	for i := 0; i < 1000; i++ {
+		if _, err := engine.ExecuteWorkflow(context.Background(), "short", map[string]interface{}{"i": i}); err != nil {
+			t.Fatalf("ExecuteWorkflow() error = %v", err)
+		}
+		if i%50 == 0 {
+			waitForNoRunning(t, engine)
+		}
+		if _, total := executionCounts(engine); total > maxSeen {
+			maxSeen = total
+		}
+	}
+	waitForNoRunning(t, engine)
+
+	if _, total := executionCounts(engine); total > maxCount {
+		t.Errorf("executions held = %d, want at most %d", total, maxCount)
+	}
+	// Only in-flight executions (at most one batch) may exceed the cap
+	if maxSeen > maxCount+50 {
+		t.Errorf("executions peaked at %d, want bounded near %d", maxSeen, maxCount)
+	}
+
+	engine.mergeMu.Lock()
+	merges := len(engine.mergeStates)
+	engine.mergeMu.Unlock()
+	if merges != 0 {
+		t.Errorf("mergeStates = %d entries after all executions finished, want 0", merges)
+	}
+}
+
+func TestEngine_Retention_MaxAge(t *testing.T) {
+	engine := newRetentionEngine(t, ExecutionRetention{MaxAge: 20 * time.Millisecond, Interval: 10 * time.Millisecond})
+
+	for i := 0; i < 10; i++ {
+		if _, err := engine.ExecuteWorkflow(context.Background(), "short", nil); err != nil {
+			t.Fatalf("ExecuteWorkflow() error = %v", err)
+		}
+	}
+	waitForNoRunning(t, engine)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, total := executionCounts(engine); total == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_, total := executionCounts(engine)
+	t.Errorf("executions held = %d after MaxAge, want 0", total)
+}
+
+func TestEngine_PurgeExecution(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	store := NewMemoryExecutionStore()
+	engine := NewEngineWithOptions(gocmd.EventBus(), EngineOptions{Store: store})
+	def := &WorkflowDefinition{
+		ID: "slow",
+		Nodes: []NodeDefinition{
+			{ID: "wait", Type: string(NodeTypeWait), Config: map[string]interface{}{"duration": "50ms"}},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	execID, err := engine.ExecuteWorkflow(context.Background(), "slow", nil)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	if err := engine.PurgeExecution(execID); err == nil {
+		t.Error("PurgeExecution() of a running execution should fail")
+	}
+
+	waitForNoRunning(t, engine)
+	if err := engine.PurgeExecution(execID); err != nil {
+		t.Fatalf("PurgeExecution() error = %v", err)
+	}
+	if _, err := engine.GetExecutionState(execID); err == nil {
+		t.Error("purged execution is still held by the engine")
+	}
+	if _, err := store.LoadState(execID); err == nil {
+		t.Error("purged execution is still in the store")
+	}
+	if err := engine.PurgeExecution(execID); err == nil {
+		t.Error("PurgeExecution() of an unknown execution should fail")
+	}
+}
@@ -0,0 +1,120 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// newTimeoutEngine registers start -> slow -> after, with slow blocking until
+// its context ends and a "fallback" node on slow's OnError edge.
+func newTimeoutEngine(t *testing.T, onTimeout string) *Engine {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	engine := NewEngine(gocmd.EventBus())
+	engine.RegisterNodeHandler("slow", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	engine.RegisterNodeHandler("broken", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		return nil, errors.New("boom")
+	})
+
+	def := &WorkflowDefinition{
+		ID: "timeouts",
+		Nodes: []NodeDefinition{
+			{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"slow"}},
+			{ID: "slow", Type: "slow", Timeout: "30ms", OnTimeout: onTimeout, Next: []string{"after"}, OnError: []string{"fallback"}},
+			{ID: "after", Type: string(NodeTypeNoOp)},
+			{ID: "fallback", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"recovered": true}}},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return engine
+}
+
+func TestEngine_NodeTimeout_FollowsOnError(t *testing.T) {
+	engine := newTimeoutEngine(t, "")
+
+	state := runGuarded(t, engine, "timeouts", map[string]interface{}{})
+	errs := state.Context.Errors
+	if len(errs) != 1 || errs[0].NodeID != "slow" || errs[0].Type != ErrorTypeTimeout {
+		t.Fatalf("errors = %+v, want one timeout error for slow", errs)
+	}
+	if !strings.Contains(errs[0].Message, "timed out after 30ms") {
+		t.Errorf("message = %q, want the timeout duration", errs[0].Message)
+	}
+	if _, ok := state.Context.NodeOutputs["fallback"]; !ok {
+		t.Error("onError branch did not run")
+	}
+	if _, ok := state.Context.NodeOutputs["after"]; ok {
+		t.Error("next node ran after a timeout")
+	}
+}
+
+func TestEngine_NodeTimeout_FailPolicy(t *testing.T) {
+	engine := newTimeoutEngine(t, TimeoutPolicyFail)
+
+	start := time.Now()
+	state := runGuarded(t, engine, "timeouts", map[string]interface{}{})
+	if state.Status != ExecutionStatusFailed {
+		t.Errorf("status = %s, want failed", state.Status)
+	}
+	if !strings.Contains(state.Error, "timed out") {
+		t.Errorf("execution error = %q, want the timeout", state.Error)
+	}
+	if errs := state.Context.Errors; len(errs) != 1 || errs[0].Type != ErrorTypeTimeout {
+		t.Errorf("errors = %+v, want one timeout error", errs)
+	}
+	if _, ok := state.Context.NodeOutputs["fallback"]; ok {
+		t.Error("onError branch ran despite onTimeout=fail")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("execution took %v to fail", elapsed)
+	}
+}
+
+func TestEngine_NodeTimeout_OtherErrorsUntyped(t *testing.T) {
+	engine := newTimeoutEngine(t, TimeoutPolicyFail)
+	def := &WorkflowDefinition{ID: "broken", Nodes: []NodeDefinition{
+		{ID: "fail", Type: "broken", Timeout: "1s", OnTimeout: TimeoutPolicyFail, OnError: []string{"fallback"}},
+		{ID: "fallback", Type: string(NodeTypeNoOp)},
+	}}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	state := runGuarded(t, engine, "broken", map[string]interface{}{})
+	if errs := state.Context.Errors; len(errs) != 1 || errs[0].Type != "" {
+		t.Errorf("errors = %+v, want one untyped error", errs)
+	}
+	if _, ok := state.Context.NodeOutputs["fallback"]; !ok {
+		t.Error("a non-timeout failure should still follow onError")
+	}
+}
+
+func TestEngine_NodeTimeout_InvalidPolicy(t *testing.T) {
+	def := &WorkflowDefinition{ID: "bad", Nodes: []NodeDefinition{
+		{ID: "a", Type: string(NodeTypeNoOp), OnTimeout: "retry"},
+	}}
+	if err := NewEngine(nil).RegisterWorkflow(def); err == nil {
+		t.Error("RegisterWorkflow() accepted onTimeout \"retry\"")
+	}
+
+	var node NodeDefinition
+	if err := json.Unmarshal([]byte(`{"id":"a","type":"http","timeout":"5s","onTimeout":"fail"}`), &node); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if node.OnTimeout != TimeoutPolicyFail {
+		t.Errorf("OnTimeout = %q, want fail", node.OnTimeout)
+	}
+}
@@ -0,0 +1,261 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/fluxorio/fluxor/pkg/appendlog"
+)
+
+// ExecutionStore persists workflow execution state so running executions
+// survive an engine restart (see Engine.ResumeExecutions).
+//
+// Implementations must be safe for concurrent use. SaveState is called after
+// every node completes, so it should be cheap.
+type ExecutionStore interface {
+	// SaveState stores (or replaces) the state of an execution.
+	SaveState(state *ExecutionState) error
+
+	// LoadState returns the stored state of an execution.
+	LoadState(executionID string) (*ExecutionState, error)
+
+	// ListRunning returns all stored executions with status running.
+	ListRunning() ([]*ExecutionState, error)
+
+	// Delete removes the stored state of an execution.
+	Delete(executionID string) error
+}
+
+// cloneExecutionState deep-copies a state through JSON so the stored copy
+// never aliases maps the engine keeps mutating.
+// Values round-trip with JSON semantics (e.g. numbers become float64).
+func cloneExecutionState(state *ExecutionState) (*ExecutionState, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode execution state: %w", err)
+	}
+	var clone ExecutionState
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, fmt.Errorf("failed to decode execution state: %w", err)
+	}
+	return &clone, nil
+}
+
+// MemoryExecutionStore keeps execution state in memory.
+// Useful for tests and single-process setups where restarts need not be survived.
+type MemoryExecutionStore struct {
+	mu     sync.RWMutex
+	states map[string]*ExecutionState
+}
+
+// NewMemoryExecutionStore creates an empty in-memory execution store.
+func NewMemoryExecutionStore() *MemoryExecutionStore {
+	return &MemoryExecutionStore{
+		states: make(map[string]*ExecutionState),
+	}
+}
+
+// SaveState implements ExecutionStore.
+func (s *MemoryExecutionStore) SaveState(state *ExecutionState) error {
+	if state == nil || state.ExecutionID == "" {
+		return fmt.Errorf("execution state with ID is required")
+	}
+	clone, err := cloneExecutionState(state)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.states[state.ExecutionID] = clone
+	s.mu.Unlock()
+	return nil
+}
+
+// LoadState implements ExecutionStore.
+func (s *MemoryExecutionStore) LoadState(executionID string) (*ExecutionState, error) {
+	s.mu.RLock()
+	state, ok := s.states[executionID]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("execution not found: %s", executionID)
+	}
+	return cloneExecutionState(state)
+}
+
+// ListRunning implements ExecutionStore.
+func (s *MemoryExecutionStore) ListRunning() ([]*ExecutionState, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*ExecutionState, 0)
+	for _, state := range s.states {
+		if state.Status != ExecutionStatusRunning {
+			continue
+		}
+		clone, err := cloneExecutionState(state)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, clone)
+	}
+	return result, nil
+}
+
+// Delete implements ExecutionStore.
+func (s *MemoryExecutionStore) Delete(executionID string) error {
+	s.mu.Lock()
+	delete(s.states, executionID)
+	s.mu.Unlock()
+	return nil
+}
+
+// appendLogRecord is one entry of the execution log.
+type appendLogRecord struct {
+	Op          string          `json:"op"` // "save" or "delete"
+	ExecutionID string          `json:"executionId"`
+	State       *ExecutionState `json:"state,omitempty"`
+}
+
+const (
+	appendLogOpSave   = "save"
+	appendLogOpDelete = "delete"
+)
+
+// appendLogReadBatch is the number of records read per Read call during replay.
+const appendLogReadBatch = 1024
+
+// AppendLogExecutionStore persists execution state in an appendlog.Store.
+//
+// Every SaveState/Delete appends a JSON record; the latest record per
+// execution wins. On open the log is replayed into an in-memory index,
+// which then serves LoadState/ListRunning.
+//
+// Durability follows the underlying store: use appendlog.DurabilityFsync if a
+// crash must not lose the last saved node. The log is never compacted.
+type AppendLogExecutionStore struct {
+	log    appendlog.Store
+	mu     sync.Mutex
+	states map[string]*ExecutionState
+}
+
+// NewAppendLogExecutionStore replays log and returns a store writing to it.
+// The caller owns log and is responsible for closing it.
+func NewAppendLogExecutionStore(log appendlog.Store) (*AppendLogExecutionStore, error) {
+	if log == nil {
+		return nil, fmt.Errorf("append log cannot be nil")
+	}
+
+	s := &AppendLogExecutionStore{
+		log:    log,
+		states: make(map[string]*ExecutionState),
+	}
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// replay rebuilds the in-memory index from the log (offsets start at 1).
+func (s *AppendLogExecutionStore) replay() error {
+	from := appendlog.Offset(1)
+	for {
+		records, err := s.log.Read(from, appendLogReadBatch)
+		if err != nil {
+			return fmt.Errorf("failed to read execution log: %w", err)
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		for _, rec := range records {
+			var entry appendLogRecord
+			if err := json.Unmarshal(rec.Data, &entry); err != nil {
+				return fmt.Errorf("corrupt execution log record at offset %d: %w", rec.Offset, err)
+			}
+			switch entry.Op {
+			case appendLogOpSave:
+				if entry.State != nil {
+					s.states[entry.ExecutionID] = entry.State
+				}
+			case appendLogOpDelete:
+				delete(s.states, entry.ExecutionID)
+			}
+		}
+		from = records[len(records)-1].Offset + 1
+	}
+}
+
+func (s *AppendLogExecutionStore) append(entry appendLogRecord) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode execution log record: %w", err)
+	}
+	if _, err := s.log.Append(data); err != nil {
+		return fmt.Errorf("failed to append execution log record: %w", err)
+	}
+	return nil
+}
+
+// SaveState implements ExecutionStore.
+func (s *AppendLogExecutionStore) SaveState(state *ExecutionState) error {
+	if state == nil || state.ExecutionID == "" {
+		return fmt.Errorf("execution state with ID is required")
+	}
+	clone, err := cloneExecutionState(state)
+	if err != nil {
+		return err
+	}
+
+	// Hold the lock across append so log order matches index order
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.append(appendLogRecord{Op: appendLogOpSave, ExecutionID: clone.ExecutionID, State: clone}); err != nil {
+		return err
+	}
+	s.states[clone.ExecutionID] = clone
+	return nil
+}
+
+// LoadState implements ExecutionStore.
+func (s *AppendLogExecutionStore) LoadState(executionID string) (*ExecutionState, error) {
+	s.mu.Lock()
+	state, ok := s.states[executionID]
+	s.mu.Unlock()
+
+	if !ok {
+		return nil, fmt.Errorf("execution not found: %s", executionID)
+	}
+	return cloneExecutionState(state)
+}
+
+// ListRunning implements ExecutionStore.
+func (s *AppendLogExecutionStore) ListRunning() ([]*ExecutionState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]*ExecutionState, 0)
+	for _, state := range s.states {
+		if state.Status != ExecutionStatusRunning {
+			continue
+		}
+		clone, err := cloneExecutionState(state)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, clone)
+	}
+	return result, nil
+}
+
+// Delete implements ExecutionStore.
+func (s *AppendLogExecutionStore) Delete(executionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.append(appendLogRecord{Op: appendLogOpDelete, ExecutionID: executionID}); err != nil {
+		return err
+	}
+	delete(s.states, executionID)
+	return nil
+}
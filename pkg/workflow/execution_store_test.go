@@ -0,0 +1,248 @@
+package workflow
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/appendlog"
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func openAppendLog(t *testing.T, dir string) appendlog.Store {
+	t.Helper()
+	cfg := appendlog.DefaultFSStoreConfig(dir)
+	cfg.Durability = appendlog.DurabilityFsync
+	log, err := appendlog.NewFSStore(cfg)
+	if err != nil {
+		t.Fatalf("NewFSStore() error = %v", err)
+	}
+	return log
+}
+
+func openAppendLogExecutionStore(t *testing.T, log appendlog.Store) *AppendLogExecutionStore {
+	t.Helper()
+	store, err := NewAppendLogExecutionStore(log)
+	if err != nil {
+		t.Fatalf("NewAppendLogExecutionStore() error = %v", err)
+	}
+	return store
+}
+
+// checkpointWorkflow is start -> checkpoint -> done; "checkpoint" is a custom
+// node type so each engine can decide whether it blocks or completes.
+func checkpointWorkflow() *WorkflowDefinition {
+	return &WorkflowDefinition{
+		ID: "resumable",
+		Nodes: []NodeDefinition{
+			{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"checkpoint"}},
+			{ID: "checkpoint", Type: "checkpoint", Next: []string{"done"}},
+			{ID: "done", Type: string(NodeTypeNoOp)},
+		},
+	}
+}
+
+func newStoreEngine(t *testing.T, store ExecutionStore, checkpoint NodeHandler) *Engine {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	engine := NewEngineWithOptions(gocmd.EventBus(), EngineOptions{Store: store})
+	engine.RegisterNodeHandler("checkpoint", checkpoint)
+	if err := engine.RegisterWorkflow(checkpointWorkflow()); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return engine
+}
+
+func waitForStoredState(t *testing.T, store ExecutionStore, executionID string, ready func(*ExecutionState) bool) *ExecutionState {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if state, err := store.LoadState(executionID); err == nil && ready(state) {
+			return state
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("stored state of %s did not reach expected condition", executionID)
+	return nil
+}
+
+// testResumeAfterRestart runs the workflow until "checkpoint" is in flight,
+// abandons the first engine, and resumes the execution on a fresh engine.
+func testResumeAfterRestart(t *testing.T, first ExecutionStore, restart func() ExecutionStore) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// First engine: checkpoint never finishes (simulates a crash mid-node)
+	engine1 := newStoreEngine(t, first, func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	execID, err := engine1.ExecuteWorkflow(ctx, "resumable", map[string]interface{}{"orderId": "o-1"})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+
+	waitForStoredState(t, first, execID, func(s *ExecutionState) bool {
+		_, pending := s.PendingNodes["checkpoint"]
+		_, started := s.Context.NodeOutputs["start"]
+		return pending && started
+	})
+
+	// Second engine on the same persisted state
+	store := restart()
+	var checkpointRuns int32
+	engine2 := newStoreEngine(t, store, func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		atomic.AddInt32(&checkpointRuns, 1)
+		return &NodeOutput{Data: input.Data}, nil
+	})
+
+	resumed, err := engine2.ResumeExecutions(context.Background())
+	if err != nil {
+		t.Fatalf("ResumeExecutions() error = %v", err)
+	}
+	if resumed != 1 {
+		t.Fatalf("ResumeExecutions() = %d, want 1", resumed)
+	}
+
+	state := waitForStoredState(t, store, execID, func(s *ExecutionState) bool {
+		return s.Status != ExecutionStatusRunning
+	})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("status = %s, want %s (errors: %v)", state.Status, ExecutionStatusCompleted, state.Context.Errors)
+	}
+	if got := atomic.LoadInt32(&checkpointRuns); got != 1 {
+		t.Errorf("checkpoint runs after resume = %d, want 1", got)
+	}
+	if _, ok := state.Context.NodeOutputs["done"]; !ok {
+		t.Errorf("NodeOutputs = %v, want output for done", state.Context.NodeOutputs)
+	}
+	if state.Context.Data["orderId"] != "o-1" {
+		t.Errorf("Data = %v, want original input preserved", state.Context.Data)
+	}
+	if len(state.PendingNodes) != 0 {
+		t.Errorf("PendingNodes = %v, want none after completion", state.PendingNodes)
+	}
+}
+
+func TestEngine_ResumeExecutions_MemoryStore(t *testing.T) {
+	store := NewMemoryExecutionStore()
+	testResumeAfterRestart(t, store, func() ExecutionStore { return store })
+}
+
+func TestEngine_ResumeExecutions_AppendLogStore(t *testing.T) {
+	dir := t.TempDir()
+
+	log1 := openAppendLog(t, dir)
+	first := openAppendLogExecutionStore(t, log1)
+
+	testResumeAfterRestart(t, first, func() ExecutionStore {
+		// Restart: close the first log and replay it from disk
+		if err := log1.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		log2 := openAppendLog(t, dir)
+		t.Cleanup(func() { _ = log2.Close() })
+		return openAppendLogExecutionStore(t, log2)
+	})
+}
+
+func TestEngine_ResumeExecutions_NoStore(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	if _, err := NewEngine(gocmd.EventBus()).ResumeExecutions(context.Background()); err == nil {
+		t.Error("ResumeExecutions() without store should fail")
+	}
+}
+
+func TestMemoryExecutionStore(t *testing.T) {
+	store := NewMemoryExecutionStore()
+
+	running := &ExecutionState{
+		ExecutionID: "e-1",
+		WorkflowID:  "wf",
+		Status:      ExecutionStatusRunning,
+		Context:     &ExecutionContext{NodeOutputs: map[string]interface{}{"a": "x"}},
+	}
+	if err := store.SaveState(running); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	if err := store.SaveState(&ExecutionState{ExecutionID: "e-2", WorkflowID: "wf", Status: ExecutionStatusCompleted}); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+
+	// Stored copy must not alias the caller's maps
+	running.Context.NodeOutputs["a"] = "mutated"
+	loaded, err := store.LoadState("e-1")
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if loaded.Context.NodeOutputs["a"] != "x" {
+		t.Errorf("NodeOutputs[a] = %v, want stored snapshot %q", loaded.Context.NodeOutputs["a"], "x")
+	}
+
+	list, err := store.ListRunning()
+	if err != nil {
+		t.Fatalf("ListRunning() error = %v", err)
+	}
+	if len(list) != 1 || list[0].ExecutionID != "e-1" {
+		t.Errorf("ListRunning() = %v, want only e-1", list)
+	}
+
+	if err := store.Delete("e-1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.LoadState("e-1"); err == nil {
+		t.Error("LoadState() after Delete should fail")
+	}
+	if err := store.SaveState(&ExecutionState{}); err == nil {
+		t.Error("SaveState() without ExecutionID should fail")
+	}
+}
+
+func TestAppendLogExecutionStore_Replay(t *testing.T) {
+	dir := t.TempDir()
+
+	log1 := openAppendLog(t, dir)
+	store := openAppendLogExecutionStore(t, log1)
+	for _, state := range []*ExecutionState{
+		{ExecutionID: "e-1", WorkflowID: "wf", Status: ExecutionStatusRunning},
+		{ExecutionID: "e-2", WorkflowID: "wf", Status: ExecutionStatusRunning},
+		{ExecutionID: "e-1", WorkflowID: "wf", Status: ExecutionStatusCompleted},
+	} {
+		if err := store.SaveState(state); err != nil {
+			t.Fatalf("SaveState() error = %v", err)
+		}
+	}
+	if err := store.Delete("e-2"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := log1.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	log2 := openAppendLog(t, dir)
+	defer log2.Close()
+	reopened := openAppendLogExecutionStore(t, log2)
+
+	state, err := reopened.LoadState("e-1")
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	if state.Status != ExecutionStatusCompleted {
+		t.Errorf("status = %s, want latest record %s", state.Status, ExecutionStatusCompleted)
+	}
+	if _, err := reopened.LoadState("e-2"); err == nil {
+		t.Error("deleted execution should not be replayed")
+	}
+	list, err := reopened.ListRunning()
+	if err != nil {
+		t.Fatalf("ListRunning() error = %v", err)
+	}
+	if len(list) != 0 {
+		t.Errorf("ListRunning() = %d executions, want 0", len(list))
+	}
+}
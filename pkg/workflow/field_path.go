@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// lookupField resolves a node config field against data. A field naming a
+// top-level key is read directly, as before paths were supported; otherwise
+// "order.customer.tier", "items[0].price" and "items.0.price" walk nested
+// maps and arrays.
+func lookupField(data interface{}, field string) (interface{}, bool) {
+	if m, ok := data.(map[string]interface{}); ok {
+		if value, ok := m[field]; ok {
+			return value, true
+		}
+	}
+	path, ok := fieldPath(field)
+	if !ok {
+		return nil, false
+	}
+	return resolvePath(data, path)
+}
+
+// fieldPath parses a dotted field into path steps. Plain names and malformed
+// paths report false.
+func fieldPath(field string) ([]interface{}, bool) {
+	if !strings.ContainsAny(field, ".[") {
+		return nil, false
+	}
+	prefix := "$."
+	if strings.HasPrefix(field, "[") {
+		prefix = "$"
+	}
+	path, err := parseGuardPath(prefix + field)
+	if err != nil {
+		return nil, false
+	}
+	return path, true
+}
+
+// setField sets field in data, creating intermediate maps for path fields.
+// Like lookupField, an existing top-level key is set directly.
+// Maps and arrays along the path are copied, so values shared with the
+// node's input are not modified.
+func setField(data map[string]interface{}, field string, value interface{}) error {
+	path, ok := fieldPath(field)
+	if _, exists := data[field]; exists || !ok {
+		data[field] = value
+		return nil
+	}
+	key, ok := path[0].(string)
+	if !ok {
+		return fmt.Errorf("cannot set %s: path must start with a field name", field)
+	}
+	updated, err := setPathValue(data[key], path[1:], value)
+	if err != nil {
+		return fmt.Errorf("cannot set %s: %w", field, err)
+	}
+	data[key] = updated
+	return nil
+}
+
+func setPathValue(current interface{}, path []interface{}, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	step := path[0]
+	if name, ok := step.(string); ok {
+		if _, isArray := current.([]interface{}); isArray {
+			if index, err := strconv.Atoi(name); err == nil {
+				step = index
+			}
+		}
+	}
+	switch step := step.(type) {
+	case string:
+		m := make(map[string]interface{})
+		if existing, ok := current.(map[string]interface{}); ok {
+			for k, v := range existing {
+				m[k] = v
+			}
+		} else if current != nil {
+			return nil, fmt.Errorf("cannot set field %s on a non-object value", step)
+		}
+		updated, err := setPathValue(m[step], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		m[step] = updated
+		return m, nil
+	case int:
+		existing, ok := current.([]interface{})
+		if !ok || step < 0 || step >= len(existing) {
+			return nil, fmt.Errorf("index %d is out of range", step)
+		}
+		s := append([]interface{}(nil), existing...)
+		updated, err := setPathValue(s[step], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		s[step] = updated
+		return s, nil
+	}
+	return current, nil
+}
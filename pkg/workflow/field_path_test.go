@@ -0,0 +1,186 @@
+package workflow
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func orderData() map[string]interface{} {
+	return map[string]interface{}{
+		"status":     "paid",
+		"legacy.key": "flat",
+		"order": map[string]interface{}{
+			"customer": map[string]interface{}{"tier": "gold", "email": "ada@example.com"},
+			"items": []interface{}{
+				map[string]interface{}{"sku": "A1", "price": 12.5},
+				map[string]interface{}{"sku": "B2", "price": 3.0},
+			},
+		},
+	}
+}
+
+func TestLookupField(t *testing.T) {
+	data := orderData()
+	tests := []struct {
+		field string
+		want  interface{}
+		found bool
+	}{
+		{"status", "paid", true},
+		{"legacy.key", "flat", true},
+		{"order.customer.tier", "gold", true},
+		{"order.items[1].price", 3.0, true},
+		{"order.items[2].price", nil, false},
+		{"order.customer.missing", nil, false},
+		{"status.length", nil, false},
+		{"order.items[x]", nil, false},
+		{"missing", nil, false},
+	}
+	for _, tt := range tests {
+		got, found := lookupField(data, tt.field)
+		if found != tt.found || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("lookupField(%q) = %v, %v; want %v, %v", tt.field, got, found, tt.want, tt.found)
+		}
+	}
+
+	if got, ok := lookupField([]interface{}{"a", "b"}, "[1]"); !ok || got != "b" {
+		t.Errorf("lookupField([1]) = %v, %v; want b", got, ok)
+	}
+}
+
+func TestEvaluateCondition_RegexAndIn(t *testing.T) {
+	tests := []struct {
+		actual   interface{}
+		operator string
+		expected interface{}
+		want     bool
+	}{
+		{"ada@example.com", "regex", `^[^@]+@example\.com$`, true},
+		{"ada@other.com", "regex", `@example\.com$`, false},
+		{42, "matches", `^\d+$`, true},
+		{"x", "regex", `(`, false},
+		{nil, "regex", `.*`, false},
+		{"gold", "in", []interface{}{"silver", "gold"}, true},
+		{"bronze", "in", []interface{}{"silver", "gold"}, false},
+		{2, "in", []interface{}{float64(1), float64(2)}, true},
+		{"old", "in", "gold", true},
+		{"gold", "notIn", []interface{}{"silver"}, true},
+	}
+	for _, tt := range tests {
+		if got := evaluateCondition(tt.actual, tt.operator, tt.expected); got != tt.want {
+			t.Errorf("evaluateCondition(%v, %s, %v) = %v, want %v", tt.actual, tt.operator, tt.expected, got, tt.want)
+		}
+	}
+}
+
+func TestConditionHandler_NestedField(t *testing.T) {
+	for _, tc := range []struct {
+		field, operator string
+		value           interface{}
+		want            bool
+	}{
+		{"order.customer.tier", "eq", "gold", true},
+		{"order.items[0].price", "gt", 10, true},
+		{"order.items[1].price", "gt", 10, false},
+		{"order.customer.email", "regex", `@example\.com$`, true},
+		{"order.customer.tier", "in", []interface{}{"silver", "platinum"}, false},
+		{"status", "eq", "paid", true},
+	} {
+		out, err := conditionHandler(context.Background(), &NodeInput{
+			Data:   orderData(),
+			Config: map[string]interface{}{"field": tc.field, "operator": tc.operator, "value": tc.value},
+		})
+		if err != nil {
+			t.Fatalf("conditionHandler() error = %v", err)
+		}
+		if got := out.Data.(map[string]interface{})["_conditionResult"]; got != tc.want {
+			t.Errorf("%s %s %v = %v, want %v", tc.field, tc.operator, tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestSwitchHandler_NestedField(t *testing.T) {
+	out, err := switchHandler(context.Background(), &NodeInput{
+		Data: orderData(),
+		Config: map[string]interface{}{
+			"field":   "order.customer.tier",
+			"cases":   map[string]interface{}{"gold": []interface{}{"vip"}},
+			"default": []interface{}{"standard"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("switchHandler() error = %v", err)
+	}
+	if !reflect.DeepEqual(out.NextNodes, []string{"vip"}) {
+		t.Errorf("NextNodes = %v, want [vip]", out.NextNodes)
+	}
+}
+
+func TestFilterNodeHandler_NestedField(t *testing.T) {
+	data := map[string]interface{}{
+		"order": map[string]interface{}{
+			"lines": []interface{}{
+				map[string]interface{}{"product": map[string]interface{}{"price": 5.0}},
+				map[string]interface{}{"product": map[string]interface{}{"price": 50.0}},
+			},
+		},
+	}
+	out, err := FilterNodeHandler(context.Background(), &NodeInput{
+		Data:   data,
+		Config: map[string]interface{}{"items": "order.lines", "field": "product.price", "operator": "gte", "value": 10},
+	})
+	if err != nil {
+		t.Fatalf("FilterNodeHandler() error = %v", err)
+	}
+	results := out.Data.([]interface{})
+	if len(results) != 1 || !reflect.DeepEqual(results[0], data["order"].(map[string]interface{})["lines"].([]interface{})[1]) {
+		t.Errorf("results = %v, want only the 50.0 line", results)
+	}
+}
+
+func TestSetHandler_NestedValues(t *testing.T) {
+	input := orderData()
+	out, err := setHandler(context.Background(), &NodeInput{
+		Data: input,
+		Config: map[string]interface{}{"values": map[string]interface{}{
+			"order.customer.tier":  "platinum",
+			"order.items[1].price": 4.0,
+			"audit.by":             "system",
+			"flag":                 true,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("setHandler() error = %v", err)
+	}
+	data := out.Data
+
+	for field, want := range map[string]interface{}{
+		"order.customer.tier":  "platinum",
+		"order.customer.email": "ada@example.com",
+		"order.items[1].price": 4.0,
+		"order.items[0].sku":   "A1",
+		"audit.by":             "system",
+		"flag":                 true,
+	} {
+		if got, _ := lookupField(data, field); got != want {
+			t.Errorf("%s = %v, want %v", field, got, want)
+		}
+	}
+	if got, _ := lookupField(input, "order.customer.tier"); got != "gold" {
+		t.Errorf("input was modified: tier = %v", got)
+	}
+	if got, _ := lookupField(input, "order.items[1].price"); got != 3.0 {
+		t.Errorf("input was modified: price = %v", got)
+	}
+
+	for _, field := range []string{"status.code", "order.items[5].price"} {
+		_, err := setHandler(context.Background(), &NodeInput{
+			Data:   orderData(),
+			Config: map[string]interface{}{"values": map[string]interface{}{field: 1}},
+		})
+		if err == nil {
+			t.Errorf("setting %s should fail", field)
+		}
+	}
+}
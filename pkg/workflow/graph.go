@@ -0,0 +1,161 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// nodeEdges returns the IDs a node can continue to: next, trueNext,
+// falseNext and onError, in that order.
+func nodeEdges(node *NodeDefinition) []string {
+	edges := make([]string, 0, len(node.Next)+len(node.TrueNext)+len(node.FalseNext)+len(node.OnError))
+	edges = append(edges, node.Next...)
+	edges = append(edges, node.TrueNext...)
+	edges = append(edges, node.FalseNext...)
+	return append(edges, node.OnError...)
+}
+
+// isLoopNode reports whether a node may close a cycle: loop node types bound
+// their own iterations.
+func isLoopNode(node *NodeDefinition) bool {
+	switch NodeType(node.Type) {
+	case NodeTypeLoop, NodeTypeDynamicLoop:
+		return true
+	}
+	return false
+}
+
+// isStartNode reports whether an execution starts at node.
+func isStartNode(node *NodeDefinition, def *WorkflowDefinition) bool {
+	// A start node is either a trigger type or has no incoming connections
+	switch NodeType(node.Type) {
+	case NodeTypeWebhook, NodeTypeSchedule, NodeTypeEvent, NodeTypeManual:
+		return true
+	}
+
+	// Check if any node points to this node
+	for _, n := range def.Nodes {
+		for _, next := range n.Next {
+			if next == node.ID {
+				return false
+			}
+		}
+		for _, next := range n.TrueNext {
+			if next == node.ID {
+				return false
+			}
+		}
+		for _, next := range n.FalseNext {
+			if next == node.ID {
+				return false
+			}
+		}
+		// Error handlers only run when the node before them fails
+		for _, next := range n.OnError {
+			if next == node.ID {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// CheckWorkflowGraph analyses the edges of def (whose node references must all
+// exist). It returns an error naming the nodes of a cycle that does not go
+// through a loop node, since such a cycle re-runs its nodes forever, or when
+// the workflow has no start node. Otherwise it returns the IDs of nodes that
+// cannot be reached from any start node, in definition order.
+func CheckWorkflowGraph(def *WorkflowDefinition) ([]string, error) {
+	nodes := make(map[string]*NodeDefinition, len(def.Nodes))
+	for i := range def.Nodes {
+		nodes[def.Nodes[i].ID] = &def.Nodes[i]
+	}
+
+	if cycle := findCycle(def, nodes); cycle != nil {
+		if len(cycle) == 2 {
+			return nil, fmt.Errorf("workflow %s: node %s references itself (only loop nodes may form cycles)", def.ID, cycle[0])
+		}
+		return nil, fmt.Errorf("workflow %s: cycle %s (only loop nodes may form cycles)", def.ID, strings.Join(cycle, " -> "))
+	}
+
+	// Walk from the start nodes over every edge
+	reached := make(map[string]bool, len(def.Nodes))
+	var queue []string
+	for i := range def.Nodes {
+		if isStartNode(&def.Nodes[i], def) {
+			reached[def.Nodes[i].ID] = true
+			queue = append(queue, def.Nodes[i].ID)
+		}
+	}
+	if len(queue) == 0 {
+		return nil, fmt.Errorf("workflow %s has no start node: every node has an incoming edge", def.ID)
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range nodeEdges(nodes[id]) {
+			if !reached[next] {
+				reached[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	var unreachable []string
+	for _, node := range def.Nodes {
+		if !reached[node.ID] {
+			unreachable = append(unreachable, node.ID)
+		}
+	}
+	return unreachable, nil
+}
+
+// findCycle returns a cycle among the non-loop nodes as the node IDs along it,
+// first node repeated at the end, or nil if there is none. A cycle through a
+// loop node is allowed, so loop nodes are left out of the search.
+func findCycle(def *WorkflowDefinition, nodes map[string]*NodeDefinition) []string {
+	const (
+		unvisited = iota
+		onPath
+		done
+	)
+	state := make(map[string]int, len(def.Nodes))
+	var path []string
+
+	var visit func(id string) []string
+	visit = func(id string) []string {
+		state[id] = onPath
+		path = append(path, id)
+		for _, next := range nodeEdges(nodes[id]) {
+			if isLoopNode(nodes[next]) {
+				continue
+			}
+			switch state[next] {
+			case onPath:
+				for i, p := range path {
+					if p == next {
+						return append(append([]string(nil), path[i:]...), next)
+					}
+				}
+			case unvisited:
+				if cycle := visit(next); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = done
+		return nil
+	}
+
+	for i := range def.Nodes {
+		node := &def.Nodes[i]
+		if state[node.ID] == unvisited && !isLoopNode(node) {
+			if cycle := visit(node.ID); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package workflow
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func TestRegisterWorkflow_RejectsCycles(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []NodeDefinition
+		want  string
+	}{
+		{
+			name: "self-loop",
+			nodes: []NodeDefinition{
+				{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"again"}},
+				{ID: "again", Type: string(NodeTypeNoOp), Next: []string{"again"}},
+			},
+			want: "node again references itself",
+		},
+		{
+			name: "longer cycle",
+			nodes: []NodeDefinition{
+				{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"a"}},
+				{ID: "a", Type: string(NodeTypeNoOp), Next: []string{"b"}},
+				{ID: "b", Type: string(NodeTypeCondition), TrueNext: []string{"c"}, FalseNext: []string{"done"}},
+				{ID: "c", Type: string(NodeTypeNoOp), Next: []string{"a"}},
+				{ID: "done", Type: string(NodeTypeNoOp)},
+			},
+			want: "cycle a -> b -> c -> a",
+		},
+		{
+			name: "cycle through onError",
+			nodes: []NodeDefinition{
+				{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"call"}},
+				{ID: "call", Type: string(NodeTypeHTTP), OnError: []string{"recover"}},
+				{ID: "recover", Type: string(NodeTypeNoOp), Next: []string{"call"}},
+			},
+			want: "cycle call -> recover -> call",
+		},
+		{
+			name: "no start node",
+			nodes: []NodeDefinition{
+				{ID: "a", Type: string(NodeTypeNoOp), Next: []string{"b"}},
+				{ID: "b", Type: string(NodeTypeLoop), Next: []string{"a"}},
+			},
+			want: "has no start node",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewEngine(nil).RegisterWorkflow(&WorkflowDefinition{ID: "wf", Nodes: tt.nodes})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("RegisterWorkflow() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckWorkflowGraph_LoopCycleAllowed(t *testing.T) {
+	def := &WorkflowDefinition{ID: "wf", Nodes: []NodeDefinition{
+		{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"each"}},
+		{ID: "each", Type: string(NodeTypeLoop), Next: []string{"body"}},
+		{ID: "body", Type: string(NodeTypeNoOp), Next: []string{"each"}},
+	}}
+	unreachable, err := CheckWorkflowGraph(def)
+	if err != nil || len(unreachable) != 0 {
+		t.Errorf("CheckWorkflowGraph() = %v, %v; want a valid graph", unreachable, err)
+	}
+}
+
+func TestCheckWorkflowGraph_UnreachableNodes(t *testing.T) {
+	// island and retry only point at each other, so no start node leads to them
+	def := &WorkflowDefinition{ID: "wf", Nodes: []NodeDefinition{
+		{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"end"}},
+		{ID: "island", Type: string(NodeTypeNoOp), Next: []string{"retry"}},
+		{ID: "retry", Type: string(NodeTypeLoop), Next: []string{"island"}},
+		{ID: "end", Type: string(NodeTypeNoOp)},
+	}}
+	unreachable, err := CheckWorkflowGraph(def)
+	if err != nil {
+		t.Fatalf("CheckWorkflowGraph() error = %v", err)
+	}
+	if want := []string{"island", "retry"}; !reflect.DeepEqual(unreachable, want) {
+		t.Errorf("unreachable = %v, want %v", unreachable, want)
+	}
+
+	// Unreachable nodes are reported, not rejected
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	if err := NewEngine(gocmd.EventBus()).RegisterWorkflow(def); err != nil {
+		t.Errorf("RegisterWorkflow() error = %v", err)
+	}
+}
+
+func TestRegisterWorkflow_UnknownOnErrorNode(t *testing.T) {
+	def := &WorkflowDefinition{ID: "wf", Nodes: []NodeDefinition{
+		{ID: "call", Type: string(NodeTypeHTTP), OnError: []string{"missing"}},
+	}}
+	err := NewEngine(nil).RegisterWorkflow(def)
+	if err == nil || !strings.Contains(err.Error(), "unknown node missing in onError") {
+		t.Errorf("RegisterWorkflow() error = %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// httpDebugBodyLimit is how much of a body an HTTP node's debug log shows.
+const httpDebugBodyLimit = 2048
+
+// redacted replaces credentials in HTTP node debug logs.
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are redacted whatever their value; headers and query
+// parameters whose name contains a sensitiveNameParts entry are too.
+var (
+	sensitiveHeaders   = map[string]bool{"Authorization": true, "Proxy-Authorization": true, "Cookie": true, "Set-Cookie": true}
+	sensitiveNameParts = []string{"token", "secret", "password", "api-key", "apikey", "api_key"}
+)
+
+// httpDebugLogger returns the logger an HTTP node logs its exchange to, or
+// nil unless its "debug" config or EngineOptions.DebugHTTP asks for it.
+func httpDebugLogger(ctx context.Context, input *NodeInput) core.Logger {
+	engine, _ := ctx.Value("workflow_engine").(*Engine)
+	if debug, _ := input.Config["debug"].(bool); !debug && (engine == nil || !engine.debugHTTP) {
+		return nil
+	}
+	return LoggerFromContext(ctx)
+}
+
+func formatHTTPRequest(nodeID string, req *http.Request, body []byte) string {
+	return fmt.Sprintf("http node %s: request %s %s headers=%s body=%s",
+		nodeID, req.Method, redactURL(req.URL), formatHeaders(req.Header), formatBody(body))
+}
+
+func formatHTTPResponse(nodeID string, resp *http.Response, body []byte) string {
+	return fmt.Sprintf("http node %s: response %d headers=%s body=%s",
+		nodeID, resp.StatusCode, formatHeaders(resp.Header), formatBody(body))
+}
+
+func isSensitiveName(name string) bool {
+	name = strings.ToLower(name)
+	for _, part := range sensitiveNameParts {
+		if strings.Contains(name, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatHeaders lists headers sorted by name, credentials redacted.
+func formatHeaders(h http.Header) string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] || isSensitiveName(name) {
+			value = redacted
+		}
+		parts = append(parts, name+": "+value)
+	}
+	return "{" + strings.Join(parts, "; ") + "}"
+}
+
+// redactURL hides the password and sensitive query parameters of u.
+func redactURL(u *url.URL) string {
+	redactedURL := *u
+	if u.RawQuery != "" {
+		query := u.Query()
+		for name, values := range query {
+			if isSensitiveName(name) {
+				for i := range values {
+					values[i] = redacted
+				}
+			}
+		}
+		redactedURL.RawQuery = query.Encode()
+	}
+	return redactedURL.Redacted()
+}
+
+// formatBody quotes body, truncated to httpDebugBodyLimit.
+func formatBody(body []byte) string {
+	if len(body) <= httpDebugBodyLimit {
+		return fmt.Sprintf("%q", body)
+	}
+	return fmt.Sprintf("%q... (%d bytes)", body[:httpDebugBodyLimit], len(body))
+}
@@ -0,0 +1,59 @@
+package workflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"sync"
+)
+
+// httpSessions holds one HTTP client per execution so that all HTTP nodes of
+// an execution share a cookie jar and keep-alive connections. A login node's
+// Set-Cookie is then sent by later nodes, and calls stick to the same upstream
+// connection when the backend keeps it open.
+type httpSessions struct {
+	mu      sync.Mutex
+	clients map[string]*http.Client // executionID -> client
+}
+
+func newHTTPSessions() *httpSessions {
+	return &httpSessions{clients: make(map[string]*http.Client)}
+}
+
+// client returns the execution's HTTP client, creating it on first use.
+func (s *httpSessions) client(executionID string) *http.Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.clients[executionID]; ok {
+		return c
+	}
+	// cookiejar.New only fails for a bad PublicSuffixList; nil never does
+	jar, _ := cookiejar.New(nil)
+	c := &http.Client{
+		Jar:       jar,
+		Transport: http.DefaultTransport.(*http.Transport).Clone(),
+	}
+	s.clients[executionID] = c
+	return c
+}
+
+// release drops the execution's client and closes its idle connections.
+func (s *httpSessions) release(executionID string) {
+	s.mu.Lock()
+	c, ok := s.clients[executionID]
+	delete(s.clients, executionID)
+	s.mu.Unlock()
+	if ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// httpClientFor returns the HTTP client an HTTP node should use: the
+// execution's shared session when run by an engine, a one-off client otherwise.
+func httpClientFor(ctx context.Context, input *NodeInput) *http.Client {
+	engine, ok := ctx.Value("workflow_engine").(*Engine)
+	if !ok || input.Context == nil || input.Context.ExecutionID == "" {
+		return &http.Client{}
+	}
+	return engine.httpSessions.client(input.Context.ExecutionID)
+}
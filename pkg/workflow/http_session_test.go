@@ -0,0 +1,80 @@
+package workflow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// sessionServer issues a session cookie on /login and requires it on /profile.
+func sessionServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/"})
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	})
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "abc123" {
+			http.Error(w, `{"error":"no session"}`, http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"user":"alice"}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPNode_SessionCookiesPerExecution(t *testing.T) {
+	server := sessionServer(t)
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	engine := NewEngine(gocmd.EventBus())
+	engine.RegisterNodeHandler(NodeTypeHTTP, HTTPNodeHandler)
+	for _, def := range []*WorkflowDefinition{
+		{
+			ID: "login-then-profile",
+			Nodes: []NodeDefinition{
+				{ID: "login", Type: string(NodeTypeHTTP), Config: map[string]interface{}{"url": server.URL + "/login", "method": "POST"}, Next: []string{"profile"}},
+				{ID: "profile", Type: string(NodeTypeHTTP), Config: map[string]interface{}{"url": server.URL + "/profile"}},
+			},
+		},
+		{
+			ID: "profile-only",
+			Nodes: []NodeDefinition{
+				{ID: "profile", Type: string(NodeTypeHTTP), Config: map[string]interface{}{"url": server.URL + "/profile"}},
+			},
+		},
+	} {
+		if err := engine.RegisterWorkflow(def); err != nil {
+			t.Fatalf("RegisterWorkflow() error = %v", err)
+		}
+	}
+
+	state := runGuarded(t, engine, "login-then-profile", map[string]interface{}{})
+	profile, _ := state.Context.NodeOutputs["profile"].(map[string]interface{})
+	if profile["statusCode"] != http.StatusOK {
+		t.Fatalf("profile status = %v, want 200 with the login cookie", profile["statusCode"])
+	}
+
+	// Another execution has its own jar and never sees that session
+	state = runGuarded(t, engine, "profile-only", map[string]interface{}{})
+	profile, _ = state.Context.NodeOutputs["profile"].(map[string]interface{})
+	if profile["statusCode"] != http.StatusUnauthorized {
+		t.Errorf("profile status in a new execution = %v, want 401", profile["statusCode"])
+	}
+
+	engine.httpSessions.mu.Lock()
+	sessions := len(engine.httpSessions.clients)
+	engine.httpSessions.mu.Unlock()
+	if sessions != 0 {
+		t.Errorf("%d HTTP sessions held after executions finished, want 0", sessions)
+	}
+}
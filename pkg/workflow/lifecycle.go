@@ -0,0 +1,140 @@
+package workflow
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// Lifecycle event addresses, published when EngineOptions.LifecycleEvents is set.
+// Use the helpers below to build them for a workflow and node.
+const (
+	lifecycleExecutionStarted  = "workflow.%s.execution.started"
+	lifecycleExecutionFinished = "workflow.%s.execution.finished"
+	lifecycleNodeStarted       = "workflow.%s.node.%s.started"
+	lifecycleNodeCompleted     = "workflow.%s.node.%s.completed"
+	lifecycleNodeFailed        = "workflow.%s.node.%s.failed"
+)
+
+// ExecutionStartedAddress is where an execution of workflowID announces its start.
+func ExecutionStartedAddress(workflowID string) string {
+	return fmt.Sprintf(lifecycleExecutionStarted, workflowID)
+}
+
+// ExecutionFinishedAddress is where an execution of workflowID announces its
+// final status (completed, failed or cancelled).
+func ExecutionFinishedAddress(workflowID string) string {
+	return fmt.Sprintf(lifecycleExecutionFinished, workflowID)
+}
+
+// NodeStartedAddress is where runs of a node announce their start.
+func NodeStartedAddress(workflowID, nodeID string) string {
+	return fmt.Sprintf(lifecycleNodeStarted, workflowID, nodeID)
+}
+
+// NodeCompletedAddress is where successful runs of a node are announced.
+func NodeCompletedAddress(workflowID, nodeID string) string {
+	return fmt.Sprintf(lifecycleNodeCompleted, workflowID, nodeID)
+}
+
+// NodeFailedAddress is where failed runs of a node (after all retries) are announced.
+func NodeFailedAddress(workflowID, nodeID string) string {
+	return fmt.Sprintf(lifecycleNodeFailed, workflowID, nodeID)
+}
+
+// engineEvents publishes lifecycle events. Every event carries "event",
+// "workflowId", "executionId", "timestamp" and "seq", a number increasing
+// with each event of the engine, since consumers of different addresses may
+// receive them out of order.
+// A nil *engineEvents (EngineOptions.LifecycleEvents unset) publishes nothing.
+type engineEvents struct {
+	bus    core.EventBus
+	seq    atomic.Int64
+	logger core.Logger
+}
+
+func newEngineEvents(bus core.EventBus, enabled bool) *engineEvents {
+	if !enabled || bus == nil {
+		return nil
+	}
+	return &engineEvents{bus: bus, logger: core.NewDefaultLogger()}
+}
+
+func (ev *engineEvents) publish(address, event, workflowID, executionID string, fields map[string]interface{}) {
+	body := map[string]interface{}{
+		"event":       event,
+		"workflowId":  workflowID,
+		"executionId": executionID,
+		"timestamp":   time.Now().Format(time.RFC3339Nano),
+		"seq":         ev.seq.Add(1),
+	}
+	for k, v := range fields {
+		body[k] = v
+	}
+	// Events are best effort: a failed publish never affects the execution
+	if err := ev.bus.Publish(address, body); err != nil {
+		ev.logger.Error(fmt.Sprintf("failed to publish %s: %v", address, err))
+	}
+}
+
+// executionStarted announces a new execution.
+func (ev *engineEvents) executionStarted(workflowID, executionID string, start time.Time) {
+	if ev == nil {
+		return
+	}
+	ev.publish(ExecutionStartedAddress(workflowID), "execution.started", workflowID, executionID, map[string]interface{}{
+		"status":    ExecutionStatusRunning,
+		"startTime": start.Format(time.RFC3339Nano),
+	})
+}
+
+// executionFinished announces an execution reaching a final status.
+func (ev *engineEvents) executionFinished(workflowID, executionID string, status ExecutionStatus, errMsg string, start, end time.Time) {
+	if ev == nil {
+		return
+	}
+	fields := map[string]interface{}{
+		"status":     status,
+		"startTime":  start.Format(time.RFC3339Nano),
+		"endTime":    end.Format(time.RFC3339Nano),
+		"durationMs": end.Sub(start).Milliseconds(),
+	}
+	if errMsg != "" {
+		fields["error"] = errMsg
+	}
+	ev.publish(ExecutionFinishedAddress(workflowID), "execution.finished", workflowID, executionID, fields)
+}
+
+// nodeStarted announces a node run.
+func (ev *engineEvents) nodeStarted(workflowID, executionID string, node *NodeDefinition) {
+	if ev == nil {
+		return
+	}
+	ev.publish(NodeStartedAddress(workflowID, node.ID), "node.started", workflowID, executionID, map[string]interface{}{
+		"nodeId":   node.ID,
+		"nodeType": node.Type,
+		"status":   ExecutionStatusRunning,
+	})
+}
+
+// nodeFinished announces the outcome of a node run (all retry attempts).
+func (ev *engineEvents) nodeFinished(workflowID, executionID string, node *NodeDefinition, start time.Time, err error) {
+	if ev == nil {
+		return
+	}
+	fields := map[string]interface{}{
+		"nodeId":     node.ID,
+		"nodeType":   node.Type,
+		"durationMs": time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		fields["status"] = ExecutionStatusFailed
+		fields["error"] = err.Error()
+		ev.publish(NodeFailedAddress(workflowID, node.ID), "node.failed", workflowID, executionID, fields)
+		return
+	}
+	fields["status"] = ExecutionStatusCompleted
+	ev.publish(NodeCompletedAddress(workflowID, node.ID), "node.completed", workflowID, executionID, fields)
+}
@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrEngineOverloaded is returned by ExecuteWorkflow when the engine runs
+// ExecutionConcurrency.MaxRunning executions and its pending queue is full.
+var ErrEngineOverloaded = errors.New("workflow engine overloaded")
+
+// ExecutionConcurrency caps the executions an engine runs at once.
+type ExecutionConcurrency struct {
+	// MaxRunning is the number of executions running at once. 0 means unlimited.
+	MaxRunning int
+
+	// QueueSize is the number of executions waiting for a slot, in status
+	// pending; once it is full ExecuteWorkflow fails fast with
+	// ErrEngineOverloaded. 0 rejects every execution over MaxRunning.
+	QueueSize int
+}
+
+// executionLimiter admits executions up to ExecutionConcurrency.
+// A nil *executionLimiter (MaxRunning unset) admits everything.
+type executionLimiter struct {
+	maxRunning int
+	queueSize  int
+
+	mu      sync.Mutex
+	running int
+	queue   []queuedExecution // oldest first
+}
+
+// queuedExecution is a pending execution; start runs it and reports false
+// if it is no longer pending (cancelled while queued).
+type queuedExecution struct {
+	executionID string
+	start       func() bool
+}
+
+func newExecutionLimiter(c ExecutionConcurrency) *executionLimiter {
+	if c.MaxRunning <= 0 {
+		return nil
+	}
+	queueSize := c.QueueSize
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	return &executionLimiter{maxRunning: c.MaxRunning, queueSize: queueSize}
+}
+
+// admit takes a slot for exec and reports true if it may start now, or
+// queues it. Returns ErrEngineOverloaded when the queue is full.
+func (l *executionLimiter) admit(exec queuedExecution) (bool, error) {
+	if l == nil {
+		return true, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.running < l.maxRunning {
+		l.running++
+		return true, nil
+	}
+	if len(l.queue) < l.queueSize {
+		l.queue = append(l.queue, exec)
+		return false, nil
+	}
+	return false, ErrEngineOverloaded
+}
+
+// acquire takes a slot even over the limit, for executions that must not wait
+// (resumed executions and sub-workflows).
+func (l *executionLimiter) acquire() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	l.running++
+	l.mu.Unlock()
+}
+
+// release frees the slot of a finished execution, handing it to the queued
+// executions in order until one starts.
+func (l *executionLimiter) release() {
+	if l == nil {
+		return
+	}
+	for {
+		l.mu.Lock()
+		if len(l.queue) == 0 || l.running > l.maxRunning {
+			l.running--
+			l.mu.Unlock()
+			return
+		}
+		next := l.queue[0]
+		l.queue[0] = queuedExecution{}
+		l.queue = l.queue[1:]
+		l.mu.Unlock()
+
+		// The slot moves to next; start outside the lock
+		if next.start() {
+			return
+		}
+	}
+}
+
+// remove drops a queued execution, reporting whether it was queued.
+func (l *executionLimiter) remove(executionID string) bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, exec := range l.queue {
+		if exec.executionID == executionID {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// newLimitedEngine registers a one-node "blocked" workflow whose node waits
+// for release to be closed.
+func newLimitedEngine(t *testing.T, concurrency ExecutionConcurrency, metrics core.Metrics) (*Engine, chan struct{}) {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	engine := NewEngineWithOptions(gocmd.EventBus(), EngineOptions{Concurrency: concurrency, Metrics: metrics})
+
+	release := make(chan struct{})
+	engine.RegisterNodeHandler("block", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return &NodeOutput{Data: input.Data}, nil
+	})
+	def := &WorkflowDefinition{ID: "blocked", Nodes: []NodeDefinition{{ID: "wait", Type: "block"}}}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return engine, release
+}
+
+func executionStatus(t *testing.T, engine *Engine, executionID string) ExecutionStatus {
+	t.Helper()
+	state, err := engine.GetExecutionState(executionID)
+	if err != nil {
+		t.Fatalf("GetExecutionState() error = %v", err)
+	}
+	return state.Status
+}
+
+func TestEngine_Concurrency_ShedsOverflow(t *testing.T) {
+	metrics := &countingMetrics{counts: make(map[string]int)}
+	engine, release := newLimitedEngine(t, ExecutionConcurrency{MaxRunning: 1, QueueSize: 1}, metrics)
+	ctx := context.Background()
+
+	running, err := engine.ExecuteWorkflow(ctx, "blocked", nil)
+	if err != nil {
+		t.Fatalf("first ExecuteWorkflow() error = %v", err)
+	}
+	queued, err := engine.ExecuteWorkflow(ctx, "blocked", nil)
+	if err != nil {
+		t.Fatalf("second ExecuteWorkflow() error = %v", err)
+	}
+	if status := executionStatus(t, engine, queued); status != ExecutionStatusPending {
+		t.Errorf("queued execution status = %s, want pending", status)
+	}
+
+	start := time.Now()
+	if _, err := engine.ExecuteWorkflow(ctx, "blocked", nil); !errors.Is(err, ErrEngineOverloaded) {
+		t.Fatalf("overflow ExecuteWorkflow() error = %v, want ErrEngineOverloaded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("overflow ExecuteWorkflow() took %v, want it to fail fast", elapsed)
+	}
+	if n := metrics.count("fluxor_workflow_executions_shed_total{blocked}"); n != 1 {
+		t.Errorf("shed executions = %d, want 1", n)
+	}
+	engine.mu.RLock()
+	tracked := len(engine.executions)
+	engine.mu.RUnlock()
+	if tracked != 2 {
+		t.Errorf("engine tracks %d executions, want the shed one dropped", tracked)
+	}
+
+	close(release)
+	awaitCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	for _, id := range []string{running, queued} {
+		if _, err := engine.AwaitExecution(awaitCtx, id); err != nil {
+			t.Errorf("AwaitExecution(%s) error = %v", id, err)
+		}
+	}
+
+	// Both slots are free again
+	if _, err := engine.ExecuteWorkflow(ctx, "blocked", nil); err != nil {
+		t.Errorf("ExecuteWorkflow() after drain error = %v", err)
+	}
+}
+
+func TestEngine_Concurrency_CancelQueued(t *testing.T) {
+	engine, release := newLimitedEngine(t, ExecutionConcurrency{MaxRunning: 1, QueueSize: 2}, nil)
+	ctx := context.Background()
+
+	running, _ := engine.ExecuteWorkflow(ctx, "blocked", nil)
+	cancelled, _ := engine.ExecuteWorkflow(ctx, "blocked", nil)
+	next, err := engine.ExecuteWorkflow(ctx, "blocked", nil)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+
+	if err := engine.CancelExecution(cancelled); err != nil {
+		t.Fatalf("CancelExecution() of a queued execution error = %v", err)
+	}
+	if status := executionStatus(t, engine, cancelled); status != ExecutionStatusCancelled {
+		t.Errorf("cancelled execution status = %s", status)
+	}
+
+	// Cancelling the running execution hands its slot to the next queued one
+	if err := engine.CancelExecution(running); err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+	if status := executionStatus(t, engine, next); status != ExecutionStatusRunning {
+		t.Errorf("next execution status = %s, want running", status)
+	}
+
+	close(release)
+	awaitCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	if _, err := engine.AwaitExecution(awaitCtx, next); err != nil {
+		t.Errorf("AwaitExecution() error = %v", err)
+	}
+	if _, err := engine.AwaitExecution(awaitCtx, cancelled); !errors.Is(err, ErrExecutionCancelled) {
+		t.Errorf("AwaitExecution() of the cancelled execution error = %v", err)
+	}
+}
+
+func TestEngine_Concurrency_NoQueue(t *testing.T) {
+	engine, release := newLimitedEngine(t, ExecutionConcurrency{MaxRunning: 2}, nil)
+	defer close(release)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := engine.ExecuteWorkflow(ctx, "blocked", nil); err != nil {
+			t.Fatalf("ExecuteWorkflow() %d error = %v", i, err)
+		}
+	}
+	if _, err := engine.ExecuteWorkflow(ctx, "blocked", nil); !errors.Is(err, ErrEngineOverloaded) {
+		t.Errorf("ExecuteWorkflow() over MaxRunning error = %v, want ErrEngineOverloaded", err)
+	}
+}
@@ -0,0 +1,30 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// loggerKey is the context key for a node's logger.
+type loggerKey struct{}
+
+// LoggerFromContext returns the logger a node handler should log with: the
+// engine's logger with executionId, workflowId and nodeId fields, so lines
+// from a busy engine can be told apart. Outside a node it returns a default
+// logger.
+func LoggerFromContext(ctx context.Context) core.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(core.Logger); ok {
+		return logger
+	}
+	return core.NewDefaultLogger()
+}
+
+// nodeLogger scopes the engine's logger to one node of an execution.
+func (e *Engine) nodeLogger(workflowID, executionID, nodeID string) core.Logger {
+	return e.logger.WithFields(map[string]interface{}{
+		"executionId": executionID,
+		"workflowId":  workflowID,
+		"nodeId":      nodeID,
+	})
+}
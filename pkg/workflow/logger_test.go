@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// logEntry is one line a recordingLogger saw.
+type logEntry struct {
+	level   string
+	message string
+	fields  map[string]interface{}
+}
+
+// recordingLogger records Error, Info and Debug lines with their fields,
+// including those of the loggers derived from it with WithFields.
+type recordingLogger struct {
+	core.Logger
+	fields  map[string]interface{}
+	mu      *sync.Mutex
+	entries *[]logEntry
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{Logger: core.NewDefaultLogger(), mu: &sync.Mutex{}, entries: &[]logEntry{}}
+}
+
+func (l *recordingLogger) record(level string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.entries = append(*l.entries, logEntry{level, fmt.Sprint(args...), l.fields})
+}
+
+func (l *recordingLogger) Error(args ...interface{}) { l.record("ERROR", args...) }
+func (l *recordingLogger) Info(args ...interface{})  { l.record("INFO", args...) }
+func (l *recordingLogger) Debug(args ...interface{}) { l.record("DEBUG", args...) }
+
+func (l *recordingLogger) WithFields(fields map[string]interface{}) core.Logger {
+	merged := make(map[string]interface{}, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &recordingLogger{Logger: l.Logger, fields: merged, mu: l.mu, entries: l.entries}
+}
+
+func (l *recordingLogger) logged() []logEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]logEntry(nil), *l.entries...)
+}
+
+// messages joins the messages logged at level.
+func (l *recordingLogger) messages(level string) string {
+	var lines []string
+	for _, entry := range l.logged() {
+		if entry.level == level {
+			lines = append(lines, entry.message)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestEngine_NodeLoggerCarriesExecutionFields(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	logger := newRecordingLogger()
+	engine := NewEngineWithOptions(gocmd.EventBus(), EngineOptions{Logger: logger})
+	engine.RegisterNodeHandler("log-step", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		LoggerFromContext(ctx).Info("step ran")
+		return &NodeOutput{Data: input.Data}, nil
+	})
+	if err := engine.RegisterWorkflow(&WorkflowDefinition{
+		ID: "orders",
+		Nodes: []NodeDefinition{
+			{ID: "charge", Type: "log-step", Next: []string{"notify"}},
+			{ID: "notify", Type: "log-step"},
+		},
+	}); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var execIDs []string
+	for i := 0; i < 2; i++ {
+		execID, err := engine.ExecuteWorkflow(ctx, "orders", map[string]interface{}{})
+		if err != nil {
+			t.Fatalf("ExecuteWorkflow() error = %v", err)
+		}
+		execIDs = append(execIDs, execID)
+	}
+	for _, execID := range execIDs {
+		if _, err := engine.AwaitExecution(ctx, execID); err != nil {
+			t.Fatalf("AwaitExecution() error = %v", err)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, entry := range logger.logged() {
+		if entry.message != "step ran" {
+			continue
+		}
+		if entry.fields["workflowId"] != "orders" {
+			t.Errorf("workflowId = %v, want orders", entry.fields["workflowId"])
+		}
+		seen[fmt.Sprintf("%v/%v", entry.fields["executionId"], entry.fields["nodeId"])] = true
+	}
+	for _, execID := range execIDs {
+		for _, nodeID := range []string{"charge", "notify"} {
+			if !seen[execID+"/"+nodeID] {
+				t.Errorf("no log line with executionId %s and nodeId %s; got %v", execID, nodeID, seen)
+			}
+		}
+	}
+
+	if LoggerFromContext(context.Background()) == nil {
+		t.Error("LoggerFromContext() outside a node = nil")
+	}
+}
@@ -0,0 +1,193 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// loopItems returns the items a loop node iterates over: the array in the
+// input field named by Config["items"], or the input itself when it is an
+// array. Anything else yields no items.
+func loopItems(config map[string]interface{}, data interface{}) []interface{} {
+	if itemsField, ok := config["items"].(string); ok {
+		m, ok := data.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		data = m[itemsField]
+	}
+	switch arr := data.(type) {
+	case []interface{}:
+		return arr
+	case []map[string]interface{}: // e.g. db node rows
+		items := make([]interface{}, len(arr))
+		for i, item := range arr {
+			items[i] = item
+		}
+		return items
+	}
+	return nil
+}
+
+// loopBatchSize reads Config["batchSize"]: how many items run at once
+// (default 1, 0 runs every item in parallel).
+func loopBatchSize(config map[string]interface{}, items int) int {
+	batchSize := 1
+	switch bs := config["batchSize"].(type) {
+	case float64:
+		batchSize = int(bs)
+	case int:
+		batchSize = bs
+	}
+	if batchSize <= 0 || batchSize > items {
+		batchSize = items
+	}
+	return batchSize
+}
+
+// executeLoop runs a loop node: each of its next nodes (the loop body) runs
+// once per item with the item as input, at most batchSize items at a time.
+// The body nodes' outputs are collected into arrays in item order; the loop
+// node's output holds one entry per item (the body output, or a map of body
+// node ID to output when the body has several nodes). Execution then continues
+// from the body nodes' own next nodes with their collected outputs.
+//
+// A failing item is recorded as an ExecutionError and leaves a nil entry; the
+// remaining items still run unless Config["failFast"] is true, in which case
+// the loop stops and fails like any other node.
+func (e *Engine) executeLoop(ctx context.Context, def *WorkflowDefinition, node *NodeDefinition, execCtx *ExecutionContext, input interface{}) {
+	defer e.markNodeInactive(execCtx.ExecutionID, node.ID)
+
+	items := loopItems(node.Config, input)
+	failFast, _ := node.Config["failFast"].(bool)
+
+	body := make([]*NodeDefinition, 0, len(node.Next))
+	bodyIndex := make(map[string]int, len(node.Next))
+	for _, id := range node.Next {
+		if n := e.findNode(def, id); n != nil {
+			bodyIndex[id] = len(body)
+			body = append(body, n)
+		}
+	}
+	results := make([][]interface{}, len(body))
+	for i := range results {
+		results[i] = make([]interface{}, len(items))
+	}
+
+	loopCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var failed atomic.Bool
+	fail := func(nodeID string, index int, err error) {
+		if failFast && !failed.CompareAndSwap(false, true) {
+			return // only the error that stopped the loop is recorded
+		}
+		e.recordNodeError(execCtx, nodeID, fmt.Sprintf("loop %s item %d: %v", node.ID, index, err), err)
+		if failFast {
+			cancel()
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, loopBatchSize(node.Config, len(items)))
+	for i, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-loopCtx.Done():
+		}
+		if loopCtx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, item interface{}) {
+			defer func() { <-sem; wg.Done() }()
+			followed, _ := e.followGuards(node, node.Next, node.Guards.Next, item)
+			for _, id := range followed {
+				j, ok := bodyIndex[id]
+				if !ok || loopCtx.Err() != nil {
+					continue
+				}
+				output, err := e.runLoopBody(loopCtx, def, body[j], execCtx, item)
+				if err != nil {
+					fail(body[j].ID, i, err)
+					continue
+				}
+				results[j][i] = output.Data
+			}
+		}(i, item)
+	}
+	wg.Wait()
+
+	// Execution cancelled while items ran: drop the results
+	if ctx.Err() != nil && !e.isRunning(execCtx.ExecutionID) {
+		return
+	}
+
+	if failed.Load() {
+		if errorNodes, _ := e.followGuards(node, node.OnError, node.Guards.OnError, input); len(errorNodes) > 0 {
+			for _, nextID := range errorNodes {
+				if nextNode := e.findNode(def, nextID); nextNode != nil {
+					e.dispatchNode(ctx, def, nextNode, execCtx, input)
+				}
+			}
+		}
+		return
+	}
+
+	perItem := make([]interface{}, len(items))
+	for i := range items {
+		if len(body) == 1 {
+			perItem[i] = results[0][i]
+			continue
+		}
+		outputs := make(map[string]interface{}, len(body))
+		for j, b := range body {
+			outputs[b.ID] = results[j][i]
+		}
+		perItem[i] = outputs
+	}
+
+	e.mu.Lock()
+	if state, ok := e.executions[execCtx.ExecutionID]; !ok || !inProgress(state.Status) {
+		e.mu.Unlock()
+		return
+	}
+	execCtx.NodeOutputs[node.ID] = perItem
+	for j, b := range body {
+		execCtx.NodeOutputs[b.ID] = results[j]
+	}
+	e.mu.Unlock()
+
+	for j, b := range body {
+		followed, skipped := e.followGuards(b, b.Next, b.Guards.Next, results[j])
+		e.skipMergeInputs(ctx, def, execCtx, skipped)
+		for _, nextID := range followed {
+			if ctx.Err() != nil {
+				return
+			}
+			nextNode := e.findNode(def, nextID)
+			if nextNode == nil {
+				continue
+			}
+			if NodeType(nextNode.Type) == NodeTypeMerge {
+				e.handleMergeInput(ctx, def, nextNode, execCtx, results[j])
+			} else {
+				e.dispatchNode(ctx, def, nextNode, execCtx, results[j])
+			}
+		}
+	}
+}
+
+// runLoopBody runs one body node of a loop for a single item. A false runIf
+// passes the item through unchanged.
+func (e *Engine) runLoopBody(ctx context.Context, def *WorkflowDefinition, node *NodeDefinition, execCtx *ExecutionContext, item interface{}) (*NodeOutput, error) {
+	if !e.shouldRun(node, item) {
+		return &NodeOutput{Data: item}, nil
+	}
+	handler, ok := e.registry.Get(NodeType(node.Type))
+	if !ok {
+		return nil, fmt.Errorf("unknown node type: %s", node.Type)
+	}
+	return e.runHandler(ctx, def, node, handler, execCtx, item)
+}
@@ -0,0 +1,179 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// loopProbe records what the loop body and the node after the loop saw.
+type loopProbe struct {
+	calls   atomic.Int32
+	running atomic.Int32
+	peak    atomic.Int32
+
+	mu    sync.Mutex
+	after interface{}
+}
+
+// newLoopEngine registers start -> loop -> double -> after, where double
+// multiplies each item by two (failing for failOn) and after records its input.
+func newLoopEngine(t *testing.T, loopConfig map[string]interface{}, failOn int) (*Engine, *loopProbe) {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	probe := &loopProbe{}
+	engine := NewEngine(gocmd.EventBus())
+	engine.RegisterNodeHandler("double", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		probe.calls.Add(1)
+		n := probe.running.Add(1)
+		defer probe.running.Add(-1)
+		for {
+			peak := probe.peak.Load()
+			if n <= peak || probe.peak.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		item, _ := input.Data.(int)
+		if item == failOn {
+			return nil, errors.New("bad item")
+		}
+		return &NodeOutput{Data: item * 2}, nil
+	})
+	engine.RegisterNodeHandler("after", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		probe.mu.Lock()
+		probe.after = input.Data
+		probe.mu.Unlock()
+		return &NodeOutput{Data: input.Data}, nil
+	})
+
+	def := &WorkflowDefinition{
+		ID: "looping",
+		Nodes: []NodeDefinition{
+			{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"loop"}},
+			{ID: "loop", Type: string(NodeTypeLoop), Config: loopConfig, Next: []string{"double"}},
+			{ID: "double", Type: "double", Next: []string{"after"}},
+			{ID: "after", Type: "after"},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return engine, probe
+}
+
+func (p *loopProbe) afterInput() interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.after
+}
+
+func TestEngine_Loop_Sequential(t *testing.T) {
+	engine, probe := newLoopEngine(t, map[string]interface{}{"items": "orders"}, -1)
+
+	state := runGuarded(t, engine, "looping", map[string]interface{}{"orders": []interface{}{1, 2, 3}})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("status = %s, errors = %v", state.Status, state.Context.Errors)
+	}
+	want := []interface{}{2, 4, 6}
+	if got := state.Context.NodeOutputs["loop"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("loop output = %v, want %v", got, want)
+	}
+	if got := probe.afterInput(); !reflect.DeepEqual(got, want) {
+		t.Errorf("node after the loop got %v, want %v", got, want)
+	}
+	if n := probe.calls.Load(); n != 3 {
+		t.Errorf("body ran %d times, want 3", n)
+	}
+	if peak := probe.peak.Load(); peak != 1 {
+		t.Errorf("peak concurrency = %d, want 1", peak)
+	}
+}
+
+func TestEngine_Loop_BatchSize(t *testing.T) {
+	engine, probe := newLoopEngine(t, map[string]interface{}{"batchSize": 2}, -1)
+
+	state := runGuarded(t, engine, "looping", []interface{}{1, 2, 3, 4, 5})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("status = %s, errors = %v", state.Status, state.Context.Errors)
+	}
+	want := []interface{}{2, 4, 6, 8, 10}
+	if got := state.Context.NodeOutputs["loop"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("loop output = %v, want %v (item order)", got, want)
+	}
+	if peak := probe.peak.Load(); peak != 2 {
+		t.Errorf("peak concurrency = %d, want 2", peak)
+	}
+}
+
+func TestEngine_Loop_ItemError(t *testing.T) {
+	engine, probe := newLoopEngine(t, map[string]interface{}{"batchSize": 3}, 2)
+
+	state := runGuarded(t, engine, "looping", []interface{}{1, 2, 3})
+	if state.Status != ExecutionStatusFailed {
+		t.Errorf("status = %s, want failed", state.Status)
+	}
+	if got, want := state.Context.NodeOutputs["double"], []interface{}{2, nil, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("body outputs = %v, want %v", got, want)
+	}
+	if n := probe.calls.Load(); n != 3 {
+		t.Errorf("body ran %d times, want every item", n)
+	}
+	if probe.afterInput() == nil {
+		t.Error("node after the loop did not run")
+	}
+	errs := state.Context.Errors
+	if len(errs) != 1 || errs[0].NodeID != "double" || !strings.Contains(errs[0].Message, "item 1") {
+		t.Errorf("errors = %+v, want one error for item 1 of double", errs)
+	}
+}
+
+func TestEngine_Loop_FailFast(t *testing.T) {
+	engine, probe := newLoopEngine(t, map[string]interface{}{"failFast": true}, 2)
+
+	state := runGuarded(t, engine, "looping", []interface{}{1, 2, 3, 4})
+	if state.Status != ExecutionStatusFailed {
+		t.Errorf("status = %s, want failed", state.Status)
+	}
+	if n := probe.calls.Load(); n != 2 {
+		t.Errorf("body ran %d times, want the loop to stop after item 2", n)
+	}
+	if probe.afterInput() != nil {
+		t.Error("node after the loop ran despite failFast")
+	}
+	if len(state.Context.Errors) != 1 {
+		t.Errorf("errors = %+v, want one", state.Context.Errors)
+	}
+}
+
+func TestEngine_Loop_NoItems(t *testing.T) {
+	for name, input := range map[string]interface{}{
+		"empty array":  []interface{}{},
+		"not an array": map[string]interface{}{"orders": "none"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			engine, probe := newLoopEngine(t, nil, -1)
+
+			state := runGuarded(t, engine, "looping", input)
+			if state.Status != ExecutionStatusCompleted {
+				t.Fatalf("status = %s, errors = %v", state.Status, state.Context.Errors)
+			}
+			if n := probe.calls.Load(); n != 0 {
+				t.Errorf("body ran %d times, want 0", n)
+			}
+			if got := probe.afterInput(); !reflect.DeepEqual(got, []interface{}{}) {
+				t.Errorf("node after the loop got %#v, want an empty array", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// engineMetrics are the instruments of an Engine.
+// A nil *engineMetrics (EngineOptions.Metrics unset) records nothing.
+type engineMetrics struct {
+	executions        core.Counter   // fluxor_workflow_executions_total{workflow,status}
+	executionDuration core.Histogram // fluxor_workflow_execution_duration_seconds{workflow,status}
+	nodeDuration      core.Histogram // fluxor_workflow_node_duration_seconds{workflow,type}
+	nodeErrors        core.Counter   // fluxor_workflow_node_errors_total{workflow,type}
+	shedExecutions    core.Counter   // fluxor_workflow_executions_shed_total{workflow}
+}
+
+func newEngineMetrics(m core.Metrics) *engineMetrics {
+	if core.IsNoopMetrics(m) {
+		return nil
+	}
+	return &engineMetrics{
+		executions:        m.Counter("fluxor_workflow_executions_total", "Finished workflow executions, by workflow and final status", "workflow", "status"),
+		executionDuration: m.Histogram("fluxor_workflow_execution_duration_seconds", "Workflow execution duration in seconds, by workflow and final status", "workflow", "status"),
+		nodeDuration:      m.Histogram("fluxor_workflow_node_duration_seconds", "Node handler duration in seconds including retries, by workflow and node type", "workflow", "type"),
+		nodeErrors:        m.Counter("fluxor_workflow_node_errors_total", "Nodes that failed after all retries, by workflow and node type", "workflow", "type"),
+		shedExecutions:    m.Counter("fluxor_workflow_executions_shed_total", "Executions rejected with ErrEngineOverloaded, by workflow", "workflow"),
+	}
+}
+
+// finished records an execution reaching a final status.
+func (m *engineMetrics) finished(workflowID string, status ExecutionStatus, start, end time.Time) {
+	if m == nil {
+		return
+	}
+	m.executions.Add(1, workflowID, string(status))
+	m.executionDuration.Observe(end.Sub(start).Seconds(), workflowID, string(status))
+}
+
+// node records one node run (all retry attempts).
+func (m *engineMetrics) node(workflowID, nodeType string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.nodeDuration.Observe(time.Since(start).Seconds(), workflowID, nodeType)
+	if err != nil {
+		m.nodeErrors.Add(1, workflowID, nodeType)
+	}
+}
+
+// shed records an execution rejected because the engine was overloaded.
+func (m *engineMetrics) shed(workflowID string) {
+	if m == nil {
+		return
+	}
+	m.shedExecutions.Add(1, workflowID)
+}
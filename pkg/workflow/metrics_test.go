@@ -0,0 +1,82 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// countingMetrics counts observations keyed by "name{v1,v2}".
+type countingMetrics struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+type countingInstrument struct {
+	m    *countingMetrics
+	name string
+}
+
+func (i countingInstrument) observe(lv []string) {
+	i.m.mu.Lock()
+	defer i.m.mu.Unlock()
+	i.m.counts[i.name+"{"+strings.Join(lv, ",")+"}"]++
+}
+
+func (i countingInstrument) Add(_ float64, lv ...string)     { i.observe(lv) }
+func (i countingInstrument) Set(_ float64, lv ...string)     { i.observe(lv) }
+func (i countingInstrument) Observe(_ float64, lv ...string) { i.observe(lv) }
+
+func (m *countingMetrics) Counter(name, _ string, _ ...string) core.Counter {
+	return countingInstrument{m, name}
+}
+func (m *countingMetrics) Gauge(name, _ string, _ ...string) core.Gauge {
+	return countingInstrument{m, name}
+}
+func (m *countingMetrics) Histogram(name, _ string, _ ...string) core.Histogram {
+	return countingInstrument{m, name}
+}
+
+func (m *countingMetrics) count(key string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[key]
+}
+
+func TestEngine_Metrics(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	metrics := &countingMetrics{counts: make(map[string]int)}
+	engine := NewEngineWithOptions(gocmd.EventBus(), EngineOptions{Metrics: metrics})
+	engine.RegisterNodeHandler("fail", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		return nil, errors.New("boom")
+	})
+	for _, def := range []*WorkflowDefinition{
+		{ID: "ok", Nodes: []NodeDefinition{{ID: "a", Type: string(NodeTypeNoOp), Next: []string{"b"}}, {ID: "b", Type: string(NodeTypeNoOp)}}},
+		{ID: "bad", Nodes: []NodeDefinition{{ID: "a", Type: "fail"}}},
+	} {
+		if err := engine.RegisterWorkflow(def); err != nil {
+			t.Fatalf("RegisterWorkflow() error = %v", err)
+		}
+	}
+
+	runGuarded(t, engine, "ok", map[string]interface{}{})
+	runGuarded(t, engine, "bad", map[string]interface{}{})
+
+	for key, want := range map[string]int{
+		"fluxor_workflow_executions_total{ok,completed}":           1,
+		"fluxor_workflow_execution_duration_seconds{ok,completed}": 1,
+		"fluxor_workflow_node_duration_seconds{ok,noop}":           2,
+		"fluxor_workflow_node_errors_total{bad,fail}":              1,
+		"fluxor_workflow_executions_total{bad,failed}":             1,
+	} {
+		if got := metrics.count(key); got != want {
+			t.Errorf("%s = %d, want %d", key, got, want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -13,7 +15,8 @@ func noOpHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 
 // setHandler sets variables in the execution context.
 func setHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
-	// Config should contain "values" map
+	// Config should contain "values" map; keys may be paths like
+	// "order.status" or "items[0].qty", which set nested values
 	values, ok := input.Config["values"].(map[string]interface{})
 	if !ok {
 		return &NodeOutput{Data: input.Data}, nil
@@ -27,7 +30,9 @@ func setHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 		}
 	}
 	for k, v := range values {
-		output[k] = v
+		if err := setField(output, k, v); err != nil {
+			return nil, err
+		}
 	}
 
 	return &NodeOutput{Data: output}, nil
@@ -36,8 +41,8 @@ func setHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 // conditionHandler evaluates a condition and determines next nodes.
 func conditionHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 	// Config should contain:
-	// - "field": field to check
-	// - "operator": eq, ne, gt, lt, gte, lte, contains, exists
+	// - "field": field to check; a path like "order.customer.tier" or "items[0].price"
+	// - "operator": eq, ne, gt, lt, gte, lte, contains, exists, regex, in
 	// - "value": value to compare against
 
 	field, _ := input.Config["field"].(string)
@@ -45,10 +50,7 @@ func conditionHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error
 	expectedValue := input.Config["value"]
 
 	// Get actual value from input data
-	var actualValue interface{}
-	if data, ok := input.Data.(map[string]interface{}); ok {
-		actualValue = data[field]
-	}
+	actualValue, _ := lookupField(input.Data, field)
 
 	result := evaluateCondition(actualValue, operator, expectedValue)
 
@@ -92,6 +94,17 @@ func evaluateCondition(actual interface{}, operator string, expected interface{}
 		return isEmpty(actual)
 	case "notEmpty":
 		return !isEmpty(actual)
+	case "regex", "matches":
+		pattern, ok := expected.(string)
+		if !ok || actual == nil {
+			return false
+		}
+		re, err := regexp.Compile(pattern)
+		return err == nil && re.MatchString(fmt.Sprintf("%v", actual))
+	case "in":
+		return isIn(actual, expected)
+	case "notIn":
+		return !isIn(actual, expected)
 	default:
 		return actual == expected
 	}
@@ -129,6 +142,30 @@ func contains(actual, expected interface{}) bool {
 	return false
 }
 
+// isIn reports whether actual is one of the expected values, or a substring
+// of an expected string.
+func isIn(actual, expected interface{}) bool {
+	switch e := expected.(type) {
+	case []interface{}:
+		for _, item := range e {
+			if fmt.Sprintf("%v", item) == fmt.Sprintf("%v", actual) {
+				return true
+			}
+		}
+	case []string:
+		for _, item := range e {
+			if item == fmt.Sprintf("%v", actual) {
+				return true
+			}
+		}
+	case string:
+		if a, ok := actual.(string); ok {
+			return strings.Contains(e, a)
+		}
+	}
+	return false
+}
+
 func isEmpty(v interface{}) bool {
 	if v == nil {
 		return true
@@ -176,23 +213,16 @@ func errorHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 	return nil, fmt.Errorf("%s", message)
 }
 
-// loopHandler iterates over an array and executes next nodes for each item.
+// loopHandler packages the items of an array for iteration. The engine runs
+// loop nodes itself (see executeLoop); this handler only serves callers that
+// invoke the registered handler directly.
 func loopHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 	// Config:
 	// - "items": field name containing array, or use input data directly
 	// - "batchSize": number of items to process in parallel (default: 1)
+	// - "failFast": stop the loop at the first failing item (default: false)
 
-	var items []interface{}
-
-	if itemsField, ok := input.Config["items"].(string); ok {
-		if data, ok := input.Data.(map[string]interface{}); ok {
-			if arr, ok := data[itemsField].([]interface{}); ok {
-				items = arr
-			}
-		}
-	} else if arr, ok := input.Data.([]interface{}); ok {
-		items = arr
-	}
+	items := loopItems(input.Config, input.Data)
 
 	if len(items) == 0 {
 		return &NodeOutput{Data: input.Data}, nil
@@ -245,7 +275,7 @@ func mergeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 // switchHandler provides multi-way branching based on value.
 func switchHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 	// Config:
-	// - "field": field to switch on
+	// - "field": field (or path) to switch on
 	// - "cases": map of value -> next node IDs
 	// - "default": default next node IDs
 
@@ -253,10 +283,7 @@ func switchHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 	cases, _ := input.Config["cases"].(map[string]interface{})
 	defaultNext, _ := input.Config["default"].([]interface{})
 
-	var value interface{}
-	if data, ok := input.Data.(map[string]interface{}); ok {
-		value = data[field]
-	}
+	value, _ := lookupField(input.Data, field)
 
 	valueStr := fmt.Sprintf("%v", value)
 
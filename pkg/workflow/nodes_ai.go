@@ -82,7 +82,7 @@ func AINodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 				processedMsg := make(map[string]interface{})
 				for k, v := range msgMap {
 					if str, ok := v.(string); ok {
-						processedMsg[k] = processOpenAITemplate(str, input.Data)
+						processedMsg[k] = processTemplate(str, input.Data)
 					} else {
 						processedMsg[k] = v
 					}
@@ -97,10 +97,10 @@ func AINodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 		if prompt, ok := input.Config["prompt"]; ok {
 			switch p := prompt.(type) {
 			case string:
-				promptText = processOpenAITemplate(p, input.Data)
+				promptText = processTemplate(p, input.Data)
 			case map[string]interface{}:
 				if text, ok := p["text"].(string); ok {
-					promptText = processOpenAITemplate(text, input.Data)
+					promptText = processTemplate(text, input.Data)
 				} else {
 					promptText = fmt.Sprintf("%v", p)
 				}
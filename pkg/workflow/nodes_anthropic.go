@@ -0,0 +1,220 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// anthropicVersion is the Messages API version the node speaks.
+const anthropicVersion = "2023-06-01"
+
+// AnthropicNodeHandler handles Anthropic Claude Messages API request nodes.
+func AnthropicNodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+	// Config:
+	// - "apiKey": Anthropic API key (or use $ANTHROPIC_API_KEY env var)
+	// - "baseURL": Base URL (default: https://api.anthropic.com/v1)
+	// - "model": Model name (default: claude-3-sonnet-20240229)
+	// - "system": System prompt template
+	// - "prompt": Prompt template (supports {{ $.input.text }} syntax)
+	// - "messages": Chat messages array; "system" role entries are moved to the system prompt
+	// - "temperature": 0-1 (default: API default)
+	// - "maxTokens": Max tokens (default: 1000)
+	// - "timeout": Request timeout (default: 60s)
+	// - "responseField": Field name for response (default: "response")
+	// - "extractText": Extract text from content[0].text (default: true)
+
+	// Get API key
+	apiKey, _ := input.Config["apiKey"].(string)
+	if apiKey == "" {
+		apiKey = getEnv("ANTHROPIC_API_KEY", "")
+		if apiKey == "" {
+			return nil, fmt.Errorf("anthropic node requires 'apiKey' config or ANTHROPIC_API_KEY env var")
+		}
+	}
+
+	baseURL := "https://api.anthropic.com/v1"
+	if url, ok := input.Config["baseURL"].(string); ok && url != "" {
+		baseURL = url
+	}
+
+	model := "claude-3-sonnet-20240229"
+	if m, ok := input.Config["model"].(string); ok && m != "" {
+		model = m
+	}
+
+	timeout := 60 * time.Second
+	if t, ok := input.Config["timeout"].(string); ok {
+		if d, err := time.ParseDuration(t); err == nil {
+			timeout = d
+		}
+	}
+
+	maxTokens := 1000
+	if mt, ok := input.Config["maxTokens"].(float64); ok {
+		maxTokens = int(mt)
+	} else if mt, ok := input.Config["maxTokens"].(int); ok {
+		maxTokens = mt
+	}
+
+	system, _ := input.Config["system"].(string)
+	system = processTemplate(system, input.Data)
+
+	// Build messages; the Messages API takes the system prompt separately
+	var messages []map[string]interface{}
+	if configured, ok := input.Config["messages"].([]interface{}); ok && len(configured) > 0 {
+		for _, msg := range configured {
+			msgMap, ok := msg.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			processedMsg := make(map[string]interface{}, len(msgMap))
+			for k, v := range msgMap {
+				if str, ok := v.(string); ok {
+					processedMsg[k] = processTemplate(str, input.Data)
+				} else {
+					processedMsg[k] = v
+				}
+			}
+			if processedMsg["role"] == "system" {
+				if content, ok := processedMsg["content"].(string); ok {
+					if system != "" {
+						system += "\n\n"
+					}
+					system += content
+				}
+				continue
+			}
+			messages = append(messages, processedMsg)
+		}
+	} else {
+		messages = []map[string]interface{}{
+			{"role": "user", "content": anthropicPrompt(input)},
+		}
+	}
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("anthropic node requires at least one non-system message")
+	}
+
+	requestBody := map[string]interface{}{
+		"model":      model,
+		"max_tokens": maxTokens,
+		"messages":   messages,
+	}
+	if system != "" {
+		requestBody["system"] = system
+	}
+	if temp, ok := input.Config["temperature"].(float64); ok {
+		requestBody["temperature"] = temp
+	}
+
+	jsonBody, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, "POST", baseURL+"/messages", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", anthropicVersion)
+
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("anthropic request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Error bodies look like {"type":"error","error":{"type":"...","message":"..."}}
+	if resp.StatusCode != http.StatusOK {
+		var errorResp map[string]interface{}
+		if err := json.Unmarshal(respBody, &errorResp); err == nil {
+			if errorMsg, ok := errorResp["error"].(map[string]interface{}); ok {
+				if message, ok := errorMsg["message"].(string); ok {
+					return nil, fmt.Errorf("anthropic API error: %s", message)
+				}
+			}
+		}
+		return nil, fmt.Errorf("anthropic API error: status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+
+	var responseData map[string]interface{}
+	if err := json.Unmarshal(respBody, &responseData); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	extractText := true
+	if et, ok := input.Config["extractText"].(bool); ok {
+		extractText = et
+	}
+
+	responseField := "response"
+	if rf, ok := input.Config["responseField"].(string); ok && rf != "" {
+		responseField = rf
+	}
+
+	output := make(map[string]interface{})
+	if data, ok := input.Data.(map[string]interface{}); ok {
+		for k, v := range data {
+			output[k] = v
+		}
+	}
+
+	if extractText {
+		// Extract text from content[0].text
+		if content, ok := responseData["content"].([]interface{}); ok && len(content) > 0 {
+			if block, ok := content[0].(map[string]interface{}); ok {
+				if text, ok := block["text"].(string); ok {
+					output[responseField] = text
+				}
+			}
+		}
+	}
+
+	output["_anthropic_response"] = responseData
+	output["_anthropic_usage"] = responseData["usage"]
+
+	return &NodeOutput{Data: output}, nil
+}
+
+// anthropicPrompt returns the templated "prompt" config, falling back to the
+// input's text or prompt field, like the OpenAI node.
+func anthropicPrompt(input *NodeInput) string {
+	if prompt, ok := input.Config["prompt"]; ok {
+		switch p := prompt.(type) {
+		case string:
+			return processTemplate(p, input.Data)
+		case map[string]interface{}:
+			if text, ok := p["text"].(string); ok {
+				return processTemplate(text, input.Data)
+			}
+			return fmt.Sprintf("%v", p)
+		default:
+			return fmt.Sprintf("%v", prompt)
+		}
+	}
+	if data, ok := input.Data.(map[string]interface{}); ok {
+		if text, ok := data["text"].(string); ok {
+			return text
+		}
+		if text, ok := data["prompt"].(string); ok {
+			return text
+		}
+	}
+	return fmt.Sprintf("%v", input.Data)
+}
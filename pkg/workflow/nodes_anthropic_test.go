@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnthropicNodeHandler_Messages(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages" {
+			t.Errorf("path = %s, want /messages", r.URL.Path)
+		}
+		if r.Header.Get("x-api-key") != "test-key" || r.Header.Get("anthropic-version") != anthropicVersion {
+			t.Errorf("headers = %v", r.Header)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"Ticket is about billing."}],"usage":{"input_tokens":12,"output_tokens":5}}`))
+	}))
+	defer server.Close()
+
+	output, err := AnthropicNodeHandler(context.Background(), &NodeInput{
+		Config: map[string]interface{}{
+			"apiKey":        "test-key",
+			"baseURL":       server.URL,
+			"model":         "claude-test",
+			"system":        "You help {{ $.input.team }}.",
+			"prompt":        "Summarize: {{ $.input.ticket }}",
+			"maxTokens":     float64(200),
+			"responseField": "summary",
+		},
+		Data: map[string]interface{}{"team": "support", "ticket": "I was charged twice"},
+	})
+	if err != nil {
+		t.Fatalf("AnthropicNodeHandler() error = %v", err)
+	}
+
+	if got["model"] != "claude-test" || got["max_tokens"] != float64(200) || got["system"] != "You help support." {
+		t.Errorf("request = %v", got)
+	}
+	messages, _ := got["messages"].([]interface{})
+	if len(messages) != 1 {
+		t.Fatalf("messages = %v, want one user message", got["messages"])
+	}
+	if msg := messages[0].(map[string]interface{}); msg["role"] != "user" || msg["content"] != "Summarize: I was charged twice" {
+		t.Errorf("message = %v", msg)
+	}
+
+	data := output.Data.(map[string]interface{})
+	if data["summary"] != "Ticket is about billing." {
+		t.Errorf("summary = %v", data["summary"])
+	}
+	if data["team"] != "support" || data["_anthropic_usage"] == nil {
+		t.Errorf("output = %v, want the input fields and usage", data)
+	}
+}
+
+func TestAnthropicNodeHandler_SystemMessagesAndErrors(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens: too large"}}`))
+	}))
+	defer server.Close()
+
+	_, err := AnthropicNodeHandler(context.Background(), &NodeInput{
+		Config: map[string]interface{}{
+			"apiKey":  "test-key",
+			"baseURL": server.URL,
+			"messages": []interface{}{
+				map[string]interface{}{"role": "system", "content": "Be brief."},
+				map[string]interface{}{"role": "user", "content": "Hi {{ name }}"},
+			},
+		},
+		Data: map[string]interface{}{"name": "Ada"},
+	})
+	if err == nil || err.Error() != "anthropic API error: max_tokens: too large" {
+		t.Errorf("error = %v, want the API error message", err)
+	}
+	if got["system"] != "Be brief." {
+		t.Errorf("system = %v, want the system message moved out of messages", got["system"])
+	}
+	if messages, _ := got["messages"].([]interface{}); len(messages) != 1 {
+		t.Errorf("messages = %v, want only the user message", got["messages"])
+	}
+}
+
+func TestAnthropicNodeHandler_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	start := time.Now()
+	_, err := AnthropicNodeHandler(context.Background(), &NodeInput{
+		Config: map[string]interface{}{"apiKey": "test-key", "baseURL": server.URL, "prompt": "hi", "timeout": "50ms"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "anthropic request failed") {
+		t.Errorf("error = %v, want a request failure", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("request took %v, want the timeout to apply", elapsed)
+	}
+}
+
+func TestAnthropicNodeHandler_RequiresAPIKey(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	if _, err := AnthropicNodeHandler(context.Background(), &NodeInput{Config: map[string]interface{}{}}); err == nil {
+		t.Error("expected an error when apiKey is missing")
+	}
+}
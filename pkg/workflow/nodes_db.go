@@ -0,0 +1,259 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DBRegistry holds the database connections db nodes run against: named
+// connections registered by the application, and connections opened on demand
+// from a node's driver/dsn config (opened once and reused).
+type DBRegistry struct {
+	named  map[string]*sql.DB
+	opened map[string]*sql.DB // driver + "\x00" + dsn -> connection
+	mu     sync.RWMutex
+}
+
+// NewDBRegistry creates an empty connection registry.
+func NewDBRegistry() *DBRegistry {
+	return &DBRegistry{
+		named:  make(map[string]*sql.DB),
+		opened: make(map[string]*sql.DB),
+	}
+}
+
+// Register makes db available to db nodes as Config["connection"] = name.
+// The registry does not close registered connections.
+func (r *DBRegistry) Register(name string, db *sql.DB) {
+	if name == "" {
+		panic("connection name cannot be empty")
+	}
+	if db == nil {
+		panic("connection cannot be nil")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.named[name] = db
+}
+
+// Get returns a named connection.
+func (r *DBRegistry) Get(name string) (*sql.DB, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	db, ok := r.named[name]
+	return db, ok
+}
+
+// open returns the shared connection for driver and dsn, opening it on first use.
+func (r *DBRegistry) open(driver, dsn string) (*sql.DB, error) {
+	key := driver + "\x00" + dsn
+	r.mu.RLock()
+	db, ok := r.opened[key]
+	r.mu.RUnlock()
+	if ok {
+		return db, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if db, ok := r.opened[key]; ok {
+		return db, nil
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	r.opened[key] = db
+	return db, nil
+}
+
+// Close closes the connections opened from driver/dsn configs.
+func (r *DBRegistry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var firstErr error
+	for key, db := range r.opened {
+		if err := db.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(r.opened, key)
+	}
+	return firstErr
+}
+
+// CreateDBHandler creates a db node handler using the given connection registry.
+func CreateDBHandler(registry *DBRegistry) NodeHandler {
+	return func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		// Config:
+		// - "connection": name of a registered connection, or
+		// - "driver" + "dsn": database/sql driver name and data source
+		// - "query": SQL with driver placeholders (? or $1)
+		// - "params" (or "args"): query arguments; "{{field}}" alone passes the
+		//   field's value, other strings are templated
+		// - "mode": "query" (rows) or "exec" (rowsAffected); default from the statement
+		// - "timeout": statement timeout (e.g. "5s"), on top of the node's timeout
+
+		db, err := dbConnection(registry, input.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		query, _ := input.Config["query"].(string)
+		if strings.TrimSpace(query) == "" {
+			return nil, fmt.Errorf("db node requires 'query' config")
+		}
+
+		var args []interface{}
+		params, ok := input.Config["params"].([]interface{})
+		if !ok {
+			params, ok = input.Config["args"].([]interface{})
+		}
+		if ok {
+			args = make([]interface{}, len(params))
+			for i, p := range params {
+				args[i] = dbParam(p, input.Data)
+			}
+		}
+
+		mode, _ := input.Config["mode"].(string)
+		if mode == "" {
+			mode = "exec"
+			if returnsRows(query) {
+				mode = "query"
+			}
+		}
+
+		if t, ok := input.Config["timeout"].(string); ok && t != "" {
+			timeout, err := time.ParseDuration(t)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("invalid db timeout: %s", t)
+			}
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		// ctx carries the timeouts; the driver aborts the statement when it ends
+		switch mode {
+		case "query":
+			rows, err := db.QueryContext(ctx, query, args...)
+			if err != nil {
+				return nil, fmt.Errorf("db query failed: %w", err)
+			}
+			defer rows.Close()
+
+			result, err := scanRows(rows)
+			if err != nil {
+				return nil, fmt.Errorf("db query failed: %w", err)
+			}
+			return &NodeOutput{Data: map[string]interface{}{
+				"rows":     result,
+				"rowCount": len(result),
+			}}, nil
+		case "exec":
+			res, err := db.ExecContext(ctx, query, args...)
+			if err != nil {
+				return nil, fmt.Errorf("db exec failed: %w", err)
+			}
+			output := map[string]interface{}{}
+			if n, err := res.RowsAffected(); err == nil {
+				output["rowsAffected"] = n
+			}
+			// Not every driver supports LastInsertId (e.g. PostgreSQL)
+			if id, err := res.LastInsertId(); err == nil {
+				output["lastInsertId"] = id
+			}
+			return &NodeOutput{Data: output}, nil
+		default:
+			return nil, fmt.Errorf("invalid db mode: %s (want query or exec)", mode)
+		}
+	}
+}
+
+func dbConnection(registry *DBRegistry, config map[string]interface{}) (*sql.DB, error) {
+	if name, ok := config["connection"].(string); ok && name != "" {
+		db, ok := registry.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("db connection not found: %s", name)
+		}
+		return db, nil
+	}
+
+	driver, _ := config["driver"].(string)
+	dsn, _ := config["dsn"].(string)
+	if driver == "" || dsn == "" {
+		return nil, fmt.Errorf("db node requires 'connection' or 'driver' and 'dsn' config")
+	}
+	db, err := registry.open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s connection: %w", driver, err)
+	}
+	return db, nil
+}
+
+// dbParam resolves a query parameter against the node input. A lone field
+// reference keeps the field's type, so numbers stay numbers.
+func dbParam(param interface{}, data interface{}) interface{} {
+	s, ok := param.(string)
+	if !ok {
+		return param
+	}
+	if value, ok := templateValue(s, data); ok {
+		return value
+	}
+	return processTemplate(s, data)
+}
+
+// returnsRows reports whether a statement produces a result set.
+func returnsRows(query string) bool {
+	fields := strings.Fields(strings.ToUpper(query))
+	if len(fields) == 0 {
+		return false
+	}
+	switch fields[0] {
+	case "SELECT", "WITH", "SHOW", "EXPLAIN", "PRAGMA", "VALUES", "DESCRIBE":
+		return true
+	}
+	for _, f := range fields {
+		if f == "RETURNING" {
+			return true
+		}
+	}
+	return false
+}
+
+// scanRows reads every row into a map of column name to value.
+func scanRows(rows *sql.Rows) ([]map[string]interface{}, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0)
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, col := range columns {
+			// Drivers return text columns as []byte
+			if b, ok := values[i].([]byte); ok {
+				row[col] = string(b)
+			} else {
+				row[col] = values[i]
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,177 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1) // every connection to :memory: is a separate database
+	t.Cleanup(func() { _ = db.Close() })
+	if _, err := db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, age INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func runDBNode(registry *DBRegistry, config map[string]interface{}, data interface{}) (map[string]interface{}, error) {
+	output, err := CreateDBHandler(registry)(context.Background(), &NodeInput{Config: config, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return output.Data.(map[string]interface{}), nil
+}
+
+func TestDBNode_ExecAndQuery(t *testing.T) {
+	registry := NewDBRegistry()
+	registry.Register("main", newTestDB(t))
+
+	out, err := runDBNode(registry, map[string]interface{}{
+		"connection": "main",
+		"query":      "INSERT INTO users (name, age) VALUES (?, ?)",
+		"params":     []interface{}{"{{ name }}", "{{age}}"},
+	}, map[string]interface{}{"name": "Ada", "age": 36})
+	if err != nil {
+		t.Fatalf("insert error = %v", err)
+	}
+	if out["rowsAffected"] != int64(1) || out["lastInsertId"] != int64(1) {
+		t.Errorf("insert output = %v", out)
+	}
+
+	out, err = runDBNode(registry, map[string]interface{}{
+		"connection": "main",
+		"query":      "SELECT id, name, age FROM users WHERE name = ? AND age > ?",
+		"params":     []interface{}{"{{user.name}}", float64(18)},
+	}, map[string]interface{}{"user": map[string]interface{}{"name": "Ada"}})
+	if err != nil {
+		t.Fatalf("select error = %v", err)
+	}
+	rows, _ := out["rows"].([]map[string]interface{})
+	if len(rows) != 1 || out["rowCount"] != 1 {
+		t.Fatalf("select output = %v, want one row", out)
+	}
+	if rows[0]["name"] != "Ada" || rows[0]["age"] != int64(36) || rows[0]["id"] != int64(1) {
+		t.Errorf("row = %v", rows[0])
+	}
+
+	out, err = runDBNode(registry, map[string]interface{}{
+		"connection": "main",
+		"query":      "UPDATE users SET age = age + 1",
+	}, nil)
+	if err != nil || out["rowsAffected"] != int64(1) {
+		t.Errorf("update output = %v, error = %v", out, err)
+	}
+}
+
+func TestDBNode_DriverAndDSN(t *testing.T) {
+	registry := NewDBRegistry()
+	defer registry.Close()
+	config := map[string]interface{}{
+		"driver": "sqlite3",
+		"dsn":    "file:dbnode_dsn?mode=memory&cache=shared",
+		"query":  "SELECT 1 AS one",
+	}
+
+	out, err := runDBNode(registry, config, nil)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if rows := out["rows"].([]map[string]interface{}); len(rows) != 1 || rows[0]["one"] != int64(1) {
+		t.Errorf("output = %v", out)
+	}
+	if _, err := runDBNode(registry, config, nil); err != nil || len(registry.opened) != 1 {
+		t.Errorf("second run: error = %v, %d connections opened, want 1 reused", err, len(registry.opened))
+	}
+}
+
+func TestDBNode_Errors(t *testing.T) {
+	registry := NewDBRegistry()
+	registry.Register("main", newTestDB(t))
+
+	if _, err := runDBNode(registry, map[string]interface{}{"connection": "main", "query": "SELECT * FROM missing"}, nil); err == nil || !strings.Contains(err.Error(), "no such table") {
+		t.Errorf("error = %v, want the driver error", err)
+	}
+	if _, err := runDBNode(registry, map[string]interface{}{"connection": "other", "query": "SELECT 1"}, nil); err == nil {
+		t.Error("expected an error for an unknown connection")
+	}
+	if _, err := runDBNode(registry, map[string]interface{}{"connection": "main"}, nil); err == nil {
+		t.Error("expected an error without a query")
+	}
+}
+
+func TestDBNode_HonorsNodeTimeout(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	engine := NewEngine(gocmd.EventBus())
+	registry := NewDBRegistry()
+	registry.Register("main", newTestDB(t))
+	engine.RegisterNodeHandler(NodeTypeDB, CreateDBHandler(registry))
+
+	def := &WorkflowDefinition{ID: "slow-query", Nodes: []NodeDefinition{
+		{ID: "count", Type: string(NodeTypeDB), Timeout: "50ms", Config: map[string]interface{}{
+			"connection": "main",
+			// Never terminates on its own
+			"query": "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT count(*) FROM c",
+		}},
+	}}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	state := runGuarded(t, engine, "slow-query", nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("query ran for %v, want it interrupted by the node timeout", elapsed)
+	}
+	if state.Status != ExecutionStatusFailed || len(state.Context.Errors) != 1 {
+		t.Errorf("status = %s, errors = %v, want a failed node", state.Status, state.Context.Errors)
+	}
+}
+
+func TestDBNode_ArgsAndQueryTimeout(t *testing.T) {
+	registry := NewDBRegistry()
+	registry.Register("main", newTestDB(t))
+
+	// "args" is accepted as an alias of "params"
+	if _, err := runDBNode(registry, map[string]interface{}{
+		"connection": "main",
+		"query":      "INSERT INTO users (name, age) VALUES (?, ?)",
+		"args":       []interface{}{"{{ $.input.people[0].name }}", "{{people.0.age}}"},
+	}, map[string]interface{}{"people": []interface{}{map[string]interface{}{"name": "Grace", "age": 45}}}); err != nil {
+		t.Fatalf("insert error = %v", err)
+	}
+	out, err := runDBNode(registry, map[string]interface{}{
+		"connection": "main",
+		"query":      "SELECT name, age FROM users",
+	}, nil)
+	if err != nil {
+		t.Fatalf("select error = %v", err)
+	}
+	if rows := out["rows"].([]map[string]interface{}); len(rows) != 1 || rows[0]["name"] != "Grace" || rows[0]["age"] != int64(45) {
+		t.Errorf("rows = %v", out["rows"])
+	}
+
+	start := time.Now()
+	_, err = runDBNode(registry, map[string]interface{}{
+		"connection": "main",
+		"timeout":    "50ms",
+		"query":      "WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c) SELECT count(*) FROM c",
+	}, nil)
+	if err == nil || time.Since(start) > time.Second {
+		t.Errorf("error = %v after %v, want the statement interrupted by its timeout", err, time.Since(start))
+	}
+	if _, err := runDBNode(registry, map[string]interface{}{"connection": "main", "query": "SELECT 1", "timeout": "soon"}, nil); err == nil {
+		t.Error("expected an error for an invalid timeout")
+	}
+}
@@ -0,0 +1,87 @@
+package workflow
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+)
+
+// encodeOperations maps an encode node's "operation" to its transform.
+var encodeOperations = map[string]func(string) (string, error){
+	"base64": func(s string) (string, error) {
+		return base64.StdEncoding.EncodeToString([]byte(s)), nil
+	},
+	"base64Decode": func(s string) (string, error) {
+		b, err := base64.StdEncoding.DecodeString(s)
+		return string(b), err
+	},
+	"base64url": func(s string) (string, error) {
+		return base64.RawURLEncoding.EncodeToString([]byte(s)), nil
+	},
+	"base64urlDecode": func(s string) (string, error) {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		return string(b), err
+	},
+	"hex": func(s string) (string, error) {
+		return hex.EncodeToString([]byte(s)), nil
+	},
+	"hexDecode": func(s string) (string, error) {
+		b, err := hex.DecodeString(s)
+		return string(b), err
+	},
+	"urlencode": func(s string) (string, error) {
+		return url.QueryEscape(s), nil
+	},
+	"urldecode": url.QueryUnescape,
+}
+
+// encodeOperationOf returns the transform named by an encode node's config.
+func encodeOperationOf(node *NodeDefinition) (func(string) (string, error), error) {
+	operation, _ := node.Config["operation"].(string)
+	transform, ok := encodeOperations[operation]
+	if !ok {
+		return nil, fmt.Errorf("node %s: invalid encode operation %q (want base64, base64url, hex, urlencode or their decodes)", node.ID, operation)
+	}
+	return transform, nil
+}
+
+// encodeHandler encodes or decodes a value.
+func encodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+	// Config should contain:
+	// - "operation": base64, base64Decode, base64url, base64urlDecode,
+	//   hex, hexDecode, urlencode or urldecode
+	// - "input": the value to transform, templated, e.g. "{{order.payload}}"
+	// - "outputField": where to store the result; a path like
+	//   "order.encoded" (default "encoded")
+	transform, err := encodeOperationOf(&NodeDefinition{ID: input.NodeID, Config: input.Config})
+	if err != nil {
+		return nil, err
+	}
+	tmpl, _ := input.Config["input"].(string)
+	value, ok := templateValue(tmpl, input.Data)
+	if !ok {
+		value = processTemplate(tmpl, input.Data)
+	}
+	outputField, _ := input.Config["outputField"].(string)
+	if outputField == "" {
+		outputField = "encoded"
+	}
+
+	result, err := transform(templateString(value))
+	if err != nil {
+		return nil, fmt.Errorf("node %s: %s failed: %w", input.NodeID, input.Config["operation"], err)
+	}
+
+	output := make(map[string]interface{})
+	if data, ok := input.Data.(map[string]interface{}); ok {
+		for k, v := range data {
+			output[k] = v
+		}
+	}
+	if err := setField(output, outputField, result); err != nil {
+		return nil, err
+	}
+	return &NodeOutput{Data: output}, nil
+}
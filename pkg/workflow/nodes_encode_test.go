@@ -0,0 +1,91 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func runEncodeNode(config map[string]interface{}, data interface{}) (map[string]interface{}, error) {
+	output, err := encodeHandler(context.Background(), &NodeInput{NodeID: "encode", Config: config, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return output.Data.(map[string]interface{}), nil
+}
+
+func TestEncodeNode_Base64RoundTrip(t *testing.T) {
+	data := map[string]interface{}{"order": map[string]interface{}{"payload": "héllo, wörld?"}}
+
+	encoded, err := runEncodeNode(map[string]interface{}{
+		"operation":   "base64",
+		"input":       "{{order.payload}}",
+		"outputField": "order.encoded",
+	}, data)
+	if err != nil {
+		t.Fatalf("base64 error = %v", err)
+	}
+	order := encoded["order"].(map[string]interface{})
+	if order["encoded"] != "aMOpbGxvLCB3w7ZybGQ/" || order["payload"] != "héllo, wörld?" {
+		t.Errorf("base64 output = %v", order)
+	}
+	if _, ok := data["order"].(map[string]interface{})["encoded"]; ok {
+		t.Error("base64 modified the node input")
+	}
+
+	decoded, err := runEncodeNode(map[string]interface{}{
+		"operation": "base64Decode",
+		"input":     "{{order.encoded}}",
+	}, encoded)
+	if err != nil {
+		t.Fatalf("base64Decode error = %v", err)
+	}
+	if decoded["encoded"] != "héllo, wörld?" {
+		t.Errorf("base64Decode = %v, want the original payload", decoded["encoded"])
+	}
+}
+
+func TestEncodeNode_Operations(t *testing.T) {
+	tests := []struct {
+		operation string
+		input     string
+		want      string
+	}{
+		{"urlencode", "a b&c=d/é", "a+b%26c%3Dd%2F%C3%A9"},
+		{"urldecode", "a+b%26c%3Dd%2F%C3%A9", "a b&c=d/é"},
+		{"base64url", "??>>", "Pz8-Pg"},
+		{"base64urlDecode", "Pz8-Pg", "??>>"},
+		{"hex", "hi!", "686921"},
+		{"hexDecode", "686921", "hi!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			out, err := runEncodeNode(map[string]interface{}{
+				"operation":   tt.operation,
+				"input":       "{{value}}",
+				"outputField": "result",
+			}, map[string]interface{}{"value": tt.input})
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if out["result"] != tt.want {
+				t.Errorf("result = %q, want %q", out["result"], tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeNode_Errors(t *testing.T) {
+	if _, err := runEncodeNode(map[string]interface{}{"operation": "hexDecode", "input": "zz"}, nil); err == nil || !strings.Contains(err.Error(), "hexDecode failed") {
+		t.Errorf("invalid hex error = %v", err)
+	}
+
+	engine := NewEngine(nil)
+	err := engine.RegisterWorkflow(&WorkflowDefinition{
+		ID:    "wf",
+		Nodes: []NodeDefinition{{ID: "enc", Type: "encode", Config: map[string]interface{}{"operation": "rot13"}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), `invalid encode operation "rot13"`) {
+		t.Errorf("RegisterWorkflow() error = %v, want invalid encode operation", err)
+	}
+}
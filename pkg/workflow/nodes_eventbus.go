@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/fluxorio/fluxor/pkg/core"
@@ -86,6 +87,146 @@ func CreateEventBusHandler(eventBus core.EventBus) NodeHandler {
 	}
 }
 
+// CreateEnrichHandler creates an enrich node handler that looks up data via
+// EventBus request/reply and merges the reply into the node data.
+// Replies are cached per (address, key) for the configured TTL, so repeated
+// lookups for the same key (across executions) do not hit the EventBus.
+func CreateEnrichHandler(eventBus core.EventBus) NodeHandler {
+	cache := newEnrichCache(1000)
+
+	return func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		// Config:
+		// - "address": EventBus address to query (required, templated)
+		// - "key": lookup key (required, templated, e.g. "{{customerId}}")
+		// - "field": field to store the reply under (default: "enrichment")
+		// - "ttl": cache TTL (default: 5m, "0s" disables caching)
+		// - "timeout": request timeout (default: 5s)
+		// The request body is {"key": <key>}.
+
+		address, ok := input.Config["address"].(string)
+		if !ok || address == "" {
+			return nil, fmt.Errorf("enrich node requires 'address' config")
+		}
+		address = processTemplate(address, input.Data)
+
+		keyTemplate, ok := input.Config["key"].(string)
+		if !ok || keyTemplate == "" {
+			return nil, fmt.Errorf("enrich node requires 'key' config")
+		}
+		key := processTemplate(keyTemplate, input.Data)
+
+		field := "enrichment"
+		if f, ok := input.Config["field"].(string); ok && f != "" {
+			field = f
+		}
+
+		ttl := 5 * time.Minute
+		if t, ok := input.Config["ttl"].(string); ok {
+			if d, err := time.ParseDuration(t); err == nil {
+				ttl = d
+			}
+		}
+
+		timeout := 5 * time.Second
+		if t, ok := input.Config["timeout"].(string); ok {
+			if d, err := time.ParseDuration(t); err == nil {
+				timeout = d
+			}
+		}
+
+		cacheKey := address + "|" + key
+		responseData, hit := cache.get(cacheKey)
+		if !hit {
+			reply, err := eventBus.Request(address, map[string]interface{}{"key": key}, timeout)
+			if err != nil {
+				return nil, fmt.Errorf("enrich request failed: %w", err)
+			}
+
+			if bodyBytes, ok := reply.Body().([]byte); ok {
+				if err := json.Unmarshal(bodyBytes, &responseData); err != nil {
+					responseData = string(bodyBytes)
+				}
+			} else {
+				responseData = reply.Body()
+			}
+
+			if ttl > 0 {
+				cache.set(cacheKey, responseData, ttl)
+			}
+		}
+
+		// Merge reply into a copy of the input data
+		output := make(map[string]interface{})
+		if data, ok := input.Data.(map[string]interface{}); ok {
+			for k, v := range data {
+				output[k] = v
+			}
+		} else if input.Data != nil {
+			output["input"] = input.Data
+		}
+		output[field] = responseData
+
+		return &NodeOutput{Data: output}, nil
+	}
+}
+
+// enrichCache is a bounded TTL cache for enrich node lookups.
+type enrichCache struct {
+	entries    map[string]enrichCacheEntry
+	maxEntries int
+	mu         sync.Mutex
+}
+
+type enrichCacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func newEnrichCache(maxEntries int) *enrichCache {
+	return &enrichCache{
+		entries:    make(map[string]enrichCacheEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+func (c *enrichCache) get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+func (c *enrichCache) set(key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		// Bound memory: drop expired entries first, then an arbitrary one
+		now := time.Now()
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			for k := range c.entries {
+				delete(c.entries, k)
+				break
+			}
+		}
+	}
+
+	c.entries[key] = enrichCacheEntry{value: value, expiresAt: time.Now().Add(ttl)}
+}
+
 // EventTriggerConfig configures event-based workflow triggers.
 type EventTriggerConfig struct {
 	Address    string `json:"address"`
@@ -96,13 +237,9 @@ type EventTriggerConfig struct {
 func RegisterEventTrigger(eventBus core.EventBus, engine *Engine, config EventTriggerConfig) error {
 	consumer := eventBus.Consumer(config.Address)
 	consumer.Handler(func(ctx core.FluxorContext, msg core.Message) error {
-		var input interface{}
-		if bodyBytes, ok := msg.Body().([]byte); ok {
-			if err := json.Unmarshal(bodyBytes, &input); err != nil {
-				input = string(bodyBytes)
-			}
-		} else {
-			input = msg.Body()
+		input := msg.Body()
+		if bodyBytes, ok := input.([]byte); ok {
+			input = triggerInput(bodyBytes)
 		}
 
 		execID, err := engine.ExecuteWorkflow(ctx.Context(), config.WorkflowID, input)
@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func TestEnrichNodeHandler_CachesByKey(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	eb := gocmd.EventBus()
+
+	var requests int32
+	eb.Consumer("customers.lookup").Handler(func(ctx core.FluxorContext, msg core.Message) error {
+		atomic.AddInt32(&requests, 1)
+		var req struct {
+			Key string `json:"key"`
+		}
+		if err := msg.DecodeBody(&req); err != nil {
+			return err
+		}
+		return msg.Reply(map[string]interface{}{"id": req.Key, "name": "Customer " + req.Key})
+	})
+
+	handler := CreateEnrichHandler(eb)
+	config := map[string]interface{}{
+		"address": "customers.lookup",
+		"key":     "{{customerId}}",
+		"field":   "customer",
+		"ttl":     "1m",
+	}
+
+	run := func(customerID string) map[string]interface{} {
+		t.Helper()
+		output, err := handler(context.Background(), &NodeInput{
+			Data:   map[string]interface{}{"orderId": "o-1", "customerId": customerID},
+			Config: config,
+		})
+		if err != nil {
+			t.Fatalf("enrich handler error = %v", err)
+		}
+		data, ok := output.Data.(map[string]interface{})
+		if !ok {
+			t.Fatalf("output data type = %T, want map", output.Data)
+		}
+		return data
+	}
+
+	first := run("c-42")
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("requests after first run = %d, want 1", got)
+	}
+	customer, _ := first["customer"].(map[string]interface{})
+	if customer["name"] != "Customer c-42" {
+		t.Errorf("customer = %v, want name %q", first["customer"], "Customer c-42")
+	}
+	if first["orderId"] != "o-1" {
+		t.Errorf("orderId = %v, want original data preserved", first["orderId"])
+	}
+
+	second := run("c-42")
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("requests after cached run = %d, want 1 (cache hit)", got)
+	}
+	if second["customer"] == nil {
+		t.Error("cached run should still merge the customer field")
+	}
+
+	run("c-7")
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("requests after different key = %d, want 2", got)
+	}
+}
+
+func TestEnrichNodeHandler_ConfigValidation(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+
+	handler := CreateEnrichHandler(gocmd.EventBus())
+
+	if _, err := handler(context.Background(), &NodeInput{Config: map[string]interface{}{"key": "k"}}); err == nil {
+		t.Error("enrich without address should fail")
+	}
+	if _, err := handler(context.Background(), &NodeInput{Config: map[string]interface{}{"address": "a.b"}}); err == nil {
+		t.Error("enrich without key should fail")
+	}
+}
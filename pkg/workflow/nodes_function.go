@@ -184,16 +184,16 @@ func MapNodeHandler(registry *FunctionRegistry) NodeHandler {
 // FilterNodeHandler filters an array based on a condition.
 func FilterNodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
 	// Config:
-	// - "items": field containing array
-	// - "field": field to check
+	// - "items": field (or path) containing array
+	// - "field": item field (or path) to check
 	// - "operator": comparison operator
 	// - "value": value to compare
 
 	var items []interface{}
 
 	if itemsField, ok := input.Config["items"].(string); ok {
-		if dataMap, ok := input.Data.(map[string]interface{}); ok {
-			if arr, ok := dataMap[itemsField].([]interface{}); ok {
+		if value, ok := lookupField(input.Data, itemsField); ok {
+			if arr, ok := value.([]interface{}); ok {
 				items = arr
 			}
 		}
@@ -215,7 +215,7 @@ func FilterNodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, erro
 		var actualValue interface{}
 
 		if itemMap, ok := item.(map[string]interface{}); ok {
-			actualValue = itemMap[field]
+			actualValue, _ = lookupField(itemMap, field)
 		} else if field == "" {
 			actualValue = item
 		}
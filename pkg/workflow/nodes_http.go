@@ -7,10 +7,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
 
+// DefaultDownloadDir is where HTTP nodes save "saveTo" files when
+// $FLUXOR_DOWNLOAD_DIR is not set.
+var DefaultDownloadDir = filepath.Join(os.TempDir(), "fluxor-downloads")
+
 func init() {
 	// Register HTTP node handler
 }
@@ -24,6 +30,10 @@ func HTTPNodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error)
 	// - "body": request body
 	// - "timeout": request timeout (default: 30s)
 	// - "responseType": "json" (default), "text", "binary"
+	// - "maxResponseBytes": fail if the response body is larger (default: no limit)
+	// - "saveTo": stream a 2xx body to this path, relative to the download
+	//   directory ($FLUXOR_DOWNLOAD_DIR or DefaultDownloadDir), instead of memory
+	// - "debug": log the request and response (see EngineOptions.DebugHTTP)
 
 	url, ok := input.Config["url"].(string)
 	if !ok || url == "" {
@@ -46,18 +56,18 @@ func HTTPNodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error)
 	}
 
 	// Prepare body
-	var bodyReader io.Reader
+	var reqBody []byte
 	if body := input.Config["body"]; body != nil {
 		switch b := body.(type) {
 		case string:
-			bodyReader = strings.NewReader(processTemplate(b, input.Data))
+			reqBody = []byte(processTemplate(b, input.Data))
 		case map[string]interface{}:
 			processedBody := processTemplateMap(b, input.Data)
 			jsonBody, err := json.Marshal(processedBody)
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal body: %w", err)
 			}
-			bodyReader = bytes.NewReader(jsonBody)
+			reqBody = jsonBody
 		}
 	} else if method == "POST" || method == "PUT" || method == "PATCH" {
 		// Use input data as body
@@ -66,9 +76,13 @@ func HTTPNodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error)
 			if err != nil {
 				return nil, fmt.Errorf("failed to marshal input data: %w", err)
 			}
-			bodyReader = bytes.NewReader(jsonBody)
+			reqBody = jsonBody
 		}
 	}
+	var bodyReader io.Reader
+	if reqBody != nil {
+		bodyReader = bytes.NewReader(reqBody)
+	}
 
 	// Create request
 	reqCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -87,19 +101,67 @@ func HTTPNodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error)
 		}
 	}
 
-	// Execute request
-	client := &http.Client{Timeout: timeout}
-	resp, err := client.Do(req)
+	debug := httpDebugLogger(ctx, input)
+	if debug != nil {
+		debug.Debug(formatHTTPRequest(input.NodeID, req, reqBody))
+	}
+
+	// Execute request; HTTP nodes of one execution share cookies and connections
+	// (the timeout is enforced by reqCtx)
+	resp, err := httpClientFor(ctx, input).Do(req)
 	if err != nil {
+		if debug != nil {
+			debug.Debug(fmt.Sprintf("http node %s: request failed: %v", input.NodeID, err))
+		}
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	var maxBytes int64
+	if mb, ok := input.Config["maxResponseBytes"].(float64); ok {
+		maxBytes = int64(mb)
+	} else if mb, ok := input.Config["maxResponseBytes"].(int); ok {
+		maxBytes = int64(mb)
+	}
+	if maxBytes > 0 && resp.ContentLength > maxBytes {
+		return nil, fmt.Errorf("response body of %d bytes exceeds maxResponseBytes %d", resp.ContentLength, maxBytes)
+	}
+	var body io.Reader = resp.Body
+	if maxBytes > 0 {
+		// Read one byte past the limit to tell "exactly max" from "too large"
+		body = io.LimitReader(resp.Body, maxBytes+1)
+	}
+
+	if saveTo, ok := input.Config["saveTo"].(string); ok && saveTo != "" && resp.StatusCode/100 == 2 {
+		file, err := saveResponse(body, processTemplate(saveTo, input.Data), maxBytes)
+		if err != nil {
+			return nil, err
+		}
+		file["contentType"] = resp.Header.Get("Content-Type")
+		if debug != nil {
+			debug.Debug(formatHTTPResponse(input.NodeID, resp, []byte(fmt.Sprintf("<saved to %s>", file["path"]))))
+		}
+		return &NodeOutput{
+			Data: map[string]interface{}{
+				"statusCode": resp.StatusCode,
+				"headers":    headerToMap(resp.Header),
+				"file":       file,
+				"_input":     input.Data,
+			},
+		}, nil
+	}
+
 	// Read response
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	if debug != nil {
+		debug.Debug(formatHTTPResponse(input.NodeID, resp, respBody))
+	}
+	if maxBytes > 0 && int64(len(respBody)) > maxBytes {
+		return nil, fmt.Errorf("response body exceeds maxResponseBytes %d", maxBytes)
+	}
 
 	// Parse response based on type
 	responseType := "json"
@@ -132,28 +194,49 @@ func HTTPNodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error)
 	}, nil
 }
 
-// processTemplate replaces {{field}} placeholders with values from data.
-func processTemplate(template string, data interface{}) string {
-	dataMap, ok := data.(map[string]interface{})
-	if !ok {
-		return template
+// saveResponse streams body to name inside the download directory and
+// returns the file's path and size. Names that would leave the directory are
+// rejected, and a partial file is removed on error.
+func saveResponse(body io.Reader, name string, maxBytes int64) (map[string]interface{}, error) {
+	if !filepath.IsLocal(name) {
+		return nil, fmt.Errorf("invalid saveTo path %q: must be relative to the download directory", name)
+	}
+	dir := getEnv("FLUXOR_DOWNLOAD_DIR", DefaultDownloadDir)
+	if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create download directory: %w", err)
 	}
 
-	result := template
-	for key, value := range dataMap {
-		placeholder := fmt.Sprintf("{{%s}}", key)
-		result = strings.ReplaceAll(result, placeholder, fmt.Sprintf("%v", value))
+	// os.Root also refuses symlinks that point outside dir
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open download directory: %w", err)
+	}
+	defer root.Close()
 
-		// Also support dot notation for nested access
-		if nested, ok := value.(map[string]interface{}); ok {
-			for nestedKey, nestedValue := range nested {
-				nestedPlaceholder := fmt.Sprintf("{{%s.%s}}", key, nestedKey)
-				result = strings.ReplaceAll(result, nestedPlaceholder, fmt.Sprintf("%v", nestedValue))
-			}
-		}
+	f, err := root.Create(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	n, err := io.Copy(f, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && maxBytes > 0 && n > maxBytes {
+		err = fmt.Errorf("response body exceeds maxResponseBytes %d", maxBytes)
+	}
+	if err != nil {
+		_ = root.Remove(name)
+		return nil, fmt.Errorf("failed to save response to %s: %w", name, err)
 	}
 
-	return result
+	path, err := filepath.Abs(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"path": path,
+		"size": n,
+	}, nil
 }
 
 func processTemplateMap(m map[string]interface{}, data interface{}) map[string]interface{} {
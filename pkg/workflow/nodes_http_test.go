@@ -0,0 +1,234 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// payloadServer serves size bytes on /file; /chunked sends them without a
+// Content-Length.
+func payloadServer(t *testing.T, size int) (*httptest.Server, []byte) {
+	t.Helper()
+	payload := bytes.Repeat([]byte("0123456789"), size/10+1)[:size]
+	mux := http.NewServeMux()
+	mux.HandleFunc("/file", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(payload)
+	})
+	mux.HandleFunc("/chunked", func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		_, _ = w.Write(payload)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, payload
+}
+
+func runHTTPNode(t *testing.T, config map[string]interface{}, data interface{}) (map[string]interface{}, error) {
+	t.Helper()
+	output, err := HTTPNodeHandler(context.Background(), &NodeInput{Data: data, Config: config})
+	if err != nil {
+		return nil, err
+	}
+	return output.Data.(map[string]interface{}), nil
+}
+
+func TestHTTPNode_MaxResponseBytes(t *testing.T) {
+	server, payload := payloadServer(t, 4096)
+
+	for _, path := range []string{"/file", "/chunked"} {
+		_, err := runHTTPNode(t, map[string]interface{}{
+			"url":              server.URL + path,
+			"maxResponseBytes": float64(1024),
+		}, nil)
+		if err == nil || !strings.Contains(err.Error(), "exceeds maxResponseBytes") {
+			t.Errorf("%s: error = %v, want maxResponseBytes error", path, err)
+		}
+	}
+
+	out, err := runHTTPNode(t, map[string]interface{}{
+		"url":              server.URL + "/file",
+		"responseType":     "text",
+		"maxResponseBytes": len(payload),
+	}, nil)
+	if err != nil {
+		t.Fatalf("body of exactly maxResponseBytes: error = %v", err)
+	}
+	if out["body"] != string(payload) {
+		t.Errorf("body has %d bytes, want %d", len(out["body"].(string)), len(payload))
+	}
+}
+
+func TestHTTPNode_SaveTo(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FLUXOR_DOWNLOAD_DIR", dir)
+	server, payload := payloadServer(t, 64*1024)
+
+	out, err := runHTTPNode(t, map[string]interface{}{
+		"url":    server.URL + "/file",
+		"saveTo": "reports/{{name}}.bin",
+	}, map[string]interface{}{"name": "daily"})
+	if err != nil {
+		t.Fatalf("HTTPNodeHandler() error = %v", err)
+	}
+	if _, ok := out["body"]; ok {
+		t.Error("saveTo output should not include the body")
+	}
+
+	file := out["file"].(map[string]interface{})
+	want := filepath.Join(dir, "reports", "daily.bin")
+	if file["path"] != want {
+		t.Errorf("path = %v, want %s", file["path"], want)
+	}
+	if file["size"] != int64(len(payload)) || file["contentType"] != "application/octet-stream" {
+		t.Errorf("file = %v, want size %d and the response content type", file, len(payload))
+	}
+	saved, err := os.ReadFile(want)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !bytes.Equal(saved, payload) {
+		t.Errorf("saved %d bytes that differ from the %d served", len(saved), len(payload))
+	}
+
+	// Error responses are returned as usual rather than saved
+	out, err = runHTTPNode(t, map[string]interface{}{"url": server.URL + "/missing", "saveTo": "missing.bin"}, nil)
+	if err != nil || out["statusCode"] != http.StatusNotFound {
+		t.Errorf("404 with saveTo: output = %v, error = %v", out, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "missing.bin")); !os.IsNotExist(err) {
+		t.Error("404 response was saved")
+	}
+}
+
+func TestHTTPNode_SaveToLimitAndSandbox(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("FLUXOR_DOWNLOAD_DIR", dir)
+	server, _ := payloadServer(t, 4096)
+
+	_, err := runHTTPNode(t, map[string]interface{}{
+		"url":              server.URL + "/chunked",
+		"saveTo":           "big.bin",
+		"maxResponseBytes": 1024,
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "exceeds maxResponseBytes") {
+		t.Errorf("error = %v, want maxResponseBytes error", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "big.bin")); !os.IsNotExist(err) {
+		t.Error("partial download was left behind")
+	}
+
+	for _, name := range []string{"../escape.bin", "/etc/escape.bin", "a/../../escape.bin"} {
+		if _, err := runHTTPNode(t, map[string]interface{}{"url": server.URL + "/file", "saveTo": name}, nil); err == nil {
+			t.Errorf("saveTo %q outside the download directory should fail", name)
+		}
+	}
+	if err := os.Symlink(t.TempDir(), filepath.Join(dir, "link")); err == nil {
+		if _, err := runHTTPNode(t, map[string]interface{}{"url": server.URL + "/file", "saveTo": "link/escape.bin"}, nil); err == nil {
+			t.Error("saveTo through a symlink out of the download directory should fail")
+		}
+	}
+}
+
+// runHTTPDebugNode runs one HTTP node with config through an engine and
+// returns what it logged at debug level.
+func runHTTPDebugNode(t *testing.T, opts EngineOptions, config map[string]interface{}) string {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	logger := newRecordingLogger()
+	opts.Logger = logger
+	engine := NewEngineWithOptions(gocmd.EventBus(), opts)
+	engine.RegisterNodeHandler(NodeTypeHTTP, HTTPNodeHandler)
+	if err := engine.RegisterWorkflow(&WorkflowDefinition{
+		ID:    "debug",
+		Nodes: []NodeDefinition{{ID: "fetch", Type: string(NodeTypeHTTP), Config: config}},
+	}); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	execID, err := engine.ExecuteWorkflow(context.Background(), "debug", map[string]interface{}{"orderId": "o-7"})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := engine.AwaitExecution(ctx, execID); err != nil {
+		t.Fatalf("AwaitExecution() error = %v", err)
+	}
+	for _, entry := range logger.logged() {
+		if entry.level == "DEBUG" && entry.fields["nodeId"] != "fetch" {
+			t.Errorf("debug line %q has nodeId %v, want fetch", entry.message, entry.fields["nodeId"])
+		}
+	}
+	return logger.messages("DEBUG")
+}
+
+func TestHTTPNode_DebugLogRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=s3cr3t-session")
+		w.Header().Set("X-Request-Id", "req-9")
+		_, _ = w.Write([]byte(`{"ok":true,"padding":"` + strings.Repeat("x", 3000) + `"}`))
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct {
+		name      string
+		opts      EngineOptions
+		nodeDebug bool
+	}{
+		{"engine", EngineOptions{DebugHTTP: true}, false},
+		{"node", EngineOptions{}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{
+				"url":    server.URL + "/orders/{{orderId}}?api_key=k-123&page=2",
+				"method": "POST",
+				"headers": map[string]interface{}{
+					"Authorization": "Bearer tok-abc",
+					"X-Trace":       "t-1",
+				},
+				"body": map[string]interface{}{"note": "rush"},
+			}
+			if tt.nodeDebug {
+				config["debug"] = true
+			}
+
+			log := runHTTPDebugNode(t, tt.opts, config)
+			for _, want := range []string{
+				"http node fetch: request POST " + server.URL + "/orders/o-7?api_key=%5BREDACTED%5D&page=2",
+				"Authorization: [REDACTED]",
+				"X-Trace: t-1",
+				`body="{\"note\":\"rush\"}"`,
+				"http node fetch: response 200",
+				"Set-Cookie: [REDACTED]",
+				"X-Request-Id: req-9",
+				"(3024 bytes)",
+			} {
+				if !strings.Contains(log, want) {
+					t.Errorf("debug log missing %q:\n%s", want, log)
+				}
+			}
+			for _, secret := range []string{"tok-abc", "k-123", "s3cr3t-session"} {
+				if strings.Contains(log, secret) {
+					t.Errorf("debug log leaks %q:\n%s", secret, log)
+				}
+			}
+		})
+	}
+
+	if log := runHTTPDebugNode(t, EngineOptions{}, map[string]interface{}{"url": server.URL}); log != "" {
+		t.Errorf("debug log without a debug flag:\n%s", log)
+	}
+}
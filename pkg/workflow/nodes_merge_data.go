@@ -0,0 +1,381 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// jsonPatchOps are the RFC 6902 operations a merge-data node accepts.
+var jsonPatchOps = map[string]bool{"add": true, "remove": true, "replace": true, "move": true, "copy": true, "test": true}
+
+// validateMergeData checks a merge-data node's config.
+func validateMergeData(node *NodeDefinition) error {
+	_, hasNodes := node.Config["nodes"]
+	_, hasMergePatch := node.Config["mergePatch"]
+	jsonPatch, hasJSONPatch := node.Config["jsonPatch"]
+	if !hasNodes && !hasMergePatch && !hasJSONPatch {
+		return fmt.Errorf("node %s: merge-data requires 'nodes', 'mergePatch' or 'jsonPatch' config", node.ID)
+	}
+	// A templated patch is only known at run time
+	if ops, ok := jsonPatch.([]interface{}); ok {
+		for i, raw := range ops {
+			op, _ := raw.(map[string]interface{})
+			name, _ := op["op"].(string)
+			if !jsonPatchOps[name] {
+				return fmt.Errorf("node %s: jsonPatch operation %d: invalid op %q", node.ID, i, name)
+			}
+			if _, ok := op["path"].(string); !ok {
+				return fmt.Errorf("node %s: jsonPatch operation %d: missing path", node.ID, i)
+			}
+		}
+	}
+	return nil
+}
+
+// mergeDataHandler combines data: named node outputs are deep-merged over the
+// input, then an RFC 7386 merge patch and an RFC 6902 JSON Patch are applied,
+// in that order. Unlike the merge node it does not wait for anything.
+func mergeDataHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+	// Config may contain:
+	// - "nodes": IDs of completed nodes whose outputs are deep-merged over
+	//   the input, later nodes winning
+	// - "mergePatch": an RFC 7386 merge patch object; null removes a field
+	// - "jsonPatch": RFC 6902 operations, e.g.
+	//   [{"op": "replace", "path": "/order/status", "value": "paid"}]
+	// mergePatch and jsonPatch may also be a template such as "{{patch}}"
+	if err := validateMergeData(&NodeDefinition{ID: input.NodeID, Config: input.Config}); err != nil {
+		return nil, err
+	}
+	result := input.Data
+
+	if ids, ok := input.Config["nodes"].([]interface{}); ok {
+		for _, raw := range ids {
+			id, _ := raw.(string)
+			output, ok := nodeOutputOf(ctx, input, id)
+			if !ok {
+				return nil, fmt.Errorf("node %s: no output from node %q to merge", input.NodeID, id)
+			}
+			result = deepMerge(result, output)
+		}
+	}
+
+	if patch, ok := input.Config["mergePatch"]; ok {
+		result = applyMergePatch(result, configValue(patch, input.Data))
+	}
+
+	if patch, ok := input.Config["jsonPatch"]; ok {
+		ops, ok := configValue(patch, input.Data).([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("node %s: jsonPatch must be a list of operations", input.NodeID)
+		}
+		patched, err := applyJSONPatch(result, ops)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", input.NodeID, err)
+		}
+		result = patched
+	}
+
+	return &NodeOutput{Data: result}, nil
+}
+
+// configValue resolves a config value that may be a "{{path}}" template.
+func configValue(v interface{}, data interface{}) interface{} {
+	if tmpl, ok := v.(string); ok {
+		if value, ok := templateValue(tmpl, data); ok {
+			return value
+		}
+	}
+	return v
+}
+
+// nodeOutputOf returns the output of another node of the execution. The
+// engine writes outputs under its lock, so reads take it too.
+func nodeOutputOf(ctx context.Context, input *NodeInput, nodeID string) (interface{}, bool) {
+	if input.Context == nil {
+		return nil, false
+	}
+	if e, ok := ctx.Value("workflow_engine").(*Engine); ok {
+		e.mu.RLock()
+		defer e.mu.RUnlock()
+	}
+	output, ok := input.Context.NodeOutputs[nodeID]
+	return output, ok
+}
+
+// deepMerge merges src over dst: objects merge key by key, anything else in
+// src replaces dst. Neither argument is modified.
+func deepMerge(dst, src interface{}) interface{} {
+	d, ok := dst.(map[string]interface{})
+	s, ok2 := src.(map[string]interface{})
+	if !ok || !ok2 {
+		return src
+	}
+	result := make(map[string]interface{}, len(d)+len(s))
+	for k, v := range d {
+		result[k] = v
+	}
+	for k, v := range s {
+		result[k] = deepMerge(result[k], v)
+	}
+	return result
+}
+
+// applyMergePatch applies an RFC 7386 merge patch to target without
+// modifying it.
+func applyMergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	result := make(map[string]interface{})
+	if t, ok := target.(map[string]interface{}); ok {
+		for k, v := range t {
+			result[k] = v
+		}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(result, k)
+		} else {
+			result[k] = applyMergePatch(result[k], v)
+		}
+	}
+	return result
+}
+
+// applyJSONPatch applies RFC 6902 operations to a copy of doc. Operations
+// are atomic: on error doc is left as it was.
+func applyJSONPatch(doc interface{}, ops []interface{}) (interface{}, error) {
+	doc = deepCopyJSON(doc)
+	for i, raw := range ops {
+		op, _ := raw.(map[string]interface{})
+		name, _ := op["op"].(string)
+		path, ok := op["path"].(string)
+		if !ok {
+			return nil, fmt.Errorf("jsonPatch operation %d: missing path", i)
+		}
+		tokens, err := parseJSONPointer(path)
+		if err != nil {
+			return nil, fmt.Errorf("jsonPatch operation %d: %w", i, err)
+		}
+
+		switch name {
+		case "add":
+			value, ok := op["value"]
+			if !ok {
+				return nil, fmt.Errorf("jsonPatch operation %d: add requires a value", i)
+			}
+			doc, err = pointerAdd(doc, tokens, deepCopyJSON(value))
+		case "remove":
+			doc, _, err = pointerRemove(doc, tokens)
+		case "replace":
+			value, ok := op["value"]
+			if !ok {
+				return nil, fmt.Errorf("jsonPatch operation %d: replace requires a value", i)
+			}
+			if doc, _, err = pointerRemove(doc, tokens); err == nil {
+				doc, err = pointerAdd(doc, tokens, deepCopyJSON(value))
+			}
+		case "move", "copy":
+			from, ok := op["from"].(string)
+			if !ok {
+				return nil, fmt.Errorf("jsonPatch operation %d: %s requires from", i, name)
+			}
+			var fromTokens []string
+			if fromTokens, err = parseJSONPointer(from); err != nil {
+				break
+			}
+			var value interface{}
+			if name == "move" {
+				if strings.HasPrefix(path+"/", from+"/") && path != from {
+					err = fmt.Errorf("cannot move %s into itself", from)
+					break
+				}
+				doc, value, err = pointerRemove(doc, fromTokens)
+			} else if value, err = pointerGet(doc, fromTokens); err == nil {
+				value = deepCopyJSON(value)
+			}
+			if err == nil {
+				doc, err = pointerAdd(doc, tokens, value)
+			}
+		case "test":
+			var actual interface{}
+			if actual, err = pointerGet(doc, tokens); err == nil && !jsonEqual(actual, op["value"]) {
+				err = fmt.Errorf("test failed: %s is %v, want %v", path, actual, op["value"])
+			}
+		default:
+			return nil, fmt.Errorf("jsonPatch operation %d: invalid op %q", i, name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("jsonPatch operation %d (%s %s): %w", i, name, path, err)
+		}
+	}
+	return doc, nil
+}
+
+// parseJSONPointer splits an RFC 6901 pointer into unescaped tokens.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with /", pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// pointerGet returns the value at tokens.
+func pointerGet(doc interface{}, tokens []string) (interface{}, error) {
+	for _, token := range tokens {
+		switch c := doc.(type) {
+		case map[string]interface{}:
+			v, ok := c[token]
+			if !ok {
+				return nil, fmt.Errorf("path not found: %q", token)
+			}
+			doc = v
+		case []interface{}:
+			i, err := arrayIndex(token, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = c[i]
+		default:
+			return nil, fmt.Errorf("path not found: %q is not in an object or array", token)
+		}
+	}
+	return doc, nil
+}
+
+// pointerAdd adds value at tokens: it sets an object member or inserts into
+// an array ("-" appends), and returns the updated document.
+func pointerAdd(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return updateParent(doc, tokens, func(parent interface{}, key string) (interface{}, error) {
+		switch c := parent.(type) {
+		case map[string]interface{}:
+			c[key] = value
+			return c, nil
+		case []interface{}:
+			if key == "-" {
+				return append(c, value), nil
+			}
+			i, err := arrayIndex(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+			return c, nil
+		}
+		return nil, fmt.Errorf("cannot add %q to a %T", key, parent)
+	})
+}
+
+// pointerRemove removes the value at tokens and returns the updated document
+// and the removed value.
+func pointerRemove(doc interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+	var removed interface{}
+	doc, err := updateParent(doc, tokens, func(parent interface{}, key string) (interface{}, error) {
+		switch c := parent.(type) {
+		case map[string]interface{}:
+			v, ok := c[key]
+			if !ok {
+				return nil, fmt.Errorf("path not found: %q", key)
+			}
+			removed = v
+			delete(c, key)
+			return c, nil
+		case []interface{}:
+			i, err := arrayIndex(key, len(c)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = c[i]
+			return append(c[:i], c[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("cannot remove %q from a %T", key, parent)
+	})
+	return doc, removed, err
+}
+
+// updateParent walks to the container of the last token, replaces it with
+// what update returns and returns the updated document.
+func updateParent(doc interface{}, tokens []string, update func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return update(doc, tokens[0])
+	}
+	child, err := pointerGet(doc, tokens[:1])
+	if err != nil {
+		return nil, err
+	}
+	updated, err := updateParent(child, tokens[1:], update)
+	if err != nil {
+		return nil, err
+	}
+	switch c := doc.(type) {
+	case map[string]interface{}:
+		c[tokens[0]] = updated
+	case []interface{}:
+		i, _ := arrayIndex(tokens[0], len(c)-1)
+		c[i] = updated
+	}
+	return doc, nil
+}
+
+// arrayIndex parses an array index token in [0, max].
+func arrayIndex(token string, max int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	if i > max {
+		return 0, fmt.Errorf("array index %d out of range", i)
+	}
+	return i, nil
+}
+
+// deepCopyJSON copies the objects and arrays of v, so patching the copy
+// leaves v and anything sharing its values untouched.
+func deepCopyJSON(v interface{}) interface{} {
+	switch c := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(c))
+		for k, v := range c {
+			m[k] = deepCopyJSON(v)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(c))
+		for i, v := range c {
+			s[i] = deepCopyJSON(v)
+		}
+		return s
+	}
+	return v
+}
+
+// jsonEqual compares values as JSON, so 1 and 1.0 are equal.
+func jsonEqual(a, b interface{}) bool {
+	var x, y interface{}
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	if errA != nil || errB != nil || json.Unmarshal(ja, &x) != nil || json.Unmarshal(jb, &y) != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return reflect.DeepEqual(x, y)
+}
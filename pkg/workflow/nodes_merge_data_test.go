@@ -0,0 +1,177 @@
+package workflow
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func runMergeDataNode(config map[string]interface{}, data interface{}) (interface{}, error) {
+	output, err := mergeDataHandler(context.Background(), &NodeInput{NodeID: "merge-data", Config: config, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return output.Data, nil
+}
+
+func TestMergeDataNode_MergePatch(t *testing.T) {
+	data := map[string]interface{}{
+		"name":  "ada",
+		"email": "ada@example.com",
+		"prefs": map[string]interface{}{"theme": "dark", "lang": "en"},
+	}
+
+	got, err := runMergeDataNode(map[string]interface{}{
+		"mergePatch": map[string]interface{}{
+			"email": nil,
+			"role":  "admin",
+			"prefs": map[string]interface{}{"lang": nil, "tz": "UTC"},
+		},
+	}, data)
+	if err != nil {
+		t.Fatalf("mergePatch error = %v", err)
+	}
+	want := map[string]interface{}{
+		"name":  "ada",
+		"role":  "admin",
+		"prefs": map[string]interface{}{"theme": "dark", "tz": "UTC"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergePatch = %v, want %v", got, want)
+	}
+	if _, ok := data["email"]; !ok || len(data["prefs"].(map[string]interface{})) != 2 {
+		t.Errorf("mergePatch modified the node input: %v", data)
+	}
+
+	// The patch can come from the data
+	got, err = runMergeDataNode(map[string]interface{}{"mergePatch": "{{changes}}"}, map[string]interface{}{
+		"status":  "new",
+		"changes": map[string]interface{}{"status": "paid", "changes": nil},
+	})
+	if err != nil {
+		t.Fatalf("templated mergePatch error = %v", err)
+	}
+	if !reflect.DeepEqual(got, map[string]interface{}{"status": "paid"}) {
+		t.Errorf("templated mergePatch = %v", got)
+	}
+}
+
+func TestMergeDataNode_JSONPatch(t *testing.T) {
+	data := map[string]interface{}{
+		"order": map[string]interface{}{
+			"status": "new",
+			"items":  []interface{}{"a", "c"},
+			"note":   "call first",
+			"a/b":    1,
+		},
+	}
+
+	got, err := runMergeDataNode(map[string]interface{}{
+		"jsonPatch": []interface{}{
+			map[string]interface{}{"op": "test", "path": "/order/status", "value": "new"},
+			map[string]interface{}{"op": "replace", "path": "/order/status", "value": "paid"},
+			map[string]interface{}{"op": "add", "path": "/order/items/1", "value": "b"},
+			map[string]interface{}{"op": "add", "path": "/order/items/-", "value": "d"},
+			map[string]interface{}{"op": "remove", "path": "/order/note"},
+			map[string]interface{}{"op": "test", "path": "/order/a~1b", "value": 1.0},
+			map[string]interface{}{"op": "move", "from": "/order/a~1b", "path": "/order/count"},
+			map[string]interface{}{"op": "copy", "from": "/order/status", "path": "/status"},
+		},
+	}, data)
+	if err != nil {
+		t.Fatalf("jsonPatch error = %v", err)
+	}
+	want := map[string]interface{}{
+		"status": "paid",
+		"order": map[string]interface{}{
+			"status": "paid",
+			"items":  []interface{}{"a", "b", "c", "d"},
+			"count":  1,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("jsonPatch = %v, want %v", got, want)
+	}
+	order := data["order"].(map[string]interface{})
+	if order["status"] != "new" || len(order["items"].([]interface{})) != 2 || order["note"] == nil {
+		t.Errorf("jsonPatch modified the node input: %v", data)
+	}
+}
+
+func TestMergeDataNode_JSONPatchErrors(t *testing.T) {
+	data := map[string]interface{}{"status": "new", "items": []interface{}{"a"}}
+	tests := []struct {
+		op   map[string]interface{}
+		want string
+	}{
+		{map[string]interface{}{"op": "test", "path": "/status", "value": "paid"}, "test failed"},
+		{map[string]interface{}{"op": "remove", "path": "/missing"}, "path not found"},
+		{map[string]interface{}{"op": "replace", "path": "/items/3", "value": "b"}, "out of range"},
+		{map[string]interface{}{"op": "add", "path": "status"}, "must start with /"},
+	}
+	for _, tt := range tests {
+		_, err := runMergeDataNode(map[string]interface{}{"jsonPatch": []interface{}{tt.op}}, data)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v error = %v, want %q", tt.op, err, tt.want)
+		}
+	}
+
+	engine := NewEngine(nil)
+	err := engine.RegisterWorkflow(&WorkflowDefinition{
+		ID: "wf",
+		Nodes: []NodeDefinition{{ID: "patch", Type: "merge-data", Config: map[string]interface{}{
+			"jsonPatch": []interface{}{map[string]interface{}{"op": "upsert", "path": "/a"}},
+		}}},
+	})
+	if err == nil || !strings.Contains(err.Error(), `invalid op "upsert"`) {
+		t.Errorf("RegisterWorkflow() error = %v, want invalid op", err)
+	}
+}
+
+func TestMergeDataNode_DeepMergesNodeOutputs(t *testing.T) {
+	gocmd := core.NewGoCMD(context.Background())
+	defer gocmd.Close()
+	engine := NewEngine(gocmd.EventBus())
+
+	def := &WorkflowDefinition{
+		ID: "combine",
+		Nodes: []NodeDefinition{
+			{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"profile"}},
+			{ID: "profile", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{
+				"user.name": "ada", "user.plan": "free",
+			}}, Next: []string{"billing"}},
+			{ID: "billing", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{
+				"user.plan": "pro", "user.seats": 3,
+			}}, Next: []string{"combine"}},
+			{ID: "combine", Type: string(NodeTypeMergeData), Config: map[string]interface{}{
+				"nodes":      []interface{}{"start", "profile"},
+				"mergePatch": map[string]interface{}{"requestId": nil},
+			}},
+		},
+	}
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	execID, err := engine.ExecuteWorkflow(context.Background(), "combine", map[string]interface{}{"requestId": "r-1"})
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	result, err := engine.AwaitExecution(ctx, execID)
+	if err != nil {
+		t.Fatalf("AwaitExecution() error = %v", err)
+	}
+
+	// billing's input is overlaid with profile's output again: profile wins
+	want := map[string]interface{}{
+		"user": map[string]interface{}{"name": "ada", "plan": "free", "seats": 3},
+	}
+	if got := result.NodeOutputs["combine"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("combine output = %v, want %v", got, want)
+	}
+}
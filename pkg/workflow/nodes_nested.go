@@ -12,13 +12,25 @@ func CreateSubWorkflowHandler(engine *Engine) NodeHandler {
 	}
 }
 
+// DefaultMaxSubWorkflowDepth is how deeply sub-workflows may nest when a
+// subworkflow node does not set "maxDepth".
+const DefaultMaxSubWorkflowDepth = 10
+
+// subWorkflowDepthKey carries the depth of the calling execution into
+// ExecuteWorkflow, which records it in the child's ExecutionContext.
+type subWorkflowDepthKey struct{}
+
 // subWorkflowNodeHandler executes a nested workflow.
 func subWorkflowNodeHandler(ctx context.Context, input *NodeInput, engine *Engine) (*NodeOutput, error) {
 	// Config:
 	// - "workflowId": ID of workflow to execute (required)
+	// - "input": Input for the sub-workflow; string values support {{field}} templates
 	// - "inputField": Field from input data to pass to sub-workflow (default: use entire input)
-	// - "outputField": Field name for sub-workflow output (default: "subworkflow_output")
+	// - "outputField": Merge the sub-workflow output into the input under this field
+	//   (default: the sub-workflow output is this node's output)
+	// - "mergeOutput": Merge the fields of an object output into the input
 	// - "waitForCompletion": Wait for sub-workflow to complete (default: true)
+	// - "maxDepth": Maximum nesting depth (default: DefaultMaxSubWorkflowDepth)
 
 	workflowID, ok := input.Config["workflowId"].(string)
 	if !ok || workflowID == "" {
@@ -35,9 +47,26 @@ func subWorkflowNodeHandler(ctx context.Context, input *NodeInput, engine *Engin
 		}
 	}
 
+	// Reject runaway recursion before starting another execution
+	maxDepth := DefaultMaxSubWorkflowDepth
+	if md, ok := input.Config["maxDepth"].(float64); ok {
+		maxDepth = int(md)
+	} else if md, ok := input.Config["maxDepth"].(int); ok {
+		maxDepth = md
+	}
+	depth := 1
+	if input.Context != nil {
+		depth = input.Context.Depth + 1
+	}
+	if depth > maxDepth {
+		return nil, fmt.Errorf("sub-workflow %s exceeds max depth %d", workflowID, maxDepth)
+	}
+
 	// Get input data for sub-workflow
 	var subWorkflowInput interface{} = input.Data
-	if inputField, ok := input.Config["inputField"].(string); ok && inputField != "" {
+	if configured, ok := input.Config["input"].(map[string]interface{}); ok {
+		subWorkflowInput = processTemplateMap(configured, input.Data)
+	} else if inputField, ok := input.Config["inputField"].(string); ok && inputField != "" {
 		if data, ok := input.Data.(map[string]interface{}); ok {
 			if fieldValue, ok := data[inputField]; ok {
 				subWorkflowInput = fieldValue
@@ -45,13 +74,14 @@ func subWorkflowNodeHandler(ctx context.Context, input *NodeInput, engine *Engin
 		}
 	}
 
-	// Execute sub-workflow
-	execID, err := engine.ExecuteWorkflow(ctx, workflowID, subWorkflowInput)
+	// The child gets its own lifetime: it is cancelled explicitly below rather
+	// than when this node's (possibly timed) context ends
+	childCtx := context.WithValue(context.WithoutCancel(ctx), subWorkflowDepthKey{}, depth)
+	execID, err := engine.ExecuteWorkflow(childCtx, workflowID, subWorkflowInput)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute sub-workflow %s: %w", workflowID, err)
 	}
 
-	// Wait for completion
 	waitForCompletion := true
 	if wait, ok := input.Config["waitForCompletion"].(bool); ok {
 		waitForCompletion = wait
@@ -59,15 +89,19 @@ func subWorkflowNodeHandler(ctx context.Context, input *NodeInput, engine *Engin
 
 	var subWorkflowOutput interface{}
 	if waitForCompletion {
-		// Wait for sub-workflow to complete
-		// This is a simplified version - in production, you'd want proper async handling
-		execCtx, err := engine.GetExecution(execID)
+		execCtx, err := engine.AwaitExecution(ctx, execID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get sub-workflow execution: %w", err)
+			if ctx.Err() != nil {
+				_ = engine.CancelExecution(execID)
+			}
+			// Surface the child's own error rather than its summary
+			if execCtx != nil && len(execCtx.Errors) > 0 {
+				first := execCtx.Errors[0]
+				return nil, fmt.Errorf("sub-workflow %s failed at node %s: %s", workflowID, first.NodeID, first.Message)
+			}
+			return nil, fmt.Errorf("sub-workflow %s: %w", workflowID, err)
 		}
-
-		// Get output from last node or all node outputs
-		subWorkflowOutput = execCtx.NodeOutputs
+		subWorkflowOutput = lastNodeOutput(engine, workflowID, execCtx)
 	} else {
 		// Return execution ID for async handling
 		subWorkflowOutput = map[string]interface{}{
@@ -76,20 +110,26 @@ func subWorkflowNodeHandler(ctx context.Context, input *NodeInput, engine *Engin
 		}
 	}
 
-	// Merge sub-workflow output into result
+	outputField, _ := input.Config["outputField"].(string)
+	mergeOutput, _ := input.Config["mergeOutput"].(bool)
+	if outputField == "" && !mergeOutput {
+		return &NodeOutput{Data: subWorkflowOutput}, nil
+	}
+
+	// Merge sub-workflow output into the input
 	output := make(map[string]interface{})
 	if data, ok := input.Data.(map[string]interface{}); ok {
 		for k, v := range data {
 			output[k] = v
 		}
 	}
-
-	outputField := "subworkflow_output"
-	if of, ok := input.Config["outputField"].(string); ok && of != "" {
-		outputField = of
+	if outputField != "" {
+		output[outputField] = subWorkflowOutput
+	} else if fields, ok := subWorkflowOutput.(map[string]interface{}); ok {
+		for k, v := range fields {
+			output[k] = v
+		}
 	}
-
-	output[outputField] = subWorkflowOutput
 	output["_subworkflow_executionId"] = execID
 
 	return &NodeOutput{Data: output}, nil
@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func newSubWorkflowEngine(t *testing.T, defs ...*WorkflowDefinition) *Engine {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	engine := NewEngine(gocmd.EventBus())
+	functions := NewFunctionRegistry()
+	functions.Register("total", func(ctx context.Context, data interface{}) (interface{}, error) {
+		m, _ := data.(map[string]interface{})
+		qty, _ := m["qty"].(float64)
+		price, _ := m["price"].(float64)
+		return map[string]interface{}{"total": qty * price}, nil
+	})
+	engine.RegisterNodeHandler(NodeTypeFunction, CreateFunctionHandler(functions))
+	for _, def := range defs {
+		if err := engine.RegisterWorkflow(def); err != nil {
+			t.Fatalf("RegisterWorkflow() error = %v", err)
+		}
+	}
+	return engine
+}
+
+func TestSubWorkflow_ReturnsChildOutput(t *testing.T) {
+	child := &WorkflowDefinition{ID: "pricing", Nodes: []NodeDefinition{
+		{ID: "total", Type: string(NodeTypeFunction), Config: map[string]interface{}{"function": "total"}},
+	}}
+	parent := &WorkflowDefinition{ID: "order", Nodes: []NodeDefinition{
+		{ID: "start", Type: string(NodeTypeNoOp), Next: []string{"price", "price-merged"}},
+		{ID: "price", Type: string(NodeTypeSubWorkflow), Config: map[string]interface{}{"workflowId": "pricing"}},
+		{ID: "price-merged", Type: string(NodeTypeSubWorkflow), Config: map[string]interface{}{
+			"workflowId":  "pricing",
+			"input":       map[string]interface{}{"qty": float64(3), "price": float64(5)},
+			"outputField": "pricing",
+		}},
+	}}
+	engine := newSubWorkflowEngine(t, child, parent)
+
+	state := runGuarded(t, engine, "order", map[string]interface{}{"qty": float64(2), "price": float64(10)})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("status = %s, errors = %v", state.Status, state.Context.Errors)
+	}
+
+	price, _ := state.Context.NodeOutputs["price"].(map[string]interface{})
+	if price["total"] != float64(20) {
+		t.Errorf("price output = %v, want the child's final output", state.Context.NodeOutputs["price"])
+	}
+	merged, _ := state.Context.NodeOutputs["price-merged"].(map[string]interface{})
+	pricing, _ := merged["pricing"].(map[string]interface{})
+	if pricing["total"] != float64(15) || merged["qty"] != float64(2) {
+		t.Errorf("price-merged output = %v, want the child output under pricing", merged)
+	}
+}
+
+func TestSubWorkflow_ChildFailure(t *testing.T) {
+	child := &WorkflowDefinition{ID: "broken", Nodes: []NodeDefinition{
+		{ID: "fail", Type: string(NodeTypeError), Config: map[string]interface{}{"message": "out of stock"}},
+	}}
+	parent := &WorkflowDefinition{ID: "caller", Nodes: []NodeDefinition{
+		{ID: "call", Type: string(NodeTypeSubWorkflow), Config: map[string]interface{}{"workflowId": "broken"}},
+	}}
+	engine := newSubWorkflowEngine(t, child, parent)
+
+	state := runGuarded(t, engine, "caller", map[string]interface{}{})
+	if state.Status != ExecutionStatusFailed {
+		t.Fatalf("status = %s, want failed", state.Status)
+	}
+	if errs := state.Context.Errors; len(errs) != 1 || !strings.Contains(errs[0].Message, "out of stock") {
+		t.Errorf("errors = %+v, want the child's failure", errs)
+	}
+}
+
+func TestSubWorkflow_MaxDepth(t *testing.T) {
+	recursive := &WorkflowDefinition{ID: "recursive", Nodes: []NodeDefinition{
+		{ID: "again", Type: string(NodeTypeSubWorkflow), Config: map[string]interface{}{"workflowId": "recursive", "maxDepth": float64(3)}},
+	}}
+	engine := newSubWorkflowEngine(t, recursive)
+
+	state := runGuarded(t, engine, "recursive", map[string]interface{}{})
+	if state.Status != ExecutionStatusFailed {
+		t.Fatalf("status = %s, want failed", state.Status)
+	}
+	if errs := state.Context.Errors; len(errs) != 1 || !strings.Contains(errs[0].Message, "exceeds max depth 3") {
+		t.Errorf("errors = %+v, want the depth rejection", errs)
+	}
+
+	// Top-level plus three nested executions; the fourth was never started
+	engine.mu.RLock()
+	n := len(engine.executions)
+	engine.mu.RUnlock()
+	if n != 4 {
+		t.Errorf("%d executions, want 4", n)
+	}
+}
+
+func TestSubWorkflow_TwoLevels(t *testing.T) {
+	pricing := &WorkflowDefinition{ID: "pricing", Nodes: []NodeDefinition{
+		{ID: "total", Type: string(NodeTypeFunction), Config: map[string]interface{}{"function": "total"}},
+	}}
+	quote := &WorkflowDefinition{ID: "quote", Nodes: []NodeDefinition{
+		{ID: "price", Type: string(NodeTypeSubWorkflow), Config: map[string]interface{}{"workflowId": "pricing", "mergeOutput": true}, Next: []string{"tag"}},
+		{ID: "tag", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"quoted": true}}},
+	}}
+	order := &WorkflowDefinition{ID: "order", Nodes: []NodeDefinition{
+		{ID: "quote", Type: string(NodeTypeSubWorkflow), Config: map[string]interface{}{"workflowId": "quote", "mergeOutput": true}},
+	}}
+	engine := newSubWorkflowEngine(t, pricing, quote, order)
+
+	state := runGuarded(t, engine, "order", map[string]interface{}{"qty": float64(4), "price": float64(2.5), "customer": "acme"})
+	if state.Status != ExecutionStatusCompleted {
+		t.Fatalf("status = %s, errors = %v", state.Status, state.Context.Errors)
+	}
+	out, _ := state.Context.NodeOutputs["quote"].(map[string]interface{})
+	if out["total"] != float64(10) || out["quoted"] != true || out["customer"] != "acme" {
+		t.Errorf("quote output = %v, want parent data merged with both levels' results", out)
+	}
+	if state.Context.Depth != 0 {
+		t.Errorf("top-level depth = %d, want 0", state.Context.Depth)
+	}
+}
+
+func TestSubWorkflow_FailureFollowsOnError(t *testing.T) {
+	child := &WorkflowDefinition{ID: "broken", Nodes: []NodeDefinition{
+		{ID: "fail", Type: string(NodeTypeError), Config: map[string]interface{}{"message": "out of stock"}},
+	}}
+	parent := &WorkflowDefinition{ID: "caller", Nodes: []NodeDefinition{
+		{ID: "call", Type: string(NodeTypeSubWorkflow), Config: map[string]interface{}{"workflowId": "broken"}, OnError: []string{"fallback"}},
+		{ID: "fallback", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"backordered": true}}},
+	}}
+	engine := newSubWorkflowEngine(t, child, parent)
+
+	state := runGuarded(t, engine, "caller", map[string]interface{}{})
+	out, _ := state.Context.NodeOutputs["fallback"].(map[string]interface{})
+	if out["backordered"] != true {
+		t.Errorf("fallback output = %v, want the onError branch to run", state.Context.NodeOutputs["fallback"])
+	}
+	if errs := state.Context.Errors; len(errs) != 1 || errs[0].NodeID != "call" || !strings.Contains(errs[0].Message, "out of stock") {
+		t.Errorf("errors = %+v, want the child's failure recorded on the call node", errs)
+	}
+}
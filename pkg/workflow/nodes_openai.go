@@ -101,7 +101,7 @@ func OpenAINodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, erro
 				processedMsg := make(map[string]interface{})
 				for k, v := range msgMap {
 					if str, ok := v.(string); ok {
-						processedMsg[k] = processOpenAITemplate(str, input.Data)
+						processedMsg[k] = processTemplate(str, input.Data)
 					} else {
 						processedMsg[k] = v
 					}
@@ -121,11 +121,11 @@ func OpenAINodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, erro
 		if prompt, ok := input.Config["prompt"]; ok {
 			switch p := prompt.(type) {
 			case string:
-				promptText = processOpenAITemplate(p, input.Data)
+				promptText = processTemplate(p, input.Data)
 			case map[string]interface{}:
 				// If prompt is a map, try to extract text or use as-is
 				if text, ok := p["text"].(string); ok {
-					promptText = processOpenAITemplate(text, input.Data)
+					promptText = processTemplate(text, input.Data)
 				} else {
 					// Use entire prompt map
 					promptText = fmt.Sprintf("%v", p)
@@ -281,47 +281,6 @@ func OpenAINodeHandler(ctx context.Context, input *NodeInput) (*NodeOutput, erro
 	return &NodeOutput{Data: output}, nil
 }
 
-// processOpenAITemplate processes template strings with support for:
-// - {{ $.input.field }} - Access input data
-// - {{ $.field }} - Access root data
-// - {{ field }} - Simple field access
-func processOpenAITemplate(template string, data interface{}) string {
-	dataMap, ok := data.(map[string]interface{})
-	if !ok {
-		return template
-	}
-
-	result := template
-
-	// Support {{ $.input.field }} syntax
-	for key, value := range dataMap {
-		// {{ $.input.key }}
-		placeholder := fmt.Sprintf("{{ $.input.%s }}", key)
-		result = strings.ReplaceAll(result, placeholder, fmt.Sprintf("%v", value))
-
-		// {{ $.key }}
-		placeholder2 := fmt.Sprintf("{{ $.%s }}", key)
-		result = strings.ReplaceAll(result, placeholder2, fmt.Sprintf("%v", value))
-
-		// {{ key }}
-		placeholder3 := fmt.Sprintf("{{ %s }}", key)
-		result = strings.ReplaceAll(result, placeholder3, fmt.Sprintf("%v", value))
-
-		// Support nested access {{ $.input.nested.field }}
-		if nested, ok := value.(map[string]interface{}); ok {
-			for nestedKey, nestedValue := range nested {
-				nestedPlaceholder := fmt.Sprintf("{{ $.input.%s.%s }}", key, nestedKey)
-				result = strings.ReplaceAll(result, nestedPlaceholder, fmt.Sprintf("%v", nestedValue))
-
-				nestedPlaceholder2 := fmt.Sprintf("{{ $.%s.%s }}", key, nestedKey)
-				result = strings.ReplaceAll(result, nestedPlaceholder2, fmt.Sprintf("%v", nestedValue))
-			}
-		}
-	}
-
-	return result
-}
-
 // getEnv gets environment variable value or returns default
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
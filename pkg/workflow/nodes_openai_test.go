@@ -47,7 +47,7 @@ func TestOpenAINodeHandler_TemplateProcessing(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := processOpenAITemplate(tt.template, tt.data)
+			result := processTemplate(tt.template, tt.data)
 			if result != tt.expected {
 				t.Errorf("Expected %q, got %q", tt.expected, result)
 			}
@@ -36,6 +36,8 @@ func (r *nodeRegistry) registerBuiltins() {
 	// Register all built-in node handlers
 	r.handlers[NodeTypeNoOp] = noOpHandler
 	r.handlers[NodeTypeSet] = setHandler
+	r.handlers[NodeTypeEncode] = encodeHandler
+	r.handlers[NodeTypeMergeData] = mergeDataHandler
 	r.handlers[NodeTypeCondition] = conditionHandler
 	r.handlers[NodeTypeWait] = waitHandler
 	r.handlers[NodeTypeError] = errorHandler
@@ -43,4 +45,7 @@ func (r *nodeRegistry) registerBuiltins() {
 	r.handlers[NodeTypeSplit] = splitHandler
 	r.handlers[NodeTypeMerge] = mergeHandler
 	r.handlers[NodeTypeSwitch] = switchHandler
+	r.handlers[NodeTypeSchedule] = scheduleTriggerHandler
+	r.handlers[NodeTypeWebhook] = webhookTriggerHandler
+	r.handlers[NodeTypeSubWorkflow] = CreateSubWorkflowHandler(nil) // engine from the node context
 }
@@ -0,0 +1,111 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Retry backoff strategies for NodeDefinition.RetryBackoff.
+const (
+	RetryBackoffLinear      = "linear"      // RetryDelay * attempt (default)
+	RetryBackoffExponential = "exponential" // RetryDelay * 2^(attempt-1)
+	RetryBackoffConstant    = "constant"    // RetryDelay between every attempt
+	RetryBackoffFixed       = "fixed"       // Alias of RetryBackoffConstant
+)
+
+// defaultRetryDelay is the base delay when RetryDelay is unset.
+const defaultRetryDelay = time.Second
+
+// retryPolicy is the parsed retry configuration of a node.
+type retryPolicy struct {
+	backoff  string
+	delay    time.Duration
+	maxDelay time.Duration // 0 means uncapped
+	jitter   float64       // fraction of each wait that is randomized
+	random   func() float64
+}
+
+func retryPolicyOf(node *NodeDefinition) (retryPolicy, error) {
+	p := retryPolicy{backoff: node.RetryBackoff, delay: defaultRetryDelay, random: rand.Float64}
+	switch p.backoff {
+	case "":
+		p.backoff = RetryBackoffLinear
+	case RetryBackoffFixed:
+		p.backoff = RetryBackoffConstant
+	case RetryBackoffLinear, RetryBackoffExponential, RetryBackoffConstant:
+	default:
+		return p, fmt.Errorf("node %s: invalid retryBackoff %q (want linear, exponential or constant)", node.ID, node.RetryBackoff)
+	}
+	if node.RetryDelay != "" {
+		d, err := time.ParseDuration(node.RetryDelay)
+		if err != nil || d < 0 {
+			return p, fmt.Errorf("node %s: invalid retryDelay %q", node.ID, node.RetryDelay)
+		}
+		p.delay = d
+	}
+	if node.MaxRetryDelay != "" {
+		d, err := time.ParseDuration(node.MaxRetryDelay)
+		if err != nil || d <= 0 {
+			return p, fmt.Errorf("node %s: invalid maxRetryDelay %q", node.ID, node.MaxRetryDelay)
+		}
+		p.maxDelay = d
+	}
+	if node.RetryJitter < 0 || node.RetryJitter > 1 {
+		return p, fmt.Errorf("node %s: invalid retryJitter %v (want 0 to 1)", node.ID, node.RetryJitter)
+	}
+	p.jitter = node.RetryJitter
+	return p, nil
+}
+
+// delayAfter returns how long to wait after the given failed attempt (1-based)
+// before the next one.
+func (p retryPolicy) delayAfter(attempt int) time.Duration {
+	d := p.delay
+	switch p.backoff {
+	case RetryBackoffLinear:
+		d = p.delay * time.Duration(attempt)
+	case RetryBackoffExponential:
+		for i := 1; i < attempt && d > 0; i++ {
+			if p.maxDelay > 0 && d >= p.maxDelay {
+				break
+			}
+			if d > time.Duration(1<<62)/2 {
+				d = time.Duration(1 << 62)
+				break
+			}
+			d *= 2
+		}
+	}
+	if p.maxDelay > 0 && d > p.maxDelay {
+		d = p.maxDelay
+	}
+	return d
+}
+
+// waitAfter is delayAfter with jitter applied: the wait is shortened by a
+// random share of up to jitter of it, so it never exceeds the backoff.
+func (p retryPolicy) waitAfter(attempt int) time.Duration {
+	d := p.delayAfter(attempt)
+	if p.jitter == 0 || d <= 0 {
+		return d
+	}
+	return d - time.Duration(float64(d)*p.jitter*p.random())
+}
+
+// waitRetry sleeps for d unless ctx is done first, in which case it returns
+// ctx.Err().
+func waitRetry(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
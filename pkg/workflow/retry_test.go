@@ -0,0 +1,212 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+func TestRetryPolicy_Delays(t *testing.T) {
+	tests := []struct {
+		name string
+		node NodeDefinition
+		want []time.Duration // after attempts 1, 2, 3, ...
+	}{
+		{"default linear", NodeDefinition{}, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}},
+		{"linear", NodeDefinition{RetryBackoff: "linear", RetryDelay: "100ms"}, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}},
+		{"constant", NodeDefinition{RetryBackoff: "constant", RetryDelay: "50ms"}, []time.Duration{50 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}},
+		{"fixed", NodeDefinition{RetryBackoff: "fixed", RetryDelay: "50ms"}, []time.Duration{50 * time.Millisecond, 50 * time.Millisecond}},
+		{"exponential", NodeDefinition{RetryBackoff: "exponential", RetryDelay: "10ms"}, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 80 * time.Millisecond}},
+		{"exponential capped", NodeDefinition{RetryBackoff: "exponential", RetryDelay: "1s", MaxRetryDelay: "3s"}, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}},
+		{"linear capped", NodeDefinition{RetryDelay: "1s", MaxRetryDelay: "1500ms"}, []time.Duration{time.Second, 1500 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := retryPolicyOf(&tt.node)
+			if err != nil {
+				t.Fatalf("retryPolicyOf() error = %v", err)
+			}
+			for i, want := range tt.want {
+				if got := policy.delayAfter(i + 1); got != want {
+					t.Errorf("delayAfter(%d) = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+
+	policy, _ := retryPolicyOf(&NodeDefinition{RetryBackoff: "exponential", RetryDelay: "1h"})
+	if got := policy.delayAfter(200); got <= 0 {
+		t.Errorf("delayAfter(200) = %v, want no overflow", got)
+	}
+}
+
+func TestRetryPolicy_Jitter(t *testing.T) {
+	policy, err := retryPolicyOf(&NodeDefinition{RetryBackoff: "exponential", RetryDelay: "100ms", RetryJitter: 0.5})
+	if err != nil {
+		t.Fatalf("retryPolicyOf() error = %v", err)
+	}
+	for _, tt := range []struct {
+		random  float64
+		attempt int
+		want    time.Duration
+	}{
+		{0, 1, 100 * time.Millisecond},
+		{1, 1, 50 * time.Millisecond},
+		{0.5, 2, 150 * time.Millisecond},
+		{0.5, 3, 300 * time.Millisecond},
+	} {
+		policy.random = func() float64 { return tt.random }
+		if got := policy.waitAfter(tt.attempt); got != tt.want {
+			t.Errorf("waitAfter(%d) with random %v = %v, want %v", tt.attempt, tt.random, got, tt.want)
+		}
+	}
+
+	// Real randomness stays within [delay*(1-jitter), delay] and varies
+	policy, _ = retryPolicyOf(&NodeDefinition{RetryDelay: "1s", RetryJitter: 1})
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		d := policy.waitAfter(1)
+		if d < 0 || d > time.Second {
+			t.Fatalf("waitAfter(1) = %v, want within [0, 1s]", d)
+		}
+		seen[d] = true
+	}
+	if len(seen) < 2 {
+		t.Error("jittered waits should differ")
+	}
+}
+
+func TestRetryPolicy_Invalid(t *testing.T) {
+	for _, node := range []NodeDefinition{
+		{ID: "a", RetryBackoff: "fibonacci"},
+		{ID: "b", RetryDelay: "soon"},
+		{ID: "c", MaxRetryDelay: "0s"},
+		{ID: "d", RetryJitter: 1.5},
+		{ID: "e", RetryJitter: -0.1},
+	} {
+		def := &WorkflowDefinition{ID: "invalid-" + node.ID, Nodes: []NodeDefinition{node}}
+		if err := NewEngine(nil).RegisterWorkflow(def); err == nil {
+			t.Errorf("RegisterWorkflow() accepted node %+v", node)
+		}
+	}
+}
+
+func TestNodeDefinition_RetryBackoffJSON(t *testing.T) {
+	var node NodeDefinition
+	data := []byte(`{"id":"call","type":"http","retryCount":3,"retryBackoff":"exponential","retryDelay":"200ms","maxRetryDelay":"1s","retryJitter":0.2}`)
+	if err := json.Unmarshal(data, &node); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if node.RetryBackoff != "exponential" || node.RetryDelay != "200ms" || node.MaxRetryDelay != "1s" || node.RetryJitter != 0.2 {
+		t.Errorf("node = %+v, want the retry backoff fields", node)
+	}
+	out, _ := json.Marshal(node)
+	var back NodeDefinition
+	_ = json.Unmarshal(out, &back)
+	if back.RetryBackoff != node.RetryBackoff || back.MaxRetryDelay != node.MaxRetryDelay || back.RetryJitter != node.RetryJitter {
+		t.Errorf("round trip = %s", out)
+	}
+}
+
+// flakyEngine runs a single "flaky" node that always fails, recording when
+// each attempt started.
+func flakyEngine(t *testing.T, node NodeDefinition) (*Engine, func() []time.Time) {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	var mu sync.Mutex
+	var attempts []time.Time
+	engine := NewEngine(gocmd.EventBus())
+	engine.RegisterNodeHandler("flaky", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		mu.Lock()
+		attempts = append(attempts, time.Now())
+		mu.Unlock()
+		return nil, errors.New("unavailable")
+	})
+	node.ID, node.Type = "call", "flaky"
+	if err := engine.RegisterWorkflow(&WorkflowDefinition{ID: "retrying", Nodes: []NodeDefinition{node}}); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	return engine, func() []time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Time(nil), attempts...)
+	}
+}
+
+func TestEngine_RetryBackoff_Exponential(t *testing.T) {
+	engine, attempts := flakyEngine(t, NodeDefinition{
+		RetryCount: 4, RetryBackoff: RetryBackoffExponential, RetryDelay: "20ms", MaxRetryDelay: "50ms",
+	})
+
+	state := runGuarded(t, engine, "retrying", nil)
+	if state.Status != ExecutionStatusFailed {
+		t.Fatalf("status = %s, want failed", state.Status)
+	}
+	times := attempts()
+	if len(times) != 4 {
+		t.Fatalf("%d attempts, want 4", len(times))
+	}
+	for i, want := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond} {
+		gap := times[i+1].Sub(times[i])
+		if gap < want || gap > want+200*time.Millisecond {
+			t.Errorf("delay before attempt %d = %v, want about %v", i+2, gap, want)
+		}
+	}
+}
+
+func TestEngine_RetryBackoff_NodeTimeoutAbortsWait(t *testing.T) {
+	engine, attempts := flakyEngine(t, NodeDefinition{
+		RetryCount: 3, RetryBackoff: RetryBackoffConstant, RetryDelay: "10s", Timeout: "50ms",
+	})
+
+	start := time.Now()
+	state := runGuarded(t, engine, "retrying", nil)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("execution took %v, want the node timeout to cut the retry wait short", elapsed)
+	}
+	if state.Status != ExecutionStatusFailed {
+		t.Errorf("status = %s, want failed", state.Status)
+	}
+	if n := len(attempts()); n != 1 {
+		t.Errorf("%d attempts, want 1", n)
+	}
+}
+
+func TestEngine_RetryBackoff_CancelAbortsWait(t *testing.T) {
+	engine, attempts := flakyEngine(t, NodeDefinition{
+		RetryCount: 3, RetryBackoff: RetryBackoffConstant, RetryDelay: "10s",
+	})
+
+	execID, err := engine.ExecuteWorkflow(context.Background(), "retrying", nil)
+	if err != nil {
+		t.Fatalf("ExecuteWorkflow() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for len(attempts()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := engine.CancelExecution(execID); err != nil {
+		t.Fatalf("CancelExecution() error = %v", err)
+	}
+
+	state, _ := engine.GetExecutionState(execID)
+	engine.mu.RLock()
+	status := state.Status
+	engine.mu.RUnlock()
+	if status != ExecutionStatusCancelled {
+		t.Errorf("status = %s, want cancelled", status)
+	}
+
+	// No further attempt once the pending retry wait is aborted
+	time.Sleep(100 * time.Millisecond)
+	if n := len(attempts()); n != 1 {
+		t.Errorf("%d attempts after cancel, want 1", n)
+	}
+}
@@ -0,0 +1,276 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// clock abstracts time so schedules can be driven by a fake clock in tests.
+type clock interface {
+	Now() time.Time
+	// NewTimer returns a channel that fires after d and a function that stops it.
+	NewTimer(d time.Duration) (<-chan time.Time, func() bool)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	t := time.NewTimer(d)
+	return t.C, t.Stop
+}
+
+// Scheduler fires workflows from their schedule trigger nodes.
+//
+// A schedule node is configured with either:
+//   - "cron": standard 5-field cron expression (e.g. "*/5 * * * *")
+//   - "interval": duration string (e.g. "30s", "1h")
+//
+// and optionally "timezone" (IANA name, default UTC) for cron schedules.
+// A run is skipped while the previous execution of the same node is still
+// running, so slow workflows never pile up.
+type Scheduler struct {
+	engine *Engine
+	clock  clock
+	logger core.Logger
+
+	mu      sync.Mutex
+	entries map[string][]*scheduleEntry // workflowID -> entries
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// scheduleEntry is a single schedule node of a workflow.
+type scheduleEntry struct {
+	workflowID string
+	nodeID     string
+	cron       *cronSchedule
+	interval   time.Duration
+	location   *time.Location
+
+	cancel     context.CancelFunc
+	lastExecID string
+	fired      int
+	skipped    int
+}
+
+// NewScheduler creates a scheduler that starts executions on engine.
+func NewScheduler(engine *Engine) *Scheduler {
+	return newSchedulerWithClock(engine, realClock{})
+}
+
+func newSchedulerWithClock(engine *Engine, c clock) *Scheduler {
+	return &Scheduler{
+		engine:  engine,
+		clock:   c,
+		logger:  core.NewDefaultLogger(),
+		entries: make(map[string][]*scheduleEntry),
+	}
+}
+
+// Schedule registers (or replaces) the schedule nodes of a workflow.
+// Workflows without schedule nodes are ignored. If the scheduler is
+// running, the new schedules start immediately.
+func (s *Scheduler) Schedule(def *WorkflowDefinition) error {
+	var entries []*scheduleEntry
+	for _, node := range def.Nodes {
+		if NodeType(node.Type) != NodeTypeSchedule {
+			continue
+		}
+		entry, err := newScheduleEntry(def.ID, &node)
+		if err != nil {
+			return fmt.Errorf("workflow %s: schedule node %s: %w", def.ID, node.ID, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unscheduleLocked(def.ID)
+	if len(entries) == 0 {
+		return nil
+	}
+	s.entries[def.ID] = entries
+	if s.ctx != nil {
+		for _, entry := range entries {
+			s.startEntryLocked(entry)
+		}
+	}
+	return nil
+}
+
+// Unschedule stops and removes the schedules of a workflow.
+func (s *Scheduler) Unschedule(workflowID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unscheduleLocked(workflowID)
+}
+
+func (s *Scheduler) unscheduleLocked(workflowID string) {
+	for _, entry := range s.entries[workflowID] {
+		if entry.cancel != nil {
+			entry.cancel()
+		}
+	}
+	delete(s.entries, workflowID)
+}
+
+// Start begins firing all registered schedules.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for _, entries := range s.entries {
+		for _, entry := range entries {
+			s.startEntryLocked(entry)
+		}
+	}
+}
+
+// Stop halts all schedules and waits for their loops to exit.
+// Executions already started keep running.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if s.ctx == nil {
+		s.mu.Unlock()
+		return
+	}
+	s.cancel()
+	s.ctx, s.cancel = nil, nil
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Scheduler) startEntryLocked(entry *scheduleEntry) {
+	ctx, cancel := context.WithCancel(s.ctx)
+	entry.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(ctx, entry)
+	}()
+}
+
+func (s *Scheduler) run(ctx context.Context, entry *scheduleEntry) {
+	for {
+		now := s.clock.Now()
+		next := entry.next(now)
+		if next.IsZero() {
+			s.logger.Info(fmt.Sprintf("schedule %s/%s has no future runs", entry.workflowID, entry.nodeID))
+			return
+		}
+
+		fire, stop := s.clock.NewTimer(next.Sub(now))
+		select {
+		case <-ctx.Done():
+			stop()
+			return
+		case <-fire:
+		}
+		s.fire(ctx, entry, next)
+	}
+}
+
+// fire starts an execution unless the previous one has not finished: it is
+// still running, paused, or queued behind EngineOptions.Concurrency.
+func (s *Scheduler) fire(ctx context.Context, entry *scheduleEntry, scheduledAt time.Time) {
+	s.mu.Lock()
+	lastExecID := entry.lastExecID
+	s.mu.Unlock()
+
+	if lastExecID != "" {
+		if status, unfinished := s.engine.unfinished(lastExecID); unfinished {
+			s.mu.Lock()
+			entry.skipped++
+			s.mu.Unlock()
+			s.logger.Info(fmt.Sprintf("schedule %s/%s skipped: execution %s still %s", entry.workflowID, entry.nodeID, lastExecID, status))
+			return
+		}
+	}
+
+	// Check for a stop that raced with the timer
+	if ctx.Err() != nil {
+		return
+	}
+
+	input := map[string]interface{}{
+		"scheduledAt":  scheduledAt.Format(time.RFC3339),
+		"scheduleNode": entry.nodeID,
+	}
+	// Scheduled executions are not tied to the scheduler's lifetime
+	execID, err := s.engine.ExecuteWorkflow(context.Background(), entry.workflowID, input)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("schedule %s/%s failed to start execution: %v", entry.workflowID, entry.nodeID, err))
+		return
+	}
+
+	s.mu.Lock()
+	entry.lastExecID = execID
+	entry.fired++
+	s.mu.Unlock()
+}
+
+func newScheduleEntry(workflowID string, node *NodeDefinition) (*scheduleEntry, error) {
+	entry := &scheduleEntry{
+		workflowID: workflowID,
+		nodeID:     node.ID,
+		location:   time.UTC,
+	}
+
+	cronExpr, _ := node.Config["cron"].(string)
+	intervalStr, _ := node.Config["interval"].(string)
+	switch {
+	case cronExpr != "" && intervalStr != "":
+		return nil, fmt.Errorf("cron and interval are mutually exclusive")
+	case cronExpr != "":
+		cron, err := parseCron(cronExpr)
+		if err != nil {
+			return nil, err
+		}
+		entry.cron = cron
+	case intervalStr != "":
+		interval, err := time.ParseDuration(intervalStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval: %w", err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("interval must be positive, got %s", intervalStr)
+		}
+		entry.interval = interval
+	default:
+		return nil, fmt.Errorf("cron or interval is required")
+	}
+
+	if tz, _ := node.Config["timezone"].(string); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %w", err)
+		}
+		entry.location = loc
+	}
+	return entry, nil
+}
+
+// next returns the next run time after now.
+func (e *scheduleEntry) next(now time.Time) time.Time {
+	if e.cron != nil {
+		return e.cron.next(now.In(e.location))
+	}
+	return now.Add(e.interval)
+}
+
+// scheduleTriggerHandler is the handler for schedule nodes: the trigger
+// payload (scheduledAt, scheduleNode) flows on to the next nodes.
+func scheduleTriggerHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+	return &NodeOutput{Data: input.Data}, nil
+}
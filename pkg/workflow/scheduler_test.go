@@ -0,0 +1,475 @@
+package workflow
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// fakeClock is a manually advanced clock for scheduler tests.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	at      time.Time
+	ch      chan time.Time
+	stopped bool
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	c.timers = append(c.timers, t)
+	return t.ch, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		wasActive := !t.stopped
+		t.stopped = true
+		return wasActive
+	}
+}
+
+// pending returns the number of armed timers.
+func (c *fakeClock) pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, t := range c.timers {
+		if !t.stopped {
+			n++
+		}
+	}
+	return n
+}
+
+// advance moves the clock forward and fires the timers that became due.
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	remaining := c.timers[:0]
+	for _, t := range c.timers {
+		switch {
+		case t.stopped:
+		case !t.at.After(c.now):
+			t.stopped = true
+			t.ch <- c.now
+		default:
+			remaining = append(remaining, t)
+		}
+	}
+	c.timers = remaining
+}
+
+// waitForTimers blocks until n timers are armed (the scheduler loops re-armed).
+func (c *fakeClock) waitForTimers(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if c.pending() >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("scheduler armed %d timers, want %d", c.pending(), n)
+}
+
+// simulate advances the clock in steps over window, letting n loops re-arm between steps.
+func (c *fakeClock) simulate(t *testing.T, window, step time.Duration, n int) {
+	t.Helper()
+	for elapsed := time.Duration(0); elapsed < window; elapsed += step {
+		c.waitForTimers(t, n)
+		c.advance(step)
+	}
+	c.waitForTimers(t, n)
+}
+
+// simulateSettled is simulate, but lets the executions started by one step
+// finish before the next, so no tick is skipped as overlapping.
+func (c *fakeClock) simulateSettled(t *testing.T, e *Engine, window, step time.Duration, n int) {
+	t.Helper()
+	for elapsed := time.Duration(0); elapsed < window; elapsed += step {
+		c.waitForTimers(t, n)
+		waitForNoRunning(t, e)
+		c.advance(step)
+	}
+	c.waitForTimers(t, n)
+}
+
+func newScheduledEngine(t *testing.T, defs ...*WorkflowDefinition) *Engine {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+
+	engine := NewEngine(gocmd.EventBus())
+	for _, def := range defs {
+		if err := engine.RegisterWorkflow(def); err != nil {
+			t.Fatalf("RegisterWorkflow() error = %v", err)
+		}
+	}
+	return engine
+}
+
+func scheduledWorkflow(id string, config map[string]interface{}) *WorkflowDefinition {
+	return &WorkflowDefinition{
+		ID: id,
+		Nodes: []NodeDefinition{
+			{ID: "tick", Type: string(NodeTypeSchedule), Config: config, Next: []string{"work"}},
+			{ID: "work", Type: string(NodeTypeNoOp)},
+		},
+	}
+}
+
+// executionsOf counts executions of a workflow and returns how many completed.
+func executionsOf(e *Engine, workflowID string) (total, completed int) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, state := range e.executions {
+		if state.WorkflowID != workflowID {
+			continue
+		}
+		total++
+		if state.Status == ExecutionStatusCompleted {
+			completed++
+		}
+	}
+	return total, completed
+}
+
+func TestScheduler_Interval(t *testing.T) {
+	engine := newScheduledEngine(t, scheduledWorkflow("every-minute", map[string]interface{}{"interval": "1m"}))
+	clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := newSchedulerWithClock(engine, clk)
+	if err := s.Schedule(engine.workflows["every-minute"]); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	s.Start()
+	defer s.Stop()
+
+	clk.simulateSettled(t, engine, 10*time.Minute, 30*time.Second, 1)
+	waitForNoRunning(t, engine)
+
+	total, completed := executionsOf(engine, "every-minute")
+	if total != 10 || completed != 10 {
+		t.Errorf("executions = %d (%d completed) over 10m, want 10", total, completed)
+	}
+}
+
+func TestScheduler_Cron(t *testing.T) {
+	engine := newScheduledEngine(t, scheduledWorkflow("five-min", map[string]interface{}{"cron": "*/5 * * * *"}))
+	clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC))
+	s := newSchedulerWithClock(engine, clk)
+	_ = s.Schedule(engine.workflows["five-min"])
+	s.Start()
+	defer s.Stop()
+
+	// 00:00:30 -> 01:00:30 covers 00:05 ... 01:00
+	clk.simulateSettled(t, engine, time.Hour, time.Minute, 1)
+	waitForNoRunning(t, engine)
+
+	if total, _ := executionsOf(engine, "five-min"); total != 12 {
+		t.Errorf("executions = %d over 1h, want 12", total)
+	}
+}
+
+func TestScheduler_Timezone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	entry, err := newScheduleEntry("wf", &NodeDefinition{
+		ID:     "tick",
+		Config: map[string]interface{}{"cron": "0 9 * * *", "timezone": "America/New_York"},
+	})
+	if err != nil {
+		t.Fatalf("newScheduleEntry() error = %v", err)
+	}
+
+	next := entry.next(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	want := time.Date(2024, 6, 1, 9, 0, 0, 0, loc)
+	if next.Before(want) || next.After(want) {
+		t.Errorf("next = %v, want %v (09:00 New York = 13:00 UTC)", next, want)
+	}
+}
+
+func TestScheduler_SkipsOverlappingRuns(t *testing.T) {
+	def := &WorkflowDefinition{
+		ID: "slow",
+		Nodes: []NodeDefinition{
+			{ID: "tick", Type: string(NodeTypeSchedule), Config: map[string]interface{}{"interval": "1m"}, Next: []string{"block"}},
+			{ID: "block", Type: "block"},
+		},
+	}
+	engine := newScheduledEngine(t)
+	release := make(chan struct{})
+	var started int32
+	engine.RegisterNodeHandler("block", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+		atomic.AddInt32(&started, 1)
+		<-release
+		return &NodeOutput{Data: input.Data}, nil
+	})
+	if err := engine.RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+
+	clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := newSchedulerWithClock(engine, clk)
+	_ = s.Schedule(def)
+	s.Start()
+	defer s.Stop()
+
+	// First run blocks; the next three ticks must be skipped
+	clk.simulate(t, 4*time.Minute, time.Minute, 1)
+	if total, _ := executionsOf(engine, "slow"); total != 1 {
+		t.Fatalf("executions while blocked = %d, want 1", total)
+	}
+
+	close(release)
+	waitForNoRunning(t, engine)
+	clk.simulate(t, time.Minute, time.Minute, 1)
+	waitForNoRunning(t, engine)
+
+	if total, _ := executionsOf(engine, "slow"); total != 2 {
+		t.Errorf("executions after release = %d, want 2", total)
+	}
+	entry := s.entries["slow"][0]
+	if entry.skipped != 3 {
+		t.Errorf("skipped = %d, want 3", entry.skipped)
+	}
+}
+
+func TestScheduler_SkipsPendingAndPausedRuns(t *testing.T) {
+	def := &WorkflowDefinition{
+		ID: "guarded-run",
+		Nodes: []NodeDefinition{
+			{ID: "tick", Type: string(NodeTypeSchedule), Config: map[string]interface{}{"interval": "1m"}, Next: []string{"block"}},
+			{ID: "block", Type: "block"},
+		},
+	}
+	hog := &WorkflowDefinition{ID: "hog", Nodes: []NodeDefinition{{ID: "start", Type: "block"}}}
+
+	tests := []struct {
+		name string
+		// hold leaves the scheduled execution unfinished but not running
+		hold func(t *testing.T, engine *Engine, execID string, release chan struct{})
+		want ExecutionStatus
+	}{
+		{
+			name: "paused",
+			hold: func(t *testing.T, engine *Engine, execID string, release chan struct{}) {
+				if err := engine.PauseExecution(execID); err != nil {
+					t.Fatalf("PauseExecution() error = %v", err)
+				}
+				close(release) // the node finishes; its next nodes are held
+			},
+			want: ExecutionStatusPaused,
+		},
+		{
+			// The hog fills the only slot, so the scheduled run waits in the queue
+			name: "pending",
+			want: ExecutionStatusPending,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gocmd := core.NewGoCMD(context.Background())
+			t.Cleanup(func() { _ = gocmd.Close() })
+			engine := NewEngineWithOptions(gocmd.EventBus(), EngineOptions{
+				Concurrency: ExecutionConcurrency{MaxRunning: 1, QueueSize: 5},
+			})
+			release := make(chan struct{})
+			var releaseOnce sync.Once
+			t.Cleanup(func() { releaseOnce.Do(func() { close(release) }) })
+			engine.RegisterNodeHandler("block", func(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+				select {
+				case <-release:
+				case <-ctx.Done():
+				}
+				return &NodeOutput{Data: input.Data}, nil
+			})
+			for _, d := range []*WorkflowDefinition{def, hog} {
+				if err := engine.RegisterWorkflow(d); err != nil {
+					t.Fatalf("RegisterWorkflow() error = %v", err)
+				}
+			}
+			if tt.want == ExecutionStatusPending {
+				if _, err := engine.ExecuteWorkflow(context.Background(), "hog", nil); err != nil {
+					t.Fatalf("ExecuteWorkflow(hog) error = %v", err)
+				}
+			}
+
+			clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+			s := newSchedulerWithClock(engine, clk)
+			_ = s.Schedule(def)
+			s.Start()
+			defer s.Stop()
+
+			clk.simulate(t, time.Minute, time.Minute, 1)
+			s.mu.Lock()
+			entry := s.entries["guarded-run"][0]
+			execID := entry.lastExecID
+			s.mu.Unlock()
+			if tt.hold != nil {
+				releaseOnce.Do(func() { tt.hold(t, engine, execID, release) })
+			}
+			if status, _ := engine.unfinished(execID); status != tt.want {
+				t.Fatalf("scheduled execution is %q, want %q", status, tt.want)
+			}
+
+			// Later ticks must not start a second execution alongside it
+			clk.simulate(t, 3*time.Minute, time.Minute, 1)
+			if total, _ := executionsOf(engine, "guarded-run"); total != 1 {
+				t.Errorf("executions = %d, want 1 while the first is %s", total, tt.want)
+			}
+			s.mu.Lock()
+			skipped := entry.skipped
+			s.mu.Unlock()
+			if skipped != 3 {
+				t.Errorf("skipped = %d, want 3", skipped)
+			}
+		})
+	}
+}
+
+func TestScheduler_StopAndUnschedule(t *testing.T) {
+	engine := newScheduledEngine(t,
+		scheduledWorkflow("a", map[string]interface{}{"interval": "1m"}),
+		scheduledWorkflow("b", map[string]interface{}{"interval": "1m"}),
+	)
+	clk := newFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := newSchedulerWithClock(engine, clk)
+	_ = s.Schedule(engine.workflows["a"])
+	_ = s.Schedule(engine.workflows["b"])
+	s.Start()
+
+	clk.simulateSettled(t, engine, 2*time.Minute, time.Minute, 2)
+	s.Unschedule("b")
+	clk.simulateSettled(t, engine, 2*time.Minute, time.Minute, 1)
+	s.Stop()
+
+	if clk.pending() != 0 {
+		t.Errorf("%d timers still armed after Stop()", clk.pending())
+	}
+	clk.advance(10 * time.Minute)
+	waitForNoRunning(t, engine)
+
+	if total, _ := executionsOf(engine, "a"); total != 4 {
+		t.Errorf("executions of a = %d, want 4", total)
+	}
+	if total, _ := executionsOf(engine, "b"); total != 2 {
+		t.Errorf("executions of b = %d, want 2 (unscheduled after 2m)", total)
+	}
+}
+
+func TestScheduler_InvalidConfig(t *testing.T) {
+	engine := newScheduledEngine(t)
+	s := NewScheduler(engine)
+
+	for name, config := range map[string]map[string]interface{}{
+		"missing":  {},
+		"both":     {"cron": "* * * * *", "interval": "1m"},
+		"badCron":  {"cron": "61 * * * *"},
+		"fields":   {"cron": "* * *"},
+		"interval": {"interval": "-1s"},
+		"timezone": {"cron": "@daily", "timezone": "Mars/Olympus"},
+	} {
+		if err := s.Schedule(scheduledWorkflow(name, config)); err == nil {
+			t.Errorf("Schedule() with %s config should fail", name)
+		}
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	base := time.Date(2024, 1, 31, 23, 59, 0, 0, time.UTC) // Wednesday
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 8 * * 1-5", time.Date(2024, 2, 1, 8, 30, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, 2, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 12 29 2 *", time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"15/20 * * * *", time.Date(2024, 2, 1, 0, 15, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches (Friday the 2nd)
+		{"0 0 10 * 5", time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := parseCron(tt.expr)
+		if err != nil {
+			t.Errorf("parseCron(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if got := s.next(base); !got.Equal(tt.want) {
+			t.Errorf("%q next = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+
+	s, _ := parseCron("0 0 30 2 *")
+	if got := s.next(base); !got.IsZero() {
+		t.Errorf("impossible schedule next = %v, want zero", got)
+	}
+}
+
+func TestWorkflowVerticle_ScheduleRunsUntilUndeploy(t *testing.T) {
+	var runs atomic.Int32
+	v := NewWorkflowVerticle(&WorkflowVerticleConfig{
+		Workflows: []*WorkflowDefinition{{
+			ID: "ticker",
+			Nodes: []NodeDefinition{
+				{ID: "tick", Type: string(NodeTypeSchedule), Config: map[string]interface{}{"interval": "20ms"}, Next: []string{"count"}},
+				{ID: "count", Type: string(NodeTypeFunction), Config: map[string]interface{}{"function": "count"}},
+			},
+		}},
+	})
+	v.RegisterFunction("count", func(data interface{}) (interface{}, error) {
+		runs.Add(1)
+		return data, nil
+	})
+
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	id, err := gocmd.DeployVerticle(v)
+	if err != nil {
+		t.Fatalf("DeployVerticle() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runs.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("scheduled workflow ran %d times in 2s, want at least 3", runs.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := gocmd.UndeployVerticle(id); err != nil {
+		t.Fatalf("UndeployVerticle() error = %v", err)
+	}
+	// Stop runs asynchronously; let it and any run already due finish
+	time.Sleep(50 * time.Millisecond)
+	stopped := runs.Load()
+	time.Sleep(100 * time.Millisecond)
+	if n := runs.Load(); n != stopped {
+		t.Errorf("workflow ran %d more times after undeploy", n-stopped)
+	}
+}
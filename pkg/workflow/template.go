@@ -0,0 +1,75 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// templatePlaceholder matches a {{ expression }} in node config strings.
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// processTemplate replaces {{expression}} placeholders with values from data.
+//
+// An expression is a field path: "name", "order.customer.tier",
+// "items.0.price" or "items[0].price". A "$." prefix addresses data
+// explicitly, and "$.input." (the AI nodes' syntax) is the same as "$."
+// unless data has no such field but an "input" object does. Placeholders
+// whose path does not resolve are left as they are.
+func processTemplate(template string, data interface{}) string {
+	if !strings.Contains(template, "{{") {
+		return template
+	}
+	return templatePlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		expr := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		value, ok := evalTemplateExpr(expr, data)
+		if !ok {
+			return placeholder
+		}
+		return templateString(value)
+	})
+}
+
+// templateValue resolves s when it is a single placeholder, keeping the
+// value's type (numbers stay numbers, objects stay objects).
+func templateValue(s string, data interface{}) (interface{}, bool) {
+	loc := templatePlaceholder.FindStringSubmatchIndex(s)
+	if loc == nil || loc[0] != 0 || loc[1] != len(s) {
+		return nil, false
+	}
+	return evalTemplateExpr(s[loc[2]:loc[3]], data)
+}
+
+// evalTemplateExpr resolves a placeholder expression against data.
+func evalTemplateExpr(expr string, data interface{}) (interface{}, bool) {
+	switch {
+	case expr == "":
+		return nil, false
+	case expr == "$":
+		return data, true
+	case strings.HasPrefix(expr, "$.input.") || strings.HasPrefix(expr, "$.input["):
+		rest := strings.TrimPrefix(expr, "$.input")
+		if value, ok := lookupField(data, strings.TrimPrefix(rest, ".")); ok {
+			return value, true
+		}
+		return lookupField(data, expr[2:])
+	case strings.HasPrefix(expr, "$.") || strings.HasPrefix(expr, "$["):
+		return lookupField(data, strings.TrimPrefix(expr[1:], "."))
+	}
+	return lookupField(data, expr)
+}
+
+// templateString formats a resolved value for a template: objects and
+// arrays as JSON, anything else as fmt prints it.
+func templateString(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		if b, err := json.Marshal(value); err == nil {
+			return string(b)
+		}
+	case nil:
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,90 @@
+package workflow
+
+import "testing"
+
+func TestProcessTemplate(t *testing.T) {
+	data := map[string]interface{}{
+		"name": "Ada",
+		"order": map[string]interface{}{
+			"id":       42,
+			"customer": map[string]interface{}{"tier": "gold"},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"sku": "a-1", "price": 9.5},
+			map[string]interface{}{"sku": "b-2", "price": 20},
+		},
+		"tags":     []interface{}{"x", "y"},
+		"user.id":  "flat",
+		"nothing":  nil,
+		"enabled":  true,
+		"input":    map[string]interface{}{"source": "webhook"},
+		"document": map[string]interface{}{"title": "t"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{"top-level field", "Hello {{name}}", "Hello Ada"},
+		{"spaces", "Hello {{ name }}", "Hello Ada"},
+		{"nested object", "{{order.customer.tier}} #{{order.id}}", "gold #42"},
+		{"dotted index", "{{items.1.price}}", "20"},
+		{"bracket index", "{{items[0].sku}}", "a-1"},
+		{"root prefix", "{{$.order.customer.tier}}", "gold"},
+		{"input prefix", "{{ $.input.items[1].sku }}", "b-2"},
+		{"input prefix falls back to input field", "{{ $.input.source }}", "webhook"},
+		{"flat key with dot wins", "{{user.id}}", "flat"},
+		{"array as JSON", "{{tags}}", `["x","y"]`},
+		{"object as JSON", "{{document}}", `{"title":"t"}`},
+		{"nil renders empty", "[{{nothing}}]", "[]"},
+		{"bool", "{{enabled}}", "true"},
+		{"missing field kept", "{{missing}}", "{{missing}}"},
+		{"missing nested field kept", "{{order.customer.name}}", "{{order.customer.name}}"},
+		{"index out of range kept", "{{items.5.sku}}", "{{items.5.sku}}"},
+		{"index on object kept", "{{order[0]}}", "{{order[0]}}"},
+		{"malformed path kept", "{{items[x]}}", "{{items[x]}}"},
+		{"empty placeholder kept", "{{}}", "{{}}"},
+		{"mixed", "{{name}} bought {{items.0.sku}} and {{unknown.path}}", "Ada bought a-1 and {{unknown.path}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := processTemplate(tt.template, data); got != tt.want {
+				t.Errorf("processTemplate(%q) = %q, want %q", tt.template, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessTemplate_NonObjectData(t *testing.T) {
+	if got := processTemplate("{{[1]}} of {{$}}", []interface{}{"a", "b"}); got != `b of ["a","b"]` {
+		t.Errorf("processTemplate() = %q", got)
+	}
+	if got := processTemplate("{{name}}", "plain"); got != "{{name}}" {
+		t.Errorf("processTemplate() on a string = %q, want the placeholder kept", got)
+	}
+}
+
+func TestTemplateValue_KeepsType(t *testing.T) {
+	data := map[string]interface{}{"order": map[string]interface{}{"items": []interface{}{3, 4}}}
+
+	if v, ok := templateValue("{{ $.input.order.items[1] }}", data); !ok || v != 4 {
+		t.Errorf("templateValue() = %v, %v; want 4", v, ok)
+	}
+	if _, ok := templateValue("n={{order.items.0}}", data); ok {
+		t.Error("templateValue() should only resolve a lone placeholder")
+	}
+	if _, ok := templateValue("{{order.missing}}", data); ok {
+		t.Error("templateValue() resolved a missing path")
+	}
+}
+
+func TestSetField_DottedIndex(t *testing.T) {
+	data := map[string]interface{}{"items": []interface{}{map[string]interface{}{"qty": 1}}}
+	if err := setField(data, "items.0.qty", 2); err != nil {
+		t.Fatalf("setField() error = %v", err)
+	}
+	if v, _ := lookupField(data, "items[0].qty"); v != 2 {
+		t.Errorf("items[0].qty = %v, want 2", v)
+	}
+}
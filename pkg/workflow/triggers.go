@@ -0,0 +1,177 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	"github.com/nats-io/nats.go"
+)
+
+// TriggerSource starts workflow executions from messages of an external
+// source, such as an EventBus address or a NATS subject. Register sources with
+// WorkflowVerticle.RegisterTrigger; the verticle starts them after its
+// workflows are registered and stops them when it stops.
+type TriggerSource interface {
+	// Start begins consuming and calls fire with each message's payload.
+	// It must not block.
+	Start(ctx core.FluxorContext, fire TriggerFunc) error
+
+	// Stop stops consuming. Executions already started keep running.
+	Stop() error
+}
+
+// TriggerFunc starts an execution with input and returns its ID.
+type TriggerFunc func(ctx context.Context, input interface{}) (string, error)
+
+// triggerInput decodes a message payload as JSON, falling back to the raw
+// string when it is not JSON.
+func triggerInput(data []byte) interface{} {
+	var input interface{}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return string(data)
+	}
+	return input
+}
+
+// EventBusTriggerSource starts an execution for every message sent or
+// published to an EventBus address. A request gets {"executionId": ...} back.
+type EventBusTriggerSource struct {
+	address  string
+	consumer core.Consumer
+}
+
+// NewEventBusTriggerSource creates a trigger source consuming address on the
+// verticle's EventBus.
+func NewEventBusTriggerSource(address string) *EventBusTriggerSource {
+	return &EventBusTriggerSource{address: address}
+}
+
+// Start implements TriggerSource.
+func (s *EventBusTriggerSource) Start(ctx core.FluxorContext, fire TriggerFunc) error {
+	if err := core.ValidateAddress(s.address); err != nil {
+		return err
+	}
+	s.consumer = ctx.EventBus().Consumer(s.address).Handler(func(mctx core.FluxorContext, msg core.Message) error {
+		input := msg.Body()
+		if body, ok := input.([]byte); ok {
+			input = triggerInput(body)
+		}
+		execID, err := fire(mctx.Context(), input)
+		if err != nil {
+			return err
+		}
+		if msg.ReplyAddress() == "" {
+			return nil
+		}
+		return msg.Reply(map[string]interface{}{"executionId": execID})
+	})
+	return nil
+}
+
+// Stop implements TriggerSource.
+func (s *EventBusTriggerSource) Stop() error {
+	if s.consumer == nil {
+		return nil
+	}
+	return s.consumer.Unregister()
+}
+
+// NATSTriggerConfig configures a NATS trigger source.
+type NATSTriggerConfig struct {
+	// URL is the NATS server URL. Default: nats.DefaultURL.
+	URL string
+
+	// Subject to subscribe to; wildcards are allowed.
+	Subject string
+
+	// Queue, when set, subscribes in a queue group so each message starts a
+	// single execution across all instances sharing the group.
+	Queue string
+
+	// Name is an optional NATS connection name.
+	Name string
+}
+
+// NATSTriggerSource starts an execution for every message on a NATS subject.
+// The message data (JSON, or else a string) is the execution input, and a
+// request gets {"executionId": ...} back.
+type NATSTriggerSource struct {
+	config NATSTriggerConfig
+	logger core.Logger
+
+	mu   sync.Mutex
+	conn *nats.Conn
+}
+
+// NewNATSTriggerSource creates a NATS trigger source. It connects on Start.
+func NewNATSTriggerSource(config NATSTriggerConfig) *NATSTriggerSource {
+	if config.URL == "" {
+		config.URL = nats.DefaultURL
+	}
+	return &NATSTriggerSource{config: config, logger: core.NewDefaultLogger()}
+}
+
+// Start implements TriggerSource.
+func (s *NATSTriggerSource) Start(ctx core.FluxorContext, fire TriggerFunc) error {
+	if s.config.Subject == "" {
+		return fmt.Errorf("nats trigger requires a subject")
+	}
+
+	var opts []nats.Option
+	if s.config.Name != "" {
+		opts = append(opts, nats.Name(s.config.Name))
+	}
+	conn, err := nats.Connect(s.config.URL, opts...)
+	if err != nil {
+		return fmt.Errorf("nats trigger: connect %s: %w", s.config.URL, err)
+	}
+
+	handler := func(msg *nats.Msg) {
+		execID, err := fire(ctx.Context(), triggerInput(msg.Data))
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("nats trigger %s: %v", msg.Subject, err))
+			return
+		}
+		if msg.Reply == "" {
+			return
+		}
+		reply, _ := json.Marshal(map[string]interface{}{"executionId": execID})
+		if err := msg.Respond(reply); err != nil {
+			s.logger.Error(fmt.Sprintf("nats trigger %s: reply failed: %v", msg.Subject, err))
+		}
+	}
+	if s.config.Queue != "" {
+		_, err = conn.QueueSubscribe(s.config.Subject, s.config.Queue, handler)
+	} else {
+		_, err = conn.Subscribe(s.config.Subject, handler)
+	}
+	if err == nil {
+		// Make sure the subscription is registered before Start returns
+		err = conn.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("nats trigger: subscribe %s: %w", s.config.Subject, err)
+	}
+
+	s.mu.Lock()
+	s.conn = conn
+	s.mu.Unlock()
+	return nil
+}
+
+// Stop implements TriggerSource. Messages already received are handled
+// before the connection closes.
+func (s *NATSTriggerSource) Stop() error {
+	s.mu.Lock()
+	conn := s.conn
+	s.conn = nil
+	s.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	return conn.Drain()
+}
@@ -0,0 +1,150 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+	natssrv "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// captureWorkflow is a workflow whose single function node sends its input
+// to the returned channel.
+func captureWorkflow(v *WorkflowVerticle, id string) (*WorkflowDefinition, <-chan interface{}) {
+	inputs := make(chan interface{}, 16)
+	v.RegisterFunction("capture-"+id, func(data interface{}) (interface{}, error) {
+		inputs <- data
+		return data, nil
+	})
+	return &WorkflowDefinition{ID: id, Nodes: []NodeDefinition{
+		{ID: "capture", Type: string(NodeTypeFunction), Config: map[string]interface{}{"function": "capture-" + id}},
+	}}, inputs
+}
+
+func deployTriggerVerticle(t *testing.T, v *WorkflowVerticle) core.GoCMD {
+	t.Helper()
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	if _, err := gocmd.DeployVerticle(v); err != nil {
+		t.Fatalf("DeployVerticle() error = %v", err)
+	}
+	return gocmd
+}
+
+// awaitInput repeats send until the workflow receives an input, since the
+// verticle starts its triggers asynchronously.
+func awaitInput(t *testing.T, inputs <-chan interface{}, send func() error) interface{} {
+	t.Helper()
+	deadline := time.After(2 * time.Second)
+	tick := time.NewTicker(20 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		select {
+		case input := <-inputs:
+			return input
+		case <-tick.C:
+			_ = send()
+		case <-deadline:
+			t.Fatal("no execution was triggered")
+			return nil
+		}
+	}
+}
+
+func TestEventBusTriggerSource_PublishStartsExecution(t *testing.T) {
+	v := NewWorkflowVerticle(nil)
+	def, inputs := captureWorkflow(v, "queued")
+	v.workflows = []*WorkflowDefinition{def}
+	v.RegisterTrigger("queued", NewEventBusTriggerSource("orders.created"))
+	gocmd := deployTriggerVerticle(t, v)
+
+	payload := map[string]interface{}{"orderId": "o-1", "total": 42.5}
+	got := awaitInput(t, inputs, func() error { return gocmd.EventBus().Publish("orders.created", payload) })
+	if !reflect.DeepEqual(got, payload) {
+		t.Errorf("execution input = %v, want %v", got, payload)
+	}
+}
+
+func TestEventBusTriggerSource_FromConfig(t *testing.T) {
+	v := NewWorkflowVerticle(&WorkflowVerticleConfig{
+		EventTriggers: []EventTriggerConfig{{Address: "jobs.new", WorkflowID: "jobs"}},
+	})
+	def, inputs := captureWorkflow(v, "jobs")
+	v.workflows = []*WorkflowDefinition{def}
+	gocmd := deployTriggerVerticle(t, v)
+
+	got := awaitInput(t, inputs, func() error {
+		_, err := gocmd.EventBus().Request("jobs.new", map[string]interface{}{"job": 7}, time.Second)
+		return err
+	})
+	if m, _ := got.(map[string]interface{}); m["job"] != float64(7) {
+		t.Errorf("execution input = %v, want job 7", got)
+	}
+}
+
+func TestWorkflowVerticle_TriggerForUnknownWorkflow(t *testing.T) {
+	v := NewWorkflowVerticle(nil)
+	v.RegisterTrigger("missing", NewEventBusTriggerSource("nowhere"))
+
+	v.engine = NewEngine(nil)
+	err := v.startTriggers(nil)
+	if err == nil || !strings.Contains(err.Error(), "unknown workflow: missing") {
+		t.Errorf("startTriggers() error = %v, want unknown workflow", err)
+	}
+}
+
+func TestNATSTriggerSource_MessageStartsExecution(t *testing.T) {
+	server, err := natssrv.NewServer(&natssrv.Options{Port: -1})
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	go server.Start()
+	t.Cleanup(server.Shutdown)
+	if !server.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	v := NewWorkflowVerticle(nil)
+	def, inputs := captureWorkflow(v, "from-nats")
+	v.workflows = []*WorkflowDefinition{def}
+	v.RegisterTrigger("from-nats", NewNATSTriggerSource(NATSTriggerConfig{URL: server.ClientURL(), Subject: "events.>", Queue: "workers"}))
+	deployTriggerVerticle(t, v)
+
+	nc, err := nats.Connect(server.ClientURL())
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer nc.Close()
+
+	payload := map[string]interface{}{"sensor": "s-9", "value": 3.5}
+	data, _ := json.Marshal(payload)
+	got := awaitInput(t, inputs, func() error { return nc.Publish("events.sensor", data) })
+	if !reflect.DeepEqual(got, payload) {
+		t.Errorf("execution input = %v, want %v", got, payload)
+	}
+
+	// A request is answered with the execution ID; plain text stays a string
+	reply, err := nc.Request("events.text", []byte("hello"), 2*time.Second)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(reply.Data, &body); err != nil || body["executionId"] == nil {
+		t.Errorf("reply = %s, want an executionId", reply.Data)
+	}
+	for {
+		select {
+		case input := <-inputs:
+			if input == "hello" {
+				return
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("text message did not start an execution")
+		}
+	}
+}
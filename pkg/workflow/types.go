@@ -45,6 +45,44 @@ type NodeDefinition struct {
 	FalseNext  []string               `json:"falseNext,omitempty"`  // For condition nodes
 	RetryCount int                    `json:"retryCount,omitempty"` // Retry on failure
 	Timeout    string                 `json:"timeout,omitempty"`    // Execution timeout
+
+	// RetryBackoff spaces retries: "linear" (default), "exponential" or
+	// "constant" ("fixed"), starting from RetryDelay (default "1s") and capped
+	// at MaxRetryDelay when set. RetryJitter (0-1) shortens each wait by a
+	// random fraction up to that much, so executions failing together do not
+	// retry in lockstep. Waits end early when the node times out or the
+	// execution is cancelled.
+	RetryBackoff  string  `json:"retryBackoff,omitempty"`
+	RetryDelay    string  `json:"retryDelay,omitempty"`
+	MaxRetryDelay string  `json:"maxRetryDelay,omitempty"`
+	RetryJitter   float64 `json:"retryJitter,omitempty"`
+
+	// RunIf is a guard expression (see Guards) evaluated against the node's
+	// input. When false the node is skipped: its handler is not called and
+	// the input is passed through as its output to the Next nodes.
+	RunIf string `json:"runIf,omitempty"`
+
+	// OnTimeout decides what happens when the node exceeds Timeout:
+	// "onError" (default) follows OnError like any other failure, "fail"
+	// fails the execution right away. Either way the error is recorded with
+	// Type ErrorTypeTimeout.
+	OnTimeout string `json:"onTimeout,omitempty"`
+
+	// Guards holds the "when" expressions of guarded edges; an edge is only
+	// followed when its expression holds. In JSON, guarded edges are written
+	// inline as {"node": "id", "when": "$.amount > 100"} entries of the edge
+	// lists.
+	Guards EdgeGuards `json:"-"`
+}
+
+// EdgeGuards holds a node's edge guards per edge list, keyed by the edge's
+// position in that list: Next[i] guards NodeDefinition.Next[i]. Edges
+// without an entry are always followed.
+type EdgeGuards struct {
+	Next      map[int]string
+	OnError   map[int]string
+	TrueNext  map[int]string
+	FalseNext map[int]string
 }
 
 // NodeType represents the type of workflow node.
@@ -58,13 +96,18 @@ const (
 	NodeTypeManual   NodeType = "manual"   // Manual trigger
 
 	// Action nodes - perform operations
-	NodeTypeFunction NodeType = "function" // Custom function
-	NodeTypeHTTP     NodeType = "http"     // HTTP request
-	NodeTypeOpenAI   NodeType = "openai"   // OpenAI API request
-	NodeTypeAI       NodeType = "ai"       // Generic AI API (OpenAI, Cursor, Anthropic, etc.)
-	NodeTypeEventBus NodeType = "eventbus" // Send to EventBus
-	NodeTypeSet      NodeType = "set"      // Set variables
-	NodeTypeCode     NodeType = "code"     // Execute code
+	NodeTypeFunction  NodeType = "function"   // Custom function
+	NodeTypeHTTP      NodeType = "http"       // HTTP request
+	NodeTypeOpenAI    NodeType = "openai"     // OpenAI API request
+	NodeTypeAnthropic NodeType = "anthropic"  // Anthropic Claude Messages API request
+	NodeTypeAI        NodeType = "ai"         // Generic AI API (OpenAI, Cursor, Anthropic, etc.)
+	NodeTypeEventBus  NodeType = "eventbus"   // Send to EventBus
+	NodeTypeEnrich    NodeType = "enrich"     // Enrich data via cached EventBus lookup
+	NodeTypeDB        NodeType = "db"         // SQL query via database/sql
+	NodeTypeSet       NodeType = "set"        // Set variables
+	NodeTypeEncode    NodeType = "encode"     // Base64, hex and URL encoding
+	NodeTypeMergeData NodeType = "merge-data" // JSON Merge Patch, JSON Patch and deep merge
+	NodeTypeCode      NodeType = "code"       // Execute code
 
 	// Flow control nodes
 	NodeTypeCondition   NodeType = "condition"   // If/else branching
@@ -91,22 +134,40 @@ type ExecutionContext struct {
 	NodeOutputs map[string]interface{} `json:"nodeOutputs"` // Output from each node
 	Variables   map[string]interface{} `json:"variables"`   // User-defined variables
 	Errors      []ExecutionError       `json:"errors,omitempty"`
+	Depth       int                    `json:"depth,omitempty"` // Sub-workflow nesting depth (0 for top-level)
 }
 
 // ExecutionError represents an error during execution.
 type ExecutionError struct {
 	NodeID    string    `json:"nodeId"`
 	Message   string    `json:"message"`
+	Type      string    `json:"type,omitempty"` // ErrorTypeTimeout, or empty for other failures
 	Timestamp time.Time `json:"timestamp"`
 	Retried   bool      `json:"retried"`
 }
 
+// ErrorTypeTimeout is the ExecutionError.Type of a node that exceeded its Timeout.
+const ErrorTypeTimeout = "timeout"
+
+// Node timeout policies for NodeDefinition.OnTimeout.
+const (
+	TimeoutPolicyOnError = "onError" // follow the node's OnError edges (default)
+	TimeoutPolicyFail    = "fail"    // fail the execution
+)
+
+// MergeTimeoutProceed is the default Config["onTimeout"] of a merge node:
+// when its timeout passes, it runs with the inputs that arrived.
+// TimeoutPolicyFail fails the execution instead.
+const MergeTimeoutProceed = "proceed"
+
 // NodeInput is passed to each node during execution.
 type NodeInput struct {
 	Data        interface{}            `json:"data"`        // Input data from previous node
 	Context     *ExecutionContext      `json:"context"`     // Execution context
 	Config      map[string]interface{} `json:"config"`      // Node configuration
 	TriggerData interface{}            `json:"triggerData"` // Original trigger data
+	NodeID      string                 `json:"nodeId"`      // ID of the node being run
+	NodeType    NodeType               `json:"nodeType"`    // Type of the node being run
 }
 
 // NodeOutput is returned from each node after execution.
@@ -120,6 +181,9 @@ type NodeOutput struct {
 // NodeHandler is the function signature for node execution.
 type NodeHandler func(ctx context.Context, input *NodeInput) (*NodeOutput, error)
 
+// NodeMiddleware wraps node handler invocations (see Engine.UseNodeMiddleware).
+type NodeMiddleware func(next NodeHandler) NodeHandler
+
 // NodeRegistry stores registered node handlers.
 type NodeRegistry interface {
 	Register(nodeType NodeType, handler NodeHandler)
@@ -150,6 +214,7 @@ type ExecutionStatus string
 const (
 	ExecutionStatusPending   ExecutionStatus = "pending"
 	ExecutionStatusRunning   ExecutionStatus = "running"
+	ExecutionStatusPaused    ExecutionStatus = "paused"
 	ExecutionStatusCompleted ExecutionStatus = "completed"
 	ExecutionStatusFailed    ExecutionStatus = "failed"
 	ExecutionStatusCancelled ExecutionStatus = "cancelled"
@@ -164,4 +229,12 @@ type ExecutionState struct {
 	EndTime     *time.Time        `json:"endTime,omitempty"`
 	Context     *ExecutionContext `json:"context"`
 	Error       string            `json:"error,omitempty"`
+
+	// PendingNodes holds dispatched-but-unfinished nodes (nodeID -> input).
+	// Persisted so ResumeExecutions can re-dispatch them after a restart.
+	PendingNodes map[string]interface{} `json:"pendingNodes,omitempty"`
+
+	// held are the pending nodes dispatched while paused, which
+	// ResumeExecution runs (nodeID -> input)
+	held map[string]interface{}
 }
@@ -2,7 +2,9 @@ package workflow
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,8 +16,23 @@ import (
 type WorkflowVerticle struct {
 	engine           *Engine
 	functionRegistry *FunctionRegistry
+	dbRegistry       *DBRegistry
+	scheduler        *Scheduler
 	server           *web.FastHTTPServer
+	webhooks         *webhookRoutes
 	httpAddr         string
+	workflows        []*WorkflowDefinition
+	store            ExecutionStore
+	retention        ExecutionRetention
+	lifecycleEvents  bool
+	concurrency      ExecutionConcurrency
+	triggers         []workflowTrigger
+}
+
+// workflowTrigger is a TriggerSource starting executions of one workflow.
+type workflowTrigger struct {
+	workflowID string
+	source     TriggerSource
 }
 
 // WorkflowVerticleConfig configures the workflow verticle.
@@ -26,21 +43,49 @@ type WorkflowVerticleConfig struct {
 	// Workflows to register on start
 	Workflows []*WorkflowDefinition
 
-	// EventTriggers to set up on start
+	// EventTriggers to set up on start (see NewEventBusTriggerSource)
 	EventTriggers []EventTriggerConfig
+
+	// ExecutionStore persists executions; running ones are resumed on start
+	ExecutionStore ExecutionStore
+
+	// ExecutionRetention bounds the finished executions kept in memory
+	ExecutionRetention ExecutionRetention
+
+	// LifecycleEvents publishes execution and node events to the EventBus
+	LifecycleEvents bool
+
+	// ExecutionConcurrency caps running executions; overflow beyond its queue
+	// is rejected with 503
+	ExecutionConcurrency ExecutionConcurrency
 }
 
 // NewWorkflowVerticle creates a new workflow verticle.
 func NewWorkflowVerticle(config *WorkflowVerticleConfig) *WorkflowVerticle {
 	v := &WorkflowVerticle{
 		functionRegistry: NewFunctionRegistry(),
+		dbRegistry:       NewDBRegistry(),
 	}
 	if config != nil {
 		v.httpAddr = config.HTTPAddr
+		v.workflows = config.Workflows
+		v.store = config.ExecutionStore
+		v.retention = config.ExecutionRetention
+		v.lifecycleEvents = config.LifecycleEvents
+		v.concurrency = config.ExecutionConcurrency
+		for _, trigger := range config.EventTriggers {
+			v.RegisterTrigger(trigger.WorkflowID, NewEventBusTriggerSource(trigger.Address))
+		}
 	}
 	return v
 }
 
+// RegisterTrigger makes source start executions of workflowID. Sources
+// registered before the verticle is deployed are started with it.
+func (v *WorkflowVerticle) RegisterTrigger(workflowID string, source TriggerSource) {
+	v.triggers = append(v.triggers, workflowTrigger{workflowID: workflowID, source: source})
+}
+
 // RegisterFunction registers a custom function for use in function nodes.
 func (v *WorkflowVerticle) RegisterFunction(name string, fn func(data interface{}) (interface{}, error)) {
 	v.functionRegistry.Register(name, func(_ context.Context, data interface{}) (interface{}, error) {
@@ -48,6 +93,17 @@ func (v *WorkflowVerticle) RegisterFunction(name string, fn func(data interface{
 	})
 }
 
+// RegisterDB registers a database connection for use in db nodes
+// (Config["connection"] = name).
+func (v *WorkflowVerticle) RegisterDB(name string, db *sql.DB) {
+	v.dbRegistry.Register(name, db)
+}
+
+// Scheduler returns the scheduler firing schedule trigger nodes.
+func (v *WorkflowVerticle) Scheduler() *Scheduler {
+	return v.scheduler
+}
+
 // Engine returns the workflow engine.
 func (v *WorkflowVerticle) Engine() *Engine {
 	return v.engine
@@ -56,16 +112,18 @@ func (v *WorkflowVerticle) Engine() *Engine {
 // Start implements core.Verticle.
 func (v *WorkflowVerticle) Start(ctx core.FluxorContext) error {
 	// Create workflow engine with EventBus
-	v.engine = NewEngine(ctx.EventBus())
+	v.engine = NewEngineWithOptions(ctx.EventBus(), EngineOptions{Store: v.store, Retention: v.retention, Metrics: ctx.GoCMD().Metrics(), LifecycleEvents: v.lifecycleEvents, Concurrency: v.concurrency})
 
 	// Register node handlers that require runtime dependencies
 	v.engine.RegisterNodeHandler(NodeTypeHTTP, HTTPNodeHandler)
 	v.engine.RegisterNodeHandler(NodeTypeOpenAI, OpenAINodeHandler)
+	v.engine.RegisterNodeHandler(NodeTypeAnthropic, AnthropicNodeHandler)
 	v.engine.RegisterNodeHandler(NodeTypeAI, AINodeHandler) // Generic AI node (supports Cursor, Anthropic, etc.)
-	v.engine.RegisterNodeHandler(NodeTypeSubWorkflow, CreateSubWorkflowHandler(v.engine))
 	v.engine.RegisterNodeHandler(NodeTypeDynamicLoop, DynamicLoopNodeHandler)
 	v.engine.RegisterNodeHandler(NodeTypeEventBus, CreateEventBusHandler(ctx.EventBus()))
+	v.engine.RegisterNodeHandler(NodeTypeEnrich, CreateEnrichHandler(ctx.EventBus()))
 	v.engine.RegisterNodeHandler(NodeTypeFunction, CreateFunctionHandler(v.functionRegistry))
+	v.engine.RegisterNodeHandler(NodeTypeDB, CreateDBHandler(v.dbRegistry))
 	v.engine.RegisterNodeHandler(NodeTypeCode, CodeNodeHandler)
 	v.engine.RegisterNodeHandler("filter", FilterNodeHandler)
 	v.engine.RegisterNodeHandler("map", MapNodeHandler(v.functionRegistry))
@@ -76,7 +134,12 @@ func (v *WorkflowVerticle) Start(ctx core.FluxorContext) error {
 	v.engine.RegisterNodeHandler(NodeType("aimodule.embed"), AIEmbedNodeHandler)
 	v.engine.RegisterNodeHandler(NodeType("aimodule.toolcall"), AIChatNodeHandler)
 
-	// Load workflows from config
+	// Register WorkflowVerticleConfig.Workflows, then workflows from the deployment config
+	for _, def := range v.workflows {
+		if err := v.engine.RegisterWorkflow(def); err != nil {
+			return fmt.Errorf("failed to register workflow %s: %w", def.ID, err)
+		}
+	}
 	if workflows, ok := ctx.Config()["workflows"].([]interface{}); ok {
 		for _, wf := range workflows {
 			if wfMap, ok := wf.(map[string]interface{}); ok {
@@ -92,6 +155,26 @@ func (v *WorkflowVerticle) Start(ctx core.FluxorContext) error {
 		}
 	}
 
+	// Fire schedule trigger nodes of the registered workflows
+	v.scheduler = NewScheduler(v.engine)
+	for _, def := range v.engine.ListWorkflows() {
+		if err := v.scheduler.Schedule(def); err != nil {
+			return err
+		}
+	}
+	v.scheduler.Start()
+
+	if err := v.startTriggers(ctx); err != nil {
+		return err
+	}
+
+	// Resume executions interrupted by a previous shutdown
+	if v.store != nil {
+		if _, err := v.engine.ResumeExecutions(ctx.Context()); err != nil {
+			return fmt.Errorf("failed to resume executions: %w", err)
+		}
+	}
+
 	// Start HTTP API if configured
 	if v.httpAddr != "" {
 		if err := v.startHTTPAPI(ctx); err != nil {
@@ -104,17 +187,62 @@ func (v *WorkflowVerticle) Start(ctx core.FluxorContext) error {
 
 // Stop implements core.Verticle.
 func (v *WorkflowVerticle) Stop(ctx core.FluxorContext) error {
+	v.stopTriggers(len(v.triggers))
+	if v.scheduler != nil {
+		v.scheduler.Stop()
+	}
+	if v.engine != nil {
+		v.engine.Close()
+	}
+	_ = v.dbRegistry.Close()
 	if v.server != nil {
 		return v.server.Stop()
 	}
 	return nil
 }
 
+// startTriggers starts the registered trigger sources. If one fails, the
+// ones already started are stopped again.
+func (v *WorkflowVerticle) startTriggers(ctx core.FluxorContext) error {
+	for i, trigger := range v.triggers {
+		workflowID := trigger.workflowID
+		if !v.engine.hasWorkflow(workflowID) {
+			v.stopTriggers(i)
+			return fmt.Errorf("trigger for unknown workflow: %s", workflowID)
+		}
+		fire := func(ctx context.Context, input interface{}) (string, error) {
+			return v.engine.ExecuteWorkflow(ctx, workflowID, input)
+		}
+		if err := trigger.source.Start(ctx, fire); err != nil {
+			v.stopTriggers(i)
+			return fmt.Errorf("failed to start trigger for workflow %s: %w", workflowID, err)
+		}
+	}
+	return nil
+}
+
+// stopTriggers stops the first n trigger sources.
+func (v *WorkflowVerticle) stopTriggers(n int) {
+	for _, trigger := range v.triggers[:n] {
+		if err := trigger.source.Stop(); err != nil {
+			core.NewDefaultLogger().Error(fmt.Sprintf("failed to stop trigger for workflow %s: %v", trigger.workflowID, err))
+		}
+	}
+}
+
 func (v *WorkflowVerticle) startHTTPAPI(ctx core.FluxorContext) error {
 	config := web.DefaultFastHTTPServerConfig(v.httpAddr)
 	v.server = web.NewFastHTTPServer(ctx.GoCMD(), config)
 	router := v.server.FastRouter()
 
+	// Webhook trigger nodes of the registered workflows
+	v.webhooks = newWebhookRoutes(v.engine, router)
+	for _, def := range v.engine.ListWorkflows() {
+		if err := v.webhooks.register(def); err != nil {
+			return err
+		}
+	}
+
 	// List workflows
 	router.GETFast("/workflows", func(c *web.FastRequestContext) error {
 		workflows := v.engine.ListWorkflows()
@@ -132,6 +260,12 @@ func (v *WorkflowVerticle) startHTTPAPI(ctx core.FluxorContext) error {
 		if err := v.engine.RegisterWorkflow(&def); err != nil {
 			return c.JSON(400, map[string]interface{}{"error": err.Error()})
 		}
+		if err := v.scheduler.Schedule(&def); err != nil {
+			return c.JSON(400, map[string]interface{}{"error": err.Error()})
+		}
+		if err := v.webhooks.register(&def); err != nil {
+			return c.JSON(400, map[string]interface{}{"error": err.Error()})
+		}
 		return c.JSON(201, map[string]interface{}{
 			"id":      def.ID,
 			"message": "workflow registered",
@@ -149,6 +283,9 @@ func (v *WorkflowVerticle) startHTTPAPI(ctx core.FluxorContext) error {
 		}
 
 		execID, err := v.engine.ExecuteWorkflow(c.Context(), workflowID, input)
+		if errors.Is(err, ErrEngineOverloaded) {
+			return c.JSON(503, map[string]interface{}{"error": err.Error()})
+		}
 		if err != nil {
 			return c.JSON(400, map[string]interface{}{"error": err.Error()})
 		}
@@ -180,6 +317,24 @@ func (v *WorkflowVerticle) startHTTPAPI(ctx core.FluxorContext) error {
 		})
 	})
 
+	// Pause and resume execution
+	router.POSTFast("/executions/:id/pause", func(c *web.FastRequestContext) error {
+		if err := v.engine.PauseExecution(c.Param("id")); err != nil {
+			return c.JSON(400, map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(200, map[string]interface{}{
+			"message": "execution paused",
+		})
+	})
+	router.POSTFast("/executions/:id/resume", func(c *web.FastRequestContext) error {
+		if err := v.engine.ResumeExecution(c.Param("id")); err != nil {
+			return c.JSON(400, map[string]interface{}{"error": err.Error()})
+		}
+		return c.JSON(200, map[string]interface{}{
+			"message": "execution resumed",
+		})
+	})
+
 	// Health check
 	router.GETFast("/health", func(c *web.FastRequestContext) error {
 		return c.JSON(200, map[string]interface{}{
@@ -250,9 +405,22 @@ func (n *NodeBuilder) Config(config map[string]interface{}) *NodeBuilder {
 	return n
 }
 
-// Next sets the next nodes.
+// Next sets the next nodes, dropping guards added by NextWhen.
 func (n *NodeBuilder) Next(nodeIDs ...string) *NodeBuilder {
-	n.node().Next = nodeIDs
+	node := n.node()
+	node.Next = nodeIDs
+	node.Guards.Next = nil
+	return n
+}
+
+// NextWhen adds a next node that is only followed when the guard expression holds.
+func (n *NodeBuilder) NextWhen(nodeID, when string) *NodeBuilder {
+	node := n.node()
+	node.Next = append(node.Next, nodeID)
+	if node.Guards.Next == nil {
+		node.Guards.Next = make(map[int]string)
+	}
+	node.Guards.Next[len(node.Next)-1] = when
 	return n
 }
 
@@ -262,6 +430,12 @@ func (n *NodeBuilder) OnError(nodeIDs ...string) *NodeBuilder {
 	return n
 }
 
+// RunIf skips the node, passing its input through, unless expr holds for it.
+func (n *NodeBuilder) RunIf(expr string) *NodeBuilder {
+	n.node().RunIf = expr
+	return n
+}
+
 // TrueNext sets the nodes to execute if condition is true.
 func (n *NodeBuilder) TrueNext(nodeIDs ...string) *NodeBuilder {
 	n.node().TrueNext = nodeIDs
@@ -280,6 +454,26 @@ func (n *NodeBuilder) Retry(count int) *NodeBuilder {
 	return n
 }
 
+// RetryJitter randomizes each retry wait down by up to fraction (0-1) of it.
+func (n *NodeBuilder) RetryJitter(fraction float64) *NodeBuilder {
+	n.node().RetryJitter = fraction
+	return n
+}
+
+// RetryBackoff sets how retries are spaced: strategy is RetryBackoffLinear,
+// RetryBackoffExponential or RetryBackoffConstant, starting from delay and
+// capped at maxDelay (0 for no cap).
+func (n *NodeBuilder) RetryBackoff(strategy string, delay, maxDelay time.Duration) *NodeBuilder {
+	node := n.node()
+	node.RetryBackoff = strategy
+	node.RetryDelay = delay.String()
+	node.MaxRetryDelay = ""
+	if maxDelay > 0 {
+		node.MaxRetryDelay = maxDelay.String()
+	}
+	return n
+}
+
 // Timeout sets the execution timeout.
 func (n *NodeBuilder) Timeout(d time.Duration) *NodeBuilder {
 	n.node().Timeout = d.String()
@@ -0,0 +1,269 @@
+package workflow
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/web"
+)
+
+// DefaultWebhookSignatureHeader carries the HMAC-SHA256 of the request body
+// ("sha256=<hex>" or plain hex) when a webhook node sets Config["secret"].
+const DefaultWebhookSignatureHeader = "X-Signature-256"
+
+// defaultWebhookTimeout bounds how long a respondMode=lastNode request waits.
+const defaultWebhookTimeout = 30 * time.Second
+
+// webhookRoute is one webhook node exposed over HTTP.
+type webhookRoute struct {
+	workflowID      string
+	nodeID          string
+	path            string
+	secret          string
+	signatureHeader string
+	respondLastNode bool
+	timeout         time.Duration
+}
+
+// webhookRoutes maps HTTP paths to webhook nodes. Each path is mounted on the
+// router once; the handler looks the route up on every request, so workflows
+// can be re-registered without stacking duplicate router entries.
+type webhookRoutes struct {
+	engine *Engine
+	router *web.FastRouter
+
+	mu      sync.RWMutex
+	routes  map[string]*webhookRoute // path -> route
+	mounted map[string]bool
+}
+
+func newWebhookRoutes(engine *Engine, router *web.FastRouter) *webhookRoutes {
+	return &webhookRoutes{
+		engine:  engine,
+		router:  router,
+		routes:  make(map[string]*webhookRoute),
+		mounted: make(map[string]bool),
+	}
+}
+
+// RegisterWebhooks exposes every webhook node of def as a POST route on the
+// verticle's HTTP server: /webhook/{workflowId} by default, or Config["path"].
+// The JSON body becomes the execution input and the response carries the
+// execution ID (202), or the final node's output (200) with
+// Config["respondMode"] = "lastNode". A "wait" query parameter overrides the
+// node's mode per request: ?wait=true waits for the output, ?wait=false does not.
+//
+// Config["secret"] requires an HMAC-SHA256 signature of the body in
+// DefaultWebhookSignatureHeader (or Config["signatureHeader"]); requests
+// without a valid one get 401.
+//
+// Routes of a previously registered workflow with the same ID are replaced.
+// Returns an error if the verticle has no HTTP server (HTTPAddr unset), a
+// config value is invalid, or a path is already used by another workflow.
+func (v *WorkflowVerticle) RegisterWebhooks(def *WorkflowDefinition) error {
+	if v.webhooks == nil {
+		return fmt.Errorf("webhooks require WorkflowVerticleConfig.HTTPAddr")
+	}
+	return v.webhooks.register(def)
+}
+
+func (w *webhookRoutes) register(def *WorkflowDefinition) error {
+	var routes []*webhookRoute
+	for i := range def.Nodes {
+		node := &def.Nodes[i]
+		if NodeType(node.Type) != NodeTypeWebhook {
+			continue
+		}
+		route, err := newWebhookRoute(def.ID, node)
+		if err != nil {
+			return fmt.Errorf("webhook node %s: %w", node.ID, err)
+		}
+		routes = append(routes, route)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	seen := make(map[string]string, len(routes))
+	for _, route := range routes {
+		if other, ok := w.routes[route.path]; ok && other.workflowID != def.ID {
+			return fmt.Errorf("webhook path %s is already used by workflow %s", route.path, other.workflowID)
+		}
+		if nodeID, ok := seen[route.path]; ok {
+			return fmt.Errorf("webhook nodes %s and %s share path %s", nodeID, route.nodeID, route.path)
+		}
+		seen[route.path] = route.nodeID
+	}
+
+	for path, route := range w.routes {
+		if route.workflowID == def.ID {
+			delete(w.routes, path)
+		}
+	}
+	for _, route := range routes {
+		w.routes[route.path] = route
+		if !w.mounted[route.path] {
+			w.router.POSTFast(route.path, w.handler(route.path))
+			w.mounted[route.path] = true
+		}
+	}
+	return nil
+}
+
+func newWebhookRoute(workflowID string, node *NodeDefinition) (*webhookRoute, error) {
+	route := &webhookRoute{
+		workflowID:      workflowID,
+		nodeID:          node.ID,
+		path:            "/webhook/" + workflowID,
+		signatureHeader: DefaultWebhookSignatureHeader,
+		timeout:         defaultWebhookTimeout,
+	}
+	if path, _ := node.Config["path"].(string); path != "" {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		route.path = path
+	}
+	route.secret, _ = node.Config["secret"].(string)
+	if header, _ := node.Config["signatureHeader"].(string); header != "" {
+		route.signatureHeader = header
+	}
+
+	switch mode, _ := node.Config["respondMode"].(string); mode {
+	case "", "immediately":
+	case "lastNode":
+		route.respondLastNode = true
+	default:
+		return nil, fmt.Errorf("invalid respondMode %q (want immediately or lastNode)", mode)
+	}
+	if timeout, _ := node.Config["timeout"].(string); timeout != "" {
+		d, err := time.ParseDuration(timeout)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q", timeout)
+		}
+		route.timeout = d
+	}
+	return route, nil
+}
+
+func (w *webhookRoutes) handler(path string) web.FastRequestHandler {
+	return func(c *web.FastRequestContext) error {
+		w.mu.RLock()
+		route, ok := w.routes[path]
+		w.mu.RUnlock()
+		if !ok {
+			return c.JSON(404, map[string]interface{}{"error": "webhook not found"})
+		}
+
+		body := c.RequestCtx.PostBody()
+		if route.secret != "" && !validSignature(route.secret, body, string(c.RequestCtx.Request.Header.Peek(route.signatureHeader))) {
+			return c.JSON(401, map[string]interface{}{"error": "invalid signature"})
+		}
+
+		wait := route.respondLastNode
+		if raw := c.RequestCtx.QueryArgs().Peek("wait"); len(raw) > 0 {
+			parsed, err := strconv.ParseBool(string(raw))
+			if err != nil {
+				return c.JSON(400, map[string]interface{}{"error": "invalid wait parameter"})
+			}
+			wait = parsed
+		}
+
+		var input interface{} = map[string]interface{}{}
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &input); err != nil {
+				return c.JSON(400, map[string]interface{}{"error": "invalid JSON body"})
+			}
+		}
+
+		execID, err := w.engine.ExecuteWorkflow(c.Context(), route.workflowID, input)
+		if errors.Is(err, ErrEngineOverloaded) {
+			return c.JSON(503, map[string]interface{}{"error": err.Error()})
+		}
+		if err != nil {
+			return c.JSON(400, map[string]interface{}{"error": err.Error()})
+		}
+		accepted := map[string]interface{}{
+			"executionId": execID,
+			"workflowId":  route.workflowID,
+		}
+		if !wait {
+			return c.JSON(202, accepted)
+		}
+
+		ctx, cancel := context.WithTimeout(c.Context(), route.timeout)
+		defer cancel()
+		execCtx, err := w.engine.AwaitExecution(ctx, execID)
+		switch {
+		case err == nil:
+			return c.JSON(200, lastNodeOutput(w.engine, route.workflowID, execCtx))
+		case ctx.Err() != nil:
+			// Still running: the caller can poll /executions/{id}
+			return c.JSON(202, accepted)
+		default:
+			status := ExecutionStatusFailed
+			if errors.Is(err, ErrExecutionCancelled) {
+				status = ExecutionStatusCancelled
+			}
+			return c.JSON(500, map[string]interface{}{
+				"executionId": execID,
+				"status":      status,
+				"error":       err.Error(),
+			})
+		}
+	}
+}
+
+// validSignature checks signature ("sha256=<hex>" or "<hex>") against the
+// HMAC-SHA256 of body keyed with secret, in constant time.
+func validSignature(secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// lastNodeOutput returns the output of the workflow's final node (one without
+// outgoing edges). When several final nodes ran, their outputs are keyed by node ID.
+func lastNodeOutput(e *Engine, workflowID string, execCtx *ExecutionContext) interface{} {
+	e.mu.RLock()
+	def := e.workflows[workflowID]
+	outputs := make(map[string]interface{})
+	if def != nil {
+		for i := range def.Nodes {
+			node := &def.Nodes[i]
+			if len(node.Next) > 0 || len(node.TrueNext) > 0 || len(node.FalseNext) > 0 {
+				continue
+			}
+			if out, ok := execCtx.NodeOutputs[node.ID]; ok {
+				outputs[node.ID] = out
+			}
+		}
+	}
+	e.mu.RUnlock()
+
+	if len(outputs) == 1 {
+		for _, out := range outputs {
+			return out
+		}
+	}
+	return outputs
+}
+
+// webhookTriggerHandler is the handler for webhook nodes: the request body
+// flows on to the next nodes.
+func webhookTriggerHandler(ctx context.Context, input *NodeInput) (*NodeOutput, error) {
+	return &NodeOutput{Data: input.Data}, nil
+}
@@ -0,0 +1,209 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fluxorio/fluxor/pkg/core"
+)
+
+// webhookClient does not keep connections alive, so stopping the server never
+// races fasthttp's idle-connection close against a pending read.
+var webhookClient = &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+// startWebhookVerticle deploys a WorkflowVerticle with its HTTP API on a free
+// port, registering workflows on start.
+func startWebhookVerticle(t *testing.T, workflows ...*WorkflowDefinition) (*WorkflowVerticle, string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	gocmd := core.NewGoCMD(context.Background())
+	t.Cleanup(func() { _ = gocmd.Close() })
+	v := NewWorkflowVerticle(&WorkflowVerticleConfig{HTTPAddr: addr, Workflows: workflows})
+	if _, err := gocmd.DeployVerticle(v); err != nil {
+		t.Fatalf("DeployVerticle() error = %v", err)
+	}
+
+	baseURL := "http://" + addr
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := webhookClient.Get(baseURL + "/health")
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("HTTP API not reachable: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return v, baseURL
+}
+
+func registerWebhookWorkflow(t *testing.T, v *WorkflowVerticle, id string, config map[string]interface{}) {
+	t.Helper()
+	def := &WorkflowDefinition{
+		ID: id,
+		Nodes: []NodeDefinition{
+			{ID: "hook", Type: string(NodeTypeWebhook), Config: config, Next: []string{"tag"}},
+			{ID: "tag", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"handled": true}}},
+		},
+	}
+	if err := v.Engine().RegisterWorkflow(def); err != nil {
+		t.Fatalf("RegisterWorkflow() error = %v", err)
+	}
+	if err := v.RegisterWebhooks(def); err != nil {
+		t.Fatalf("RegisterWebhooks() error = %v", err)
+	}
+}
+
+func postJSON(t *testing.T, url string, body []byte, header map[string]string) (int, map[string]interface{}) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	var out map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	return resp.StatusCode, out
+}
+
+func TestWebhook_ExecutesWorkflow(t *testing.T) {
+	v, baseURL := startWebhookVerticle(t)
+	registerWebhookWorkflow(t, v, "orders", nil)
+
+	status, out := postJSON(t, baseURL+"/webhook/orders", []byte(`{"orderId":"42"}`), nil)
+	if status != http.StatusAccepted {
+		t.Fatalf("status = %d (%v), want 202", status, out)
+	}
+	execID, _ := out["executionId"].(string)
+	if execID == "" {
+		t.Fatalf("response %v has no executionId", out)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	execCtx, err := v.Engine().AwaitExecution(ctx, execID)
+	if err != nil {
+		t.Fatalf("AwaitExecution() error = %v", err)
+	}
+	tag, _ := execCtx.NodeOutputs["tag"].(map[string]interface{})
+	if tag["orderId"] != "42" || tag["handled"] != true {
+		t.Errorf("tag output = %v, want the webhook body plus handled", tag)
+	}
+}
+
+func TestWebhook_RespondLastNodeAndPath(t *testing.T) {
+	v, baseURL := startWebhookVerticle(t)
+	registerWebhookWorkflow(t, v, "sync", map[string]interface{}{"path": "hooks/sync", "respondMode": "lastNode"})
+
+	status, out := postJSON(t, baseURL+"/hooks/sync", []byte(`{"user":"alice"}`), nil)
+	if status != http.StatusOK {
+		t.Fatalf("status = %d (%v), want 200", status, out)
+	}
+	if out["user"] != "alice" || out["handled"] != true {
+		t.Errorf("response = %v, want the last node output", out)
+	}
+
+	if status, _ := postJSON(t, baseURL+"/webhook/sync", []byte(`{}`), nil); status != http.StatusNotFound {
+		t.Errorf("default path with override status = %d, want 404", status)
+	}
+}
+
+func TestWebhook_WaitQueryParam(t *testing.T) {
+	def := &WorkflowDefinition{
+		ID: "configured",
+		Nodes: []NodeDefinition{
+			{ID: "hook", Type: string(NodeTypeWebhook), Next: []string{"tag"}},
+			{ID: "tag", Type: string(NodeTypeSet), Config: map[string]interface{}{"values": map[string]interface{}{"handled": true}}},
+		},
+	}
+	v, baseURL := startWebhookVerticle(t, def)
+	registerWebhookWorkflow(t, v, "lazy", map[string]interface{}{"respondMode": "lastNode"})
+
+	// Workflows from the verticle config are served without RegisterWebhooks
+	status, out := postJSON(t, baseURL+"/webhook/configured?wait=true", []byte(`{"sku":"A1"}`), nil)
+	if status != http.StatusOK || out["sku"] != "A1" || out["handled"] != true {
+		t.Errorf("wait=true: status = %d, response = %v; want 200 with the last node output", status, out)
+	}
+	if status, out := postJSON(t, baseURL+"/webhook/lazy?wait=false", []byte(`{}`), nil); status != http.StatusAccepted || out["executionId"] == nil {
+		t.Errorf("wait=false on a lastNode webhook: status = %d, response = %v; want 202", status, out)
+	}
+	if status, _ := postJSON(t, baseURL+"/webhook/configured?wait=maybe", []byte(`{}`), nil); status != http.StatusBadRequest {
+		t.Errorf("invalid wait status = %d, want 400", status)
+	}
+}
+
+func TestWebhook_Signature(t *testing.T) {
+	v, baseURL := startWebhookVerticle(t)
+	registerWebhookWorkflow(t, v, "signed", map[string]interface{}{"secret": "s3cret"})
+
+	body := []byte(`{"event":"push"}`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if status, _ := postJSON(t, baseURL+"/webhook/signed", body, nil); status != http.StatusUnauthorized {
+		t.Errorf("unsigned status = %d, want 401", status)
+	}
+	if status, _ := postJSON(t, baseURL+"/webhook/signed", body, map[string]string{DefaultWebhookSignatureHeader: "sha256=00ff"}); status != http.StatusUnauthorized {
+		t.Errorf("bad signature status = %d, want 401", status)
+	}
+	if status, out := postJSON(t, baseURL+"/webhook/signed", body, map[string]string{DefaultWebhookSignatureHeader: signature}); status != http.StatusAccepted {
+		t.Errorf("signed status = %d (%v), want 202", status, out)
+	}
+}
+
+func TestWebhook_RegisterErrors(t *testing.T) {
+	v := NewWorkflowVerticle(nil)
+	if err := v.RegisterWebhooks(&WorkflowDefinition{ID: "x"}); err == nil {
+		t.Error("RegisterWebhooks() without an HTTP server should fail")
+	}
+
+	v, _ = startWebhookVerticle(t)
+	registerWebhookWorkflow(t, v, "first", map[string]interface{}{"path": "/shared"})
+	taken := &WorkflowDefinition{ID: "second", Nodes: []NodeDefinition{
+		{ID: "hook", Type: string(NodeTypeWebhook), Config: map[string]interface{}{"path": "/shared"}},
+	}}
+	if err := v.RegisterWebhooks(taken); err == nil {
+		t.Error("RegisterWebhooks() should reject a path used by another workflow")
+	}
+	bad := &WorkflowDefinition{ID: "third", Nodes: []NodeDefinition{
+		{ID: "hook", Type: string(NodeTypeWebhook), Config: map[string]interface{}{"respondMode": "later"}},
+	}}
+	if err := v.RegisterWebhooks(bad); err == nil {
+		t.Error("RegisterWebhooks() should reject an unknown respondMode")
+	}
+}
+
+func TestWebhook_RegisteredOverHTTPAPI(t *testing.T) {
+	_, baseURL := startWebhookVerticle(t)
+
+	def := []byte(`{"id":"api","nodes":[{"id":"hook","type":"webhook","config":{"respondMode":"lastNode"}}]}`)
+	if status, out := postJSON(t, baseURL+"/workflows", def, nil); status != http.StatusCreated {
+		t.Fatalf("POST /workflows status = %d (%v), want 201", status, out)
+	}
+	status, out := postJSON(t, baseURL+"/webhook/api", []byte(`{"ping":true}`), nil)
+	if status != http.StatusOK || out["ping"] != true {
+		t.Errorf("webhook status = %d, body = %v; want 200 echoing the body", status, out)
+	}
+}